@@ -7,9 +7,10 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/kaspanet/kaspad/app/snapshot"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
-	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/infrastructure/os/execenv"
 	"github.com/kaspanet/kaspad/infrastructure/os/limits"
@@ -122,6 +123,10 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 		return nil
 	}
 
+	if app.cfg.ExportSnapshot != "" {
+		return app.exportSnapshot(databaseContext)
+	}
+
 	// Create componentManager and start it.
 	componentManager, err := NewComponentManager(app.cfg, databaseContext, interrupt)
 	if err != nil {
@@ -161,6 +166,24 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 	return nil
 }
 
+// exportSnapshot writes a snapshot of the node's consensus to the file given
+// in --export-snapshot. The node is not started, so the consensus doesn't change
+// while the snapshot is being written.
+func (app *kaspadApp) exportSnapshot(databaseContext database.Database) error {
+	domain, err := newDomain(app.cfg, databaseContext)
+	if err != nil {
+		log.Errorf("Unable to load the consensus: %+v", err)
+		return err
+	}
+
+	err = snapshot.Export(domain.Consensus(), app.cfg.ActiveNetParams, app.cfg.ExportSnapshot)
+	if err != nil {
+		log.Errorf("Unable to export the snapshot: %+v", err)
+		return err
+	}
+	return nil
+}
+
 // dbPath returns the path to the block database given a database type.
 func databasePath(cfg *config.Config) string {
 	return filepath.Join(cfg.AppDir, defaultDataDirname)
@@ -180,7 +203,7 @@ func openDB(cfg *config.Config) (database.Database, error) {
 	}
 
 	log.Infof("Loading database from '%s'", dbPath)
-	db, err := ldb.NewLevelDB(dbPath, leveldbCacheSizeMiB)
+	db, err := backend.Open(cfg.DbType, dbPath, leveldbCacheSizeMiB, cfg.BoltNoSync)
 	if err != nil {
 		return nil, err
 	}
@@ -159,6 +159,31 @@ const (
 	CmdNotifyNewBlockTemplateRequestMessage
 	CmdNotifyNewBlockTemplateResponseMessage
 	CmdNewBlockTemplateNotificationMessage
+	CmdGetTransactionRequestMessage
+	CmdGetTransactionResponseMessage
+	CmdGetTransactionAcceptanceRequestMessage
+	CmdGetTransactionAcceptanceResponseMessage
+	CmdGetAddressHistoryRequestMessage
+	CmdGetAddressHistoryResponseMessage
+	CmdGetFeeEstimateRequestMessage
+	CmdGetFeeEstimateResponseMessage
+	CmdSubmitTransactionReplacementRequestMessage
+	CmdSubmitTransactionReplacementResponseMessage
+	CmdSaveMempoolRequestMessage
+	CmdSaveMempoolResponseMessage
+	CmdLoadMempoolRequestMessage
+	CmdLoadMempoolResponseMessage
+	CmdSetLogLevelRequestMessage
+	CmdSetLogLevelResponseMessage
+	CmdGetLogLevelsRequestMessage
+	CmdGetLogLevelsResponseMessage
+	CmdNotifyMempoolChangedRequestMessage
+	CmdNotifyMempoolChangedResponseMessage
+	CmdMempoolChangedNotificationMessage
+	CmdStopNotifyingMempoolChangedRequestMessage
+	CmdStopNotifyingMempoolChangedResponseMessage
+	CmdDebugTransactionScriptsRequestMessage
+	CmdDebugTransactionScriptsResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -255,6 +280,8 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdFinalityConflictResolvedNotificationMessage:                "FinalityConflictResolvedNotification",
 	CmdGetMempoolEntriesRequestMessage:                            "GetMempoolEntriesRequest",
 	CmdGetMempoolEntriesResponseMessage:                           "GetMempoolEntriesResponse",
+	CmdShutDownRequestMessage:                                     "ShutDownRequest",
+	CmdShutDownResponseMessage:                                    "ShutDownResponse",
 	CmdGetHeadersRequestMessage:                                   "GetHeadersRequest",
 	CmdGetHeadersResponseMessage:                                  "GetHeadersResponse",
 	CmdNotifyUTXOsChangedRequestMessage:                           "NotifyUTXOsChangedRequest",
@@ -292,6 +319,31 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdNotifyNewBlockTemplateRequestMessage:                       "NotifyNewBlockTemplateRequest",
 	CmdNotifyNewBlockTemplateResponseMessage:                      "NotifyNewBlockTemplateResponse",
 	CmdNewBlockTemplateNotificationMessage:                        "NewBlockTemplateNotification",
+	CmdGetTransactionRequestMessage:                               "GetTransactionRequest",
+	CmdGetTransactionResponseMessage:                              "GetTransactionResponse",
+	CmdGetTransactionAcceptanceRequestMessage:                     "GetTransactionAcceptanceRequest",
+	CmdGetTransactionAcceptanceResponseMessage:                    "GetTransactionAcceptanceResponse",
+	CmdGetAddressHistoryRequestMessage:                            "GetAddressHistoryRequest",
+	CmdGetAddressHistoryResponseMessage:                           "GetAddressHistoryResponse",
+	CmdGetFeeEstimateRequestMessage:                               "GetFeeEstimateRequest",
+	CmdGetFeeEstimateResponseMessage:                              "GetFeeEstimateResponse",
+	CmdSubmitTransactionReplacementRequestMessage:                 "SubmitTransactionReplacementRequest",
+	CmdSubmitTransactionReplacementResponseMessage:                "SubmitTransactionReplacementResponse",
+	CmdSaveMempoolRequestMessage:                                  "SaveMempoolRequest",
+	CmdSaveMempoolResponseMessage:                                 "SaveMempoolResponse",
+	CmdLoadMempoolRequestMessage:                                  "LoadMempoolRequest",
+	CmdLoadMempoolResponseMessage:                                 "LoadMempoolResponse",
+	CmdSetLogLevelRequestMessage:                                  "SetLogLevelRequest",
+	CmdSetLogLevelResponseMessage:                                 "SetLogLevelResponse",
+	CmdGetLogLevelsRequestMessage:                                 "GetLogLevelsRequest",
+	CmdGetLogLevelsResponseMessage:                                "GetLogLevelsResponse",
+	CmdNotifyMempoolChangedRequestMessage:                         "NotifyMempoolChangedRequest",
+	CmdNotifyMempoolChangedResponseMessage:                        "NotifyMempoolChangedResponse",
+	CmdMempoolChangedNotificationMessage:                          "MempoolChangedNotification",
+	CmdStopNotifyingMempoolChangedRequestMessage:                  "StopNotifyingMempoolChangedRequest",
+	CmdStopNotifyingMempoolChangedResponseMessage:                 "StopNotifyingMempoolChangedResponse",
+	CmdDebugTransactionScriptsRequestMessage:                      "DebugTransactionScriptsRequest",
+	CmdDebugTransactionScriptsResponseMessage:                     "DebugTransactionScriptsResponse",
 }
 
 // Message is an interface that describes a kaspa message. A type that
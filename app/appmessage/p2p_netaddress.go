@@ -5,9 +5,14 @@
 package appmessage
 
 import (
+	"bytes"
+	"encoding/base32"
 	"net"
+	"strconv"
+	"strings"
 
 	"github.com/kaspanet/kaspad/util/mstime"
+	"golang.org/x/crypto/sha3"
 )
 
 // NetAddress defines information about a peer on the network including the time
@@ -22,6 +27,15 @@ type NetAddress struct {
 	// Port the peer is using. This is encoded in big endian on the appmessage
 	// which differs from most everything else.
 	Port uint16
+
+	// OnionHost is the host name of the Tor onion service of the peer.
+	// It's set only for onion addresses, in which case IP is nil.
+	OnionHost string
+}
+
+// IsOnion returns whether the NetAddress is the address of a Tor onion service
+func (na *NetAddress) IsOnion() bool {
+	return na.OnionHost != ""
 }
 
 // TCPAddress converts the NetAddress to *net.TCPAddr
@@ -53,6 +67,16 @@ func NewNetAddressTimestamp(
 	return &na
 }
 
+// NewNetAddressOnionPort returns a new NetAddress using the provided onion service
+// host name and port
+func NewNetAddressOnionPort(onionHost string, port uint16) *NetAddress {
+	return &NetAddress{
+		Timestamp: mstime.Now(),
+		OnionHost: strings.ToLower(onionHost),
+		Port:      port,
+	}
+}
+
 // NewNetAddress returns a new NetAddress using the provided TCP address and
 // supported services with defaults for the remaining fields.
 func NewNetAddress(addr *net.TCPAddr) *NetAddress {
@@ -60,5 +84,49 @@ func NewNetAddress(addr *net.TCPAddr) *NetAddress {
 }
 
 func (na NetAddress) String() string {
+	if na.IsOnion() {
+		return net.JoinHostPort(na.OnionHost, strconv.Itoa(int(na.Port)))
+	}
 	return na.TCPAddress().String()
 }
+
+const (
+	onionHostSuffix     = ".onion"
+	onionVersion        = 3
+	onionPublicKeyLen   = 32
+	onionChecksumLen    = 2
+	onionChecksumPrefix = ".onion checksum"
+)
+
+var onionBase32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// IsOnionHost returns whether the given host name belongs to the .onion
+// domain. Such hosts must never be resolved or connected to directly.
+func IsOnionHost(host string) bool {
+	return strings.HasSuffix(strings.ToLower(host), onionHostSuffix)
+}
+
+// IsValidOnionHost returns whether the given host name is a well-formed
+// host name of a version 3 Tor onion service, including its checksum
+func IsValidOnionHost(host string) bool {
+	if !IsOnionHost(host) {
+		return false
+	}
+	encoded := strings.ToUpper(strings.TrimSuffix(strings.ToLower(host), onionHostSuffix))
+	decoded, err := onionBase32Encoding.DecodeString(encoded)
+	if err != nil || len(decoded) != onionPublicKeyLen+onionChecksumLen+1 {
+		return false
+	}
+	publicKey := decoded[:onionPublicKeyLen]
+	checksum := decoded[onionPublicKeyLen : onionPublicKeyLen+onionChecksumLen]
+	version := decoded[onionPublicKeyLen+onionChecksumLen]
+	if version != onionVersion {
+		return false
+	}
+
+	hasher := sha3.New256()
+	hasher.Write([]byte(onionChecksumPrefix))
+	hasher.Write(publicKey)
+	hasher.Write([]byte{version})
+	return bytes.Equal(hasher.Sum(nil)[:onionChecksumLen], checksum)
+}
@@ -6,6 +6,7 @@ package appmessage
 
 import (
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -26,3 +27,42 @@ func TestNetAddress(t *testing.T) {
 			port)
 	}
 }
+
+// TestOnionNetAddress tests the onion service support of NetAddress.
+func TestOnionNetAddress(t *testing.T) {
+	const onionHost = "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion"
+
+	na := NewNetAddressOnionPort(strings.ToUpper(onionHost), 16111)
+	if !na.IsOnion() {
+		t.Fatalf("IsOnion: expected an onion address")
+	}
+	if na.OnionHost != onionHost {
+		t.Errorf("NewNetAddressOnionPort: wrong onion host - got %s, want %s", na.OnionHost, onionHost)
+	}
+	if na.String() != onionHost+":16111" {
+		t.Errorf("String: got %s, want %s", na.String(), onionHost+":16111")
+	}
+
+	tests := []struct {
+		host    string
+		isOnion bool
+		isValid bool
+	}{
+		{host: onionHost, isOnion: true, isValid: true},
+		{host: strings.ToUpper(onionHost), isOnion: true, isValid: true},
+		// Wrong checksum
+		{host: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczaa.onion", isOnion: true, isValid: false},
+		// Version 2 onion services are obsolete
+		{host: "3g2upl4pq6kufc4m.onion", isOnion: true, isValid: false},
+		{host: "example.com", isOnion: false, isValid: false},
+		{host: "127.0.0.1", isOnion: false, isValid: false},
+	}
+	for _, test := range tests {
+		if IsOnionHost(test.host) != test.isOnion {
+			t.Errorf("IsOnionHost(%s): got %t, want %t", test.host, !test.isOnion, test.isOnion)
+		}
+		if IsValidOnionHost(test.host) != test.isValid {
+			t.Errorf("IsValidOnionHost(%s): got %t, want %t", test.host, !test.isValid, test.isValid)
+		}
+	}
+}
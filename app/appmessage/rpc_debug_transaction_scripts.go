@@ -0,0 +1,68 @@
+package appmessage
+
+// DebugTransactionScriptsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type DebugTransactionScriptsRequestMessage struct {
+	baseMessage
+	Transaction *RPCTransaction
+
+	// UTXOEntries are the entries spent by Transaction, one per input.
+	// If empty, kaspad looks the entries up by itself.
+	UTXOEntries []*RPCUTXOEntry
+}
+
+// Command returns the protocol command string for the message
+func (msg *DebugTransactionScriptsRequestMessage) Command() MessageCommand {
+	return CmdDebugTransactionScriptsRequestMessage
+}
+
+// NewDebugTransactionScriptsRequestMessage returns a instance of the message
+func NewDebugTransactionScriptsRequestMessage(transaction *RPCTransaction,
+	utxoEntries []*RPCUTXOEntry) *DebugTransactionScriptsRequestMessage {
+
+	return &DebugTransactionScriptsRequestMessage{
+		Transaction: transaction,
+		UTXOEntries: utxoEntries,
+	}
+}
+
+// RPCScriptInputTrace is the execution trace of the scripts of a single
+// transaction input
+type RPCScriptInputTrace struct {
+	InputIndex uint32
+	Scripts    []string
+	Steps      []*RPCScriptTraceStep
+	IsValid    bool
+	Error      string
+}
+
+// RPCScriptTraceStep is a single executed opcode within an RPCScriptInputTrace
+type RPCScriptTraceStep struct {
+	ScriptIndex  uint32
+	ScriptOffset uint32
+	Opcode       string
+	IsExecuted   bool
+	DataStack    []string
+	AltStack     []string
+}
+
+// DebugTransactionScriptsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type DebugTransactionScriptsResponseMessage struct {
+	baseMessage
+	InputTraces []*RPCScriptInputTrace
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *DebugTransactionScriptsResponseMessage) Command() MessageCommand {
+	return CmdDebugTransactionScriptsResponseMessage
+}
+
+// NewDebugTransactionScriptsResponseMessage returns a instance of the message
+func NewDebugTransactionScriptsResponseMessage(inputTraces []*RPCScriptInputTrace) *DebugTransactionScriptsResponseMessage {
+	return &DebugTransactionScriptsResponseMessage{
+		InputTraces: inputTraces,
+	}
+}
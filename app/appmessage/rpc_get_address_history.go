@@ -0,0 +1,61 @@
+package appmessage
+
+// GetAddressHistoryRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetAddressHistoryRequestMessage struct {
+	baseMessage
+	Address string
+	Limit   uint32
+	Cursor  string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetAddressHistoryRequestMessage) Command() MessageCommand {
+	return CmdGetAddressHistoryRequestMessage
+}
+
+// NewGetAddressHistoryRequestMessage returns a instance of the message
+func NewGetAddressHistoryRequestMessage(address string, limit uint32, cursor string) *GetAddressHistoryRequestMessage {
+	return &GetAddressHistoryRequestMessage{
+		Address: address,
+		Limit:   limit,
+		Cursor:  cursor,
+	}
+}
+
+// AddressHistoryEntry represents a single credit or debit of some address
+type AddressHistoryEntry struct {
+	Outpoint           *RPCOutpoint
+	TransactionID      string
+	Amount             uint64
+	IsDebit            bool
+	AcceptingBlockHash string
+	DAAScore           uint64
+}
+
+// GetAddressHistoryResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetAddressHistoryResponseMessage struct {
+	baseMessage
+	Address    string
+	Entries    []*AddressHistoryEntry
+	NextCursor string
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetAddressHistoryResponseMessage) Command() MessageCommand {
+	return CmdGetAddressHistoryResponseMessage
+}
+
+// NewGetAddressHistoryResponseMessage returns a instance of the message
+func NewGetAddressHistoryResponseMessage(address string, entries []*AddressHistoryEntry,
+	nextCursor string) *GetAddressHistoryResponseMessage {
+
+	return &GetAddressHistoryResponseMessage{
+		Address:    address,
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}
+}
@@ -4,7 +4,8 @@ package appmessage
 // its respective RPC message
 type GetBalanceByAddressRequestMessage struct {
 	baseMessage
-	Address string
+	Address            string
+	IncludeUnconfirmed bool
 }
 
 // Command returns the protocol command string for the message
@@ -13,9 +14,10 @@ func (msg *GetBalanceByAddressRequestMessage) Command() MessageCommand {
 }
 
 // NewGetBalanceByAddressRequest returns a instance of the message
-func NewGetBalanceByAddressRequest(address string) *GetBalanceByAddressRequestMessage {
+func NewGetBalanceByAddressRequest(address string, includeUnconfirmed bool) *GetBalanceByAddressRequestMessage {
 	return &GetBalanceByAddressRequestMessage{
-		Address: address,
+		Address:            address,
+		IncludeUnconfirmed: includeUnconfirmed,
 	}
 }
 
@@ -23,7 +25,9 @@ func NewGetBalanceByAddressRequest(address string) *GetBalanceByAddressRequestMe
 // its respective RPC message
 type GetBalanceByAddressResponseMessage struct {
 	baseMessage
-	Balance uint64
+	Balance             uint64
+	UnconfirmedIncoming uint64
+	UnconfirmedOutgoing uint64
 
 	Error *RPCError
 }
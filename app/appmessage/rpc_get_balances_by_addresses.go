@@ -4,7 +4,8 @@ package appmessage
 // its respective RPC message
 type GetBalancesByAddressesRequestMessage struct {
 	baseMessage
-	Addresses []string
+	Addresses          []string
+	IncludeUnconfirmed bool
 }
 
 // Command returns the protocol command string for the message
@@ -13,16 +14,19 @@ func (msg *GetBalancesByAddressesRequestMessage) Command() MessageCommand {
 }
 
 // NewGetBalancesByAddressesRequest returns a instance of the message
-func NewGetBalancesByAddressesRequest(addresses []string) *GetBalancesByAddressesRequestMessage {
+func NewGetBalancesByAddressesRequest(addresses []string, includeUnconfirmed bool) *GetBalancesByAddressesRequestMessage {
 	return &GetBalancesByAddressesRequestMessage{
-		Addresses: addresses,
+		Addresses:          addresses,
+		IncludeUnconfirmed: includeUnconfirmed,
 	}
 }
 
 // BalancesByAddressesEntry represents the balance of some address
 type BalancesByAddressesEntry struct {
-	Address string
-	Balance uint64
+	Address             string
+	Balance             uint64
+	UnconfirmedIncoming uint64
+	UnconfirmedOutgoing uint64
 }
 
 // GetBalancesByAddressesResponseMessage is an appmessage corresponding to
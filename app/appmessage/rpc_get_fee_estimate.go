@@ -0,0 +1,53 @@
+package appmessage
+
+// GetFeeEstimateRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetFeeEstimateRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetFeeEstimateRequestMessage) Command() MessageCommand {
+	return CmdGetFeeEstimateRequestMessage
+}
+
+// NewGetFeeEstimateRequestMessage returns a instance of the message
+func NewGetFeeEstimateRequestMessage() *GetFeeEstimateRequestMessage {
+	return &GetFeeEstimateRequestMessage{}
+}
+
+// RPCFeeRateBucket is a fee rate, in sompi per gram of mass, together with
+// the DAA score by which a transaction paying it is expected to be included
+type RPCFeeRateBucket struct {
+	FeeRate                    float64
+	EstimatedInclusionDAAScore uint64
+}
+
+// GetFeeEstimateResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetFeeEstimateResponseMessage struct {
+	baseMessage
+	PriorityBucket  *RPCFeeRateBucket
+	NormalBucket    *RPCFeeRateBucket
+	LowBucket       *RPCFeeRateBucket
+	VirtualDAAScore uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetFeeEstimateResponseMessage) Command() MessageCommand {
+	return CmdGetFeeEstimateResponseMessage
+}
+
+// NewGetFeeEstimateResponseMessage returns a instance of the message
+func NewGetFeeEstimateResponseMessage(priorityBucket, normalBucket, lowBucket *RPCFeeRateBucket,
+	virtualDAAScore uint64) *GetFeeEstimateResponseMessage {
+
+	return &GetFeeEstimateResponseMessage{
+		PriorityBucket:  priorityBucket,
+		NormalBucket:    normalBucket,
+		LowBucket:       lowBucket,
+		VirtualDAAScore: virtualDAAScore,
+	}
+}
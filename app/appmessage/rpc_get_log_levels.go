@@ -0,0 +1,44 @@
+package appmessage
+
+// GetLogLevelsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetLogLevelsRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetLogLevelsRequestMessage) Command() MessageCommand {
+	return CmdGetLogLevelsRequestMessage
+}
+
+// NewGetLogLevelsRequestMessage returns a instance of the message
+func NewGetLogLevelsRequestMessage() *GetLogLevelsRequestMessage {
+	return &GetLogLevelsRequestMessage{}
+}
+
+// SubsystemLogLevel is the logging level of a single subsystem
+type SubsystemLogLevel struct {
+	Subsystem string
+	Level     string
+}
+
+// GetLogLevelsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetLogLevelsResponseMessage struct {
+	baseMessage
+	LogLevels []*SubsystemLogLevel
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetLogLevelsResponseMessage) Command() MessageCommand {
+	return CmdGetLogLevelsResponseMessage
+}
+
+// NewGetLogLevelsResponseMessage returns a instance of the message
+func NewGetLogLevelsResponseMessage(logLevels []*SubsystemLogLevel) *GetLogLevelsResponseMessage {
+	return &GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+	}
+}
@@ -0,0 +1,56 @@
+package appmessage
+
+// GetTransactionRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionRequestMessage struct {
+	baseMessage
+	TransactionID                 string
+	IncludeTransactionVerboseData bool
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionRequestMessage) Command() MessageCommand {
+	return CmdGetTransactionRequestMessage
+}
+
+// NewGetTransactionRequestMessage returns a instance of the message
+func NewGetTransactionRequestMessage(transactionID string, includeTransactionVerboseData bool) *GetTransactionRequestMessage {
+	return &GetTransactionRequestMessage{
+		TransactionID:                 transactionID,
+		IncludeTransactionVerboseData: includeTransactionVerboseData,
+	}
+}
+
+// GetTransactionResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionResponseMessage struct {
+	baseMessage
+	Transaction             *RPCTransaction
+	IncludingBlockHashes    []string
+	AcceptingBlockHash      string
+	AcceptingBlockBlueScore uint64
+	Confirmations           uint64
+	IsInMempool             bool
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionResponseMessage) Command() MessageCommand {
+	return CmdGetTransactionResponseMessage
+}
+
+// NewGetTransactionResponseMessage returns a instance of the message
+func NewGetTransactionResponseMessage(transaction *RPCTransaction, includingBlockHashes []string,
+	acceptingBlockHash string, acceptingBlockBlueScore uint64, confirmations uint64,
+	isInMempool bool) *GetTransactionResponseMessage {
+
+	return &GetTransactionResponseMessage{
+		Transaction:             transaction,
+		IncludingBlockHashes:    includingBlockHashes,
+		AcceptingBlockHash:      acceptingBlockHash,
+		AcceptingBlockBlueScore: acceptingBlockBlueScore,
+		Confirmations:           confirmations,
+		IsInMempool:             isInMempool,
+	}
+}
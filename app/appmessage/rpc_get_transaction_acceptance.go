@@ -0,0 +1,51 @@
+package appmessage
+
+// GetTransactionAcceptanceRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionAcceptanceRequestMessage struct {
+	baseMessage
+	TransactionIDs []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionAcceptanceRequestMessage) Command() MessageCommand {
+	return CmdGetTransactionAcceptanceRequestMessage
+}
+
+// NewGetTransactionAcceptanceRequestMessage returns a instance of the message
+func NewGetTransactionAcceptanceRequestMessage(transactionIDs []string) *GetTransactionAcceptanceRequestMessage {
+	return &GetTransactionAcceptanceRequestMessage{
+		TransactionIDs: transactionIDs,
+	}
+}
+
+// TransactionAcceptanceEntry represents the acceptance status of some transaction
+type TransactionAcceptanceEntry struct {
+	TransactionID           string
+	IsAccepted              bool
+	AcceptingBlockHash      string
+	IncludingBlockHash      string
+	AcceptingBlockBlueScore uint64
+	Confirmations           uint64
+}
+
+// GetTransactionAcceptanceResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionAcceptanceResponseMessage struct {
+	baseMessage
+	Entries []*TransactionAcceptanceEntry
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionAcceptanceResponseMessage) Command() MessageCommand {
+	return CmdGetTransactionAcceptanceResponseMessage
+}
+
+// NewGetTransactionAcceptanceResponseMessage returns a instance of the message
+func NewGetTransactionAcceptanceResponseMessage(entries []*TransactionAcceptanceEntry) *GetTransactionAcceptanceResponseMessage {
+	return &GetTransactionAcceptanceResponseMessage{
+		Entries: entries,
+	}
+}
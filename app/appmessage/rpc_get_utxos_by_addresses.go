@@ -4,7 +4,8 @@ package appmessage
 // its respective RPC message
 type GetUTXOsByAddressesRequestMessage struct {
 	baseMessage
-	Addresses []string
+	Addresses          []string
+	IncludeUnconfirmed bool
 }
 
 // Command returns the protocol command string for the message
@@ -13,9 +14,10 @@ func (msg *GetUTXOsByAddressesRequestMessage) Command() MessageCommand {
 }
 
 // NewGetUTXOsByAddressesRequestMessage returns a instance of the message
-func NewGetUTXOsByAddressesRequestMessage(addresses []string) *GetUTXOsByAddressesRequestMessage {
+func NewGetUTXOsByAddressesRequestMessage(addresses []string, includeUnconfirmed bool) *GetUTXOsByAddressesRequestMessage {
 	return &GetUTXOsByAddressesRequestMessage{
-		Addresses: addresses,
+		Addresses:          addresses,
+		IncludeUnconfirmed: includeUnconfirmed,
 	}
 }
 
@@ -0,0 +1,42 @@
+package appmessage
+
+// LoadMempoolRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type LoadMempoolRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *LoadMempoolRequestMessage) Command() MessageCommand {
+	return CmdLoadMempoolRequestMessage
+}
+
+// NewLoadMempoolRequestMessage returns a instance of the message
+func NewLoadMempoolRequestMessage() *LoadMempoolRequestMessage {
+	return &LoadMempoolRequestMessage{}
+}
+
+// LoadMempoolResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type LoadMempoolResponseMessage struct {
+	baseMessage
+	AcceptedTransactionCount uint64
+	RejectedTransactionCount uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *LoadMempoolResponseMessage) Command() MessageCommand {
+	return CmdLoadMempoolResponseMessage
+}
+
+// NewLoadMempoolResponseMessage returns a instance of the message
+func NewLoadMempoolResponseMessage(acceptedTransactionCount uint64,
+	rejectedTransactionCount uint64) *LoadMempoolResponseMessage {
+
+	return &LoadMempoolResponseMessage{
+		AcceptedTransactionCount: acceptedTransactionCount,
+		RejectedTransactionCount: rejectedTransactionCount,
+	}
+}
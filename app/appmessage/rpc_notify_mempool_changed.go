@@ -0,0 +1,69 @@
+package appmessage
+
+// NotifyMempoolChangedRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolChangedRequestMessage struct {
+	baseMessage
+	Addresses        []string
+	ScriptPublicKeys []*RPCScriptPublicKey
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolChangedRequestMessage) Command() MessageCommand {
+	return CmdNotifyMempoolChangedRequestMessage
+}
+
+// NewNotifyMempoolChangedRequestMessage returns a instance of the message
+func NewNotifyMempoolChangedRequestMessage(addresses []string,
+	scriptPublicKeys []*RPCScriptPublicKey) *NotifyMempoolChangedRequestMessage {
+
+	return &NotifyMempoolChangedRequestMessage{
+		Addresses:        addresses,
+		ScriptPublicKeys: scriptPublicKeys,
+	}
+}
+
+// NotifyMempoolChangedResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolChangedResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolChangedResponseMessage) Command() MessageCommand {
+	return CmdNotifyMempoolChangedResponseMessage
+}
+
+// NewNotifyMempoolChangedResponseMessage returns a instance of the message
+func NewNotifyMempoolChangedResponseMessage() *NotifyMempoolChangedResponseMessage {
+	return &NotifyMempoolChangedResponseMessage{}
+}
+
+// MempoolChangedNotificationMessage is an appmessage corresponding to
+// its respective RPC message
+type MempoolChangedNotificationMessage struct {
+	baseMessage
+	Changes []*MempoolChange
+}
+
+// MempoolChange represents a single transaction that was added to, removed
+// from, or promoted out of the orphan pool of the mempool
+type MempoolChange struct {
+	Type          string
+	Transaction   *RPCTransaction
+	IsOrphan      bool
+	RemovalReason string
+}
+
+// Command returns the protocol command string for the message
+func (msg *MempoolChangedNotificationMessage) Command() MessageCommand {
+	return CmdMempoolChangedNotificationMessage
+}
+
+// NewMempoolChangedNotificationMessage returns a instance of the message
+func NewMempoolChangedNotificationMessage(changes []*MempoolChange) *MempoolChangedNotificationMessage {
+	return &MempoolChangedNotificationMessage{
+		Changes: changes,
+	}
+}
@@ -49,6 +49,11 @@ type UTXOsByAddressesEntry struct {
 	Address   string
 	Outpoint  *RPCOutpoint
 	UTXOEntry *RPCUTXOEntry
+
+	// IsUnconfirmed and IsSpentByUnconfirmed are set only by GetUTXOsByAddresses
+	// requests that include unconfirmed UTXOs
+	IsUnconfirmed        bool
+	IsSpentByUnconfirmed bool
 }
 
 // Command returns the protocol command string for the message
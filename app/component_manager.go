@@ -2,32 +2,45 @@ package app
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
 
+	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/protocol"
 	"github.com/kaspanet/kaspad/app/rpc"
+	"github.com/kaspanet/kaspad/app/snapshot"
 	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/addresshistoryindex"
 	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/txindex"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	infrastructuredatabase "github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
 	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
 	"github.com/kaspanet/kaspad/infrastructure/network/connmanager"
+	"github.com/kaspanet/kaspad/infrastructure/network/nat"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/id"
 	"github.com/kaspanet/kaspad/util/panics"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // ComponentManager is a wrapper for all the kaspad services
 type ComponentManager struct {
 	cfg               *config.Config
+	domain            domain.Domain
 	addressManager    *addressmanager.AddressManager
 	protocolManager   *protocol.Manager
 	rpcManager        *rpc.Manager
 	connectionManager *connmanager.ConnectionManager
 	netAdapter        *netadapter.NetAdapter
+	portMapper        *nat.PortMapper
+	metricsServer     *metrics.Server
+	nodeMetrics       []prometheus.Collector
 
 	started, shutdown int32
 }
@@ -41,12 +54,26 @@ func (a *ComponentManager) Start() {
 
 	log.Trace("Starting kaspad")
 
+	a.loadMempool()
+
 	err := a.netAdapter.Start()
 	if err != nil {
 		panics.Exit(log, fmt.Sprintf("Error starting the net adapter: %+v", err))
 	}
 
 	a.connectionManager.Start()
+
+	if a.portMapper != nil {
+		a.portMapper.Start()
+	}
+
+	if a.metricsServer != nil {
+		err := metrics.Register(a.nodeMetrics...)
+		if err != nil {
+			panics.Exit(log, fmt.Sprintf("Error registering the node metrics: %+v", err))
+		}
+		a.metricsServer.Start()
+	}
 }
 
 // Stop gracefully shuts down all the kaspad services.
@@ -59,6 +86,18 @@ func (a *ComponentManager) Stop() {
 
 	log.Warnf("Kaspad shutting down")
 
+	if a.metricsServer != nil {
+		err := a.metricsServer.Stop()
+		if err != nil {
+			log.Errorf("Error stopping the metrics server: %+v", err)
+		}
+		metrics.Unregister(a.nodeMetrics...)
+	}
+
+	if a.portMapper != nil {
+		a.portMapper.Stop()
+	}
+
 	a.connectionManager.Stop()
 
 	err := a.netAdapter.Stop()
@@ -68,14 +107,43 @@ func (a *ComponentManager) Stop() {
 
 	a.protocolManager.Close()
 
+	a.saveMempool()
+
 	return
 }
 
-// NewComponentManager returns a new ComponentManager instance.
-// Use Start() to begin all services within this ComponentManager
-func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database, interrupt chan<- struct{}) (
-	*ComponentManager, error) {
+func (a *ComponentManager) loadMempool() {
+	if a.cfg.NoPersistMempool {
+		return
+	}
 
+	mempoolFilePath := a.cfg.MempoolFilePath()
+	acceptedTransactions, rejectedTransactionCount, err := a.domain.MiningManager().LoadMempool(mempoolFilePath)
+	if err != nil {
+		log.Errorf("Error loading the mempool from %s: %+v", mempoolFilePath, err)
+		return
+	}
+	if len(acceptedTransactions) > 0 || rejectedTransactionCount > 0 {
+		log.Infof("Loaded %d transactions from %s (%d dropped as no longer valid)",
+			len(acceptedTransactions), mempoolFilePath, rejectedTransactionCount)
+	}
+}
+
+func (a *ComponentManager) saveMempool() {
+	if a.cfg.NoPersistMempool {
+		return
+	}
+
+	mempoolFilePath := a.cfg.MempoolFilePath()
+	savedTransactionCount, err := a.domain.MiningManager().SaveMempool(mempoolFilePath)
+	if err != nil {
+		log.Errorf("Error saving the mempool to %s: %+v", mempoolFilePath, err)
+		return
+	}
+	log.Infof("Saved %d mempool transactions to %s", savedTransactionCount, mempoolFilePath)
+}
+
+func newDomain(cfg *config.Config, db infrastructuredatabase.Database) (domain.Domain, error) {
 	consensusConfig := consensus.Config{
 		Params:                          *cfg.ActiveNetParams,
 		IsArchival:                      cfg.IsArchivalNode,
@@ -85,11 +153,28 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	mempoolConfig.MaximumOrphanTransactionCount = cfg.MaxOrphanTxs
 	mempoolConfig.MinimumRelayTransactionFee = cfg.MinRelayTxFee
 
-	domain, err := domain.New(&consensusConfig, mempoolConfig, db)
+	return domain.New(&consensusConfig, mempoolConfig, db)
+}
+
+// NewComponentManager returns a new ComponentManager instance.
+// Use Start() to begin all services within this ComponentManager
+func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database, interrupt chan<- struct{}) (
+	*ComponentManager, error) {
+
+	domain, err := newDomain(cfg, db)
 	if err != nil {
 		return nil, err
 	}
 
+	// The snapshot is imported before the indexes are created, so that they
+	// notice the new virtual and reset themselves
+	if cfg.ImportSnapshot != "" {
+		err = snapshot.Import(domain, cfg.ActiveNetParams, cfg.ImportSnapshot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	netAdapter, err := netadapter.NewNetAdapter(cfg)
 	if err != nil {
 		return nil, err
@@ -110,6 +195,31 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 		log.Infof("UTXO index started")
 	}
 
+	var txIndex *txindex.TXIndex
+	if cfg.TXIndex {
+		txIndex, err = txindex.New(domain, db)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("TX index started")
+	}
+
+	var addressHistoryIndex *addresshistoryindex.AddressHistoryIndex
+	if cfg.AddressHistoryIndex {
+		addressHistoryIndex, err = addresshistoryindex.New(domain, db)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("Address history index started")
+	}
+
+	portMapper, err := newPortMapper(cfg, addressManager)
+	if err != nil {
+		return nil, err
+	}
+
 	connectionManager, err := connmanager.New(cfg, netAdapter, addressManager)
 	if err != nil {
 		return nil, err
@@ -118,19 +228,67 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	if err != nil {
 		return nil, err
 	}
-	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, interrupt)
+	var metricsServer *metrics.Server
+	if cfg.Metrics != "" {
+		metricsServer, err = metrics.NewServer(cfg.Metrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rpcManager, err := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex,
+		txIndex, addressHistoryIndex, interrupt)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ComponentManager{
 		cfg:               cfg,
+		domain:            domain,
 		protocolManager:   protocolManager,
 		rpcManager:        rpcManager,
 		connectionManager: connectionManager,
 		netAdapter:        netAdapter,
+		portMapper:        portMapper,
+		metricsServer:     metricsServer,
+		nodeMetrics:       nodeMetrics(domain, netAdapter, db),
 		addressManager:    addressManager,
 	}, nil
 
 }
 
+// newPortMapper returns a PortMapper that maps the P2P listen port on the NAT gateway
+// and advertises the resulting external address, or nil if port mapping is disabled.
+// Explicitly specified external IPs take precedence over port mapping
+func newPortMapper(cfg *config.Config, addressManager *addressmanager.AddressManager) (*nat.PortMapper, error) {
+	if !cfg.Upnp || cfg.DisableListen || len(cfg.ExternalIPs) > 0 || len(cfg.Listeners) == 0 {
+		return nil, nil
+	}
+
+	// Only the port of the first listener is mapped, as a single
+	// external address is advertised anyway
+	_, portString, err := net.SplitHostPort(cfg.Listeners[0])
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	return nat.NewPortMapper(uint16(port), func(previous, current *net.TCPAddr) {
+		if previous != nil {
+			addressManager.RemoveLocalAddress(appmessage.NewNetAddress(previous))
+		}
+		if current != nil {
+			err := addressManager.AddLocalAddress(appmessage.NewNetAddress(current), addressmanager.UpnpPrio)
+			if err != nil {
+				log.Warnf("Not advertising the external address %s: %s", current, err)
+			}
+		}
+	}), nil
+}
+
 func setupRPC(
 	cfg *config.Config,
 	domain domain.Domain,
@@ -139,10 +297,12 @@ func setupRPC(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
 	shutDownChan chan<- struct{},
-) *rpc.Manager {
+) (*rpc.Manager, error) {
 
-	rpcManager := rpc.NewManager(
+	rpcManager, err := rpc.NewManager(
 		cfg,
 		domain,
 		netAdapter,
@@ -150,14 +310,20 @@ func setupRPC(
 		connectionManager,
 		addressManager,
 		utxoIndex,
+		txIndex,
+		addressHistoryIndex,
 		shutDownChan,
 	)
+	if err != nil {
+		return nil, err
+	}
 	protocolManager.SetOnVirtualChange(rpcManager.NotifyVirtualChange)
 	protocolManager.SetOnNewBlockTemplateHandler(rpcManager.NotifyNewBlockTemplate)
 	protocolManager.SetOnBlockAddedToDAGHandler(rpcManager.NotifyBlockAddedToDAG)
 	protocolManager.SetOnPruningPointUTXOSetOverrideHandler(rpcManager.NotifyPruningPointUTXOSetOverride)
+	domain.MiningManager().SetOnMempoolChangedHandler(rpcManager.NotifyMempoolChanged)
 
-	return rpcManager
+	return rpcManager, nil
 }
 
 // P2PNodeID returns the network ID associated with this ComponentManager
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsProvider is implemented by components that export their own metrics, such as the database
+type metricsProvider interface {
+	Metrics() []prometheus.Collector
+}
+
+// nodeMetrics returns the metrics that are read from the node's components. Unlike metrics
+// that are defined as package variables, these are only registered when the metrics server
+// is enabled, since they're bound to this specific instance of the node
+func nodeMetrics(domain domain.Domain, netAdapter *netadapter.NetAdapter, db interface{}) []prometheus.Collector {
+	virtualInfo := func() *externalapi.VirtualInfo {
+		virtualInfo, err := domain.Consensus().GetVirtualInfo()
+		if err != nil {
+			log.Debugf("Error getting the virtual info for metrics: %s", err)
+			return &externalapi.VirtualInfo{}
+		}
+		return virtualInfo
+	}
+	mempoolStats := func() *model.MempoolStats {
+		return domain.MiningManager().MempoolStats()
+	}
+
+	nodeMetrics := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_virtual_daa_score",
+			Help: "DAA score of the virtual block",
+		}, func() float64 { return float64(virtualInfo().DAAScore) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_virtual_blue_score",
+			Help: "Blue score of the virtual block",
+		}, func() float64 { return float64(virtualInfo().BlueScore) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_mempool_transactions",
+			Help: "Number of transactions in the mempool",
+		}, func() float64 { return float64(mempoolStats().TransactionCount) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_mempool_mass",
+			Help: "Total mass of the transactions in the mempool",
+		}, func() float64 { return float64(mempoolStats().TotalMass) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_mempool_orphans",
+			Help: "Number of orphan transactions in the mempool",
+		}, func() float64 { return float64(mempoolStats().OrphanCount) }),
+		metrics.NewGaugeVecFunc("kaspad_peers", "Number of connected peers, by connection direction",
+			func() []metrics.LabeledValue {
+				return peersByDirection(netAdapter, func(*netadapter.NetConnection) uint64 { return 1 })
+			}, "direction"),
+		// The traffic is aggregated by direction rather than exported per peer, since peer
+		// addresses come and go, and would make the amount of exported series unbounded.
+		// These are gauges, since they go down whenever a peer disconnects
+		metrics.NewGaugeVecFunc("kaspad_peers_sent_bytes",
+			"Number of bytes sent to the connected peers, by connection direction",
+			func() []metrics.LabeledValue {
+				return peersByDirection(netAdapter, (*netadapter.NetConnection).BytesSent)
+			}, "direction"),
+		metrics.NewGaugeVecFunc("kaspad_peers_received_bytes",
+			"Number of bytes received from the connected peers, by connection direction",
+			func() []metrics.LabeledValue {
+				return peersByDirection(netAdapter, (*netadapter.NetConnection).BytesReceived)
+			}, "direction"),
+	}
+
+	if provider, ok := db.(metricsProvider); ok {
+		nodeMetrics = append(nodeMetrics, provider.Metrics()...)
+	}
+	return nodeMetrics
+}
+
+// peersByDirection sums the given value over the connected peers, separately for
+// inbound and outbound connections
+func peersByDirection(netAdapter *netadapter.NetAdapter,
+	value func(connection *netadapter.NetConnection) uint64) []metrics.LabeledValue {
+
+	inbound, outbound := uint64(0), uint64(0)
+	for _, connection := range netAdapter.P2PConnections() {
+		if connection.IsOutbound() {
+			outbound += value(connection)
+		} else {
+			inbound += value(connection)
+		}
+	}
+	return []metrics.LabeledValue{
+		{LabelValues: []string{"inbound"}, Value: float64(inbound)},
+		{LabelValues: []string{"outbound"}, Value: float64(outbound)},
+	}
+}
@@ -22,6 +22,42 @@ func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction, allowOrp
 	return f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
 }
 
+// AddTransactionReplacement adds transaction to the mempool instead of the transactions
+// it double spends and propagates it. It returns the replaced transactions.
+func (f *FlowContext) AddTransactionReplacement(tx *externalapi.DomainTransaction) (
+	replacedTransactions []*externalapi.DomainTransaction, err error) {
+
+	acceptedTransactions, replacedTransactions, err := f.Domain().MiningManager().ValidateAndReplaceTransaction(tx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	err = f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return replacedTransactions, nil
+}
+
+// LoadMempool loads the transactions saved in the given mempool file into the mempool
+// and propagates the ones that were accepted
+func (f *FlowContext) LoadMempool(filePath string) (
+	acceptedTransactions []*externalapi.DomainTransaction, rejectedTransactionCount int, err error) {
+
+	acceptedTransactions, rejectedTransactionCount, err = f.Domain().MiningManager().LoadMempool(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	acceptedTransactionIDs := consensushashing.TransactionIDs(acceptedTransactions)
+	err = f.EnqueueTransactionIDsForPropagation(acceptedTransactionIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return acceptedTransactions, rejectedTransactionCount, nil
+}
+
 func (f *FlowContext) shouldRebroadcastTransactions() bool {
 	const rebroadcastInterval = 30 * time.Second
 	return time.Since(f.lastRebroadcastTime) > rebroadcastInterval
@@ -0,0 +1,21 @@
+package blockrelay
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	ibdProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaspad_ibd_progress_ratio",
+		Help: "Progress of the current or last IBD stage, between 0 and 1, by the kind of object it syncs",
+	}, []string{"object"})
+	ibdProcessed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaspad_ibd_processed",
+		Help: "Number of objects processed by the current or last IBD stage, by their kind",
+	}, []string{"object"})
+)
+
+func init() {
+	metrics.MustRegister(ibdProgress, ibdProcessed)
+}
@@ -159,6 +159,21 @@ func (flow *handleRelayedTransactionsFlow) readMsgTxOrNotFound() (
 	}
 }
 
+// validateAndInsertOrReplaceTransaction inserts the given transaction into the mempool. If it
+// double spends mempool transactions, it's inserted instead of them under the rules of
+// ValidateAndReplaceTransaction, so that replacements propagate like any other transaction
+func (flow *handleRelayedTransactionsFlow) validateAndInsertOrReplaceTransaction(tx *externalapi.DomainTransaction) (
+	acceptedTransactions []*externalapi.DomainTransaction, isReplacement bool, err error) {
+
+	acceptedTransactions, err = flow.Domain().MiningManager().ValidateAndInsertTransaction(tx, false, true)
+	if err == nil || !mempool.IsDoubleSpendError(err) {
+		return acceptedTransactions, false, err
+	}
+
+	acceptedTransactions, _, err = flow.Domain().MiningManager().ValidateAndReplaceTransaction(tx, false)
+	return acceptedTransactions, true, err
+}
+
 func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransactions []*externalapi.DomainTransactionID) error {
 	// In case the function returns earlier than expected, we want to make sure sharedRequestedTransactions is
 	// clean from any pending transactions.
@@ -183,17 +198,18 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 				expectedID, txID)
 		}
 
-		acceptedTransactions, err :=
-			flow.Domain().MiningManager().ValidateAndInsertTransaction(tx, false, true)
+		acceptedTransactions, isReplacement, err := flow.validateAndInsertOrReplaceTransaction(tx)
 		if err != nil {
 			ruleErr := &mempool.RuleError{}
 			if !errors.As(err, ruleErr) {
 				return errors.Wrapf(err, "failed to process transaction %s", txID)
 			}
 
+			// The replacement rules depend on the state of the local mempool, which may
+			// differ from the mempool of the peer, so breaking them isn't a reason to ban it
 			shouldBan := false
 			if txRuleErr := (&mempool.TxRuleError{}); errors.As(ruleErr.Err, txRuleErr) {
-				if txRuleErr.RejectCode == mempool.RejectInvalid {
+				if txRuleErr.RejectCode == mempool.RejectInvalid && !isReplacement {
 					shouldBan = true
 				}
 			}
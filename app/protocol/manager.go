@@ -67,6 +67,22 @@ func (m *Manager) AddTransaction(tx *externalapi.DomainTransaction, allowOrphan
 	return m.context.AddTransaction(tx, allowOrphan)
 }
 
+// AddTransactionReplacement adds transaction to the mempool instead of the transactions
+// it double spends and propagates it. It returns the replaced transactions.
+func (m *Manager) AddTransactionReplacement(tx *externalapi.DomainTransaction) (
+	replacedTransactions []*externalapi.DomainTransaction, err error) {
+
+	return m.context.AddTransactionReplacement(tx)
+}
+
+// LoadMempool loads the transactions saved in the given mempool file into the mempool
+// and propagates the ones that were accepted
+func (m *Manager) LoadMempool(filePath string) (
+	acceptedTransactions []*externalapi.DomainTransaction, rejectedTransactionCount int, err error) {
+
+	return m.context.LoadMempool(filePath)
+}
+
 // AddBlock adds the given block to the DAG and propagates it.
 func (m *Manager) AddBlock(block *externalapi.DomainBlock) error {
 	return m.context.AddBlock(block)
@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+// errorResponseBuilders map every RPC command to a function that builds its response with the
+// given error. They're used to respond to requests that are rejected before reaching their handler
+var errorResponseBuilders = map[appmessage.MessageCommand]func(rpcError *appmessage.RPCError) appmessage.Message{
+	appmessage.CmdGetCurrentNetworkRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetCurrentNetworkResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSubmitBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SubmitBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockTemplateRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockTemplateResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyBlockAddedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyBlockAddedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetPeerAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetPeerAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetSelectedTipHashRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetSelectedTipHashResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetMempoolEntryRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetMempoolEntryResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetConnectedPeerInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetConnectedPeerInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdAddPeerRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.AddPeerResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSubmitTransactionRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SubmitTransactionResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualSelectedParentChainChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualSelectedParentChainChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetSubnetworkRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetSubnetworkResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetVirtualSelectedParentChainFromBlockRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetVirtualSelectedParentChainFromBlockResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlocksRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlocksResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockCountRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockCountResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBalanceByAddressRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBalanceByAddressResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBlockDAGInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBlockDAGInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdResolveFinalityConflictRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.ResolveFinalityConflictResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyFinalityConflictsRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyFinalityConflictsResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetMempoolEntriesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetMempoolEntriesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdShutDownRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.ShutDownResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetHeadersRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetHeadersResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyUTXOsChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyUTXOsChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdStopNotifyingUTXOsChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.StopNotifyingUTXOsChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetUTXOsByAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetUTXOsByAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetBalancesByAddressesRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetBalancesByAddressesResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetVirtualSelectedParentBlueScoreRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetVirtualSelectedParentBlueScoreResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualSelectedParentBlueScoreChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualSelectedParentBlueScoreChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdBanRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.BanResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdUnbanRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.UnbanResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetInfoRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetInfoResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyPruningPointUTXOSetOverrideRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyPruningPointUTXOSetOverrideResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.StopNotifyingPruningPointUTXOSetOverrideResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdEstimateNetworkHashesPerSecondRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.EstimateNetworkHashesPerSecondResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyVirtualDaaScoreChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyVirtualDaaScoreChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyNewBlockTemplateRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyNewBlockTemplateResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetTransactionRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetTransactionResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetTransactionAcceptanceRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetTransactionAcceptanceResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetAddressHistoryRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetAddressHistoryResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetFeeEstimateRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetFeeEstimateResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSubmitTransactionReplacementRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SubmitTransactionReplacementResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSaveMempoolRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SaveMempoolResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdLoadMempoolRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.LoadMempoolResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdSetLogLevelRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.SetLogLevelResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdGetLogLevelsRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.GetLogLevelsResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdNotifyMempoolChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.NotifyMempoolChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdStopNotifyingMempoolChangedRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.StopNotifyingMempoolChangedResponseMessage{Error: rpcError}
+	},
+	appmessage.CmdDebugTransactionScriptsRequestMessage: func(rpcError *appmessage.RPCError) appmessage.Message {
+		return &appmessage.DebugTransactionScriptsResponseMessage{Error: rpcError}
+	},
+}
+
+func newErrorResponse(command appmessage.MessageCommand, rpcError *appmessage.RPCError) appmessage.Message {
+	return errorResponseBuilders[command](rpcError)
+}
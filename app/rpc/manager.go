@@ -5,7 +5,10 @@ import (
 	"github.com/kaspanet/kaspad/app/protocol"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/addresshistoryindex"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/domain/txindex"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
@@ -17,6 +20,12 @@ import (
 // Manager is an RPC manager
 type Manager struct {
 	context *rpccontext.Context
+
+	rateLimiter *rateLimiter // nil if rate limiting is disabled
+
+	// requestSemaphore limits the amount of requests that are handled
+	// concurrently. It's nil if the amount isn't limited
+	requestSemaphore chan struct{}
 }
 
 // NewManager creates a new RPC Manager
@@ -28,9 +37,22 @@ func NewManager(
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
-	shutDownChan chan<- struct{}) *Manager {
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
+	shutDownChan chan<- struct{}) (*Manager, error) {
+
+	rateLimiter, err := newRateLimiter(cfg.RPCRateLimit, cfg.RPCRateBurst, cfg.RPCCommandCostOverrides)
+	if err != nil {
+		return nil, err
+	}
+	var requestSemaphore chan struct{}
+	if cfg.RPCMaxConcurrentReqs > 0 {
+		requestSemaphore = make(chan struct{}, cfg.RPCMaxConcurrentReqs)
+	}
 
 	manager := Manager{
+		rateLimiter:      rateLimiter,
+		requestSemaphore: requestSemaphore,
 		context: rpccontext.NewContext(
 			cfg,
 			domain,
@@ -39,12 +61,14 @@ func NewManager(
 			connectionManager,
 			addressManager,
 			utxoIndex,
+			txIndex,
+			addressHistoryIndex,
 			shutDownChan,
 		),
 	}
 	netAdapter.SetRPCRouterInitializer(manager.routerInitializer)
 
-	return &manager
+	return &manager, nil
 }
 
 // NotifyBlockAddedToDAG notifies the manager that a block has been added to the DAG
@@ -78,6 +102,20 @@ func (m *Manager) NotifyVirtualChange(virtualChangeSet *externalapi.VirtualChang
 		}
 	}
 
+	if m.context.Config.TXIndex {
+		err := m.updateTXIndex(virtualChangeSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressHistoryIndex {
+		err := m.updateAddressHistoryIndex(virtualChangeSet)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := m.notifyVirtualSelectedParentBlueScoreChanged()
 	if err != nil {
 		return err
@@ -116,6 +154,20 @@ func (m *Manager) NotifyPruningPointUTXOSetOverride() error {
 		}
 	}
 
+	if m.context.Config.TXIndex {
+		err := m.context.TXIndex.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.context.Config.AddressHistoryIndex {
+		err := m.context.AddressHistoryIndex.Reset()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -137,6 +189,17 @@ func (m *Manager) NotifyFinalityConflictResolved(finalityBlockHash string) error
 	return m.context.NotificationManager.NotifyFinalityConflictResolved(notification)
 }
 
+// NotifyMempoolChanged notifies the manager that the contents of the mempool have changed
+func (m *Manager) NotifyMempoolChanged(changes []*miningmanagermodel.MempoolChange) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyMempoolChanged")
+	defer onEnd()
+
+	err := m.context.NotificationManager.NotifyMempoolChanged(changes, m.context.ConvertMempoolChangeToRPCMempoolChange)
+	if err != nil {
+		log.Errorf("Couldn't send mempool changed notifications: %+v", err)
+	}
+}
+
 func (m *Manager) notifyUTXOsChanged(virtualChangeSet *externalapi.VirtualChangeSet) error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyUTXOsChanged")
 	defer onEnd()
@@ -148,6 +211,20 @@ func (m *Manager) notifyUTXOsChanged(virtualChangeSet *externalapi.VirtualChange
 	return m.context.NotificationManager.NotifyUTXOsChanged(utxoIndexChanges)
 }
 
+func (m *Manager) updateTXIndex(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.updateTXIndex")
+	defer onEnd()
+
+	return m.context.TXIndex.Update(virtualChangeSet)
+}
+
+func (m *Manager) updateAddressHistoryIndex(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.updateAddressHistoryIndex")
+	defer onEnd()
+
+	return m.context.AddressHistoryIndex.Update(virtualChangeSet)
+}
+
 func (m *Manager) notifyPruningPointUTXOSetOverride() error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.notifyPruningPointUTXOSetOverride")
 	defer onEnd()
@@ -195,8 +272,13 @@ func (m *Manager) notifyVirtualSelectedParentChainChanged(virtualChangeSet *exte
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyVirtualSelectedParentChainChanged")
 	defer onEnd()
 
+	hasListeners, includeAcceptedTransactionIDs := m.context.NotificationManager.HasListenersThatPropagateVirtualSelectedParentChainChanged()
+	if !hasListeners {
+		return nil
+	}
+
 	notification, err := m.context.ConvertVirtualSelectedParentChainChangesToChainChangedNotificationMessage(
-		virtualChangeSet.VirtualSelectedParentChainChanges)
+		virtualChangeSet.VirtualSelectedParentChainChanges, includeAcceptedTransactionIDs)
 	if err != nil {
 		return err
 	}
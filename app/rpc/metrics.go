@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kaspad_rpc_requests_total",
+		Help: "Number of RPC requests that were handled, by command",
+	}, []string{"command"})
+	rpcRequestsDenied = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kaspad_rpc_requests_denied_total",
+		Help: "Number of RPC requests that were denied for lack of permissions, by command",
+	}, []string{"command"})
+	rpcRequestsThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kaspad_rpc_requests_throttled_total",
+		Help: "Number of RPC requests that were rejected by rate limiting, by command",
+	}, []string{"command"})
+	rpcRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kaspad_rpc_request_duration_seconds",
+		Help:    "Time it took to handle RPC requests, by command",
+		Buckets: metrics.DefaultDurationBuckets,
+	}, []string{"command"})
+)
+
+func init() {
+	metrics.MustRegister(rpcRequests, rpcRequestsDenied, rpcRequestsThrottled, rpcRequestDuration)
+}
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+// defaultCommandCost is the rate limiting cost of commands that aren't in defaultCommandCosts
+const defaultCommandCost = 1
+
+// defaultCommandCosts are the rate limiting costs of commands that are heavier than most
+var defaultCommandCosts = map[appmessage.MessageCommand]float64{
+	appmessage.CmdGetBlocksRequestMessage:                              10,
+	appmessage.CmdGetHeadersRequestMessage:                             5,
+	appmessage.CmdGetUTXOsByAddressesRequestMessage:                    20,
+	appmessage.CmdGetBalancesByAddressesRequestMessage:                 10,
+	appmessage.CmdGetBalanceByAddressRequestMessage:                    4,
+	appmessage.CmdGetVirtualSelectedParentChainFromBlockRequestMessage: 10,
+	appmessage.CmdGetMempoolEntriesRequestMessage:                      5,
+	appmessage.CmdGetAddressHistoryRequestMessage:                      10,
+	appmessage.CmdEstimateNetworkHashesPerSecondRequestMessage:         5,
+	appmessage.CmdGetBlockTemplateRequestMessage:                       2,
+	appmessage.CmdSubmitBlockRequestMessage:                            2,
+	appmessage.CmdSaveMempoolRequestMessage:                            10,
+	appmessage.CmdLoadMempoolRequestMessage:                            10,
+	appmessage.CmdDebugTransactionScriptsRequestMessage:                10,
+}
+
+// defaultRateBurstSeconds is the amount of seconds' worth of the rate the default burst allows
+const defaultRateBurstSeconds = 20
+
+// bucketPruneInterval is how often the buckets of clients that haven't made requests for a while are removed
+const bucketPruneInterval = time.Minute
+
+// rateLimiter limits the rate of the RPC requests of every client with a token bucket.
+// Every request takes the cost of its command from the bucket of its client, and the
+// buckets are refilled at a constant rate up to their capacity
+type rateLimiter struct {
+	rate         float64
+	burst        float64
+	commandCosts map[appmessage.MessageCommand]float64
+
+	lock          sync.Mutex
+	buckets       map[string]*tokenBucket
+	lastPruneTime time.Time
+}
+
+type tokenBucket struct {
+	tokens         float64
+	lastRefillTime time.Time
+}
+
+// newRateLimiter creates a rateLimiter that refills the buckets at the given rate per second up to burst,
+// where burst of 0 means the default burst. The costs of commands may be overridden by their names,
+// which are the names of their request messages without the Request suffix. It returns nil if rate is 0,
+// which disables rate limiting
+func newRateLimiter(rate float64, burst float64, commandCostOverrides map[string]float64) (*rateLimiter, error) {
+	if rate == 0 {
+		return nil, nil
+	}
+
+	commandCosts := make(map[appmessage.MessageCommand]float64, len(handlers))
+	for command := range handlers {
+		commandCosts[command] = defaultCommandCost
+		if cost, ok := defaultCommandCosts[command]; ok {
+			commandCosts[command] = cost
+		}
+	}
+	for commandName, cost := range commandCostOverrides {
+		command, ok := commandByName(commandName)
+		if !ok {
+			return nil, errors.Errorf("unknown RPC command %s", commandName)
+		}
+		commandCosts[command] = cost
+	}
+
+	if burst == 0 {
+		burst = rate * defaultRateBurstSeconds
+	}
+	for command, cost := range commandCosts {
+		if cost > burst {
+			return nil, errors.Errorf("the cost of %s, %g, is greater than the rate limiting burst %g, "+
+				"so it could never be used", commandName(command), cost, burst)
+		}
+	}
+
+	return &rateLimiter{
+		rate:         rate,
+		burst:        burst,
+		commandCosts: commandCosts,
+		buckets:      make(map[string]*tokenBucket),
+	}, nil
+}
+
+func commandName(command appmessage.MessageCommand) string {
+	return strings.TrimSuffix(appmessage.RPCMessageCommandToString[command], "Request")
+}
+
+func commandByName(name string) (appmessage.MessageCommand, bool) {
+	for command := range handlers {
+		if strings.EqualFold(commandName(command), name) {
+			return command, true
+		}
+	}
+	return 0, false
+}
+
+// take takes the cost of the given command from the bucket of the given client. If the bucket doesn't
+// have enough tokens, nothing is taken, and the time until it will have enough tokens is returned
+func (rl *rateLimiter) take(client string, command appmessage.MessageCommand, now time.Time) (
+	isAllowed bool, retryAfter time.Duration) {
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	if now.Sub(rl.lastPruneTime) >= bucketPruneInterval {
+		rl.pruneFullBuckets(now)
+		rl.lastPruneTime = now
+	}
+
+	bucket, ok := rl.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: rl.burst, lastRefillTime: now}
+		rl.buckets[client] = bucket
+	}
+	rl.refill(bucket, now)
+
+	cost := rl.commandCosts[command]
+	if bucket.tokens < cost {
+		missingTokens := cost - bucket.tokens
+		return false, time.Duration(math.Ceil(missingTokens / rl.rate * float64(time.Second)))
+	}
+	bucket.tokens -= cost
+	return true, 0
+}
+
+func (rl *rateLimiter) refill(bucket *tokenBucket, now time.Time) {
+	elapsed := now.Sub(bucket.lastRefillTime)
+	if elapsed <= 0 {
+		return
+	}
+	bucket.tokens = math.Min(rl.burst, bucket.tokens+elapsed.Seconds()*rl.rate)
+	bucket.lastRefillTime = now
+}
+
+// pruneFullBuckets removes the buckets that are full, since they're
+// the same as the new buckets of clients that weren't seen before
+func (rl *rateLimiter) pruneFullBuckets(now time.Time) {
+	for client, bucket := range rl.buckets {
+		rl.refill(bucket, now)
+		if bucket.tokens >= rl.burst {
+			delete(rl.buckets, client)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rateLimiter, err := newRateLimiter(2, 20, map[string]float64{"GetInfo": 4})
+	if err != nil {
+		t.Fatalf("newRateLimiter: %s", err)
+	}
+
+	now := time.Unix(1000, 0)
+	take := func(client string, command appmessage.MessageCommand) (bool, time.Duration) {
+		return rateLimiter.take(client, command, now)
+	}
+
+	// GetUTXOsByAddresses costs 20 by default, which uses up the whole burst
+	isAllowed, _ := take("1.1.1.1", appmessage.CmdGetUTXOsByAddressesRequestMessage)
+	if !isAllowed {
+		t.Fatalf("expected the first request to be allowed")
+	}
+	isAllowed, retryAfter := take("1.1.1.1", appmessage.CmdGetCurrentNetworkRequestMessage)
+	if isAllowed || retryAfter != 500*time.Millisecond {
+		t.Fatalf("expected the request to be throttled for 500ms, but got %t, %s", isAllowed, retryAfter)
+	}
+
+	// Other clients have their own buckets
+	isAllowed, _ = take("2.2.2.2", appmessage.CmdGetCurrentNetworkRequestMessage)
+	if !isAllowed {
+		t.Fatalf("expected the request of another client to be allowed")
+	}
+
+	// The bucket is refilled at the rate
+	now = now.Add(time.Second)
+	isAllowed, _ = take("1.1.1.1", appmessage.CmdGetCurrentNetworkRequestMessage)
+	if !isAllowed {
+		t.Fatalf("expected the request to be allowed after the bucket was refilled")
+	}
+	isAllowed, retryAfter = take("1.1.1.1", appmessage.CmdGetInfoRequestMessage)
+	if isAllowed || retryAfter != 1500*time.Millisecond {
+		t.Fatalf("expected the overridden cost to be used, but got %t, %s", isAllowed, retryAfter)
+	}
+
+	// The bucket isn't refilled beyond the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 20; i++ {
+		isAllowed, _ = take("1.1.1.1", appmessage.CmdGetCurrentNetworkRequestMessage)
+		if !isAllowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+	isAllowed, _ = take("1.1.1.1", appmessage.CmdGetCurrentNetworkRequestMessage)
+	if isAllowed {
+		t.Fatalf("expected the request to be throttled once the burst was used")
+	}
+
+	// Full buckets are pruned
+	now = now.Add(time.Hour)
+	take("3.3.3.3", appmessage.CmdGetCurrentNetworkRequestMessage)
+	if len(rateLimiter.buckets) != 1 {
+		t.Fatalf("expected only the bucket of the last client to remain, but got %d buckets", len(rateLimiter.buckets))
+	}
+}
+
+func TestNewRateLimiterErrors(t *testing.T) {
+	rateLimiter, err := newRateLimiter(0, 0, nil)
+	if err != nil || rateLimiter != nil {
+		t.Fatalf("expected a rate of 0 to disable rate limiting")
+	}
+
+	_, err = newRateLimiter(1, 0, map[string]float64{"NoSuchCommand": 1})
+	if err == nil || !strings.Contains(err.Error(), "unknown RPC command") {
+		t.Fatalf("expected an unknown command error but got %v", err)
+	}
+
+	_, err = newRateLimiter(1, 5, nil)
+	if err == nil || !strings.Contains(err.Error(), "greater than the rate limiting burst") {
+		t.Fatalf("expected an error for a burst that's lower than the cost of GetBlocks but got %v", err)
+	}
+
+	// The default burst is 20 seconds' worth of the rate
+	rateLimiter, err = newRateLimiter(1, 0, nil)
+	if err != nil {
+		t.Fatalf("newRateLimiter: %s", err)
+	}
+	if rateLimiter.burst != 20 {
+		t.Fatalf("expected a default burst of 20 but got %g", rateLimiter.burst)
+	}
+}
+
+func TestErrorResponseBuilders(t *testing.T) {
+	for command := range handlers {
+		builder, ok := errorResponseBuilders[command]
+		if !ok {
+			t.Errorf("no error response builder for %s", command)
+			continue
+		}
+		response := builder(appmessage.RPCErrorf("error"))
+		expectedResponseType := "*appmessage." + commandName(command) + "ResponseMessage"
+		if fmt.Sprintf("%T", response) != expectedResponseType {
+			t.Errorf("the error response of %s is %T", command, response)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package rpc
 
 import (
+	"net"
+	"time"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/pkg/errors"
 )
 
@@ -49,6 +53,46 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdEstimateNetworkHashesPerSecondRequestMessage:              rpchandlers.HandleEstimateNetworkHashesPerSecond,
 	appmessage.CmdNotifyVirtualDaaScoreChangedRequestMessage:                rpchandlers.HandleNotifyVirtualDaaScoreChanged,
 	appmessage.CmdNotifyNewBlockTemplateRequestMessage:                      rpchandlers.HandleNotifyNewBlockTemplate,
+	appmessage.CmdGetTransactionRequestMessage:                              rpchandlers.HandleGetTransaction,
+	appmessage.CmdGetTransactionAcceptanceRequestMessage:                    rpchandlers.HandleGetTransactionAcceptance,
+	appmessage.CmdGetAddressHistoryRequestMessage:                           rpchandlers.HandleGetAddressHistory,
+	appmessage.CmdGetFeeEstimateRequestMessage:                              rpchandlers.HandleGetFeeEstimate,
+	appmessage.CmdSubmitTransactionReplacementRequestMessage:                rpchandlers.HandleSubmitTransactionReplacement,
+	appmessage.CmdSaveMempoolRequestMessage:                                 rpchandlers.HandleSaveMempool,
+	appmessage.CmdLoadMempoolRequestMessage:                                 rpchandlers.HandleLoadMempool,
+	appmessage.CmdSetLogLevelRequestMessage:                                 rpchandlers.HandleSetLogLevel,
+	appmessage.CmdGetLogLevelsRequestMessage:                                rpchandlers.HandleGetLogLevels,
+	appmessage.CmdNotifyMempoolChangedRequestMessage:                        rpchandlers.HandleNotifyMempoolChanged,
+	appmessage.CmdStopNotifyingMempoolChangedRequestMessage:                 rpchandlers.HandleStopNotifyingMempoolChanged,
+	appmessage.CmdDebugTransactionScriptsRequestMessage:                     rpchandlers.HandleDebugTransactionScripts,
+}
+
+// adminCommands are the commands that manage the node itself, and which
+// only RPC clients with admin permissions may use
+var adminCommands = map[appmessage.MessageCommand]struct{}{
+	appmessage.CmdAddPeerRequestMessage:                 {},
+	appmessage.CmdBanRequestMessage:                     {},
+	appmessage.CmdUnbanRequestMessage:                   {},
+	appmessage.CmdShutDownRequestMessage:                {},
+	appmessage.CmdResolveFinalityConflictRequestMessage: {},
+	appmessage.CmdSaveMempoolRequestMessage:             {},
+	appmessage.CmdLoadMempoolRequestMessage:             {},
+	appmessage.CmdSetLogLevelRequestMessage:             {},
+}
+
+// submitCommands are the commands that change the DAG or the mempool, and which
+// only RPC clients with submit or admin permissions may use
+var submitCommands = map[appmessage.MessageCommand]struct{}{
+	appmessage.CmdSubmitBlockRequestMessage:                  {},
+	appmessage.CmdSubmitTransactionRequestMessage:            {},
+	appmessage.CmdSubmitTransactionReplacementRequestMessage: {},
+}
+
+// miningCommands are exempt from the limit on concurrent requests. They're few, cheap
+// and latency-sensitive, and a miner must not wait behind the heavy queries of other clients
+var miningCommands = map[appmessage.MessageCommand]struct{}{
+	appmessage.CmdGetBlockTemplateRequestMessage: {},
+	appmessage.CmdSubmitBlockRequestMessage:      {},
 }
 
 func (m *Manager) routerInitializer(router *router.Router, netConnection *netadapter.NetConnection) {
@@ -65,12 +109,14 @@ func (m *Manager) routerInitializer(router *router.Router, netConnection *netada
 	spawn("routerInitializer-handleIncomingMessages", func() {
 		defer m.context.NotificationManager.RemoveListener(router)
 
-		err := m.handleIncomingMessages(router, incomingRoute)
+		err := m.handleIncomingMessages(router, incomingRoute, netConnection.RPCRole(), clientHost(netConnection))
 		m.handleError(err, netConnection)
 	})
 }
 
-func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *router.Route) error {
+func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *router.Route, rpcRole server.RPCRole,
+	client string) error {
+
 	outgoingRoute := router.OutgoingRoute()
 	for {
 		request, err := incomingRoute.Dequeue()
@@ -81,7 +127,36 @@ func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *r
 		if !ok {
 			return err
 		}
+		command := appmessage.RPCMessageCommandToString[request.Command()]
+		if requiredRole := requiredRPCRole(request.Command()); rpcRole < requiredRole {
+			rpcRequestsDenied.WithLabelValues(command).Inc()
+			err = outgoingRoute.Enqueue(newErrorResponse(request.Command(),
+				appmessage.RPCErrorf("%s requires %s permissions", request.Command(), requiredRole)))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if m.rateLimiter != nil {
+			isAllowed, retryAfter := m.rateLimiter.take(client, request.Command(), time.Now())
+			if !isAllowed {
+				log.Debugf("Throttled %s request of %s", request.Command(), client)
+				rpcRequestsThrottled.WithLabelValues(command).Inc()
+				err = outgoingRoute.Enqueue(newErrorResponse(request.Command(),
+					appmessage.RPCErrorf("rate limit exceeded: retry %s in %s", request.Command(), retryAfter)))
+				if err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		releaseRequestSlot := m.acquireRequestSlot(request.Command())
+		start := time.Now()
 		response, err := handler(m.context, router, request)
+		releaseRequestSlot()
+		rpcRequests.WithLabelValues(command).Inc()
+		rpcRequestDuration.WithLabelValues(command).Observe(time.Since(start).Seconds())
 		if err != nil {
 			return err
 		}
@@ -92,6 +167,39 @@ func (m *Manager) handleIncomingMessages(router *router.Router, incomingRoute *r
 	}
 }
 
+// acquireRequestSlot waits until the amount of requests that are being handled
+// concurrently is below the configured max, and reserves a slot for a request
+// of the given command. It returns a function that releases the slot.
+// Mining commands don't take a slot, see miningCommands
+func (m *Manager) acquireRequestSlot(command appmessage.MessageCommand) (release func()) {
+	if _, isMiningCommand := miningCommands[command]; isMiningCommand || m.requestSemaphore == nil {
+		return func() {}
+	}
+	m.requestSemaphore <- struct{}{}
+	return func() { <-m.requestSemaphore }
+}
+
+// requiredRPCRole returns the lowest RPC role that may use the given command
+func requiredRPCRole(command appmessage.MessageCommand) server.RPCRole {
+	if _, isAdminCommand := adminCommands[command]; isAdminCommand {
+		return server.RPCRoleAdmin
+	}
+	if _, isSubmitCommand := submitCommands[command]; isSubmitCommand {
+		return server.RPCRoleSubmit
+	}
+	return server.RPCRoleReadOnly
+}
+
+// clientHost returns the host of the given RPC client, which identifies
+// the client for rate limiting regardless of how many connections it opens
+func clientHost(netConnection *netadapter.NetConnection) string {
+	host, _, err := net.SplitHostPort(netConnection.Address())
+	if err != nil {
+		return netConnection.Address()
+	}
+	return host
+}
+
 func (m *Manager) handleError(err error, netConnection *netadapter.NetConnection) {
 	if errors.Is(err, router.ErrTimeout) {
 		log.Warnf("Got timeout from %s. Disconnecting...", netConnection)
@@ -0,0 +1,48 @@
+package rpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestAcquireRequestSlotExemptsMiningCommands(t *testing.T) {
+	manager := &Manager{requestSemaphore: make(chan struct{}, 1)}
+
+	releaseFirst := manager.acquireRequestSlot(appmessage.CmdGetBlocksRequestMessage)
+
+	// All the slots are taken, but mining commands don't need one
+	done := make(chan struct{})
+	go func() {
+		release := manager.acquireRequestSlot(appmessage.CmdGetBlockTemplateRequestMessage)
+		release()
+		release = manager.acquireRequestSlot(appmessage.CmdSubmitBlockRequestMessage)
+		release()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("mining commands waited for a request slot")
+	}
+
+	// Other commands wait until a slot is released
+	acquired := make(chan struct{})
+	go func() {
+		release := manager.acquireRequestSlot(appmessage.CmdGetInfoRequestMessage)
+		close(acquired)
+		release()
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("a request slot was acquired while all of them were taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+	releaseFirst()
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("a request slot was not acquired after one was released")
+	}
+}
@@ -0,0 +1,28 @@
+package rpccontext
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/addresshistoryindex"
+)
+
+// ConvertAddressHistoryEntriesToRPCAddressHistoryEntries converts
+// AddressHistoryEntries to a slice of appmessage.AddressHistoryEntry
+func ConvertAddressHistoryEntriesToRPCAddressHistoryEntries(
+	entries []*addresshistoryindex.AddressHistoryEntry) []*appmessage.AddressHistoryEntry {
+
+	rpcEntries := make([]*appmessage.AddressHistoryEntry, len(entries))
+	for i, entry := range entries {
+		rpcEntries[i] = &appmessage.AddressHistoryEntry{
+			Outpoint: &appmessage.RPCOutpoint{
+				TransactionID: entry.Outpoint.TransactionID.String(),
+				Index:         entry.Outpoint.Index,
+			},
+			TransactionID:      entry.TransactionID.String(),
+			Amount:             entry.Amount,
+			IsDebit:            entry.IsDebit,
+			AcceptingBlockHash: entry.AcceptingBlockHash.String(),
+			DAAScore:           entry.DAAScore,
+		}
+	}
+	return rpcEntries
+}
@@ -3,6 +3,8 @@ package rpccontext
 import (
 	"github.com/kaspanet/kaspad/app/protocol"
 	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/addresshistoryindex"
+	"github.com/kaspanet/kaspad/domain/txindex"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
@@ -12,14 +14,16 @@ import (
 
 // Context represents the RPC context
 type Context struct {
-	Config            *config.Config
-	NetAdapter        *netadapter.NetAdapter
-	Domain            domain.Domain
-	ProtocolManager   *protocol.Manager
-	ConnectionManager *connmanager.ConnectionManager
-	AddressManager    *addressmanager.AddressManager
-	UTXOIndex         *utxoindex.UTXOIndex
-	ShutDownChan      chan<- struct{}
+	Config              *config.Config
+	NetAdapter          *netadapter.NetAdapter
+	Domain              domain.Domain
+	ProtocolManager     *protocol.Manager
+	ConnectionManager   *connmanager.ConnectionManager
+	AddressManager      *addressmanager.AddressManager
+	UTXOIndex           *utxoindex.UTXOIndex
+	TXIndex             *txindex.TXIndex
+	AddressHistoryIndex *addresshistoryindex.AddressHistoryIndex
+	ShutDownChan        chan<- struct{}
 
 	NotificationManager *NotificationManager
 }
@@ -32,17 +36,21 @@ func NewContext(cfg *config.Config,
 	connectionManager *connmanager.ConnectionManager,
 	addressManager *addressmanager.AddressManager,
 	utxoIndex *utxoindex.UTXOIndex,
+	txIndex *txindex.TXIndex,
+	addressHistoryIndex *addresshistoryindex.AddressHistoryIndex,
 	shutDownChan chan<- struct{}) *Context {
 
 	context := &Context{
-		Config:            cfg,
-		NetAdapter:        netAdapter,
-		Domain:            domain,
-		ProtocolManager:   protocolManager,
-		ConnectionManager: connectionManager,
-		AddressManager:    addressManager,
-		UTXOIndex:         utxoIndex,
-		ShutDownChan:      shutDownChan,
+		Config:              cfg,
+		NetAdapter:          netAdapter,
+		Domain:              domain,
+		ProtocolManager:     protocolManager,
+		ConnectionManager:   connectionManager,
+		AddressManager:      addressManager,
+		UTXOIndex:           utxoIndex,
+		TXIndex:             txIndex,
+		AddressHistoryIndex: addressHistoryIndex,
+		ShutDownChan:        shutDownChan,
 	}
 	context.NotificationManager = NewNotificationManager()
 
@@ -0,0 +1,64 @@
+package rpccontext
+
+import (
+	"encoding/hex"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// ConvertMempoolChangedNotificationFilters converts the addresses and script public keys
+// of a NotifyMempoolChanged request to the script public keys they represent
+func (ctx *Context) ConvertMempoolChangedNotificationFilters(addressStrings []string,
+	rpcScriptPublicKeys []*appmessage.RPCScriptPublicKey) ([]*externalapi.ScriptPublicKey, error) {
+
+	scriptPublicKeys := make([]*externalapi.ScriptPublicKey, 0, len(addressStrings)+len(rpcScriptPublicKeys))
+	for _, addressString := range addressStrings {
+		address, err := util.DecodeAddress(addressString, ctx.Config.ActiveNetParams.Prefix)
+		if err != nil {
+			return nil, errors.Errorf("Could not decode address '%s': %s", addressString, err)
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			return nil, errors.Errorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
+		}
+		scriptPublicKeys = append(scriptPublicKeys, scriptPublicKey)
+	}
+	for _, rpcScriptPublicKey := range rpcScriptPublicKeys {
+		script, err := hex.DecodeString(rpcScriptPublicKey.Script)
+		if err != nil {
+			return nil, errors.Errorf("Could not decode scriptPublicKey '%s': %s", rpcScriptPublicKey.Script, err)
+		}
+		scriptPublicKeys = append(scriptPublicKeys, &externalapi.ScriptPublicKey{
+			Script:  script,
+			Version: rpcScriptPublicKey.Version,
+		})
+	}
+	return scriptPublicKeys, nil
+}
+
+// ConvertMempoolChangeToRPCMempoolChange converts a change in the mempool to its RPC
+// representation, including the verbose data of its transaction
+func (ctx *Context) ConvertMempoolChangeToRPCMempoolChange(
+	change *miningmanagermodel.MempoolChange) (*appmessage.MempoolChange, error) {
+
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(change.Transaction)
+	err := ctx.PopulateTransactionWithVerboseData(rpcTransaction, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcChange := &appmessage.MempoolChange{
+		Type:        change.Type.String(),
+		Transaction: rpcTransaction,
+		IsOrphan:    change.IsOrphan,
+	}
+	if change.Type == miningmanagermodel.MempoolChangeRemoved {
+		rpcChange.RemovalReason = change.RemovalReason.String()
+	}
+	return rpcChange, nil
+}
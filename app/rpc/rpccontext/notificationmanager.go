@@ -4,6 +4,8 @@ import (
 	"sync"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
@@ -33,10 +35,20 @@ type NotificationListener struct {
 	propagateVirtualDaaScoreChangedNotifications                bool
 	propagatePruningPointUTXOSetOverrideNotifications           bool
 	propagateNewBlockTemplateNotifications                      bool
+	propagateMempoolChangedNotifications                        bool
+
+	includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications bool
 
 	propagateUTXOsChangedNotificationAddresses map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress
+
+	// propagateMempoolChangedScriptPublicKeys is empty if mempool changed notifications
+	// are sent for all transactions
+	propagateMempoolChangedScriptPublicKeys map[utxoindex.ScriptPublicKeyString]struct{}
 }
 
+// MempoolChangeConverter converts a mempool change to its RPC representation
+type MempoolChangeConverter func(change *miningmanagermodel.MempoolChange) (*appmessage.MempoolChange, error)
+
 // NewNotificationManager creates a new NotificationManager
 func NewNotificationManager() *NotificationManager {
 	return &NotificationManager{
@@ -91,14 +103,43 @@ func (nm *NotificationManager) NotifyBlockAdded(notification *appmessage.BlockAd
 	return nil
 }
 
-// NotifyVirtualSelectedParentChainChanged notifies the notification manager that the DAG's selected parent chain has changed
+// HasListenersThatPropagateVirtualSelectedParentChainChanged returns whether there's any listener that is
+// subscribed to VirtualSelectedParentChainChanged notifications as well as checks if any such listener requested
+// to include AcceptedTransactionIDs.
+func (nm *NotificationManager) HasListenersThatPropagateVirtualSelectedParentChainChanged() (hasListeners, hasListenersThatRequireAcceptedTransactionIDs bool) {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for _, listener := range nm.listeners {
+		if listener.propagateVirtualSelectedParentChainChangedNotifications {
+			hasListeners = true
+			if listener.includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications {
+				return true, true
+			}
+		}
+	}
+	return hasListeners, false
+}
+
+// NotifyVirtualSelectedParentChainChanged notifies the notification manager that the DAG's selected parent chain has changed.
+// Listeners that did not ask for accepted transaction IDs receive the notification without them.
 func (nm *NotificationManager) NotifyVirtualSelectedParentChainChanged(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage) error {
 	nm.RLock()
 	defer nm.RUnlock()
 
+	notificationWithoutAcceptedTransactionIDs := &appmessage.VirtualSelectedParentChainChangedNotificationMessage{
+		RemovedChainBlockHashes: notification.RemovedChainBlockHashes,
+		AddedChainBlockHashes:   notification.AddedChainBlockHashes,
+	}
+
 	for router, listener := range nm.listeners {
 		if listener.propagateVirtualSelectedParentChainChangedNotifications {
-			err := router.OutgoingRoute().Enqueue(notification)
+			var err error
+			if listener.includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications {
+				err = router.OutgoingRoute().Enqueue(notification)
+			} else {
+				err = router.OutgoingRoute().Enqueue(notificationWithoutAcceptedTransactionIDs)
+			}
 			if err != nil {
 				return err
 			}
@@ -221,6 +262,52 @@ func (nm *NotificationManager) NotifyNewBlockTemplate(
 	return nil
 }
 
+// NotifyMempoolChanged notifies the notification manager that the contents of the mempool
+// have changed. Every change is converted using convertChange at most once, and only if
+// some listener is interested in it.
+func (nm *NotificationManager) NotifyMempoolChanged(changes []*miningmanagermodel.MempoolChange,
+	convertChange MempoolChangeConverter) error {
+
+	nm.RLock()
+	defer nm.RUnlock()
+
+	convertedChanges := make([]*appmessage.MempoolChange, len(changes))
+	for router, listener := range nm.listeners {
+		if !listener.propagateMempoolChangedNotifications {
+			continue
+		}
+
+		var notificationChanges []*appmessage.MempoolChange
+		for i, change := range changes {
+			if !listener.isInterestedInMempoolChange(change) {
+				continue
+			}
+			if convertedChanges[i] == nil {
+				convertedChange, err := convertChange(change)
+				if err != nil {
+					return err
+				}
+				convertedChanges[i] = convertedChange
+			}
+			notificationChanges = append(notificationChanges, convertedChanges[i])
+		}
+
+		// Don't send the notification if it's empty
+		if len(notificationChanges) == 0 {
+			continue
+		}
+
+		notification := appmessage.NewMempoolChangedNotificationMessage(notificationChanges)
+		err := router.OutgoingRoute().Enqueue(notification)
+		if errors.Is(err, routerpkg.ErrRouteClosed) {
+			log.Warnf("Couldn't send notification: %s", err)
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NotifyPruningPointUTXOSetOverride notifies the notification manager that the UTXO index
 // reset due to pruning point change via IBD.
 func (nm *NotificationManager) NotifyPruningPointUTXOSetOverride() error {
@@ -258,9 +345,11 @@ func (nl *NotificationListener) PropagateBlockAddedNotifications() {
 }
 
 // PropagateVirtualSelectedParentChainChangedNotifications instructs the listener to send chain changed notifications
-// to the remote listener
-func (nl *NotificationListener) PropagateVirtualSelectedParentChainChangedNotifications() {
+// to the remote listener. If includeAcceptedTransactionIDs is set, the notifications contain the transaction IDs
+// accepted by every added chain block
+func (nl *NotificationListener) PropagateVirtualSelectedParentChainChangedNotifications(includeAcceptedTransactionIDs bool) {
 	nl.propagateVirtualSelectedParentChainChangedNotifications = true
+	nl.includeAcceptedTransactionIDsInVirtualSelectedParentChainChangedNotifications = includeAcceptedTransactionIDs
 }
 
 // PropagateFinalityConflictNotifications instructs the listener to send finality conflict notifications
@@ -361,6 +450,52 @@ func (nl *NotificationListener) PropagateNewBlockTemplateNotifications() {
 	nl.propagateNewBlockTemplateNotifications = true
 }
 
+// PropagateMempoolChangedNotifications instructs the listener to send mempool changed
+// notifications to the remote listener for transactions that pay to or spend from any
+// of the given script public keys. If no script public keys are given, notifications are
+// sent for all transactions. Subsequent calls replace the script public keys of the
+// previous ones.
+func (nl *NotificationListener) PropagateMempoolChangedNotifications(scriptPublicKeys []*externalapi.ScriptPublicKey) {
+	nl.propagateMempoolChangedNotifications = true
+	nl.propagateMempoolChangedScriptPublicKeys = make(map[utxoindex.ScriptPublicKeyString]struct{}, len(scriptPublicKeys))
+	for _, scriptPublicKey := range scriptPublicKeys {
+		nl.propagateMempoolChangedScriptPublicKeys[utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)] = struct{}{}
+	}
+}
+
+// StopPropagatingMempoolChangedNotifications instructs the listener to stop sending
+// mempool changed notifications to the remote listener
+func (nl *NotificationListener) StopPropagatingMempoolChangedNotifications() {
+	nl.propagateMempoolChangedNotifications = false
+	nl.propagateMempoolChangedScriptPublicKeys = nil
+}
+
+// isInterestedInMempoolChange returns whether the transaction of the given change has
+// an output or a known spent UTXO entry with one of the script public keys the listener
+// was registered with
+func (nl *NotificationListener) isInterestedInMempoolChange(change *miningmanagermodel.MempoolChange) bool {
+	if len(nl.propagateMempoolChangedScriptPublicKeys) == 0 {
+		return true
+	}
+	for _, output := range change.Transaction.Outputs {
+		if nl.isPropagatedMempoolChangedScriptPublicKey(output.ScriptPublicKey) {
+			return true
+		}
+	}
+	for _, input := range change.Transaction.Inputs {
+		// The UTXO entries of inputs that spend outputs of missing parents are unknown
+		if input.UTXOEntry != nil && nl.isPropagatedMempoolChangedScriptPublicKey(input.UTXOEntry.ScriptPublicKey()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (nl *NotificationListener) isPropagatedMempoolChangedScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) bool {
+	_, ok := nl.propagateMempoolChangedScriptPublicKeys[utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)]
+	return ok
+}
+
 // PropagatePruningPointUTXOSetOverrideNotifications instructs the listener to send pruning point UTXO set override notifications
 // to the remote listener.
 func (nl *NotificationListener) PropagatePruningPointUTXOSetOverrideNotifications() {
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 )
 
@@ -32,6 +33,46 @@ func ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(address string, pair
 	return utxosByAddressesEntries
 }
 
+// AddUnconfirmedUTXOsToUTXOsByAddressesEntries marks the entries that are spent by
+// mempool transactions, and appends the unspent outputs of mempool transactions to them.
+// addressesByScriptPublicKey maps the script public keys of the unconfirmed UTXOs to the
+// addresses they were requested by
+func AddUnconfirmedUTXOsToUTXOsByAddressesEntries(entries []*appmessage.UTXOsByAddressesEntry,
+	unconfirmedUTXOs *miningmanagermodel.UnconfirmedUTXOs,
+	addressesByScriptPublicKey map[utxoindex.ScriptPublicKeyString]string) []*appmessage.UTXOsByAddressesEntry {
+
+	spentOutpoints := make(map[appmessage.RPCOutpoint]struct{}, len(unconfirmedUTXOs.Spent))
+	for outpoint := range unconfirmedUTXOs.Spent {
+		spentOutpoints[appmessage.RPCOutpoint{TransactionID: outpoint.TransactionID.String(), Index: outpoint.Index}] = struct{}{}
+	}
+	for _, entry := range entries {
+		if _, ok := spentOutpoints[*entry.Outpoint]; ok {
+			entry.IsSpentByUnconfirmed = true
+		}
+	}
+
+	for outpoint, utxoEntry := range unconfirmedUTXOs.Added {
+		address, ok := addressesByScriptPublicKey[utxoindex.ConvertScriptPublicKeyToString(utxoEntry.ScriptPublicKey())]
+		if !ok {
+			continue
+		}
+		entries = append(entries, &appmessage.UTXOsByAddressesEntry{
+			Address: address,
+			Outpoint: &appmessage.RPCOutpoint{
+				TransactionID: outpoint.TransactionID.String(),
+				Index:         outpoint.Index,
+			},
+			UTXOEntry: &appmessage.RPCUTXOEntry{
+				Amount:          utxoEntry.Amount(),
+				ScriptPublicKey: &appmessage.RPCScriptPublicKey{Script: hex.EncodeToString(utxoEntry.ScriptPublicKey().Script), Version: utxoEntry.ScriptPublicKey().Version},
+				IsCoinbase:      utxoEntry.IsCoinbase(),
+			},
+			IsUnconfirmed: true,
+		})
+	}
+	return entries
+}
+
 // convertUTXOOutpointsToUTXOsByAddressesEntries converts
 // UTXOOutpoints to a slice of UTXOsByAddressesEntry
 func convertUTXOOutpointsToUTXOsByAddressesEntries(address string, outpoints utxoindex.UTXOOutpoints) []*appmessage.UTXOsByAddressesEntry {
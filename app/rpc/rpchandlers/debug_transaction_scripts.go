@@ -0,0 +1,171 @@
+package rpchandlers
+
+import (
+	"encoding/hex"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// maxDebugTransactionScriptsInputs is the maximum number of inputs of a transaction whose
+// scripts may be debugged. Tracing an input takes much more CPU and memory than validating it
+const maxDebugTransactionScriptsInputs = 100
+
+// maxDebugTransactionScriptsTraceSize is the maximum total size, in bytes, of the traces of all
+// the inputs of a single request. See txscript.TraceStep.Size
+const maxDebugTransactionScriptsTraceSize = 10_000_000
+
+// HandleDebugTransactionScripts handles the respectively named RPC command
+func HandleDebugTransactionScripts(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	debugTransactionScriptsRequest := request.(*appmessage.DebugTransactionScriptsRequestMessage)
+
+	domainTransaction, err := appmessage.RPCTransactionToDomainTransaction(debugTransactionScriptsRequest.Transaction)
+	if err != nil {
+		errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not parse transaction: %s", err)
+		return errorMessage, nil
+	}
+
+	if len(domainTransaction.Inputs) > maxDebugTransactionScriptsInputs {
+		errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction has %d inputs, which is more than "+
+			"the maximum of %d", len(domainTransaction.Inputs), maxDebugTransactionScriptsInputs)
+		return errorMessage, nil
+	}
+	context.Domain.Consensus().PopulateMass(domainTransaction)
+	if domainTransaction.Mass > mempool.MaximumStandardTransactionMass {
+		errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction mass of %d is larger than the "+
+			"max allowed size of %d", domainTransaction.Mass, mempool.MaximumStandardTransactionMass)
+		return errorMessage, nil
+	}
+
+	if len(debugTransactionScriptsRequest.UTXOEntries) > 0 {
+		if len(debugTransactionScriptsRequest.UTXOEntries) != len(domainTransaction.Inputs) {
+			errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Expected %d UTXO entries, one per input, but got %d",
+				len(domainTransaction.Inputs), len(debugTransactionScriptsRequest.UTXOEntries))
+			return errorMessage, nil
+		}
+		for i, rpcUTXOEntry := range debugTransactionScriptsRequest.UTXOEntries {
+			domainTransaction.Inputs[i].UTXOEntry, err = appmessage.RPCUTXOEntryToUTXOEntry(rpcUTXOEntry)
+			if err != nil {
+				errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+				errorMessage.Error = appmessage.RPCErrorf("Could not parse UTXO entry of input %d: %s", i, err)
+				return errorMessage, nil
+			}
+		}
+	} else {
+		err = populateUTXOEntries(context, domainTransaction)
+		if err != nil {
+			return nil, err
+		}
+		for i, input := range domainTransaction.Inputs {
+			if input.UTXOEntry == nil {
+				errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+				errorMessage.Error = appmessage.RPCErrorf("Could not find the UTXO entry of input %d "+
+					"which references output %s", i, input.PreviousOutpoint)
+				return errorMessage, nil
+			}
+		}
+	}
+
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	inputTraces := make([]*appmessage.RPCScriptInputTrace, len(domainTransaction.Inputs))
+	remainingTraceSize := maxDebugTransactionScriptsTraceSize
+	for i := range domainTransaction.Inputs {
+		// A maxTraceSize of 0 means no limit, so a used up budget can't be passed on
+		if remainingTraceSize == 0 {
+			return traceTooLargeResponse(i), nil
+		}
+		inputTrace, err := txscript.TraceTransactionInput(domainTransaction, i, sighashReusedValues, remainingTraceSize)
+		if err != nil {
+			if errors.Is(err, txscript.ErrTraceTooLarge) {
+				return traceTooLargeResponse(i), nil
+			}
+			return nil, err
+		}
+		for _, step := range inputTrace.Steps {
+			remainingTraceSize -= step.Size()
+		}
+		inputTraces[i] = convertInputTraceToRPCScriptInputTrace(uint32(i), inputTrace)
+	}
+
+	response := appmessage.NewDebugTransactionScriptsResponseMessage(inputTraces)
+	return response, nil
+}
+
+func traceTooLargeResponse(inputIndex int) *appmessage.DebugTransactionScriptsResponseMessage {
+	errorMessage := &appmessage.DebugTransactionScriptsResponseMessage{}
+	errorMessage.Error = appmessage.RPCErrorf("The traces of the transaction scripts exceed "+
+		"the maximum size of %d bytes at input %d", maxDebugTransactionScriptsTraceSize, inputIndex)
+	return errorMessage
+}
+
+// populateUTXOEntries fills the UTXO entries of the transaction inputs first from
+// the outputs of mempool transactions and then from the virtual UTXO set. Inputs
+// that can't be found in either are left unpopulated.
+func populateUTXOEntries(context *rpccontext.Context, transaction *externalapi.DomainTransaction) error {
+	for _, input := range transaction.Inputs {
+		parent, ok := context.Domain.MiningManager().GetTransaction(&input.PreviousOutpoint.TransactionID)
+		if !ok || input.PreviousOutpoint.Index >= uint32(len(parent.Outputs)) {
+			continue
+		}
+		output := parent.Outputs[input.PreviousOutpoint.Index]
+		input.UTXOEntry = utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, false, constants.UnacceptedDAAScore)
+	}
+
+	// Validation may fail for reasons that are irrelevant to script
+	// debugging, in which case the inputs are still populated
+	err := context.Domain.Consensus().ValidateTransactionAndPopulateWithConsensusData(transaction)
+	if err != nil {
+		if !errors.As(err, &ruleerrors.RuleError{}) {
+			return err
+		}
+		log.Debugf("Transaction %s failed validation while populating its UTXO entries: %s",
+			consensushashing.TransactionID(transaction), err)
+	}
+	return nil
+}
+
+func convertInputTraceToRPCScriptInputTrace(inputIndex uint32, inputTrace *txscript.InputTrace) *appmessage.RPCScriptInputTrace {
+	steps := make([]*appmessage.RPCScriptTraceStep, len(inputTrace.Steps))
+	for i, step := range inputTrace.Steps {
+		steps[i] = &appmessage.RPCScriptTraceStep{
+			ScriptIndex:  uint32(step.ScriptIndex),
+			ScriptOffset: uint32(step.ScriptOffset),
+			Opcode:       step.Opcode,
+			IsExecuted:   step.IsExecuted,
+			DataStack:    hexEncodeStackItems(step.DataStack),
+			AltStack:     hexEncodeStackItems(step.AltStack),
+		}
+	}
+
+	rpcInputTrace := &appmessage.RPCScriptInputTrace{
+		InputIndex: inputIndex,
+		Scripts:    inputTrace.Scripts,
+		Steps:      steps,
+		IsValid:    inputTrace.Err == nil,
+	}
+	if inputTrace.Err != nil {
+		rpcInputTrace.Error = inputTrace.Err.Error()
+	}
+	return rpcInputTrace
+}
+
+func hexEncodeStackItems(items [][]byte) []string {
+	encodedItems := make([]string, len(items))
+	for i, item := range items {
+		encodedItems[i] = hex.EncodeToString(item)
+	}
+	return encodedItems
+}
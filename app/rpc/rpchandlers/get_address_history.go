@@ -0,0 +1,73 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/addresshistoryindex"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// maxAddressHistoryLimit is the maximum amount of entries that may be
+// returned by a single GetAddressHistory request
+const maxAddressHistoryLimit = 1000
+
+// HandleGetAddressHistory handles the respectively named RPC command
+func HandleGetAddressHistory(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.AddressHistoryIndex {
+		errorMessage := &appmessage.GetAddressHistoryResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --addresshistoryindex")
+		return errorMessage, nil
+	}
+
+	getAddressHistoryRequest := request.(*appmessage.GetAddressHistoryRequestMessage)
+
+	limit := getAddressHistoryRequest.Limit
+	if limit == 0 {
+		limit = maxAddressHistoryLimit
+	}
+	if limit > maxAddressHistoryLimit {
+		errorMessage := &appmessage.GetAddressHistoryResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Limit %d exceeds the maximum of %d",
+			getAddressHistoryRequest.Limit, maxAddressHistoryLimit)
+		return errorMessage, nil
+	}
+
+	address, err := util.DecodeAddress(getAddressHistoryRequest.Address, context.Config.ActiveNetParams.Prefix)
+	if err != nil {
+		errorMessage := &appmessage.GetAddressHistoryResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not decode address '%s': %s",
+			getAddressHistoryRequest.Address, err)
+		return errorMessage, nil
+	}
+
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		errorMessage := &appmessage.GetAddressHistoryResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s",
+			getAddressHistoryRequest.Address, err)
+		return errorMessage, nil
+	}
+
+	entries, err := context.AddressHistoryIndex.History(scriptPublicKey,
+		getAddressHistoryRequest.Cursor, uint64(limit))
+	if err != nil {
+		if errors.Is(err, addresshistoryindex.ErrInvalidCursor) {
+			errorMessage := &appmessage.GetAddressHistoryResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not parse cursor '%s': %s",
+				getAddressHistoryRequest.Cursor, err)
+			return errorMessage, nil
+		}
+		return nil, err
+	}
+
+	nextCursor := getAddressHistoryRequest.Cursor
+	if len(entries) > 0 {
+		nextCursor = entries[len(entries)-1].Cursor()
+	}
+
+	rpcEntries := rpccontext.ConvertAddressHistoryEntriesToRPCAddressHistoryEntries(entries)
+	return appmessage.NewGetAddressHistoryResponseMessage(getAddressHistoryRequest.Address, rpcEntries, nextCursor), nil
+}
@@ -3,6 +3,7 @@ package rpchandlers
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/util"
@@ -19,7 +20,8 @@ func HandleGetBalanceByAddress(context *rpccontext.Context, _ *router.Router, re
 
 	getBalanceByAddressRequest := request.(*appmessage.GetBalanceByAddressRequestMessage)
 
-	balance, err := getBalanceByAddress(context, getBalanceByAddressRequest.Address)
+	balance, err := getBalanceByAddress(context, getBalanceByAddressRequest.Address,
+		getBalanceByAddressRequest.IncludeUnconfirmed)
 	if err != nil {
 		rpcError := &appmessage.RPCError{}
 		if !errors.As(err, rpcError) {
@@ -30,28 +32,45 @@ func HandleGetBalanceByAddress(context *rpccontext.Context, _ *router.Router, re
 		return errorMessage, nil
 	}
 
-	response := appmessage.NewGetBalanceByAddressResponse(balance)
+	response := appmessage.NewGetBalanceByAddressResponse(balance.Balance)
+	response.UnconfirmedIncoming = balance.UnconfirmedIncoming
+	response.UnconfirmedOutgoing = balance.UnconfirmedOutgoing
 	return response, nil
 }
 
-func getBalanceByAddress(context *rpccontext.Context, addressString string) (uint64, error) {
+// getBalanceByAddress returns the balance of the given address. If includeUnconfirmed is set,
+// the amounts that the transactions in the mempool add to and spend from it are returned as well
+func getBalanceByAddress(context *rpccontext.Context, addressString string,
+	includeUnconfirmed bool) (*appmessage.BalancesByAddressesEntry, error) {
+
 	address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
 	if err != nil {
-		return 0, appmessage.RPCErrorf("Couldn't decode address '%s': %s", addressString, err)
+		return nil, appmessage.RPCErrorf("Couldn't decode address '%s': %s", addressString, err)
 	}
 
 	scriptPublicKey, err := txscript.PayToAddrScript(address)
 	if err != nil {
-		return 0, appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
+		return nil, appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
 	}
 	utxoOutpointEntryPairs, err := context.UTXOIndex.UTXOs(scriptPublicKey)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	balance := uint64(0)
+	balance := &appmessage.BalancesByAddressesEntry{Address: addressString}
 	for _, utxoOutpointEntryPair := range utxoOutpointEntryPairs {
-		balance += utxoOutpointEntryPair.Amount()
+		balance.Balance += utxoOutpointEntryPair.Amount()
+	}
+
+	if includeUnconfirmed {
+		unconfirmedUTXOs := context.Domain.MiningManager().UnconfirmedUTXOs(
+			[]*externalapi.ScriptPublicKey{scriptPublicKey}, utxoOutpointEntryPairs)
+		for _, utxoEntry := range unconfirmedUTXOs.Added {
+			balance.UnconfirmedIncoming += utxoEntry.Amount()
+		}
+		for _, utxoEntry := range unconfirmedUTXOs.Spent {
+			balance.UnconfirmedOutgoing += utxoEntry.Amount()
+		}
 	}
 	return balance, nil
 }
@@ -19,7 +19,7 @@ func HandleGetBalancesByAddresses(context *rpccontext.Context, _ *router.Router,
 
 	allEntries := make([]*appmessage.BalancesByAddressesEntry, len(getBalancesByAddressesRequest.Addresses))
 	for i, address := range getBalancesByAddressesRequest.Addresses {
-		balance, err := getBalanceByAddress(context, address)
+		balance, err := getBalanceByAddress(context, address, getBalancesByAddressesRequest.IncludeUnconfirmed)
 
 		if err != nil {
 			rpcError := &appmessage.RPCError{}
@@ -30,10 +30,7 @@ func HandleGetBalancesByAddresses(context *rpccontext.Context, _ *router.Router,
 			errorMessage.Error = rpcError
 			return errorMessage, nil
 		}
-		allEntries[i] = &appmessage.BalancesByAddressesEntry{
-			Address: address,
-			Balance: balance,
-		}
+		allEntries[i] = balance
 	}
 
 	response := appmessage.NewGetBalancesByAddressesResponse(allEntries)
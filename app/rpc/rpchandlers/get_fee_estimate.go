@@ -0,0 +1,31 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/miningmanager"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetFeeEstimate handles the respectively named RPC command
+func HandleGetFeeEstimate(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	virtualDAAScore, err := context.Domain.Consensus().GetVirtualDAAScore()
+	if err != nil {
+		return nil, err
+	}
+
+	feeEstimate := context.Domain.MiningManager().GetFeeEstimate()
+
+	toRPCFeeRateBucket := func(bucket *miningmanager.FeeRateBucket) *appmessage.RPCFeeRateBucket {
+		return &appmessage.RPCFeeRateBucket{
+			FeeRate:                    bucket.FeeRate,
+			EstimatedInclusionDAAScore: virtualDAAScore + bucket.EstimatedBlocks,
+		}
+	}
+
+	return appmessage.NewGetFeeEstimateResponseMessage(
+		toRPCFeeRateBucket(feeEstimate.PriorityBucket),
+		toRPCFeeRateBucket(feeEstimate.NormalBucket),
+		toRPCFeeRateBucket(feeEstimate.LowBucket),
+		virtualDAAScore), nil
+}
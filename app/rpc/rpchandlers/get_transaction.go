@@ -0,0 +1,154 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/hashes"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// HandleGetTransaction handles the respectively named RPC command
+func HandleGetTransaction(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.TXIndex {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --txindex")
+		return errorMessage, nil
+	}
+
+	getTransactionRequest := request.(*appmessage.GetTransactionRequestMessage)
+
+	transactionID, err := transactionid.FromString(getTransactionRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	includingBlockHashes, err := context.TXIndex.TXInclusions(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(includingBlockHashes) == 0 {
+		// The index only knows blocks that were merged by the virtual selected parent
+		// chain. The virtual selected parent and the blocks in its anticone were not
+		// merged by a chain block yet, so their transactions are included but not accepted
+		unmergedBlockHashes, err := getBlocksNotMergedByChain(context)
+		if err != nil {
+			return nil, err
+		}
+		transaction, block, err := findTransactionInBlocks(context, transactionID, unmergedBlockHashes)
+		if err != nil {
+			return nil, err
+		}
+		if transaction != nil {
+			rpcTransaction := appmessage.DomainTransactionToRPCTransaction(transaction)
+			if getTransactionRequest.IncludeTransactionVerboseData {
+				err = context.PopulateTransactionWithVerboseData(rpcTransaction, block.Header)
+				if err != nil {
+					return nil, err
+				}
+			}
+			blockHash := consensushashing.BlockHash(block)
+			return appmessage.NewGetTransactionResponseMessage(rpcTransaction, []string{blockHash.String()},
+				"", 0, 0, false), nil
+		}
+
+		mempoolTransaction, ok := context.Domain.MiningManager().GetTransaction(transactionID)
+		if !ok {
+			errorMessage := &appmessage.GetTransactionResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+			return errorMessage, nil
+		}
+
+		rpcTransaction := appmessage.DomainTransactionToRPCTransaction(mempoolTransaction)
+		if getTransactionRequest.IncludeTransactionVerboseData {
+			err = context.PopulateTransactionWithVerboseData(rpcTransaction, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return appmessage.NewGetTransactionResponseMessage(rpcTransaction, nil, "", 0, 0, true), nil
+	}
+
+	txAcceptance, isAccepted, err := context.TXIndex.TXAcceptance(transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer the copy of the transaction that was actually accepted
+	candidateBlockHashes := includingBlockHashes
+	if isAccepted {
+		candidateBlockHashes = append([]*externalapi.DomainHash{txAcceptance.IncludingBlockHash}, includingBlockHashes...)
+	}
+	transaction, block, err := findTransactionInBlocks(context, transactionID, candidateBlockHashes)
+	if err != nil {
+		return nil, err
+	}
+	if transaction == nil {
+		errorMessage := &appmessage.GetTransactionResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The data of transaction %s is no longer available. "+
+			"Its blocks were probably pruned", transactionID)
+		return errorMessage, nil
+	}
+
+	rpcTransaction := appmessage.DomainTransactionToRPCTransaction(transaction)
+	if getTransactionRequest.IncludeTransactionVerboseData {
+		err = context.PopulateTransactionWithVerboseData(rpcTransaction, block.Header)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var acceptingBlockHash string
+	var acceptingBlockBlueScore, confirmations uint64
+	if isAccepted {
+		acceptingBlockHash = txAcceptance.AcceptingBlockHash.String()
+		acceptingBlockBlueScore, confirmations, err = getAcceptingBlockBlueScoreAndConfirmations(
+			context, txAcceptance.AcceptingBlockHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return appmessage.NewGetTransactionResponseMessage(rpcTransaction, hashes.ToStrings(includingBlockHashes),
+		acceptingBlockHash, acceptingBlockBlueScore, confirmations, false), nil
+}
+
+func getBlocksNotMergedByChain(context *rpccontext.Context) ([]*externalapi.DomainHash, error) {
+	virtualSelectedParent, err := context.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	anticone, err := context.Domain.Consensus().Anticone(virtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*externalapi.DomainHash{virtualSelectedParent}, anticone...), nil
+}
+
+func findTransactionInBlocks(context *rpccontext.Context, transactionID *externalapi.DomainTransactionID,
+	blockHashes []*externalapi.DomainHash) (*externalapi.DomainTransaction, *externalapi.DomainBlock, error) {
+
+	for _, blockHash := range blockHashes {
+		block, err := context.Domain.Consensus().GetBlock(blockHash)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				continue
+			}
+			return nil, nil, err
+		}
+
+		for _, transaction := range block.Transactions {
+			if consensushashing.TransactionID(transaction).Equal(transactionID) {
+				return transaction, block, nil
+			}
+		}
+	}
+	return nil, nil, nil
+}
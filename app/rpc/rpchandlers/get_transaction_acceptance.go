@@ -0,0 +1,80 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetTransactionAcceptance handles the respectively named RPC command
+func HandleGetTransactionAcceptance(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.TXIndex {
+		errorMessage := &appmessage.GetTransactionAcceptanceResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --txindex")
+		return errorMessage, nil
+	}
+
+	getTransactionAcceptanceRequest := request.(*appmessage.GetTransactionAcceptanceRequestMessage)
+
+	entries := make([]*appmessage.TransactionAcceptanceEntry, len(getTransactionAcceptanceRequest.TransactionIDs))
+	for i, transactionIDString := range getTransactionAcceptanceRequest.TransactionIDs {
+		transactionID, err := transactionid.FromString(transactionIDString)
+		if err != nil {
+			errorMessage := &appmessage.GetTransactionAcceptanceResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Transaction ID %s could not be parsed: %s", transactionIDString, err)
+			return errorMessage, nil
+		}
+
+		txAcceptance, isAccepted, err := context.TXIndex.TXAcceptance(transactionID)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &appmessage.TransactionAcceptanceEntry{
+			TransactionID: transactionID.String(),
+			IsAccepted:    isAccepted,
+		}
+		if isAccepted {
+			entry.AcceptingBlockHash = txAcceptance.AcceptingBlockHash.String()
+			entry.IncludingBlockHash = txAcceptance.IncludingBlockHash.String()
+			entry.AcceptingBlockBlueScore, entry.Confirmations, err = getAcceptingBlockBlueScoreAndConfirmations(
+				context, txAcceptance.AcceptingBlockHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+		entries[i] = entry
+	}
+
+	return appmessage.NewGetTransactionAcceptanceResponseMessage(entries), nil
+}
+
+// getAcceptingBlockBlueScoreAndConfirmations returns the blue score of the given accepting
+// chain block, as well as the amount of chain blocks since it, including the block itself
+func getAcceptingBlockBlueScoreAndConfirmations(context *rpccontext.Context,
+	acceptingBlockHash *externalapi.DomainHash) (acceptingBlockBlueScore uint64, confirmations uint64, err error) {
+
+	acceptingBlockInfo, err := context.Domain.Consensus().GetBlockInfo(acceptingBlockHash)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !acceptingBlockInfo.Exists {
+		return 0, 0, nil
+	}
+
+	virtualSelectedParent, err := context.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return 0, 0, err
+	}
+	virtualSelectedParentInfo, err := context.Domain.Consensus().GetBlockInfo(virtualSelectedParent)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if virtualSelectedParentInfo.BlueScore < acceptingBlockInfo.BlueScore {
+		return acceptingBlockInfo.BlueScore, 0, nil
+	}
+	return acceptingBlockInfo.BlueScore, virtualSelectedParentInfo.BlueScore - acceptingBlockInfo.BlueScore + 1, nil
+}
@@ -3,7 +3,9 @@ package rpchandlers
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/util"
 )
@@ -19,6 +21,9 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 	getUTXOsByAddressesRequest := request.(*appmessage.GetUTXOsByAddressesRequestMessage)
 
 	allEntries := make([]*appmessage.UTXOsByAddressesEntry, 0)
+	scriptPublicKeys := make([]*externalapi.ScriptPublicKey, 0, len(getUTXOsByAddressesRequest.Addresses))
+	addressesByScriptPublicKey := make(map[utxoindex.ScriptPublicKeyString]string, len(getUTXOsByAddressesRequest.Addresses))
+	confirmedUTXOs := make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry)
 	for _, addressString := range getUTXOsByAddressesRequest.Addresses {
 		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
 		if err != nil {
@@ -38,6 +43,20 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 		}
 		entries := rpccontext.ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(addressString, utxoOutpointEntryPairs)
 		allEntries = append(allEntries, entries...)
+		if getUTXOsByAddressesRequest.IncludeUnconfirmed {
+			for outpoint, utxoEntry := range utxoOutpointEntryPairs {
+				confirmedUTXOs[outpoint] = utxoEntry
+			}
+		}
+
+		scriptPublicKeys = append(scriptPublicKeys, scriptPublicKey)
+		addressesByScriptPublicKey[utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)] = addressString
+	}
+
+	if getUTXOsByAddressesRequest.IncludeUnconfirmed {
+		unconfirmedUTXOs := context.Domain.MiningManager().UnconfirmedUTXOs(scriptPublicKeys, confirmedUTXOs)
+		allEntries = rpccontext.AddUnconfirmedUTXOsToUTXOsByAddressesEntries(
+			allEntries, unconfirmedUTXOs, addressesByScriptPublicKey)
 	}
 
 	response := appmessage.NewGetUTXOsByAddressesResponseMessage(allEntries)
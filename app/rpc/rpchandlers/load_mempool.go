@@ -0,0 +1,22 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleLoadMempool handles the respectively named RPC command
+func HandleLoadMempool(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	mempoolFilePath := context.Config.MempoolFilePath()
+	acceptedTransactions, rejectedTransactionCount, err := context.ProtocolManager.LoadMempool(mempoolFilePath)
+	if err != nil {
+		errorMessage := &appmessage.LoadMempoolResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not load the mempool from %s: %s", mempoolFilePath, err)
+		return errorMessage, nil
+	}
+
+	log.Infof("Loaded %d transactions from %s (%d dropped)",
+		len(acceptedTransactions), mempoolFilePath, rejectedTransactionCount)
+	return appmessage.NewLoadMempoolResponseMessage(uint64(len(acceptedTransactions)), uint64(rejectedTransactionCount)), nil
+}
@@ -0,0 +1,28 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleNotifyMempoolChanged handles the respectively named RPC command
+func HandleNotifyMempoolChanged(context *rpccontext.Context, router *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	notifyMempoolChangedRequest := request.(*appmessage.NotifyMempoolChangedRequestMessage)
+	scriptPublicKeys, err := context.ConvertMempoolChangedNotificationFilters(
+		notifyMempoolChangedRequest.Addresses, notifyMempoolChangedRequest.ScriptPublicKeys)
+	if err != nil {
+		errorMessage := appmessage.NewNotifyMempoolChangedResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Parsing error: %s", err)
+		return errorMessage, nil
+	}
+
+	listener, err := context.NotificationManager.Listener(router)
+	if err != nil {
+		return nil, err
+	}
+	listener.PropagateMempoolChangedNotifications(scriptPublicKeys)
+
+	response := appmessage.NewNotifyMempoolChangedResponseMessage()
+	return response, nil
+}
@@ -0,0 +1,21 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleSaveMempool handles the respectively named RPC command
+func HandleSaveMempool(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	mempoolFilePath := context.Config.MempoolFilePath()
+	savedTransactionCount, err := context.Domain.MiningManager().SaveMempool(mempoolFilePath)
+	if err != nil {
+		errorMessage := &appmessage.SaveMempoolResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not save the mempool to %s: %s", mempoolFilePath, err)
+		return errorMessage, nil
+	}
+
+	log.Infof("Saved %d mempool transactions to %s", savedTransactionCount, mempoolFilePath)
+	return appmessage.NewSaveMempoolResponseMessage(uint64(savedTransactionCount)), nil
+}
@@ -0,0 +1,293 @@
+package snapshot
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxHeadersPerRecord is the number of pruning point future headers
+	// written in every BlockHeadersMessage record.
+	// It MUST be >= MergeSetSizeLimit + 1, see GetHashesBetween.
+	maxHeadersPerRecord = 1 << 10
+
+	// utxosPerRecord is the number of UTXOs written in every
+	// MsgPruningPointUTXOSetChunk record
+	utxosPerRecord = 1000
+)
+
+// Export writes a snapshot of the given consensus to the file at the given path:
+// the pruning point proof, the past pruning points, the pruning point and its
+// anticone along with their trusted data, the pruning point future and the
+// pruning point UTXO set. Importing it brings a new node to the same state
+// without downloading any of it from peers.
+func Export(consensus externalapi.Consensus, params *dagconfig.Params, filePath string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "snapshot.Export")
+	defer onEnd()
+
+	pruningPoint, err := consensus.PruningPoint()
+	if err != nil {
+		return err
+	}
+	if pruningPoint.Equal(params.GenesisHash) {
+		return errors.New("the pruning point is still the genesis, so there's nothing to snapshot. " +
+			"Nodes this young sync quickly from peers")
+	}
+
+	writer, err := newSnapshotWriter(filePath, params.Name)
+	if err != nil {
+		return err
+	}
+
+	exporter := &exporter{
+		consensus:    consensus,
+		params:       params,
+		writer:       writer,
+		pruningPoint: pruningPoint,
+	}
+	err = exporter.export()
+	if err != nil {
+		writer.abort()
+		return err
+	}
+	err = writer.finish()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Wrote a snapshot of pruning point %s to %s", pruningPoint, filePath)
+	return nil
+}
+
+type exporter struct {
+	consensus    externalapi.Consensus
+	params       *dagconfig.Params
+	writer       *snapshotWriter
+	pruningPoint *externalapi.DomainHash
+}
+
+func (e *exporter) export() error {
+	log.Infof("Writing the pruning point proof")
+	pruningPointProof, err := e.consensus.BuildPruningPointProof()
+	if err != nil {
+		return err
+	}
+	err = e.writer.writeMessage(appmessage.DomainPruningPointProofToMsgPruningPointProof(pruningPointProof))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Writing the past pruning points")
+	pruningPointHeaders, err := e.consensus.PruningPointHeaders()
+	if err != nil {
+		return err
+	}
+	msgPruningPointHeaders := make([]*appmessage.MsgBlockHeader, len(pruningPointHeaders))
+	for i, header := range pruningPointHeaders {
+		msgPruningPointHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(header)
+	}
+	err = e.writer.writeMessage(appmessage.NewMsgPruningPoints(msgPruningPointHeaders))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Writing the pruning point and its anticone")
+	err = e.exportPruningPointAndItsAnticone()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Writing the pruning point future headers")
+	futureHashes, err := e.exportPruningPointFutureHeaders()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Writing the pruning point UTXO set")
+	err = e.exportPruningPointUTXOSet()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Writing the pruning point future blocks")
+	return e.exportBlockBodies(futureHashes)
+}
+
+func (e *exporter) exportPruningPointAndItsAnticone() error {
+	pointAndItsAnticone, err := e.consensus.PruningPointAndItsAnticone()
+	if err != nil {
+		return err
+	}
+
+	// The trusted data is deduplicated in the same way HandlePruningPointAndItsAnticoneRequests
+	// does, and every block refers to its trusted data by index
+	windowSize := e.params.DifficultyAdjustmentWindowSize
+	daaWindowBlocks := make([]*externalapi.TrustedDataDataDAAHeader, 0, windowSize)
+	daaWindowHashesToIndex := make(map[externalapi.DomainHash]int, windowSize)
+	trustedDataDAABlockIndexes := make(map[externalapi.DomainHash][]uint64)
+
+	ghostdagData := make([]*externalapi.BlockGHOSTDAGDataHashPair, 0)
+	ghostdagDataHashToIndex := make(map[externalapi.DomainHash]int)
+	trustedDataGHOSTDAGDataIndexes := make(map[externalapi.DomainHash][]uint64)
+	for _, blockHash := range pointAndItsAnticone {
+		blockDAAWindowHashes, err := e.consensus.BlockDAAWindowHashes(blockHash)
+		if err != nil {
+			return err
+		}
+
+		trustedDataDAABlockIndexes[*blockHash] = make([]uint64, 0, windowSize)
+		for i, daaBlockHash := range blockDAAWindowHashes {
+			index, exists := daaWindowHashesToIndex[*daaBlockHash]
+			if !exists {
+				trustedDataDataDAAHeader, err := e.consensus.TrustedDataDataDAAHeader(blockHash, daaBlockHash, uint64(i))
+				if err != nil {
+					return err
+				}
+				daaWindowBlocks = append(daaWindowBlocks, trustedDataDataDAAHeader)
+				index = len(daaWindowBlocks) - 1
+				daaWindowHashesToIndex[*daaBlockHash] = index
+			}
+
+			trustedDataDAABlockIndexes[*blockHash] = append(trustedDataDAABlockIndexes[*blockHash], uint64(index))
+		}
+
+		ghostdagDataBlockHashes, err := e.consensus.TrustedBlockAssociatedGHOSTDAGDataBlockHashes(blockHash)
+		if err != nil {
+			return err
+		}
+
+		trustedDataGHOSTDAGDataIndexes[*blockHash] = make([]uint64, 0, e.params.K)
+		for _, ghostdagDataBlockHash := range ghostdagDataBlockHashes {
+			index, exists := ghostdagDataHashToIndex[*ghostdagDataBlockHash]
+			if !exists {
+				data, err := e.consensus.TrustedGHOSTDAGData(ghostdagDataBlockHash)
+				if err != nil {
+					return err
+				}
+				ghostdagData = append(ghostdagData, &externalapi.BlockGHOSTDAGDataHashPair{
+					Hash:         ghostdagDataBlockHash,
+					GHOSTDAGData: data,
+				})
+				index = len(ghostdagData) - 1
+				ghostdagDataHashToIndex[*ghostdagDataBlockHash] = index
+			}
+
+			trustedDataGHOSTDAGDataIndexes[*blockHash] = append(trustedDataGHOSTDAGDataIndexes[*blockHash], uint64(index))
+		}
+	}
+
+	err = e.writer.writeMessage(appmessage.DomainTrustedDataToTrustedData(daaWindowBlocks, ghostdagData))
+	if err != nil {
+		return err
+	}
+
+	for _, blockHash := range pointAndItsAnticone {
+		block, err := e.consensus.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+
+		err = e.writer.writeMessage(appmessage.DomainBlockWithTrustedDataToBlockWithTrustedDataV4(
+			block, trustedDataDAABlockIndexes[*blockHash], trustedDataGHOSTDAGDataIndexes[*blockHash]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.writer.writeMessage(appmessage.NewMsgDoneBlocksWithTrustedData())
+}
+
+// exportPruningPointFutureHeaders writes the headers of the past of the headers
+// selected tip that aren't in the past of the pruning point, and returns their hashes
+func (e *exporter) exportPruningPointFutureHeaders() ([]*externalapi.DomainHash, error) {
+	headersSelectedTip, err := e.consensus.GetHeadersSelectedTip()
+	if err != nil {
+		return nil, err
+	}
+
+	var futureHashes []*externalapi.DomainHash
+	lowHash := e.pruningPoint
+	for !lowHash.Equal(headersSelectedTip) {
+		blockHashes, _, err := e.consensus.GetHashesBetween(lowHash, headersSelectedTip, maxHeadersPerRecord)
+		if err != nil {
+			return nil, err
+		}
+
+		blockHeaders := make([]*appmessage.MsgBlockHeader, len(blockHashes))
+		for i, blockHash := range blockHashes {
+			blockHeader, err := e.consensus.GetBlockHeader(blockHash)
+			if err != nil {
+				return nil, err
+			}
+			blockHeaders[i] = appmessage.DomainBlockHeaderToBlockHeader(blockHeader)
+		}
+		err = e.writer.writeMessage(appmessage.NewBlockHeadersMessage(blockHeaders))
+		if err != nil {
+			return nil, err
+		}
+
+		futureHashes = append(futureHashes, blockHashes...)
+		lowHash = blockHashes[len(blockHashes)-1]
+	}
+
+	log.Infof("Wrote %d pruning point future headers", len(futureHashes))
+	return futureHashes, e.writer.writeMessage(appmessage.NewMsgDoneHeaders())
+}
+
+func (e *exporter) exportPruningPointUTXOSet() error {
+	var fromOutpoint *externalapi.DomainOutpoint
+	utxoCount := 0
+	for {
+		pruningPointUTXOs, err := e.consensus.GetPruningPointUTXOs(e.pruningPoint, fromOutpoint, utxosPerRecord)
+		if err != nil {
+			return err
+		}
+
+		if len(pruningPointUTXOs) > 0 {
+			err = e.writer.writeMessage(appmessage.NewMsgPruningPointUTXOSetChunk(
+				appmessage.DomainOutpointAndUTXOEntryPairsToOutpointAndUTXOEntryPairs(pruningPointUTXOs)))
+			if err != nil {
+				return err
+			}
+			utxoCount += len(pruningPointUTXOs)
+			fromOutpoint = pruningPointUTXOs[len(pruningPointUTXOs)-1].Outpoint
+		}
+
+		if len(pruningPointUTXOs) < utxosPerRecord {
+			break
+		}
+	}
+
+	log.Infof("Wrote %d pruning point UTXOs", utxoCount)
+	return e.writer.writeMessage(appmessage.NewMsgDonePruningPointUTXOSetChunks())
+}
+
+// exportBlockBodies writes the given blocks, skipping the ones the node only has the header of
+func (e *exporter) exportBlockBodies(blockHashes []*externalapi.DomainHash) error {
+	blockCount := 0
+	for _, blockHash := range blockHashes {
+		blockInfo, err := e.consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			return err
+		}
+		if blockInfo.BlockStatus == externalapi.StatusHeaderOnly {
+			continue
+		}
+
+		block, err := e.consensus.GetBlock(blockHash)
+		if err != nil {
+			return err
+		}
+		err = e.writer.writeMessage(appmessage.DomainBlockToMsgBlock(block))
+		if err != nil {
+			return err
+		}
+		blockCount++
+	}
+
+	log.Infof("Wrote %d pruning point future blocks", blockCount)
+	return nil
+}
@@ -0,0 +1,250 @@
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// snapshotFileVersion is the version of the snapshot file format.
+// It is written at the start of the file, and Import refuses to read
+// files of any other version.
+const snapshotFileVersion uint32 = 1
+
+// maxRecordLength is the maximum length of a single record. It matches
+// the maximum size of a P2P message, since every record is one.
+const maxRecordLength = 1024 * 1024 * 1024
+
+// A snapshot file is laid out as follows:
+//
+//	version        uint32
+//	network name   uint32 length followed by the name
+//	records        uint32 length followed by a protowire.KaspadMessage, in this order:
+//	                 MsgPruningPointProof
+//	                 MsgPruningPoints
+//	                 MsgTrustedData
+//	                 MsgBlockWithTrustedDataV4 for the pruning point and its anticone, then MsgDoneBlocksWithTrustedData
+//	                 BlockHeadersMessage for the pruning point future, then MsgDoneHeaders
+//	                 MsgPruningPointUTXOSetChunk for the pruning point UTXO set, then MsgDonePruningPointUTXOSetChunks
+//	                 MsgBlock for the pruning point future blocks that have a body
+//	end marker     a zero record length
+//	checksum       SHA-256 of everything above
+//
+// The records are the same messages a syncer sends during IBD with a
+// headers proof, so they're validated the exact same way on import.
+
+// snapshotWriter writes a snapshot file into a temporary file, and moves
+// it into place once it's complete
+type snapshotWriter struct {
+	filePath string
+	file     *os.File
+	buffered *bufio.Writer
+	hasher   hash.Hash
+	writer   io.Writer
+}
+
+func newSnapshotWriter(filePath string, networkName string) (*snapshotWriter, error) {
+	file, err := os.Create(filePath + ".tmp")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	buffered := bufio.NewWriter(file)
+	hasher := sha256.New()
+	writer := &snapshotWriter{
+		filePath: filePath,
+		file:     file,
+		buffered: buffered,
+		hasher:   hasher,
+		writer:   io.MultiWriter(buffered, hasher),
+	}
+
+	err = binary.Write(writer.writer, binary.LittleEndian, snapshotFileVersion)
+	if err != nil {
+		writer.abort()
+		return nil, errors.WithStack(err)
+	}
+	err = writer.writeBytes([]byte(networkName))
+	if err != nil {
+		writer.abort()
+		return nil, err
+	}
+	return writer, nil
+}
+
+func (sw *snapshotWriter) writeBytes(data []byte) error {
+	err := binary.Write(sw.writer, binary.LittleEndian, uint32(len(data)))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = sw.writer.Write(data)
+	return errors.WithStack(err)
+}
+
+func (sw *snapshotWriter) writeMessage(message appmessage.Message) error {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return err
+	}
+	serializedMessage, err := proto.Marshal(kaspadMessage)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return sw.writeBytes(serializedMessage)
+}
+
+// finish writes the end marker and the checksum, and moves the file into place
+func (sw *snapshotWriter) finish() error {
+	err := binary.Write(sw.writer, binary.LittleEndian, uint32(0))
+	if err != nil {
+		sw.abort()
+		return errors.WithStack(err)
+	}
+	_, err = sw.buffered.Write(sw.hasher.Sum(nil))
+	if err != nil {
+		sw.abort()
+		return errors.WithStack(err)
+	}
+	err = sw.buffered.Flush()
+	if err != nil {
+		sw.abort()
+		return errors.WithStack(err)
+	}
+	err = sw.file.Close()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.Rename(sw.file.Name(), sw.filePath))
+}
+
+// abort removes the partially written file
+func (sw *snapshotWriter) abort() {
+	_ = sw.file.Close()
+	_ = os.Remove(sw.file.Name())
+}
+
+// snapshotReader reads the records of a snapshot file whose checksum was
+// already verified
+type snapshotReader struct {
+	reader io.Reader
+}
+
+func newSnapshotReader(file *os.File, networkName string) (*snapshotReader, error) {
+	err := verifyChecksum(file)
+	if err != nil {
+		return nil, err
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	reader := &snapshotReader{reader: bufio.NewReader(file)}
+	var version uint32
+	err = binary.Read(reader.reader, binary.LittleEndian, &version)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if version != snapshotFileVersion {
+		return nil, errors.Errorf("unsupported snapshot file version %d", version)
+	}
+	snapshotNetworkName, err := reader.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	if string(snapshotNetworkName) != networkName {
+		return nil, errors.Errorf("the snapshot is of network %s while the node runs on %s",
+			snapshotNetworkName, networkName)
+	}
+	return reader, nil
+}
+
+// verifyChecksum makes sure the file is complete and uncorrupted before
+// anything in it is imported
+func verifyChecksum(file *os.File) error {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	contentSize := fileInfo.Size() - sha256.Size
+	if contentSize < 0 {
+		return errors.New("the snapshot file is truncated")
+	}
+
+	hasher := sha256.New()
+	_, err = io.Copy(hasher, io.LimitReader(file, contentSize))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	checksum := make([]byte, sha256.Size)
+	_, err = io.ReadFull(file, checksum)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !bytes.Equal(checksum, hasher.Sum(nil)) {
+		return errors.New("the snapshot file is corrupted or truncated: its checksum doesn't match its content")
+	}
+	return nil
+}
+
+func (sr *snapshotReader) readBytes() ([]byte, error) {
+	var length uint32
+	err := binary.Read(sr.reader, binary.LittleEndian, &length)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if length > maxRecordLength {
+		return nil, errors.Errorf("record of length %d exceeds the maximum of %d", length, maxRecordLength)
+	}
+	data := make([]byte, length)
+	_, err = io.ReadFull(sr.reader, data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
+
+// readMessage returns the next record, or nil once the end marker is reached
+func (sr *snapshotReader) readMessage() (appmessage.Message, error) {
+	serializedMessage, err := sr.readBytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(serializedMessage) == 0 {
+		return nil, nil
+	}
+
+	kaspadMessage := &protowire.KaspadMessage{}
+	err = proto.Unmarshal(serializedMessage, kaspadMessage)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return kaspadMessage.ToAppMessage()
+}
+
+// readExpectedMessage returns the next record, and fails if it isn't of the given command
+func (sr *snapshotReader) readExpectedMessage(command appmessage.MessageCommand) (appmessage.Message, error) {
+	message, err := sr.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	if message == nil || message.Command() != command {
+		return nil, unexpectedMessageError(message, command)
+	}
+	return message, nil
+}
+
+func unexpectedMessageError(message appmessage.Message, expectedCommands ...appmessage.MessageCommand) error {
+	if message == nil {
+		return errors.Errorf("unexpected end of snapshot. expected: %s", expectedCommands)
+	}
+	return errors.Errorf("unexpected record in snapshot. expected: %s, got: %s", expectedCommands, message.Command())
+}
@@ -0,0 +1,415 @@
+package snapshot
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+// Import bootstraps the given domain from the snapshot file at the given path.
+//
+// The snapshot goes through the same validation as the data received during
+// IBD with a headers proof: the pruning point proof is validated against the
+// current consensus, and everything up to the pruning point UTXO set is
+// inserted into a staging consensus that is committed only once
+// ValidateAndInsertImportedPruningPoint succeeds. The pruning point future
+// blocks are then inserted into the committed consensus.
+//
+// If the node is already at the pruning point of the snapshot, only the
+// blocks it's missing are inserted, so it's safe to import the same snapshot
+// on every startup.
+func Import(domain domain.Domain, params *dagconfig.Params, filePath string) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "snapshot.Import")
+	defer onEnd()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+
+	log.Infof("Verifying the checksum of snapshot %s", filePath)
+	reader, err := newSnapshotReader(file, params.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read snapshot %s", filePath)
+	}
+
+	importer := &importer{
+		domain: domain,
+		params: params,
+		reader: reader,
+	}
+	err = importer.importSnapshot()
+	if err != nil {
+		return errors.Wrapf(err, "failed to import snapshot %s", filePath)
+	}
+	return nil
+}
+
+type importer struct {
+	domain domain.Domain
+	params *dagconfig.Params
+	reader *snapshotReader
+}
+
+func (i *importer) importSnapshot() error {
+	message, err := i.reader.readExpectedMessage(appmessage.CmdPruningPointProof)
+	if err != nil {
+		return err
+	}
+	pruningPointProof := appmessage.MsgPruningPointProofToDomainPruningPointProof(message.(*appmessage.MsgPruningPointProof))
+	if len(pruningPointProof.Headers) == 0 || len(pruningPointProof.Headers[0]) == 0 {
+		return errors.New("the pruning point proof is empty")
+	}
+	proofPruningPoint := consensushashing.HeaderHash(pruningPointProof.Headers[0][len(pruningPointProof.Headers[0])-1])
+
+	currentPruningPoint, err := i.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+	if currentPruningPoint.Equal(proofPruningPoint) {
+		log.Infof("The node is already at the snapshot pruning point %s. Only inserting the blocks it's missing",
+			proofPruningPoint)
+		err = i.skipPruningPointData()
+		if err != nil {
+			return err
+		}
+	} else {
+		err = i.importPruningPoint(pruningPointProof, proofPruningPoint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return i.importBlocks()
+}
+
+// importPruningPoint imports everything up to the pruning point UTXO set into
+// a staging consensus, and replaces the current consensus with it if it's valid
+func (i *importer) importPruningPoint(
+	pruningPointProof *externalapi.PruningPointProof, proofPruningPoint *externalapi.DomainHash) error {
+
+	err := i.domain.InitStagingConsensusWithoutGenesis()
+	if err != nil {
+		return err
+	}
+
+	err = i.importIntoStagingConsensus(pruningPointProof, proofPruningPoint)
+	if err != nil {
+		log.Infof("Importing the snapshot was unsuccessful. Deleting the staging consensus.")
+		deleteStagingConsensusErr := i.domain.DeleteStagingConsensus()
+		if deleteStagingConsensusErr != nil {
+			return deleteStagingConsensusErr
+		}
+		return err
+	}
+
+	log.Infof("Imported pruning point %s. Committing the staging consensus and deleting the previous one",
+		proofPruningPoint)
+	return i.domain.CommitStagingConsensus()
+}
+
+func (i *importer) importIntoStagingConsensus(
+	pruningPointProof *externalapi.PruningPointProof, proofPruningPoint *externalapi.DomainHash) error {
+
+	log.Infof("Validating the pruning point proof")
+	err := i.domain.Consensus().ValidatePruningPointProof(pruningPointProof)
+	if err != nil {
+		return errors.Wrapf(err, "pruning point proof validation failed")
+	}
+	err = i.domain.StagingConsensus().ApplyPruningPointProof(pruningPointProof)
+	if err != nil {
+		return err
+	}
+
+	// See the matching check in downloadHeadersAndPruningUTXOSet
+	if proofPruningPoint.Equal(i.params.GenesisHash) {
+		return errors.New("the genesis pruning point violates finality")
+	}
+
+	log.Infof("Importing the past pruning points")
+	err = i.importPruningPoints(proofPruningPoint)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing the pruning point and its anticone")
+	err = i.importPruningPointAndItsAnticone(proofPruningPoint)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Importing the pruning point future headers")
+	err = i.importPruningPointFutureHeaders()
+	if err != nil {
+		return err
+	}
+
+	isValid, err := i.domain.StagingConsensus().IsValidPruningPoint(proofPruningPoint)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return errors.Errorf("invalid pruning point %s", proofPruningPoint)
+	}
+
+	log.Infof("Importing the pruning point UTXO set")
+	return i.importPruningPointUTXOSet(proofPruningPoint)
+}
+
+func (i *importer) importPruningPoints(proofPruningPoint *externalapi.DomainHash) error {
+	message, err := i.reader.readExpectedMessage(appmessage.CmdPruningPoints)
+	if err != nil {
+		return err
+	}
+	msgPruningPoints := message.(*appmessage.MsgPruningPoints)
+	if len(msgPruningPoints.Headers) == 0 {
+		return errors.New("the snapshot has no pruning points")
+	}
+
+	headers := make([]externalapi.BlockHeader, len(msgPruningPoints.Headers))
+	for i, header := range msgPruningPoints.Headers {
+		headers[i] = appmessage.BlockHeaderToDomainBlockHeader(header)
+	}
+
+	arePruningPointsViolatingFinality, err := i.domain.Consensus().ArePruningPointsViolatingFinality(headers)
+	if err != nil {
+		return err
+	}
+	if arePruningPointsViolatingFinality {
+		return errors.New("the pruning points are violating finality")
+	}
+
+	lastPruningPoint := consensushashing.HeaderHash(headers[len(headers)-1])
+	if !lastPruningPoint.Equal(proofPruningPoint) {
+		return errors.New("the proof pruning point is not equal to the last pruning point in the list")
+	}
+
+	return i.domain.StagingConsensus().ImportPruningPoints(headers)
+}
+
+func (i *importer) importPruningPointAndItsAnticone(proofPruningPoint *externalapi.DomainHash) error {
+	message, err := i.reader.readExpectedMessage(appmessage.CmdTrustedData)
+	if err != nil {
+		return err
+	}
+	msgTrustedData := message.(*appmessage.MsgTrustedData)
+
+	for blockCount := 0; ; blockCount++ {
+		message, err := i.reader.readMessage()
+		if err != nil {
+			return err
+		}
+
+		switch message := message.(type) {
+		case *appmessage.MsgBlockWithTrustedDataV4:
+			block := appmessage.MsgBlockToDomainBlock(message.Block)
+			if blockCount == 0 && !consensushashing.BlockHash(block).Equal(proofPruningPoint) {
+				return errors.New("the first block with trusted data is not the pruning point")
+			}
+			err := i.processBlockWithTrustedData(block, message, msgTrustedData)
+			if err != nil {
+				return err
+			}
+		case *appmessage.MsgDoneBlocksWithTrustedData:
+			if blockCount == 0 {
+				return errors.New("the snapshot doesn't contain the pruning point")
+			}
+			log.Infof("Imported the pruning point and %d blocks in its anticone", blockCount-1)
+			return nil
+		default:
+			return unexpectedMessageError(message, appmessage.CmdBlockWithTrustedDataV4, appmessage.CmdDoneBlocksWithTrustedData)
+		}
+	}
+}
+
+func (i *importer) processBlockWithTrustedData(block *externalapi.DomainBlock,
+	message *appmessage.MsgBlockWithTrustedDataV4, msgTrustedData *appmessage.MsgTrustedData) error {
+
+	blockWithTrustedData := &externalapi.BlockWithTrustedData{
+		Block:        block,
+		DAAWindow:    make([]*externalapi.TrustedDataDataDAAHeader, 0, len(message.DAAWindowIndices)),
+		GHOSTDAGData: make([]*externalapi.BlockGHOSTDAGDataHashPair, 0, len(message.GHOSTDAGDataIndices)),
+	}
+
+	for _, index := range message.DAAWindowIndices {
+		if index >= uint64(len(msgTrustedData.DAAWindow)) {
+			return errors.Errorf("DAA window index %d is out of range", index)
+		}
+		blockWithTrustedData.DAAWindow = append(blockWithTrustedData.DAAWindow,
+			appmessage.TrustedDataDataDAABlockV4ToTrustedDataDataDAAHeader(msgTrustedData.DAAWindow[index]))
+	}
+
+	for _, index := range message.GHOSTDAGDataIndices {
+		if index >= uint64(len(msgTrustedData.GHOSTDAGData)) {
+			return errors.Errorf("GHOSTDAG data index %d is out of range", index)
+		}
+		blockWithTrustedData.GHOSTDAGData = append(blockWithTrustedData.GHOSTDAGData,
+			appmessage.GHOSTDAGHashPairToDomainGHOSTDAGHashPair(msgTrustedData.GHOSTDAGData[index]))
+	}
+
+	_, err := i.domain.StagingConsensus().ValidateAndInsertBlockWithTrustedData(blockWithTrustedData, false)
+	return err
+}
+
+func (i *importer) importPruningPointFutureHeaders() error {
+	headerCount := 0
+	for {
+		message, err := i.reader.readMessage()
+		if err != nil {
+			return err
+		}
+
+		switch message := message.(type) {
+		case *appmessage.BlockHeadersMessage:
+			for _, msgBlockHeader := range message.BlockHeaders {
+				err := i.processHeader(msgBlockHeader)
+				if err != nil {
+					return err
+				}
+			}
+			headerCount += len(message.BlockHeaders)
+			log.Infof("Imported %d pruning point future headers", headerCount)
+		case *appmessage.MsgDoneHeaders:
+			return nil
+		default:
+			return unexpectedMessageError(message, appmessage.CmdBlockHeaders, appmessage.CmdDoneHeaders)
+		}
+	}
+}
+
+func (i *importer) processHeader(msgBlockHeader *appmessage.MsgBlockHeader) error {
+	block := &externalapi.DomainBlock{
+		Header:       appmessage.BlockHeaderToDomainBlockHeader(msgBlockHeader),
+		Transactions: nil,
+	}
+
+	blockHash := consensushashing.BlockHash(block)
+	blockInfo, err := i.domain.StagingConsensus().GetBlockInfo(blockHash)
+	if err != nil {
+		return err
+	}
+	if blockInfo.Exists {
+		return nil
+	}
+
+	_, err = i.domain.StagingConsensus().ValidateAndInsertBlock(block, false)
+	if err != nil {
+		if errors.Is(err, ruleerrors.ErrDuplicateBlock) {
+			return nil
+		}
+		return errors.Wrapf(err, "invalid block header %s", blockHash)
+	}
+	return nil
+}
+
+func (i *importer) importPruningPointUTXOSet(pruningPoint *externalapi.DomainHash) error {
+	defer func() {
+		err := i.domain.StagingConsensus().ClearImportedPruningPointData()
+		if err != nil {
+			panic(fmt.Sprintf("failed to clear imported pruning point data: %s", err))
+		}
+	}()
+
+	utxoCount := 0
+	for {
+		message, err := i.reader.readMessage()
+		if err != nil {
+			return err
+		}
+
+		switch message := message.(type) {
+		case *appmessage.MsgPruningPointUTXOSetChunk:
+			err := i.domain.StagingConsensus().AppendImportedPruningPointUTXOs(
+				appmessage.OutpointAndUTXOEntryPairsToDomainOutpointAndUTXOEntryPairs(message.OutpointAndUTXOEntryPairs))
+			if err != nil {
+				return err
+			}
+			utxoCount += len(message.OutpointAndUTXOEntryPairs)
+		case *appmessage.MsgDonePruningPointUTXOSetChunks:
+			log.Infof("Read %d pruning point UTXOs. Validating them against the pruning point UTXO commitment",
+				utxoCount)
+			return i.domain.StagingConsensus().ValidateAndInsertImportedPruningPoint(pruningPoint)
+		default:
+			return unexpectedMessageError(message, appmessage.CmdPruningPointUTXOSetChunk,
+				appmessage.CmdDonePruningPointUTXOSetChunks)
+		}
+	}
+}
+
+// skipPruningPointData reads past everything up to the pruning point future blocks
+func (i *importer) skipPruningPointData() error {
+	for _, endCommand := range []appmessage.MessageCommand{
+		appmessage.CmdPruningPoints,
+		appmessage.CmdDoneBlocksWithTrustedData,
+		appmessage.CmdDoneHeaders,
+		appmessage.CmdDonePruningPointUTXOSetChunks,
+	} {
+		for {
+			message, err := i.reader.readMessage()
+			if err != nil {
+				return err
+			}
+			if message == nil {
+				return unexpectedMessageError(message, endCommand)
+			}
+			if message.Command() == endCommand {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// importBlocks inserts the pruning point future blocks into the current
+// consensus the same way IBD inserts block bodies, and resolves the virtual
+func (i *importer) importBlocks() error {
+	insertedBlockCount := 0
+	for {
+		message, err := i.reader.readMessage()
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			break
+		}
+		msgBlock, ok := message.(*appmessage.MsgBlock)
+		if !ok {
+			return unexpectedMessageError(message, appmessage.CmdBlock)
+		}
+
+		block := appmessage.MsgBlockToDomainBlock(msgBlock)
+		_, err = i.domain.Consensus().ValidateAndInsertBlock(block, false)
+		if err != nil {
+			if errors.Is(err, ruleerrors.ErrDuplicateBlock) {
+				continue
+			}
+			return errors.Wrapf(err, "invalid block %s", consensushashing.BlockHash(block))
+		}
+
+		insertedBlockCount++
+		if insertedBlockCount%1000 == 0 {
+			log.Infof("Inserted %d pruning point future blocks", insertedBlockCount)
+		}
+	}
+	log.Infof("Inserted %d pruning point future blocks", insertedBlockCount)
+
+	log.Infof("Resolving the virtual")
+	for {
+		_, isCompletelyResolved, err := i.domain.Consensus().ResolveVirtual()
+		if err != nil {
+			return err
+		}
+		if isCompletelyResolved {
+			return nil
+		}
+	}
+}
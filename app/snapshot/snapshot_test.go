@@ -0,0 +1,145 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+)
+
+func newTestDomain(t *testing.T, consensusConfig *consensus.Config, dataDir string) domain.Domain {
+	db, err := ldb.NewLevelDB(dataDir, 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %+v", err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	domainInstance, err := domain.New(consensusConfig, mempool.DefaultConfig(&consensusConfig.Params), db)
+	if err != nil {
+		t.Fatalf("New: %+v", err)
+	}
+	return domainInstance
+}
+
+func TestExportAndImport(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	consensusConfig.SkipProofOfWork = true
+
+	// This is done to reduce the pruning depth to 6 blocks
+	finalityDepth := 5
+	consensusConfig.FinalityDuration = time.Duration(finalityDepth) * consensusConfig.TargetTimePerBlock
+	consensusConfig.K = 0
+	consensusConfig.PruningProofM = 1
+
+	dataDir := t.TempDir()
+	source := newTestDomain(t, consensusConfig, filepath.Join(dataDir, "source"))
+	destination := newTestDomain(t, consensusConfig, filepath.Join(dataDir, "destination"))
+	snapshotPath := filepath.Join(dataDir, "snapshot")
+
+	err := Export(source.Consensus(), &consensusConfig.Params, snapshotPath)
+	if err == nil {
+		t.Fatalf("Export: expected an error when the pruning point is the genesis")
+	}
+
+	coinbaseData := &externalapi.DomainCoinbaseData{
+		ScriptPublicKey: &externalapi.ScriptPublicKey{},
+		ExtraData:       []byte{},
+	}
+	for i := 0; i < 20; i++ {
+		block, err := source.Consensus().BuildBlock(coinbaseData, nil)
+		if err != nil {
+			t.Fatalf("BuildBlock: %+v", err)
+		}
+		_, err = source.Consensus().ValidateAndInsertBlock(block, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+	}
+
+	err = Export(source.Consensus(), &consensusConfig.Params, snapshotPath)
+	if err != nil {
+		t.Fatalf("Export: %+v", err)
+	}
+	if _, err := os.Stat(snapshotPath + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("Export left its temporary file behind")
+	}
+
+	// Importing twice makes sure a node that's already at the snapshot
+	// pruning point can import it again
+	for i := 0; i < 2; i++ {
+		err = Import(destination, &consensusConfig.Params, snapshotPath)
+		if err != nil {
+			t.Fatalf("Import: %+v", err)
+		}
+	}
+
+	sourcePruningPoint, err := source.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	destinationPruningPoint, err := destination.Consensus().PruningPoint()
+	if err != nil {
+		t.Fatalf("PruningPoint: %+v", err)
+	}
+	if !destinationPruningPoint.Equal(sourcePruningPoint) {
+		t.Fatalf("expected pruning point %s, got %s", sourcePruningPoint, destinationPruningPoint)
+	}
+
+	sourceVirtualInfo, err := source.Consensus().GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	destinationVirtualInfo, err := destination.Consensus().GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	if !externalapi.HashesEqual(sourceVirtualInfo.ParentHashes, destinationVirtualInfo.ParentHashes) {
+		t.Fatalf("expected virtual parents %s, got %s",
+			sourceVirtualInfo.ParentHashes, destinationVirtualInfo.ParentHashes)
+	}
+}
+
+func TestImportInvalidSnapshot(t *testing.T) {
+	consensusConfig := &consensus.Config{Params: dagconfig.SimnetParams}
+	dataDir := t.TempDir()
+	destination := newTestDomain(t, consensusConfig, filepath.Join(dataDir, "destination"))
+	snapshotPath := filepath.Join(dataDir, "snapshot")
+
+	writer, err := newSnapshotWriter(snapshotPath, consensusConfig.Name)
+	if err != nil {
+		t.Fatalf("newSnapshotWriter: %+v", err)
+	}
+	err = writer.finish()
+	if err != nil {
+		t.Fatalf("finish: %+v", err)
+	}
+
+	err = Import(destination, &dagconfig.MainnetParams, snapshotPath)
+	if err == nil {
+		t.Fatalf("Import: expected an error for a snapshot of another network")
+	}
+
+	content, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("ReadFile: %+v", err)
+	}
+	content[len(content)-1] ^= 1
+	err = os.WriteFile(snapshotPath, content, 0600)
+	if err != nil {
+		t.Fatalf("WriteFile: %+v", err)
+	}
+
+	err = Import(destination, &consensusConfig.Params, snapshotPath)
+	if err == nil {
+		t.Fatalf("Import: expected an error for a corrupted snapshot")
+	}
+}
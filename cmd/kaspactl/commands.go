@@ -32,9 +32,20 @@ var commandTypes = []reflect.Type{
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntryRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntriesRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionReplacementRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetFeeEstimateRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_DebugTransactionScriptsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_SaveMempoolRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_LoadMempoolRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_SetLogLevelRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetLogLevelsRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetUtxosByAddressesRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetBalanceByAddressRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetAddressHistoryRequest{}),
+
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionAcceptanceRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_BanRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_UnbanRequest{}),
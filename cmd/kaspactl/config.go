@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/jessevdk/go-flags"
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 	"github.com/pkg/errors"
 )
 
@@ -18,6 +19,7 @@ type configFlags struct {
 	ListCommands                       bool   `short:"l" long:"list-commands" description:"List all commands and exit"`
 	AllowConnectionToDifferentVersions bool   `short:"a" long:"allow-connection-to-different-versions" description:"Allow connections to versions different than kaspactl's version'"`
 	CommandAndParameters               []string
+	grpcclient.ConnectOptions
 	config.NetworkFlags
 }
 
@@ -28,7 +28,7 @@ func main() {
 	if err != nil {
 		printErrorAndExit(fmt.Sprintf("error parsing RPC server address: %s", err))
 	}
-	client, err := grpcclient.Connect(rpcAddress)
+	client, err := grpcclient.ConnectWithOptions(rpcAddress, &cfg.ConnectOptions)
 	if err != nil {
 		printErrorAndExit(fmt.Sprintf("error connecting to the RPC server: %s", err))
 	}
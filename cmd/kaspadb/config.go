@@ -0,0 +1,136 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	migrateSubCmd  = "migrate"
+	statsSubCmd    = "stats"
+	verifySubCmd   = "verify"
+	rollbackSubCmd = "rollback"
+)
+
+const (
+	// defaultDataDirname is the name of the database directory inside the
+	// network directory of kaspad's appdir
+	defaultDataDirname = "datadir2"
+
+	defaultBatchSize = 10_000
+)
+
+// dataDirFlags locate the database of a kaspad node
+type dataDirFlags struct {
+	AppDir string `long:"appdir" short:"b" description:"Directory that kaspad stores its data in (default: ~/.kaspad)"`
+	config.NetworkFlags
+}
+
+// databasePath returns the path of the database directory the same way kaspad does
+func (dataDirFlags *dataDirFlags) databasePath() string {
+	return filepath.Join(dataDirFlags.AppDir, dataDirFlags.NetParams().Name, defaultDataDirname)
+}
+
+type migrateConfig struct {
+	DbType    string `long:"dbtype" description:"Database backend to migrate the database to {leveldb, bbolt}" required:"true"`
+	Output    string `long:"output" short:"o" description:"Directory to write the migrated database to (default: the database directory suffixed with the new backend, e.g. datadir2-bbolt)"`
+	Replace   bool   `long:"replace" description:"Once the migration is done, rename the original database directory to <datadir>-<old backend>-backup and move the migrated database in its place"`
+	BatchSize int    `long:"batch-size" description:"Number of keys to write in every database transaction"`
+	dataDirFlags
+}
+
+type statsConfig struct {
+	dataDirFlags
+}
+
+type verifyConfig struct {
+	dataDirFlags
+}
+
+type rollbackConfig struct {
+	To       string `long:"to" description:"Hash of the virtual selected parent chain block to roll the virtual back to" required:"true"`
+	Archival bool   `long:"archival" description:"Set if the node is an archival node (kaspad's --archival)"`
+	dataDirFlags
+}
+
+func parseCommandLine() (subCommand string, config interface{}) {
+	cfg := &struct{}{}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+
+	migrateConf := &migrateConfig{BatchSize: defaultBatchSize}
+	parser.AddCommand(migrateSubCmd, "Migrates a database to another backend",
+		"Copies all the data of a stopped node's database into a new database of another backend", migrateConf)
+
+	statsConf := &statsConfig{}
+	parser.AddCommand(statsSubCmd, "Prints the size of every store in a database",
+		"Opens a stopped node's database read-only and prints the number of keys and bytes of every store in it", statsConf)
+
+	verifyConf := &verifyConfig{}
+	parser.AddCommand(verifySubCmd, "Verifies the consistency of a database",
+		"Opens a stopped node's database read-only and verifies that its consensus stores are consistent, "+
+			"e.g. that the UTXO sets match the multiset commitments of their blocks", verifyConf)
+
+	rollbackConf := &rollbackConfig{}
+	parser.AddCommand(rollbackSubCmd, "Rolls the virtual state back to an earlier chain block",
+		"Rolls the virtual state of a stopped node's database back to a block in its virtual selected parent chain. "+
+			"The blocks above it are verified again once kaspad starts", rollbackConf)
+
+	_, err := parser.Parse()
+
+	if err != nil {
+		var flagsErr *flags.Error
+		if ok := errors.As(err, &flagsErr); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+		return "", nil
+	}
+
+	switch parser.Command.Active.Name {
+	case migrateSubCmd:
+		err := migrateConf.resolve(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = migrateConf
+	case statsSubCmd:
+		err := statsConf.resolve(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = statsConf
+	case verifySubCmd:
+		err := verifyConf.resolve(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = verifyConf
+	case rollbackSubCmd:
+		err := rollbackConf.resolve(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = rollbackConf
+	}
+
+	return parser.Command.Active.Name, config
+}
+
+func (dataDirFlags *dataDirFlags) resolve(parser *flags.Parser) error {
+	if dataDirFlags.AppDir == "" {
+		dataDirFlags.AppDir = config.DefaultAppDir
+	}
+	return dataDirFlags.ResolveNetwork(parser)
+}
+
+func (migrateConf *migrateConfig) resolve(parser *flags.Parser) error {
+	if migrateConf.BatchSize <= 0 {
+		return errors.Errorf("--batch-size must be positive")
+	}
+	return migrateConf.dataDirFlags.resolve(parser)
+}
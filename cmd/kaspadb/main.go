@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+func main() {
+	subCmd, config := parseCommandLine()
+
+	var err error
+	switch subCmd {
+	case migrateSubCmd:
+		err = migrate(config.(*migrateConfig))
+	case statsSubCmd:
+		err = stats(config.(*statsConfig))
+	case verifySubCmd:
+		err = verify(config.(*verifyConfig))
+	case rollbackSubCmd:
+		err = rollback(config.(*rollbackConfig))
+	default:
+		err = errors.Errorf("Unknown sub-command '%s'\n", subCmd)
+	}
+
+	if err != nil {
+		printErrorAndExit(err)
+	}
+}
+
+func printErrorAndExit(err error) {
+	fmt.Fprintf(os.Stderr, "%s\n", err)
+	os.Exit(1)
+}
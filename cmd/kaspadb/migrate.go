@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
+	"github.com/pkg/errors"
+)
+
+const (
+	// cacheSizeMiB is the leveldb cache size the databases are opened with
+	cacheSizeMiB = 256
+
+	// versionFileName is the file kaspad keeps the database version in
+	versionFileName = "version"
+)
+
+func migrate(conf *migrateConfig) error {
+	if !backend.IsValidType(conf.DbType) {
+		return errors.Errorf("unknown database type %s", conf.DbType)
+	}
+
+	sourcePath := conf.databasePath()
+	sourceType, err := backend.Detect(sourcePath)
+	if err != nil {
+		return err
+	}
+	if sourceType == "" {
+		return errors.Errorf("there's no database in %s", sourcePath)
+	}
+	if sourceType == conf.DbType {
+		return errors.Errorf("the database in %s is already a %s database", sourcePath, sourceType)
+	}
+
+	destinationPath := conf.Output
+	if destinationPath == "" {
+		destinationPath = sourcePath + "-" + conf.DbType
+	}
+	err = ensureEmptyDirectory(destinationPath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Migrating the %s database in %s to a %s database in %s\n",
+		sourceType, sourcePath, conf.DbType, destinationPath)
+	keyCount, err := copyDatabase(sourceType, sourcePath, conf.DbType, destinationPath, conf.BatchSize)
+	if err != nil {
+		return err
+	}
+	err = copyVersionFile(sourcePath, destinationPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Migrated %d keys\n", keyCount)
+
+	if !conf.Replace {
+		fmt.Printf("Replace %s with %s in order to start kaspad with the migrated database\n",
+			sourcePath, destinationPath)
+		return nil
+	}
+
+	backupPath := sourcePath + "-" + sourceType + "-backup"
+	err = os.Rename(sourcePath, backupPath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = os.Rename(destinationPath, sourcePath)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	fmt.Printf("Moved the migrated database to %s. The original database was moved to %s\n",
+		sourcePath, backupPath)
+	return nil
+}
+
+// ensureEmptyDirectory makes sure that the given path is either an empty
+// directory or doesn't exist, so that a migration never overwrites data
+func ensureEmptyDirectory(path string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	if len(entries) > 0 {
+		return errors.Errorf("the directory %s is not empty", path)
+	}
+	return nil
+}
+
+// copyDatabase copies all the keys of the source database into the
+// destination database, committing a transaction every batchSize keys.
+// It returns the number of keys that were copied.
+func copyDatabase(sourceType, sourcePath, destinationType, destinationPath string, batchSize int) (keyCount int, err error) {
+	source, err := backend.Open(sourceType, sourcePath, cacheSizeMiB, false)
+	if err != nil {
+		return 0, err
+	}
+	defer closeDatabase(source, &err)
+
+	destination, err := backend.Open(destinationType, destinationPath, cacheSizeMiB, false)
+	if err != nil {
+		return 0, err
+	}
+	defer closeDatabase(destination, &err)
+
+	cursor, err := source.Cursor(database.MakeBucket(nil))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close()
+
+	transaction, err := destination.Begin()
+	if err != nil {
+		return 0, err
+	}
+	// transaction is replaced after every batch, so it's evaluated when the function returns
+	defer func() { transaction.RollbackUnlessClosed() }()
+
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return 0, err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return 0, err
+		}
+		err = transaction.Put(key, value)
+		if err != nil {
+			return 0, err
+		}
+		keyCount++
+
+		if keyCount%batchSize == 0 {
+			err = transaction.Commit()
+			if err != nil {
+				return 0, err
+			}
+			fmt.Printf("Copied %d keys\n", keyCount)
+			transaction, err = destination.Begin()
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+	err = transaction.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return keyCount, nil
+}
+
+func closeDatabase(db database.Database, err *error) {
+	closeErr := db.Close()
+	if *err == nil {
+		*err = closeErr
+	}
+}
+
+func copyVersionFile(sourcePath, destinationPath string) error {
+	versionBytes, err := os.ReadFile(filepath.Join(sourcePath, versionFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(os.WriteFile(filepath.Join(destinationPath, versionFileName), versionBytes, 0600))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/prefixmanager"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
+	"github.com/pkg/errors"
+)
+
+func rollback(conf *rollbackConfig) (err error) {
+	chainBlockHash, err := externalapi.NewDomainHashFromString(conf.To)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse --to")
+	}
+
+	databasePath := conf.databasePath()
+	dbType, err := backend.Detect(databasePath)
+	if err != nil {
+		return err
+	}
+	if dbType == "" {
+		return errors.Errorf("there's no database in %s", databasePath)
+	}
+	db, err := backend.Open(dbType, databasePath, cacheSizeMiB, false)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, &err)
+
+	activePrefix, hasActivePrefix, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return err
+	}
+	if !hasActivePrefix {
+		return errors.Errorf("the database in %s has no active consensus", databasePath)
+	}
+
+	consensusFactory := consensus.NewFactory()
+	consensusFactory.SetTestPreAllocateCache(false)
+	consensusConfig := &consensus.Config{
+		Params:     *conf.NetParams(),
+		IsArchival: conf.Archival,
+	}
+	consensusInstance, shouldMigrate, err := consensusFactory.NewConsensus(consensusConfig, db, activePrefix)
+	if err != nil {
+		return err
+	}
+	if shouldMigrate {
+		return errors.Errorf("the database in %s requires a migration. Start kaspad once in order to migrate it",
+			databasePath)
+	}
+
+	fmt.Printf("Rolling the virtual back to chain block %s\n", chainBlockHash)
+	virtualChangeSet, err := consensusInstance.RollbackVirtual(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	removedChainBlocks := virtualChangeSet.VirtualSelectedParentChainChanges.Removed
+	fmt.Printf("Removed %d blocks from the virtual selected parent chain:\n", len(removedChainBlocks))
+	for _, removedChainBlock := range removedChainBlocks {
+		fmt.Printf("  %s\n", removedChainBlock)
+	}
+	fmt.Println("The removed blocks are kept and will be verified again once kaspad starts. " +
+		"The UTXO, transaction and address indexes will be rebuilt if they are enabled.")
+	return nil
+}
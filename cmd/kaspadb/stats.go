@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/kaspanet/kaspad/domain/prefixmanager"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
+)
+
+// bucketSeparator is the byte that separates bucket names in database keys
+const bucketSeparator = '/'
+
+// storeStats accumulates the size of a single store
+type storeStats struct {
+	name       string
+	keyCount   uint64
+	keyBytes   uint64
+	valueBytes uint64
+}
+
+func stats(conf *statsConfig) (err error) {
+	databasePath := conf.databasePath()
+	db, err := backend.OpenReadOnly(databasePath, cacheSizeMiB)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, &err)
+
+	activePrefix, hasActivePrefix, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return err
+	}
+	var activePrefixByte byte
+	if hasActivePrefix {
+		activePrefixByte = activePrefix.Serialize()[0]
+	}
+
+	allStats := make(map[string]*storeStats)
+	cursor, err := db.Cursor(database.MakeBucket(nil))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+
+		keyBytes := key.Bytes()
+		name := storeName(keyBytes, hasActivePrefix, activePrefixByte)
+		statsOfStore, ok := allStats[name]
+		if !ok {
+			statsOfStore = &storeStats{name: name}
+			allStats[name] = statsOfStore
+		}
+		statsOfStore.keyCount++
+		statsOfStore.keyBytes += uint64(len(keyBytes))
+		statsOfStore.valueBytes += uint64(len(value))
+	}
+
+	sortedStats := make([]*storeStats, 0, len(allStats))
+	for _, statsOfStore := range allStats {
+		sortedStats = append(sortedStats, statsOfStore)
+	}
+	sort.Slice(sortedStats, func(i, j int) bool {
+		return sortedStats[i].name < sortedStats[j].name
+	})
+
+	fmt.Printf("Database: %s\n", databasePath)
+	if hasActivePrefix {
+		fmt.Printf("Active prefix: %d\n", activePrefixByte)
+	} else {
+		fmt.Printf("Active prefix: none\n")
+	}
+	fmt.Println()
+
+	var total storeStats
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "Store\tKeys\tKey bytes\tValue bytes\t\n")
+	for _, statsOfStore := range sortedStats {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t\n",
+			statsOfStore.name, statsOfStore.keyCount, statsOfStore.keyBytes, statsOfStore.valueBytes)
+		total.keyCount += statsOfStore.keyCount
+		total.keyBytes += statsOfStore.keyBytes
+		total.valueBytes += statsOfStore.valueBytes
+	}
+	fmt.Fprintf(writer, "Total\t%d\t%d\t%d\t\n", total.keyCount, total.keyBytes, total.valueBytes)
+	return writer.Flush()
+}
+
+// storeName returns the name of the store the given key belongs to.
+//
+// Consensus keys are laid out as <prefix>/<store>/<suffix> or, for stores
+// that are kept per block level, as <prefix>/<level>/<store>/<suffix>.
+// Singleton keys (e.g. the virtual tips) have no suffix. Keys outside the
+// consensus prefixes belong to the indexes and the prefix manager.
+//
+// Stores of all the block levels above 0 are reported together, since most
+// of them only hold a handful of blocks.
+func storeName(key []byte, hasActivePrefix bool, activePrefixByte byte) string {
+	if len(key) >= 2 && key[1] == bucketSeparator && (key[0] == 0 || key[0] == 1) {
+		prefixDescription := "inactive"
+		if hasActivePrefix && key[0] == activePrefixByte {
+			prefixDescription = "active"
+		}
+
+		remainder := key[2:]
+		level, remainder, isPerLevel := splitBlockLevel(remainder)
+		if isPerLevel && level > 0 {
+			return fmt.Sprintf("%s: %s (levels above 0)", prefixDescription, firstComponent(remainder))
+		}
+		return fmt.Sprintf("%s: %s", prefixDescription, firstComponent(remainder))
+	}
+
+	return firstComponent(key)
+}
+
+// splitBlockLevel splits the block level off a key of a per-level store,
+// and returns whether the key belongs to such a store at all
+func splitBlockLevel(key []byte) (level byte, remainder []byte, isPerLevel bool) {
+	// The separator isn't repeated when the level itself is the separator byte
+	if len(key) >= 1 && key[0] == bucketSeparator {
+		return bucketSeparator, key[1:], true
+	}
+	if len(key) >= 2 && key[1] == bucketSeparator {
+		return key[0], key[2:], true
+	}
+	return 0, key, false
+}
+
+// firstComponent returns the bucket name at the start of the given key, or
+// the whole key if it isn't inside a bucket
+func firstComponent(key []byte) string {
+	component := key
+	separatorIndex := bytes.IndexByte(key, bucketSeparator)
+	if separatorIndex >= 0 {
+		component = key[:separatorIndex]
+	}
+	for _, b := range component {
+		if b > unicode.MaxASCII || !unicode.IsPrint(rune(b)) {
+			return fmt.Sprintf("%x", component)
+		}
+	}
+	return string(component)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"fmt"
+
+	consensusdatabase "github.com/kaspanet/kaspad/domain/consensus/database"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/blockheaderstore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/blockstatusstore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/consensusstatestore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/ghostdagdatastore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/headersselectedtipstore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/pruningstore"
+	"github.com/kaspanet/kaspad/domain/consensus/datastructures/utxodiffstore"
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/multiset"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/prefixmanager"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
+	"github.com/pkg/errors"
+)
+
+// storeCacheSize is the cache size of the stores the verifier reads from.
+// Every block is read only a handful of times, so the caches are kept small.
+const storeCacheSize = 100
+
+// verifier checks the consistency of the consensus stores of a database.
+// It reads the stores directly instead of going through a consensus
+// instance, since creating one writes to the database.
+type verifier struct {
+	dbContext   model.DBManager
+	stagingArea *model.StagingArea
+
+	blockHeaderStore        model.BlockHeaderStore
+	blockStatusStore        model.BlockStatusStore
+	consensusStateStore     model.ConsensusStateStore
+	ghostdagDataStore       model.GHOSTDAGDataStore
+	headersSelectedTipStore model.HeaderSelectedTipStore
+	pruningStore            model.PruningStore
+	utxoDiffStore           model.UTXODiffStore
+}
+
+func verify(conf *verifyConfig) (err error) {
+	databasePath := conf.databasePath()
+	db, err := backend.OpenReadOnly(databasePath, cacheSizeMiB)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db, &err)
+
+	activePrefix, hasActivePrefix, err := prefixmanager.ActivePrefix(db)
+	if err != nil {
+		return err
+	}
+	if !hasActivePrefix {
+		return errors.Errorf("the database in %s has no active consensus", databasePath)
+	}
+
+	dbContext := consensusdatabase.New(db)
+	prefixBucket := consensusdatabase.MakeBucket(activePrefix.Serialize())
+	blockHeaderStore, err := blockheaderstore.New(dbContext, prefixBucket, storeCacheSize, false)
+	if err != nil {
+		return err
+	}
+	v := &verifier{
+		dbContext:               dbContext,
+		stagingArea:             model.NewStagingArea(),
+		blockHeaderStore:        blockHeaderStore,
+		blockStatusStore:        blockstatusstore.New(prefixBucket, storeCacheSize, false),
+		consensusStateStore:     consensusstatestore.New(prefixBucket, storeCacheSize, false),
+		ghostdagDataStore:       ghostdagdatastore.New(prefixBucket.Bucket([]byte{0}), storeCacheSize, false),
+		headersSelectedTipStore: headersselectedtipstore.New(prefixBucket),
+		pruningStore:            pruningstore.New(prefixBucket, storeCacheSize, false),
+		utxoDiffStore:           utxodiffstore.New(prefixBucket, storeCacheSize, false),
+	}
+
+	fmt.Printf("Verifying the database in %s\n", databasePath)
+	checks := []struct {
+		description string
+		check       func() error
+	}{
+		{"No UTXO set update was interrupted", v.checkNoInterruptedUTXOSetUpdate},
+		{"The headers selected tip is known", v.checkHeadersSelectedTip},
+		{"The virtual tips are known", v.checkTips},
+		{"The pruning point UTXO set matches the pruning point UTXO commitment", v.checkPruningPointUTXOSet},
+		{"The virtual UTXO set matches the UTXO commitment of the virtual selected parent", v.checkVirtualUTXOSet},
+	}
+	failedChecks := 0
+	for _, check := range checks {
+		err := check.check()
+		if err != nil {
+			failedChecks++
+			fmt.Printf("FAILED: %s: %s\n", check.description, err)
+			continue
+		}
+		fmt.Printf("OK: %s\n", check.description)
+	}
+
+	if failedChecks > 0 {
+		return errors.Errorf("%d out of %d checks failed", failedChecks, len(checks))
+	}
+	fmt.Println("The database is consistent")
+	return nil
+}
+
+func (v *verifier) checkNoInterruptedUTXOSetUpdate() error {
+	hadStartedUpdatingPruningPointUTXOSet, err := v.pruningStore.HadStartedUpdatingPruningPointUTXOSet(v.dbContext)
+	if err != nil {
+		return err
+	}
+	if hadStartedUpdatingPruningPointUTXOSet {
+		return errors.New("an update of the pruning point UTXO set was interrupted " +
+			"and will be resumed when kaspad starts")
+	}
+
+	hadStartedImportingPruningPointUTXOSet, err := v.consensusStateStore.HadStartedImportingPruningPointUTXOSet(v.dbContext)
+	if err != nil {
+		return err
+	}
+	if hadStartedImportingPruningPointUTXOSet {
+		return errors.New("an import of the pruning point UTXO set into the virtual UTXO set was interrupted " +
+			"and will be resumed when kaspad starts")
+	}
+	return nil
+}
+
+func (v *verifier) checkHeadersSelectedTip() error {
+	headersSelectedTip, err := v.headersSelectedTipStore.HeadersSelectedTip(v.dbContext, v.stagingArea)
+	if err != nil {
+		return err
+	}
+	return v.checkHeaderExists(headersSelectedTip)
+}
+
+func (v *verifier) checkTips() error {
+	tips, err := v.consensusStateStore.Tips(v.stagingArea, v.dbContext)
+	if err != nil {
+		return err
+	}
+	for _, tip := range tips {
+		err := v.checkHeaderExists(tip)
+		if err != nil {
+			return err
+		}
+		_, err = v.blockStatusStore.Get(v.dbContext, v.stagingArea, tip)
+		if err != nil {
+			return errors.Wrapf(err, "the status of tip %s is missing", tip)
+		}
+	}
+	return nil
+}
+
+func (v *verifier) checkPruningPointUTXOSet() error {
+	pruningPoint, err := v.pruningStore.PruningPoint(v.dbContext, v.stagingArea)
+	if err != nil {
+		return err
+	}
+	pruningPointHeader, err := v.blockHeaderStore.BlockHeader(v.dbContext, v.stagingArea, pruningPoint)
+	if err != nil {
+		return errors.Wrapf(err, "the header of pruning point %s is missing", pruningPoint)
+	}
+
+	iterator, err := v.pruningStore.PruningPointUTXOIterator(v.dbContext)
+	if err != nil {
+		return err
+	}
+	utxoSetMultiset, utxoCount, err := utxoSetMultiset(iterator)
+	if err != nil {
+		return err
+	}
+
+	if !utxoSetMultiset.Hash().Equal(pruningPointHeader.UTXOCommitment()) {
+		return errors.Errorf("the multiset of the %d UTXOs of pruning point %s is %s but its header commits to %s",
+			utxoCount, pruningPoint, utxoSetMultiset.Hash(), pruningPointHeader.UTXOCommitment())
+	}
+	return nil
+}
+
+func (v *verifier) checkVirtualUTXOSet() error {
+	virtualGHOSTDAGData, err := v.ghostdagDataStore.Get(v.dbContext, v.stagingArea, model.VirtualBlockHash, false)
+	if err != nil {
+		return err
+	}
+	virtualSelectedParent := virtualGHOSTDAGData.SelectedParent()
+	virtualSelectedParentStatus, err := v.blockStatusStore.Get(v.dbContext, v.stagingArea, virtualSelectedParent)
+	if err != nil {
+		return err
+	}
+	if virtualSelectedParentStatus != externalapi.StatusUTXOValid {
+		return errors.Errorf("the status of virtual selected parent %s is %s",
+			virtualSelectedParent, virtualSelectedParentStatus)
+	}
+	virtualSelectedParentHeader, err := v.blockHeaderStore.BlockHeader(v.dbContext, v.stagingArea, virtualSelectedParent)
+	if err != nil {
+		return errors.Wrapf(err, "the header of virtual selected parent %s is missing", virtualSelectedParent)
+	}
+
+	// The UTXO diff of the virtual selected parent is the diff from the virtual
+	// UTXO set to its own UTXO set, which is what its header commits to
+	hasUTXODiffChild, err := v.utxoDiffStore.HasUTXODiffChild(v.dbContext, v.stagingArea, virtualSelectedParent)
+	if err != nil {
+		return err
+	}
+	if hasUTXODiffChild {
+		return errors.Errorf("the UTXO diff of virtual selected parent %s isn't relative to the virtual",
+			virtualSelectedParent)
+	}
+	utxoDiff, err := v.utxoDiffStore.UTXODiff(v.dbContext, v.stagingArea, virtualSelectedParent)
+	if err != nil {
+		return err
+	}
+
+	iterator, err := v.consensusStateStore.VirtualUTXOSetIterator(v.dbContext, v.stagingArea)
+	if err != nil {
+		return err
+	}
+	virtualSelectedParentMultiset, _, err := utxoSetMultiset(iterator)
+	if err != nil {
+		return err
+	}
+	err = applyToMultiset(utxoDiff.ToRemove(), virtualSelectedParentMultiset.Remove)
+	if err != nil {
+		return err
+	}
+	err = applyToMultiset(utxoDiff.ToAdd(), virtualSelectedParentMultiset.Add)
+	if err != nil {
+		return err
+	}
+
+	if !virtualSelectedParentMultiset.Hash().Equal(virtualSelectedParentHeader.UTXOCommitment()) {
+		return errors.Errorf("the UTXO set of virtual selected parent %s has multiset %s but its header commits to %s",
+			virtualSelectedParent, virtualSelectedParentMultiset.Hash(), virtualSelectedParentHeader.UTXOCommitment())
+	}
+	return nil
+}
+
+func (v *verifier) checkHeaderExists(blockHash *externalapi.DomainHash) error {
+	hasHeader, err := v.blockHeaderStore.HasBlockHeader(v.dbContext, v.stagingArea, blockHash)
+	if err != nil {
+		return err
+	}
+	if !hasHeader {
+		return errors.Errorf("the header of block %s is missing", blockHash)
+	}
+	return nil
+}
+
+// utxoSetMultiset builds the multiset of all the UTXOs in the given iterator
+// and closes it
+func utxoSetMultiset(iterator externalapi.ReadOnlyUTXOSetIterator) (model.Multiset, uint64, error) {
+	defer iterator.Close()
+
+	utxoSetMultiset := multiset.New()
+	var utxoCount uint64
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		outpoint, entry, err := iterator.Get()
+		if err != nil {
+			return nil, 0, err
+		}
+		serializedUTXO, err := utxo.SerializeUTXO(entry, outpoint)
+		if err != nil {
+			return nil, 0, err
+		}
+		utxoSetMultiset.Add(serializedUTXO)
+		utxoCount++
+	}
+	return utxoSetMultiset, utxoCount, nil
+}
+
+// applyToMultiset calls apply with every serialized UTXO in the given collection
+func applyToMultiset(collection externalapi.UTXOCollection, apply func(data []byte)) error {
+	iterator := collection.Iterator()
+	defer iterator.Close()
+
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		outpoint, entry, err := iterator.Get()
+		if err != nil {
+			return err
+		}
+		serializedUTXO, err := utxo.SerializeUTXO(entry, outpoint)
+		if err != nil {
+			return err
+		}
+		apply(serializedUTXO)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 
 	"github.com/kaspanet/kaspad/util"
 	"github.com/pkg/errors"
@@ -38,6 +39,7 @@ type configFlags struct {
 	MineWhenNotSynced     bool     `long:"mine-when-not-synced" description:"Mine even if the node is not synced with the rest of the network."`
 	Profile               string   `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
 	TargetBlocksPerSecond *float64 `long:"target-blocks-per-second" description:"Sets a maximum block rate. 0 means no limit (The default one is 2 * target network block rate)"`
+	grpcclient.ConnectOptions
 	config.NetworkFlags
 }
 
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+)
+
+const allInputs = -1
+
+type configFlags struct {
+	TransactionFile string `short:"f" long:"transaction-file" description:"A JSON file containing the transaction and the UTXO entries it spends, in the format of the DebugTransactionScripts RPC request (required)"`
+	Input           int    `short:"i" long:"input" description:"Only debug the input at this index (default: all inputs)"`
+	NonInteractive  bool   `long:"non-interactive" description:"Print the whole trace without stopping after every opcode"`
+}
+
+func parseConfig() (*configFlags, error) {
+	cfg := &configFlags{
+		Input: allInputs,
+	}
+	parser := flags.NewParser(cfg, flags.HelpFlag)
+	parser.Usage = "kaspascript [OPTIONS]"
+	_, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.TransactionFile == "" {
+		return nil, errors.New("--transaction-file is required")
+	}
+	if cfg.Input < allInputs {
+		return nil, errors.Errorf("--input must be a non-negative input index")
+	}
+
+	return cfg, nil
+}
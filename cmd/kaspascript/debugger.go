@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/pkg/errors"
+)
+
+const debuggerHelp = `Commands:
+  step, s (or an empty line)  execute the next opcode
+  continue, c                 run the current input to completion
+  stack                       print the data and alt stacks
+  disasm                      print the scripts of the current input
+  quit, q                     exit kaspascript
+  help, h                     print this help`
+
+type debugger struct {
+	transaction         *externalapi.DomainTransaction
+	sighashReusedValues *consensushashing.SighashReusedValues
+	reader              *bufio.Reader
+}
+
+func newDebugger(transaction *externalapi.DomainTransaction, reader *bufio.Reader) *debugger {
+	return &debugger{
+		transaction:         transaction,
+		sighashReusedValues: &consensushashing.SighashReusedValues{},
+		reader:              reader,
+	}
+}
+
+func (d *debugger) debugInputs(inputIndexes []int) error {
+	fmt.Println(debuggerHelp)
+	for _, inputIndex := range inputIndexes {
+		shouldQuit, err := d.debugInput(inputIndex)
+		if err != nil {
+			return err
+		}
+		if shouldQuit {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (d *debugger) debugInput(inputIndex int) (shouldQuit bool, err error) {
+	fmt.Printf("\nInput %d:\n", inputIndex)
+
+	scriptPublicKey := d.transaction.Inputs[inputIndex].UTXOEntry.ScriptPublicKey()
+	if scriptPublicKey.Version > constants.MaxScriptPublicKeyVersion {
+		fmt.Printf("Script public key version %d is unknown; the input is considered valid\n",
+			scriptPublicKey.Version)
+		return false, nil
+	}
+
+	vm, err := txscript.NewEngine(scriptPublicKey, d.transaction, inputIndex, txscript.ScriptNoFlags,
+		nil, nil, d.sighashReusedValues)
+	if err != nil {
+		fmt.Printf("Input %d is invalid: %s\n", inputIndex, err)
+		return false, nil
+	}
+	printScripts(vm)
+
+	isContinuing := false
+	for !vm.IsDone() {
+		disassembly, err := vm.DisasmPC()
+		if err != nil {
+			return false, err
+		}
+		fmt.Printf("next: %s\n", disassembly)
+
+		if !isContinuing {
+			command, err := d.readCommand()
+			if err != nil {
+				return false, err
+			}
+			switch command {
+			case "", "s", "step":
+			case "c", "continue":
+				isContinuing = true
+			case "stack":
+				printStacks(vm.GetStack(), vm.GetAltStack())
+				continue
+			case "disasm":
+				printScripts(vm)
+				continue
+			case "q", "quit":
+				return true, nil
+			case "h", "help":
+				fmt.Println(debuggerHelp)
+				continue
+			default:
+				fmt.Printf("Unknown command %q. Type help for a list of commands\n", command)
+				continue
+			}
+		}
+
+		_, err = vm.Step()
+		printStacks(vm.GetStack(), vm.GetAltStack())
+		if err != nil {
+			fmt.Printf("Input %d is invalid: %s\n", inputIndex, err)
+			return false, nil
+		}
+	}
+
+	err = vm.CheckErrorCondition(true)
+	if err != nil {
+		fmt.Printf("Input %d is invalid: %s\n", inputIndex, err)
+		return false, nil
+	}
+	fmt.Printf("Input %d is valid\n", inputIndex)
+	return false, nil
+}
+
+func (d *debugger) readCommand() (string, error) {
+	fmt.Print("(kaspascript) ")
+	line, err := d.reader.ReadString('\n')
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return "q", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// traceInputs prints the whole execution trace of the given inputs without stopping
+func traceInputs(transaction *externalapi.DomainTransaction, inputIndexes []int) error {
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	for _, inputIndex := range inputIndexes {
+		inputTrace, err := txscript.TraceTransactionInput(transaction, inputIndex, sighashReusedValues, 0)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Input %d:\n", inputIndex)
+		for i, script := range inputTrace.Scripts {
+			fmt.Printf("Script %d:\n%s", i, script)
+		}
+		for _, step := range inputTrace.Steps {
+			fmt.Println(step)
+			printStacks(step.DataStack, step.AltStack)
+		}
+		if inputTrace.Err != nil {
+			fmt.Printf("Input %d is invalid: %s\n\n", inputIndex, inputTrace.Err)
+		} else {
+			fmt.Printf("Input %d is valid\n\n", inputIndex)
+		}
+	}
+	return nil
+}
+
+func printScripts(vm *txscript.Engine) {
+	for i := 0; i < vm.NumScripts(); i++ {
+		disassembly, err := vm.DisasmScript(i)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		fmt.Printf("Script %d:\n%s", i, disassembly)
+	}
+}
+
+func printStacks(dataStack [][]byte, altStack [][]byte) {
+	fmt.Printf("  data stack: %s\n", formatStack(dataStack))
+	if len(altStack) > 0 {
+		fmt.Printf("  alt stack:  %s\n", formatStack(altStack))
+	}
+}
+
+// formatStack formats the stack items from bottom to top
+func formatStack(stack [][]byte) string {
+	items := make([]string, len(stack))
+	for i, item := range stack {
+		items[i] = hex.EncodeToString(item)
+		if len(item) == 0 {
+			items[i] = "<empty>"
+		}
+	}
+	return "[" + strings.Join(items, " ") + "]"
+}
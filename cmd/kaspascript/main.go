@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func main() {
+	cfg, err := parseConfig()
+	if err != nil {
+		printErrorAndExit(fmt.Sprintf("error parsing command-line arguments: %s", err))
+	}
+
+	transaction, err := loadTransaction(cfg.TransactionFile)
+	if err != nil {
+		printErrorAndExit(fmt.Sprintf("error loading the transaction: %s", err))
+	}
+
+	inputIndexes := make([]int, 0, len(transaction.Inputs))
+	if cfg.Input == allInputs {
+		for i := range transaction.Inputs {
+			inputIndexes = append(inputIndexes, i)
+		}
+	} else {
+		if cfg.Input >= len(transaction.Inputs) {
+			printErrorAndExit(fmt.Sprintf("input %d is out of range: the transaction has %d inputs",
+				cfg.Input, len(transaction.Inputs)))
+		}
+		inputIndexes = append(inputIndexes, cfg.Input)
+	}
+
+	if cfg.NonInteractive {
+		err = traceInputs(transaction, inputIndexes)
+	} else {
+		err = newDebugger(transaction, bufio.NewReader(os.Stdin)).debugInputs(inputIndexes)
+	}
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+}
+
+// loadTransaction reads a transaction along with the UTXO entries it spends from the
+// given file. The file holds a DebugTransactionScripts RPC request in JSON format
+func loadTransaction(path string) (*externalapi.DomainTransaction, error) {
+	fileContent, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &protowire.DebugTransactionScriptsRequestMessage{}
+	err = protojson.Unmarshal(fileContent, request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not parse %s", path)
+	}
+	appMessage, err := (&protowire.KaspadMessage{
+		Payload: &protowire.KaspadMessage_DebugTransactionScriptsRequest{DebugTransactionScriptsRequest: request},
+	}).ToAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	debugRequest := appMessage.(*appmessage.DebugTransactionScriptsRequestMessage)
+
+	transaction, err := appmessage.RPCTransactionToDomainTransaction(debugRequest.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	if len(debugRequest.UTXOEntries) != len(transaction.Inputs) {
+		return nil, errors.Errorf("expected %d UTXO entries, one per input, but got %d",
+			len(transaction.Inputs), len(debugRequest.UTXOEntries))
+	}
+	for i, rpcUTXOEntry := range debugRequest.UTXOEntries {
+		transaction.Inputs[i].UTXOEntry, err = appmessage.RPCUTXOEntryToUTXOEntry(rpcUTXOEntry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse the UTXO entry of input %d", i)
+		}
+	}
+	return transaction, nil
+}
+
+func printErrorAndExit(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	os.Exit(1)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/pkg/errors"
+)
+
+// passwordEnvironmentVariable is the environment variable the wallet password is read
+// from if --password-file is not set
+const passwordEnvironmentVariable = "KASPASIGNER_PASSWORD"
+
+type configFlags struct {
+	KeysFile     string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	PasswordFile string `long:"password-file" description:"File containing the wallet password (default: the password is read from the KASPASIGNER_PASSWORD environment variable)"`
+	config.NetworkFlags
+
+	password string
+}
+
+func parseConfig() (*configFlags, error) {
+	cfg := &configFlags{}
+	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
+	_, err := parser.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.ResolveNetwork(parser)
+	if err != nil {
+		return nil, err
+	}
+
+	// The standard input is reserved for the signing request, so the password cannot be prompted for.
+	// It's not accepted on the command line either, since command lines are visible to other users.
+	cfg.password, err = readPassword(cfg.PasswordFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func readPassword(passwordFile string) (string, error) {
+	if passwordFile == "" {
+		password, ok := os.LookupEnv(passwordEnvironmentVariable)
+		if !ok || password == "" {
+			return "", errors.Errorf("either --password-file or the %s environment variable is required",
+				passwordEnvironmentVariable)
+		}
+		return password, nil
+	}
+
+	passwordBytes, err := os.ReadFile(passwordFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "could not read the password file %s", passwordFile)
+	}
+	password := strings.TrimRight(string(passwordBytes), "\r\n")
+	if password == "" {
+		return "", errors.Errorf("the password file %s is empty", passwordFile)
+	}
+	return password, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/pkg/errors"
+)
+
+func main() {
+	cfg, err := parseConfig()
+	if err != nil {
+		// Flag parsing errors are already printed by the parser
+		var flagsErr *flags.Error
+		if !errors.As(err, &flagsErr) {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+		}
+		os.Exit(1)
+	}
+
+	response, err := handleRequest(cfg, os.Stdin)
+	if err != nil {
+		response = &libkaspawallet.ExternalSignerResponse{Error: err.Error()}
+	}
+
+	encodeErr := json.NewEncoder(os.Stdout).Encode(response)
+	if encodeErr != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", encodeErr)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleRequest(cfg *configFlags, requestReader io.Reader) (*libkaspawallet.ExternalSignerResponse, error) {
+	request := &libkaspawallet.ExternalSignerRequest{}
+	err := json.NewDecoder(requestReader).Decode(request)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse the signing request")
+	}
+
+	keysFile, err := keys.ReadKeysFile(cfg.NetParams(), cfg.KeysFile)
+	if err != nil {
+		return nil, err
+	}
+	if request.ECDSA != keysFile.ECDSA {
+		return nil, errors.Errorf("the request asks for ECDSA=%t signatures, but the keys file has ECDSA=%t",
+			request.ECDSA, keysFile.ECDSA)
+	}
+
+	mnemonics, err := keysFile.DecryptMnemonics(cfg.password)
+	if err != nil {
+		return nil, err
+	}
+
+	return libkaspawallet.SignExternalSignerRequest(cfg.NetParams(), mnemonics, request)
+}
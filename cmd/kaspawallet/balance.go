@@ -25,7 +25,7 @@ func balance(conf *balanceConfig) error {
 
 	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
 	defer cancel()
-	response, err := daemonClient.GetBalance(ctx, &pb.GetBalanceRequest{})
+	response, err := daemonClient.GetBalance(ctx, &pb.GetBalanceRequest{IncludeUnconfirmed: conf.IncludeUnconfirmed})
 	if err != nil {
 		return err
 	}
@@ -45,6 +45,9 @@ func balance(conf *balanceConfig) error {
 		print("                                                 ")
 	}
 	fmt.Printf("Total balance, KAS %s %s%s\n", formatKas(response.Available), formatKas(response.Pending), pendingSuffix)
+	if conf.IncludeUnconfirmed && response.Unconfirmed > 0 {
+		fmt.Printf("Unconfirmed incoming, KAS %s\n", formatKas(response.Unconfirmed))
+	}
 
 	return nil
 }
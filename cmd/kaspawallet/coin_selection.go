@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/pkg/errors"
+)
+
+var coinSelectionStrategies = map[string]pb.CoinSelectionStrategy{
+	"largest-first":    pb.CoinSelectionStrategy_LARGEST_FIRST,
+	"smallest-first":   pb.CoinSelectionStrategy_SMALLEST_FIRST,
+	"branch-and-bound": pb.CoinSelectionStrategy_BRANCH_AND_BOUND,
+}
+
+// fillRequest sets the coin selection fields of the given request according to the flags
+func (flags *coinSelectionFlags) fillRequest(request *pb.CreateUnsignedTransactionsRequest) error {
+	var err error
+	request.IncludeOutpoints, err = parseOutpoints(flags.UTXOs)
+	if err != nil {
+		return err
+	}
+	request.ExcludeOutpoints, err = parseOutpoints(flags.ExcludeUTXOs)
+	if err != nil {
+		return err
+	}
+	request.FromAddresses = flags.FromAddresses
+
+	strategy, ok := coinSelectionStrategies[flags.CoinSelection]
+	if !ok {
+		return errors.Errorf("Unknown coin selection strategy %s", flags.CoinSelection)
+	}
+	request.CoinSelectionStrategy = strategy
+
+	return nil
+}
+
+func parseOutpoints(outpointStrings []string) ([]*pb.Outpoint, error) {
+	outpoints := make([]*pb.Outpoint, len(outpointStrings))
+	for i, outpointString := range outpointStrings {
+		parts := strings.Split(outpointString, ":")
+		if len(parts) != 2 {
+			return nil, errors.Errorf("UTXO %s is not in the format <transaction ID>:<index>", outpointString)
+		}
+		index, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "UTXO %s has an invalid index", outpointString)
+		}
+		outpoints[i] = &pb.Outpoint{
+			TransactionId: parts[0],
+			Index:         uint32(index),
+		}
+	}
+	return outpoints, nil
+}
+
+// printExtraFee notifies the user about value that is paid as fee to avoid a change output
+func printExtraFee(extraFee uint64) {
+	if extraFee > 0 {
+		fmt.Printf("Paying an extra fee of %.8f KAS to avoid a change output\n",
+			float64(extraFee)/constants.SompiPerKaspa)
+	}
+}
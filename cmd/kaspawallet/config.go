@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 	"github.com/pkg/errors"
 
 	"github.com/jessevdk/go-flags"
@@ -21,6 +22,9 @@ const (
 	newAddressSubCmd                = "new-address"
 	dumpUnencryptedDataSubCmd       = "dump-unencrypted-data"
 	startDaemonSubCmd               = "start-daemon"
+	historySubCmd                   = "history"
+	labelSubCmd                     = "label"
+	listUTXOsSubCmd                 = "list-utxos"
 )
 
 const (
@@ -33,20 +37,23 @@ type configFlags struct {
 }
 
 type createConfig struct {
-	KeysFile          string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
-	Password          string `long:"password" short:"p" description:"Wallet password"`
-	Yes               bool   `long:"yes" short:"y" description:"Assume \"yes\" to all questions"`
-	MinimumSignatures uint32 `long:"min-signatures" short:"m" description:"Minimum required signatures" default:"1"`
-	NumPrivateKeys    uint32 `long:"num-private-keys" short:"k" description:"Number of private keys" default:"1"`
-	NumPublicKeys     uint32 `long:"num-public-keys" short:"n" description:"Total number of keys" default:"1"`
-	ECDSA             bool   `long:"ecdsa" description:"Create an ECDSA wallet"`
-	Import            bool   `long:"import" short:"i" description:"Import private keys (as opposed to generating them)"`
+	KeysFile           string   `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
+	Password           string   `long:"password" short:"p" description:"Wallet password"`
+	Yes                bool     `long:"yes" short:"y" description:"Assume \"yes\" to all questions"`
+	MinimumSignatures  uint32   `long:"min-signatures" short:"m" description:"Minimum required signatures" default:"1"`
+	NumPrivateKeys     uint32   `long:"num-private-keys" short:"k" description:"Number of private keys" default:"1"`
+	NumPublicKeys      uint32   `long:"num-public-keys" short:"n" description:"Total number of keys" default:"1"`
+	ECDSA              bool     `long:"ecdsa" description:"Create an ECDSA wallet"`
+	Import             bool     `long:"import" short:"i" description:"Import private keys (as opposed to generating them)"`
+	WatchOnly          bool     `long:"watch-only" description:"Create a watch-only wallet that holds only extended public keys, and can't sign transactions"`
+	ExtendedPublicKeys []string `long:"public-key" description:"Extended public key of a watch-only wallet (may be used multiple times). If omitted, --num-public-keys keys are asked for"`
 	config.NetworkFlags
 }
 
 type balanceConfig struct {
-	DaemonAddress string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
-	Verbose       bool   `long:"verbose" short:"v" description:"Verbose: show addresses with balance"`
+	DaemonAddress      string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
+	Verbose            bool   `long:"verbose" short:"v" description:"Verbose: show addresses with balance"`
+	IncludeUnconfirmed bool   `long:"include-unconfirmed" description:"Leave out UTXOs spent by transactions in the mempool, and show what such transactions pay to the wallet"`
 	config.NetworkFlags
 }
 
@@ -56,6 +63,9 @@ type sendConfig struct {
 	DaemonAddress string  `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
 	ToAddress     string  `long:"to-address" short:"t" description:"The public address to send Kaspa to" required:"true"`
 	SendAmount    float64 `long:"send-amount" short:"v" description:"An amount to send in Kaspa (e.g. 1234.12345678)" required:"true"`
+	FeeRate       float64 `long:"fee-rate" description:"Fee rate in sompi per gram of transaction mass (default: the fee rate suggested by kaspad)"`
+	coinSelectionFlags
+	externalSignerFlags
 	config.NetworkFlags
 }
 
@@ -63,14 +73,29 @@ type createUnsignedTransactionConfig struct {
 	DaemonAddress string  `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
 	ToAddress     string  `long:"to-address" short:"t" description:"The public address to send Kaspa to" required:"true"`
 	SendAmount    float64 `long:"send-amount" short:"v" description:"An amount to send in Kaspa (e.g. 1234.12345678)" required:"true"`
+	FeeRate       float64 `long:"fee-rate" description:"Fee rate in sompi per gram of transaction mass (default: the fee rate suggested by kaspad)"`
+	coinSelectionFlags
 	config.NetworkFlags
 }
 
+type coinSelectionFlags struct {
+	UTXOs         []string `long:"utxo" description:"A UTXO to spend, as <transaction ID>:<index>. The given UTXOs are always spent, and more are selected only if they are not enough (may be used multiple times)"`
+	ExcludeUTXOs  []string `long:"exclude-utxo" description:"A UTXO not to spend, as <transaction ID>:<index> (may be used multiple times)"`
+	FromAddresses []string `long:"from-address" description:"Spend only UTXOs of this address (may be used multiple times)"`
+	CoinSelection string   `long:"coin-selection" description:"The strategy to select the UTXOs to spend by" choice:"largest-first" choice:"smallest-first" choice:"branch-and-bound" default:"largest-first"`
+}
+
+type externalSignerFlags struct {
+	ExternalSigner     string   `long:"external-signer" description:"Path of an external signer executable to sign the transaction with instead of the keys file mnemonics"`
+	ExternalSignerArgs []string `long:"external-signer-arg" description:"An argument to pass to the external signer (may be used multiple times)"`
+}
+
 type signConfig struct {
 	KeysFile        string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
 	Password        string `long:"password" short:"p" description:"Wallet password"`
 	Transaction     string `long:"transaction" short:"t" description:"The unsigned transaction(s) to sign on (encoded in hex)"`
 	TransactionFile string `long:"transaction-file" short:"F" description:"The file containing the unsigned transaction(s) to sign on (encoded in hex)"`
+	externalSignerFlags
 	config.NetworkFlags
 }
 
@@ -98,12 +123,34 @@ type newAddressConfig struct {
 	config.NetworkFlags
 }
 
+type historyConfig struct {
+	DaemonAddress string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
+	Verbose       bool   `long:"verbose" short:"v" description:"Verbose: show the addresses involved in each transaction"`
+	config.NetworkFlags
+}
+
+type labelConfig struct {
+	DaemonAddress string `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
+	TransactionID string `long:"transaction-id" short:"t" description:"The ID of the transaction to label"`
+	SpentOutpoint string `long:"spent-outpoint" short:"o" description:"The outpoint (<transaction ID>:<index>) whose spend by an unknown transaction to label"`
+	Address       string `long:"address" short:"a" description:"The address to label"`
+	Label         string `long:"label" short:"l" description:"The label to set. An empty label removes the existing one"`
+	config.NetworkFlags
+}
+
+type listUTXOsConfig struct {
+	DaemonAddress string   `long:"daemonaddress" short:"d" description:"Wallet daemon server to connect to (default: localhost:8082)"`
+	Addresses     []string `long:"address" short:"a" description:"Show only the UTXOs of this address (may be used multiple times)"`
+	config.NetworkFlags
+}
+
 type startDaemonConfig struct {
 	KeysFile  string `long:"keys-file" short:"f" description:"Keys file location (default: ~/.kaspawallet/keys.json (*nix), %USERPROFILE%\\AppData\\Local\\Kaspawallet\\key.json (Windows))"`
 	Password  string `long:"password" short:"p" description:"Wallet password"`
 	RPCServer string `long:"rpcserver" short:"s" description:"RPC server to connect to"`
 	Listen    string `short:"l" long:"listen" description:"Address to listen on (default: 0.0.0.0:8082)"`
 	Profile   string `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	grpcclient.ConnectOptions
 	config.NetworkFlags
 }
 
@@ -159,6 +206,18 @@ func parseCommandLine() (subCommand string, config interface{}) {
 		"Prints the unencrypted wallet data including its private keys. Anyone that sees it can access "+
 			"the funds. Use only on safe environment.", dumpUnencryptedDataConf)
 
+	historyConf := &historyConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(historySubCmd, "Shows the transaction history of the current wallet",
+		"Shows the transactions that paid to the current wallet or spent from it, together with their labels", historyConf)
+
+	labelConf := &labelConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(labelSubCmd, "Labels a transaction or an address",
+		"Sets the label of either a transaction or an address of the current wallet", labelConf)
+
+	listUTXOsConf := &listUTXOsConfig{DaemonAddress: defaultListen}
+	parser.AddCommand(listUTXOsSubCmd, "Lists the UTXOs of the current wallet",
+		"Lists the UTXOs of the current wallet together with their addresses and maturity", listUTXOsConf)
+
 	startDaemonConf := &startDaemonConfig{
 		RPCServer: defaultRPCServer,
 		Listen:    defaultListen,
@@ -248,6 +307,27 @@ func parseCommandLine() (subCommand string, config interface{}) {
 			printErrorAndExit(err)
 		}
 		config = dumpUnencryptedDataConf
+	case historySubCmd:
+		combineNetworkFlags(&historyConf.NetworkFlags, &cfg.NetworkFlags)
+		err := historyConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = historyConf
+	case labelSubCmd:
+		combineNetworkFlags(&labelConf.NetworkFlags, &cfg.NetworkFlags)
+		err := labelConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = labelConf
+	case listUTXOsSubCmd:
+		combineNetworkFlags(&listUTXOsConf.NetworkFlags, &cfg.NetworkFlags)
+		err := listUTXOsConf.ResolveNetwork(parser)
+		if err != nil {
+			printErrorAndExit(err)
+		}
+		config = listUTXOsConf
 	case startDaemonSubCmd:
 		combineNetworkFlags(&startDaemonConf.NetworkFlags, &cfg.NetworkFlags)
 		err := startDaemonConf.ResolveNetwork(parser)
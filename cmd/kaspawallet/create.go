@@ -13,6 +13,10 @@ import (
 )
 
 func create(conf *createConfig) error {
+	if conf.WatchOnly {
+		return createWatchOnly(conf)
+	}
+
 	var encryptedMnemonics []*keys.EncryptedMnemonic
 	var signerExtendedPublicKeys []string
 	var err error
@@ -32,23 +36,11 @@ func create(conf *createConfig) error {
 
 	extendedPublicKeys := make([]string, conf.NumPrivateKeys, conf.NumPublicKeys)
 	copy(extendedPublicKeys, signerExtendedPublicKeys)
-	reader := bufio.NewReader(os.Stdin)
-	for i := conf.NumPrivateKeys; i < conf.NumPublicKeys; i++ {
-		fmt.Printf("Enter public key #%d here:\n", i+1)
-		extendedPublicKey, err := utils.ReadLine(reader)
-		if err != nil {
-			return err
-		}
-
-		_, err = bip32.DeserializeExtendedKey(string(extendedPublicKey))
-		if err != nil {
-			return errors.Wrapf(err, "%s is invalid extended public key", string(extendedPublicKey))
-		}
-
-		fmt.Println()
-
-		extendedPublicKeys = append(extendedPublicKeys, string(extendedPublicKey))
+	otherExtendedPublicKeys, err := readExtendedPublicKeys(conf.NumPrivateKeys, conf.NumPublicKeys)
+	if err != nil {
+		return err
 	}
+	extendedPublicKeys = append(extendedPublicKeys, otherExtendedPublicKeys...)
 
 	cosignerIndex, err := libkaspawallet.MinimumCosignerIndex(signerExtendedPublicKeys, extendedPublicKeys)
 	if err != nil {
@@ -77,3 +69,90 @@ func create(conf *createConfig) error {
 	fmt.Printf("Wrote the keys into %s\n", file.Path())
 	return nil
 }
+
+// createWatchOnly creates a wallet that holds only extended public keys. Such a wallet
+// can be used by kaspawalletd to track balances, derive addresses and create unsigned
+// transactions, but the transactions have to be signed elsewhere.
+func createWatchOnly(conf *createConfig) error {
+	extendedPublicKeys := conf.ExtendedPublicKeys
+	if len(extendedPublicKeys) == 0 {
+		var err error
+		extendedPublicKeys, err = readExtendedPublicKeys(0, conf.NumPublicKeys)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, extendedPublicKey := range extendedPublicKeys {
+		err := validateExtendedPublicKey(extendedPublicKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	if conf.MinimumSignatures == 0 || conf.MinimumSignatures > uint32(len(extendedPublicKeys)) {
+		return errors.Errorf("The minimum number of signatures must be between 1 and the number of public keys (%d)",
+			len(extendedPublicKeys))
+	}
+
+	// A watch-only wallet is not a cosigner, so it derives its new addresses from a path
+	// of its own. The addresses of all cosigners are tracked regardless.
+	file := keys.File{
+		Version:            keys.LastVersion,
+		EncryptedMnemonics: []*keys.EncryptedMnemonic{},
+		ExtendedPublicKeys: extendedPublicKeys,
+		MinimumSignatures:  conf.MinimumSignatures,
+		CosignerIndex:      libkaspawallet.WatchOnlyCosignerIndex(extendedPublicKeys),
+		ECDSA:              conf.ECDSA,
+	}
+
+	err := file.SetPath(conf.NetParams(), conf.KeysFile, conf.Yes)
+	if err != nil {
+		return err
+	}
+
+	err = file.Save()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote the watch-only wallet keys into %s\n", file.Path())
+	return nil
+}
+
+// readExtendedPublicKeys asks the user to enter the public keys numbered from start+1 to end
+func readExtendedPublicKeys(start, end uint32) ([]string, error) {
+	extendedPublicKeys := make([]string, 0, end-start)
+	reader := bufio.NewReader(os.Stdin)
+	for i := start; i < end; i++ {
+		fmt.Printf("Enter public key #%d here:\n", i+1)
+		extendedPublicKey, err := utils.ReadLine(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		err = validateExtendedPublicKey(string(extendedPublicKey))
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Println()
+
+		extendedPublicKeys = append(extendedPublicKeys, string(extendedPublicKey))
+	}
+
+	return extendedPublicKeys, nil
+}
+
+func validateExtendedPublicKey(extendedPublicKey string) error {
+	extendedKey, err := bip32.DeserializeExtendedKey(extendedPublicKey)
+	if err != nil {
+		return errors.Wrapf(err, "%s is invalid extended public key", extendedPublicKey)
+	}
+
+	if extendedKey.IsPrivate() {
+		return errors.New("an extended private key was given where an extended public key is expected")
+	}
+
+	return nil
+}
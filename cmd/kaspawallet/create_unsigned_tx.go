@@ -20,15 +20,22 @@ func createUnsignedTransaction(conf *createUnsignedTransactionConfig) error {
 	defer cancel()
 
 	sendAmountSompi := uint64(conf.SendAmount * constants.SompiPerKaspa)
-	response, err := daemonClient.CreateUnsignedTransactions(ctx, &pb.CreateUnsignedTransactionsRequest{
+	request := &pb.CreateUnsignedTransactionsRequest{
 		Address: conf.ToAddress,
 		Amount:  sendAmountSompi,
-	})
+		FeeRate: conf.FeeRate,
+	}
+	err = conf.coinSelectionFlags.fillRequest(request)
+	if err != nil {
+		return err
+	}
+	response, err := daemonClient.CreateUnsignedTransactions(ctx, request)
 	if err != nil {
 		return err
 	}
 
 	fmt.Println("Created unsigned transaction")
+	printExtraFee(response.ExtraFee)
 	fmt.Println(encodeTransactionsToHex(response.UnsignedTransactions))
 	return nil
 }
@@ -7,10 +7,10 @@ import (
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
 )
 
-type balancesType struct{ available, pending uint64 }
+type balancesType struct{ available, pending, unconfirmed uint64 }
 type balancesMapType map[*walletAddress]*balancesType
 
-func (s *server) GetBalance(_ context.Context, _ *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+func (s *server) GetBalance(_ context.Context, request *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
@@ -23,6 +23,13 @@ func (s *server) GetBalance(_ context.Context, _ *pb.GetBalanceRequest) (*pb.Get
 
 	balancesMap := make(balancesMapType, 0)
 	for _, entry := range s.utxosSortedByAmount {
+		if entry.isUnconfirmed && !request.IncludeUnconfirmed {
+			continue
+		}
+		if entry.isSpentByUnconfirmed && request.IncludeUnconfirmed {
+			continue
+		}
+
 		amount := entry.UTXOEntry.Amount()
 		address := entry.address
 		balances, ok := balancesMap[address]
@@ -30,39 +37,49 @@ func (s *server) GetBalance(_ context.Context, _ *pb.GetBalanceRequest) (*pb.Get
 			balances = new(balancesType)
 			balancesMap[address] = balances
 		}
-		if isUTXOSpendable(entry, daaScore, maturity) {
+		switch {
+		case entry.isUnconfirmed:
+			balances.unconfirmed += amount
+		// A UTXO spent by a mempool transaction is mature, or else it couldn't have been spent
+		case entry.isSpentByUnconfirmed || isUTXOSpendable(entry, daaScore, maturity):
 			balances.available += amount
-		} else {
+		default:
 			balances.pending += amount
 		}
 	}
 
 	addressBalances := make([]*pb.AddressBalances, len(balancesMap))
 	i := 0
-	var available, pending uint64
+	var available, pending, unconfirmed uint64
 	for walletAddress, balances := range balancesMap {
 		address, err := libkaspawallet.Address(s.params, s.keysFile.ExtendedPublicKeys, s.keysFile.MinimumSignatures, s.walletAddressPath(walletAddress), s.keysFile.ECDSA)
 		if err != nil {
 			return nil, err
 		}
 		addressBalances[i] = &pb.AddressBalances{
-			Address:   address.String(),
-			Available: balances.available,
-			Pending:   balances.pending,
+			Address:     address.String(),
+			Available:   balances.available,
+			Pending:     balances.pending,
+			Unconfirmed: balances.unconfirmed,
 		}
 		i++
 		available += balances.available
 		pending += balances.pending
+		unconfirmed += balances.unconfirmed
 	}
 
 	return &pb.GetBalanceResponse{
 		Available:       available,
 		Pending:         pending,
+		Unconfirmed:     unconfirmed,
 		AddressBalances: addressBalances,
 	}, nil
 }
 
 func isUTXOSpendable(entry *walletUTXO, virtualDAAScore uint64, coinbaseMaturity uint64) bool {
+	if entry.isSpentByUnconfirmed {
+		return false
+	}
 	if !entry.UTXOEntry.IsCoinbase() {
 		return true
 	}
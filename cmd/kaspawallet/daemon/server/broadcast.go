@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"time"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
@@ -22,6 +23,25 @@ func (s *server) Broadcast(_ context.Context, request *pb.BroadcastRequest) (*pb
 		return nil, err
 	}
 
+	spentOutpoints := make([]string, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		spentOutpoints[i] = outpointString(input.PreviousOutpoint.TransactionID.String(), input.PreviousOutpoint.Index)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err = s.transactionHistory.addPendingSpends(txID, spentOutpoints, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// Refresh the UTXOs right away, so that the UTXOs spent by the transaction
+	// won't be selected by another one that's created before the next sync
+	err = s.refreshUTXOs()
+	if err != nil {
+		return nil, err
+	}
+
 	return &pb.BroadcastResponse{TxID: txID}, nil
 }
 
@@ -0,0 +1,239 @@
+package server
+
+import (
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// utxoSelection holds the restrictions the caller puts on the UTXOs a transaction may spend
+type utxoSelection struct {
+	strategy          pb.CoinSelectionStrategy
+	includedOutpoints []*externalapi.DomainOutpoint
+	excludedOutpoints map[externalapi.DomainOutpoint]struct{}
+	fromAddresses     map[string]struct{}
+}
+
+func (s *server) utxoSelectionFromRequest(request *pb.CreateUnsignedTransactionsRequest) (*utxoSelection, error) {
+	if _, ok := pb.CoinSelectionStrategy_name[int32(request.CoinSelectionStrategy)]; !ok {
+		return nil, errors.Errorf("Unknown coin selection strategy %d", request.CoinSelectionStrategy)
+	}
+
+	selection := &utxoSelection{
+		strategy:          request.CoinSelectionStrategy,
+		includedOutpoints: make([]*externalapi.DomainOutpoint, 0, len(request.IncludeOutpoints)),
+		excludedOutpoints: make(map[externalapi.DomainOutpoint]struct{}, len(request.ExcludeOutpoints)),
+		fromAddresses:     make(map[string]struct{}, len(request.FromAddresses)),
+	}
+
+	includedOutpointsSet := make(map[externalapi.DomainOutpoint]struct{}, len(request.IncludeOutpoints))
+	for _, protoOutpoint := range request.IncludeOutpoints {
+		outpoint, err := outpointFromProto(protoOutpoint)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := includedOutpointsSet[*outpoint]; ok {
+			continue
+		}
+		includedOutpointsSet[*outpoint] = struct{}{}
+		selection.includedOutpoints = append(selection.includedOutpoints, outpoint)
+	}
+
+	for _, protoOutpoint := range request.ExcludeOutpoints {
+		outpoint, err := outpointFromProto(protoOutpoint)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := includedOutpointsSet[*outpoint]; ok {
+			return nil, errors.Errorf("UTXO %s is both included and excluded", outpoint)
+		}
+		selection.excludedOutpoints[*outpoint] = struct{}{}
+	}
+
+	for _, addressString := range request.FromAddresses {
+		address, err := util.DecodeAddress(addressString, s.params.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		selection.fromAddresses[address.String()] = struct{}{}
+	}
+
+	return selection, nil
+}
+
+// candidateUTXOs returns the spendable UTXOs that a transaction may spend under the given selection.
+// The included UTXOs are returned as mandatory, and the other UTXOs that pass the selection's filters
+// as optional. Both are sorted by amount in descending order.
+func (s *server) candidateUTXOs(selection *utxoSelection, virtualDAAScore uint64) (
+	mandatory []*walletUTXO, optional []*walletUTXO, err error) {
+
+	includedOutpoints := make(map[externalapi.DomainOutpoint]struct{}, len(selection.includedOutpoints))
+	for _, outpoint := range selection.includedOutpoints {
+		includedOutpoints[*outpoint] = struct{}{}
+	}
+
+	mandatory = make([]*walletUTXO, 0, len(selection.includedOutpoints))
+	optional = make([]*walletUTXO, 0, len(s.utxosSortedByAmount))
+	for _, utxo := range s.utxosSortedByAmount {
+		if _, ok := includedOutpoints[*utxo.Outpoint]; ok {
+			if utxo.isSpentByUnconfirmed {
+				continue
+			}
+			if !isUTXOSpendable(utxo, virtualDAAScore, s.params.BlockCoinbaseMaturity) {
+				return nil, nil, errors.Errorf("UTXO %s is a coinbase output that hasn't matured yet", utxo.Outpoint)
+			}
+			isFromAddresses, err := s.isUTXOFromAddresses(utxo, selection)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !isFromAddresses {
+				return nil, nil, errors.Errorf("UTXO %s is included but doesn't belong to any of the "+
+					"from addresses", utxo.Outpoint)
+			}
+			mandatory = append(mandatory, utxo)
+			continue
+		}
+
+		// Outputs of unconfirmed transactions are spent only when they're explicitly included
+		if utxo.isUnconfirmed || !isUTXOSpendable(utxo, virtualDAAScore, s.params.BlockCoinbaseMaturity) {
+			continue
+		}
+		if _, ok := selection.excludedOutpoints[*utxo.Outpoint]; ok {
+			continue
+		}
+		isFromAddresses, err := s.isUTXOFromAddresses(utxo, selection)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !isFromAddresses {
+			continue
+		}
+		optional = append(optional, utxo)
+	}
+
+	if len(mandatory) < len(selection.includedOutpoints) {
+		for _, outpoint := range selection.includedOutpoints {
+			if !containsOutpoint(mandatory, outpoint) {
+				return nil, nil, errors.Errorf("UTXO %s doesn't belong to the wallet or was already spent", outpoint)
+			}
+		}
+	}
+
+	return mandatory, optional, nil
+}
+
+// isUTXOFromAddresses returns whether the given UTXO belongs to one of the selection's from
+// addresses. Every UTXO passes if the selection has no from addresses.
+func (s *server) isUTXOFromAddresses(utxo *walletUTXO, selection *utxoSelection) (bool, error) {
+	if len(selection.fromAddresses) == 0 {
+		return true, nil
+	}
+	address, err := s.walletAddressString(utxo.address)
+	if err != nil {
+		return false, err
+	}
+	_, ok := selection.fromAddresses[address]
+	return ok, nil
+}
+
+func containsOutpoint(utxos []*walletUTXO, outpoint *externalapi.DomainOutpoint) bool {
+	for _, utxo := range utxos {
+		if utxo.Outpoint.Equal(outpoint) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectUTXOsGreedily selects candidates in the given order until their total value
+// covers spendAmount together with the fee of the selected inputs
+func selectUTXOsGreedily(candidates []*walletUTXO, spendAmount uint64, feePerInput uint64) []*walletUTXO {
+	selectedUTXOs := []*walletUTXO{}
+	totalValue := uint64(0)
+	for _, utxo := range candidates {
+		selectedUTXOs = append(selectedUTXOs, utxo)
+		totalValue += utxo.UTXOEntry.Amount()
+
+		fee := feePerInput * uint64(len(selectedUTXOs))
+		totalSpend := spendAmount + fee
+		if totalValue >= totalSpend {
+			break
+		}
+	}
+	return selectedUTXOs
+}
+
+// selectUTXOsSmallestFirst selects the smallest candidates first, skipping the ones that
+// are worth less than the fee of spending them
+func selectUTXOsSmallestFirst(candidates []*walletUTXO, spendAmount uint64, feePerInput uint64) []*walletUTXO {
+	candidatesSmallestFirst := make([]*walletUTXO, 0, len(candidates))
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if candidates[i].UTXOEntry.Amount() <= feePerInput {
+			continue
+		}
+		candidatesSmallestFirst = append(candidatesSmallestFirst, candidates[i])
+	}
+	return selectUTXOsGreedily(candidatesSmallestFirst, spendAmount, feePerInput)
+}
+
+// maxBranchAndBoundTries bounds the number of subsets selectUTXOsBranchAndBound examines
+const maxBranchAndBoundTries = 100000
+
+// selectUTXOsBranchAndBound searches for a set of candidates that pays for spendAmount and the fee of its
+// inputs without needing a change output. The value the set may exceed this by is bounded by feePerInput,
+// which is roughly what creating and later spending a change output would cost, and is paid as fee instead.
+// The candidates are expected to be sorted by amount in descending order.
+// Returns nil if no such set is found within maxBranchAndBoundTries tries.
+func selectUTXOsBranchAndBound(candidates []*walletUTXO, spendAmount uint64, feePerInput uint64) []*walletUTXO {
+	// Work with the value every candidate adds after paying for its own input
+	effectiveCandidates := make([]*walletUTXO, 0, len(candidates))
+	effectiveValues := make([]uint64, 0, len(candidates))
+	for _, utxo := range candidates {
+		if utxo.UTXOEntry.Amount() <= feePerInput {
+			continue
+		}
+		effectiveCandidates = append(effectiveCandidates, utxo)
+		effectiveValues = append(effectiveValues, utxo.UTXOEntry.Amount()-feePerInput)
+	}
+
+	// remainingValues[i] is the total effective value of the candidates starting from index i
+	remainingValues := make([]uint64, len(effectiveValues)+1)
+	for i := len(effectiveValues) - 1; i >= 0; i-- {
+		remainingValues[i] = remainingValues[i+1] + effectiveValues[i]
+	}
+
+	upperBound := spendAmount + feePerInput
+	tries := 0
+	selectedIndexes := []int{}
+	var search func(index int, value uint64) bool
+	search = func(index int, value uint64) bool {
+		tries++
+		if tries > maxBranchAndBoundTries || value > upperBound {
+			return false
+		}
+		if value >= spendAmount {
+			return true
+		}
+		if index == len(effectiveValues) || value+remainingValues[index] < spendAmount {
+			return false
+		}
+
+		selectedIndexes = append(selectedIndexes, index)
+		if search(index+1, value+effectiveValues[index]) {
+			return true
+		}
+		selectedIndexes = selectedIndexes[:len(selectedIndexes)-1]
+		return search(index+1, value)
+	}
+
+	if !search(0, 0) {
+		return nil
+	}
+
+	selectedUTXOs := make([]*walletUTXO, len(selectedIndexes))
+	for i, index := range selectedIndexes {
+		selectedUTXOs[i] = effectiveCandidates[index]
+	}
+	return selectedUTXOs
+}
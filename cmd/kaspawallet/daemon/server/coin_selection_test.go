@@ -0,0 +1,184 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+func testWalletUTXOs(amounts ...uint64) []*walletUTXO {
+	utxos := make([]*walletUTXO, len(amounts))
+	for i, amount := range amounts {
+		utxos[i] = &walletUTXO{
+			Outpoint:  &externalapi.DomainOutpoint{Index: uint32(i)},
+			UTXOEntry: utxo.NewUTXOEntry(amount, &externalapi.ScriptPublicKey{}, false, 0),
+			address:   &walletAddress{},
+		}
+	}
+	return utxos
+}
+
+func TestSelectUTXOsStrategies(t *testing.T) {
+	const feePerInput = 10
+	candidates := testWalletUTXOs(1000, 500, 300, 200, 5)
+
+	largestFirst := selectUTXOsGreedily(candidates, 600, feePerInput)
+	if len(largestFirst) != 1 || largestFirst[0].UTXOEntry.Amount() != 1000 {
+		t.Fatalf("Unexpected largest-first selection of %d UTXOs worth %d",
+			len(largestFirst), utxosValue(largestFirst))
+	}
+
+	// The UTXO worth 5 is worth less than its fee, so it should be skipped
+	smallestFirst := selectUTXOsSmallestFirst(candidates, 600, feePerInput)
+	if len(smallestFirst) != 3 || utxosValue(smallestFirst) != 1000 {
+		t.Fatalf("Unexpected smallest-first selection of %d UTXOs worth %d",
+			len(smallestFirst), utxosValue(smallestFirst))
+	}
+
+	// 500 + 200 pay exactly for 680 and the fees of two inputs
+	branchAndBound := selectUTXOsBranchAndBound(candidates, 680, feePerInput)
+	if len(branchAndBound) != 2 || utxosValue(branchAndBound) != 700 {
+		t.Fatalf("Unexpected branch-and-bound selection of %d UTXOs worth %d",
+			len(branchAndBound), utxosValue(branchAndBound))
+	}
+
+	// An excess of up to feePerInput is allowed
+	branchAndBound = selectUTXOsBranchAndBound(candidates, 675, feePerInput)
+	if utxosValue(branchAndBound) != 700 {
+		t.Fatalf("Unexpected branch-and-bound selection worth %d", utxosValue(branchAndBound))
+	}
+
+	// No set of UTXOs pays for 100 without a change worth more than feePerInput
+	branchAndBound = selectUTXOsBranchAndBound(candidates, 100, feePerInput)
+	if branchAndBound != nil {
+		t.Fatalf("Unexpected branch-and-bound selection worth %d", utxosValue(branchAndBound))
+	}
+}
+
+func TestSelectWalletUTXOsWithMandatoryUTXOs(t *testing.T) {
+	const feePerInput = 10
+	utxos := testWalletUTXOs(1000, 500, 300, 200, 100)
+	mandatory := []*walletUTXO{utxos[4]}
+	optional := utxos[:4]
+
+	// The mandatory UTXO alone pays for 80 and its fee
+	selected, isChangeless := selectWalletUTXOs(mandatory, optional, 80, feePerInput,
+		pb.CoinSelectionStrategy_LARGEST_FIRST)
+	if len(selected) != 1 || selected[0] != utxos[4] || isChangeless {
+		t.Fatalf("Unexpected selection of %d UTXOs worth %d", len(selected), utxosValue(selected))
+	}
+
+	// The strategy selects the UTXOs that pay for the rest
+	selected, _ = selectWalletUTXOs(mandatory, optional, 500, feePerInput, pb.CoinSelectionStrategy_LARGEST_FIRST)
+	if len(selected) != 2 || selected[0] != utxos[4] || selected[1] != utxos[0] {
+		t.Fatalf("Unexpected largest-first selection of %d UTXOs worth %d", len(selected), utxosValue(selected))
+	}
+	selected, _ = selectWalletUTXOs(mandatory, optional, 500, feePerInput, pb.CoinSelectionStrategy_SMALLEST_FIRST)
+	if len(selected) != 3 || selected[0] != utxos[4] || utxosValue(selected) != 600 {
+		t.Fatalf("Unexpected smallest-first selection of %d UTXOs worth %d", len(selected), utxosValue(selected))
+	}
+
+	// 100 + 500 pay exactly for 580 and the fees of two inputs
+	selected, isChangeless = selectWalletUTXOs(mandatory, optional, 580, feePerInput,
+		pb.CoinSelectionStrategy_BRANCH_AND_BOUND)
+	if len(selected) != 2 || selected[0] != utxos[4] || selected[1] != utxos[1] || !isChangeless {
+		t.Fatalf("Unexpected branch-and-bound selection of %d UTXOs worth %d", len(selected), utxosValue(selected))
+	}
+}
+
+func TestCandidateUTXOs(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	utxos := testWalletUTXOs(1000, 500, 300)
+	// Add an immature coinbase UTXO
+	utxos = append(utxos, &walletUTXO{
+		Outpoint:  &externalapi.DomainOutpoint{Index: uint32(len(utxos))},
+		UTXOEntry: utxo.NewUTXOEntry(2000, &externalapi.ScriptPublicKey{}, true, 100),
+		address:   &walletAddress{},
+	})
+	serverInstance := &server{
+		params:              params,
+		utxosSortedByAmount: utxos,
+	}
+	const virtualDAAScore = 100
+
+	mandatory, optional, err := serverInstance.candidateUTXOs(&utxoSelection{
+		excludedOutpoints: map[externalapi.DomainOutpoint]struct{}{*utxos[1].Outpoint: {}},
+	}, virtualDAAScore)
+	if err != nil {
+		t.Fatalf("candidateUTXOs: %+v", err)
+	}
+	if len(mandatory) != 0 || len(optional) != 2 || optional[0] != utxos[0] || optional[1] != utxos[2] {
+		t.Fatalf("Unexpected candidates when excluding a UTXO")
+	}
+
+	// The exclusions still apply to the UTXOs that aren't included
+	mandatory, optional, err = serverInstance.candidateUTXOs(&utxoSelection{
+		strategy:          pb.CoinSelectionStrategy_LARGEST_FIRST,
+		includedOutpoints: []*externalapi.DomainOutpoint{utxos[2].Outpoint, utxos[1].Outpoint},
+		excludedOutpoints: map[externalapi.DomainOutpoint]struct{}{*utxos[0].Outpoint: {}},
+	}, virtualDAAScore)
+	if err != nil {
+		t.Fatalf("candidateUTXOs: %+v", err)
+	}
+	if len(mandatory) != 2 || mandatory[0] != utxos[1] || mandatory[1] != utxos[2] {
+		t.Fatalf("Unexpected mandatory candidates when including UTXOs")
+	}
+	if len(optional) != 0 {
+		t.Fatalf("Unexpected optional candidates when including UTXOs")
+	}
+
+	_, _, err = serverInstance.candidateUTXOs(&utxoSelection{
+		includedOutpoints: []*externalapi.DomainOutpoint{utxos[3].Outpoint},
+	}, virtualDAAScore)
+	if err == nil {
+		t.Fatalf("Including an immature coinbase UTXO unexpectedly succeeded")
+	}
+
+	_, _, err = serverInstance.candidateUTXOs(&utxoSelection{
+		includedOutpoints: []*externalapi.DomainOutpoint{{Index: 100}},
+	}, virtualDAAScore)
+	if err == nil {
+		t.Fatalf("Including an unknown UTXO unexpectedly succeeded")
+	}
+}
+
+func TestCandidateUTXOsWithUnconfirmed(t *testing.T) {
+	utxos := testWalletUTXOs(1000, 500, 300)
+	utxos[0].isSpentByUnconfirmed = true
+	utxos[2].isUnconfirmed = true
+	serverInstance := &server{
+		params:              &dagconfig.SimnetParams,
+		utxosSortedByAmount: utxos,
+	}
+	const virtualDAAScore = 100
+
+	// UTXOs that are spent by mempool transactions and outputs of mempool
+	// transactions are not selected automatically
+	_, optional, err := serverInstance.candidateUTXOs(&utxoSelection{}, virtualDAAScore)
+	if err != nil {
+		t.Fatalf("candidateUTXOs: %+v", err)
+	}
+	if len(optional) != 1 || optional[0] != utxos[1] {
+		t.Fatalf("Unexpected candidates when some UTXOs are unconfirmed")
+	}
+
+	_, _, err = serverInstance.candidateUTXOs(&utxoSelection{
+		includedOutpoints: []*externalapi.DomainOutpoint{utxos[0].Outpoint},
+	}, virtualDAAScore)
+	if err == nil {
+		t.Fatalf("Including a UTXO that is spent by a mempool transaction unexpectedly succeeded")
+	}
+
+	mandatory, _, err := serverInstance.candidateUTXOs(&utxoSelection{
+		includedOutpoints: []*externalapi.DomainOutpoint{utxos[2].Outpoint},
+	}, virtualDAAScore)
+	if err != nil {
+		t.Fatalf("candidateUTXOs: %+v", err)
+	}
+	if len(mandatory) != 1 || mandatory[0] != utxos[2] {
+		t.Fatalf("Unexpected candidates when including an unconfirmed UTXO")
+	}
+}
@@ -6,6 +6,11 @@ type walletUTXO struct {
 	Outpoint  *externalapi.DomainOutpoint
 	UTXOEntry externalapi.UTXOEntry
 	address   *walletAddress
+
+	// isUnconfirmed is set for outputs of transactions that are still in the
+	// mempool, and isSpentByUnconfirmed for UTXOs that such transactions spend
+	isUnconfirmed        bool
+	isSpentByUnconfirmed bool
 }
 
 type walletAddress struct {
@@ -2,17 +2,19 @@ package server
 
 import (
 	"context"
+	"math"
 
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/pkg/errors"
 )
 
-// TODO: Implement a better fee estimation mechanism
-const feePerInput = 10000
-
 func (s *server) CreateUnsignedTransactions(_ context.Context, request *pb.CreateUnsignedTransactionsRequest) (
 	*pb.CreateUnsignedTransactionsResponse, error) {
 	s.lock.Lock()
@@ -32,7 +34,22 @@ func (s *server) CreateUnsignedTransactions(_ context.Context, request *pb.Creat
 		return nil, err
 	}
 
-	selectedUTXOs, changeSompi, err := s.selectUTXOs(request.Amount, feePerInput)
+	feeRate, err := s.feeRate(request.FeeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := s.estimateTransactionFees(feeRate, toAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	selection, err := s.utxoSelectionFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	selectedUTXOs, hasChange, extraFee, err := s.selectUTXOs(request.Amount, fees, selection)
 	if err != nil {
 		return nil, err
 	}
@@ -42,63 +59,221 @@ func (s *server) CreateUnsignedTransactions(_ context.Context, request *pb.Creat
 		return nil, err
 	}
 
+	// The change is calculated from the mass of the actual transaction. Without
+	// change, whatever is left after the payment is paid as fee.
+	var fee *libkaspawallet.Fee
+	if hasChange {
+		fee = s.fee(fees, changeAddress)
+	}
+	payments := []*libkaspawallet.Payment{{
+		Address: toAddress,
+		Amount:  request.Amount,
+	}}
 	unsignedTransaction, err := libkaspawallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
-		s.keysFile.MinimumSignatures,
-		[]*libkaspawallet.Payment{{
-			Address: toAddress,
-			Amount:  request.Amount,
-		}, {
-			Address: changeAddress,
-			Amount:  changeSompi,
-		}}, selectedUTXOs)
+		s.keysFile.MinimumSignatures, payments, selectedUTXOs, fee)
 	if err != nil {
 		return nil, err
 	}
 
-	unsignedTransactions, err := s.maybeAutoCompoundTransaction(unsignedTransaction, toAddress, changeAddress, changeWalletAddress)
+	unsignedTransactions, err := s.maybeAutoCompoundTransaction(unsignedTransaction, toAddress, changeAddress,
+		changeWalletAddress, fees, selection)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.CreateUnsignedTransactionsResponse{UnsignedTransactions: unsignedTransactions}, nil
+	return &pb.CreateUnsignedTransactionsResponse{
+		UnsignedTransactions: unsignedTransactions,
+		ExtraFee:             extraFee,
+	}, nil
 }
 
-func (s *server) selectUTXOs(spendAmount uint64, feePerInput uint64) (
-	selectedUTXOs []*libkaspawallet.UTXO, changeSompi uint64, err error) {
-
-	selectedUTXOs = []*libkaspawallet.UTXO{}
-	totalValue := uint64(0)
+// selectUTXOs selects the UTXOs to spend according to the given selection, and returns them
+// together with whether some change is left for a change output, and the extra fee paid to
+// avoid a change output
+func (s *server) selectUTXOs(spendAmount uint64, fees *transactionFees, selection *utxoSelection) (
+	selectedUTXOs []*libkaspawallet.UTXO, hasChange bool, extraFee uint64, err error) {
 
 	dagInfo, err := s.rpcClient.GetBlockDAGInfo()
 	if err != nil {
-		return nil, 0, err
+		return nil, false, 0, err
 	}
 
-	for _, utxo := range s.utxosSortedByAmount {
-		if !isUTXOSpendable(utxo, dagInfo.VirtualDAAScore, s.params.BlockCoinbaseMaturity) {
-			continue
-		}
+	mandatory, optional, err := s.candidateUTXOs(selection, dagInfo.VirtualDAAScore)
+	if err != nil {
+		return nil, false, 0, err
+	}
 
-		selectedUTXOs = append(selectedUTXOs, &libkaspawallet.UTXO{
+	// The base fee is paid once regardless of the inputs, so it's selected for like the spent amount
+	selectedWalletUTXOs, isChangeless := selectWalletUTXOs(mandatory, optional, spendAmount+fees.baseFee,
+		fees.feePerInput, selection.strategy)
+
+	selectedUTXOs = make([]*libkaspawallet.UTXO, len(selectedWalletUTXOs))
+	for i, utxo := range selectedWalletUTXOs {
+		selectedUTXOs[i] = &libkaspawallet.UTXO{
 			Outpoint:       utxo.Outpoint,
 			UTXOEntry:      utxo.UTXOEntry,
 			DerivationPath: s.walletAddressPath(utxo.address),
-		})
-		totalValue += utxo.UTXOEntry.Amount()
-
-		fee := feePerInput * uint64(len(selectedUTXOs))
-		totalSpend := spendAmount + fee
-		if totalValue >= totalSpend {
-			break
 		}
 	}
 
-	fee := feePerInput * uint64(len(selectedUTXOs))
-	totalSpend := spendAmount + fee
+	totalValue := utxosValue(selectedWalletUTXOs)
+	totalSpend := spendAmount + fees.forInputs(len(selectedUTXOs))
 	if totalValue < totalSpend {
-		return nil, 0, errors.Errorf("Insufficient funds for send: %f required, while only %f available",
+		return nil, false, 0, errors.Errorf("Insufficient funds for send: %f required, while only %f available",
 			float64(totalSpend)/constants.SompiPerKaspa, float64(totalValue)/constants.SompiPerKaspa)
 	}
 
-	return selectedUTXOs, totalValue - totalSpend, nil
+	// The excess of a changeless selection is paid as fee. It's bounded by the cost
+	// of the change output it saves, and is reported to the caller.
+	excess := totalValue - totalSpend
+	if isChangeless && excess <= fees.feePerInput {
+		return selectedUTXOs, false, excess, nil
+	}
+	return selectedUTXOs, excess > 0, 0, nil
+}
+
+// selectWalletUTXOs selects all the mandatory UTXOs, and if they don't pay for spendAmount and their own fees,
+// lets the given strategy select optional UTXOs that pay for the rest. The returned boolean is true if the
+// selection was made not to need a change output.
+func selectWalletUTXOs(mandatory []*walletUTXO, optional []*walletUTXO, spendAmount uint64, feePerInput uint64,
+	strategy pb.CoinSelectionStrategy) (selectedUTXOs []*walletUTXO, isChangeless bool) {
+
+	mandatorySpend := spendAmount + feePerInput*uint64(len(mandatory))
+	mandatoryValue := utxosValue(mandatory)
+	if mandatoryValue >= mandatorySpend {
+		return mandatory, false
+	}
+
+	remainingAmount := mandatorySpend - mandatoryValue
+	var additionalUTXOs []*walletUTXO
+	switch strategy {
+	case pb.CoinSelectionStrategy_BRANCH_AND_BOUND:
+		additionalUTXOs = selectUTXOsBranchAndBound(optional, remainingAmount, feePerInput)
+		if additionalUTXOs != nil {
+			isChangeless = true
+		} else {
+			additionalUTXOs = selectUTXOsGreedily(optional, remainingAmount, feePerInput)
+		}
+	case pb.CoinSelectionStrategy_SMALLEST_FIRST:
+		additionalUTXOs = selectUTXOsSmallestFirst(optional, remainingAmount, feePerInput)
+	default:
+		additionalUTXOs = selectUTXOsGreedily(optional, remainingAmount, feePerInput)
+	}
+
+	selectedUTXOs = make([]*walletUTXO, 0, len(mandatory)+len(additionalUTXOs))
+	selectedUTXOs = append(selectedUTXOs, mandatory...)
+	return append(selectedUTXOs, additionalUTXOs...), isChangeless
+}
+
+func utxosValue(utxos []*walletUTXO) uint64 {
+	value := uint64(0)
+	for _, utxo := range utxos {
+		value += utxo.UTXOEntry.Amount()
+	}
+	return value
+}
+
+// feeRate returns the requested fee rate, or the one suggested by kaspad
+// if none was requested
+func (s *server) feeRate(requestedFeeRate float64) (float64, error) {
+	if requestedFeeRate < 0 {
+		return 0, errors.Errorf("Fee rate cannot be negative, but got %f", requestedFeeRate)
+	}
+	if requestedFeeRate > 0 {
+		return requestedFeeRate, nil
+	}
+
+	feeEstimate, err := s.rpcClient.GetFeeEstimate()
+	if err != nil {
+		return 0, err
+	}
+	return feeEstimate.NormalBucket.FeeRate, nil
+}
+
+// transactionFees are the estimated fees of the transactions this wallet creates. The UTXOs
+// are selected before the transaction is created, so its fee is estimated as a base fee that
+// every transaction pays once, and a fee that every input adds to it.
+type transactionFees struct {
+	feeRate     float64
+	baseFee     uint64
+	feePerInput uint64
+}
+
+// forInputs returns the estimated fee of a transaction with the given amount of inputs
+func (tf *transactionFees) forInputs(inputCount int) uint64 {
+	return tf.baseFee + tf.feePerInput*uint64(inputCount)
+}
+
+// fee returns the fee of a transaction that sends its change to the given address
+func (s *server) fee(fees *transactionFees, changeAddress util.Address) *libkaspawallet.Fee {
+	return &libkaspawallet.Fee{
+		Rate:             fees.feeRate,
+		ChangeAddress:    changeAddress,
+		ECDSA:            s.keysFile.ECDSA,
+		TxMassCalculator: s.txMassCalculator,
+	}
+}
+
+// estimateTransactionFees estimates the fees of transactions created by this wallet so that
+// they pay at least the given fee rate. The base fee is based on the mass of a transaction with
+// a payment and a change output but no inputs, and the fee per input on the mass a single input
+// adds to it.
+func (s *server) estimateTransactionFees(feeRate float64, toAddress util.Address) (*transactionFees, error) {
+	// The mass doesn't depend on the derivation index, so we use the last used
+	// change address instead of generating a new one
+	walletAddr := &walletAddress{
+		index:         s.keysFile.LastUsedInternalIndex(),
+		cosignerIndex: s.keysFile.CosignerIndex,
+		keyChain:      libkaspawallet.InternalKeychain,
+	}
+	path := s.walletAddressPath(walletAddr)
+	address, err := libkaspawallet.Address(s.params, s.keysFile.ExtendedPublicKeys, s.keysFile.MinimumSignatures, path, s.keysFile.ECDSA)
+	if err != nil {
+		return nil, err
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := []*libkaspawallet.Payment{{
+		Address: toAddress,
+		Amount:  0,
+	}, {
+		Address: address,
+		Amount:  0,
+	}}
+	massWithoutInputs, err := s.estimateDummyTransactionMass(payments, nil)
+	if err != nil {
+		return nil, err
+	}
+	massWithInput, err := s.estimateDummyTransactionMass(payments, []*libkaspawallet.UTXO{{
+		Outpoint:       &externalapi.DomainOutpoint{},
+		UTXOEntry:      utxo.NewUTXOEntry(0, scriptPublicKey, false, constants.UnacceptedDAAScore),
+		DerivationPath: path,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &transactionFees{
+		feeRate:     feeRate,
+		baseFee:     uint64(math.Ceil(feeRate * float64(massWithoutInputs))),
+		feePerInput: uint64(math.Ceil(feeRate * float64(massWithInput-massWithoutInputs))),
+	}, nil
+}
+
+func (s *server) estimateDummyTransactionMass(payments []*libkaspawallet.Payment, utxos []*libkaspawallet.UTXO) (
+	uint64, error) {
+
+	dummyTransactionBytes, err := libkaspawallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
+		s.keysFile.MinimumSignatures, payments, utxos, nil)
+	if err != nil {
+		return 0, err
+	}
+	dummyTransaction, err := serialization.DeserializePartiallySignedTransaction(dummyTransactionBytes)
+	if err != nil {
+		return 0, err
+	}
+	return s.estimateMassAfterSignatures(dummyTransaction)
 }
@@ -0,0 +1,451 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+// historyFileVersion is the most up to date history file format version
+const historyFileVersion = 1
+
+// pendingSpendExpiry is how long a pending spend of an outpoint the history
+// doesn't track is kept. Such outpoints are unconfirmed outputs, so if the
+// spend wasn't attributed by then, the outpoint either never got accepted
+// or was accepted together with its spending transaction.
+const pendingSpendExpiry = 24 * time.Hour
+
+// historyOutput is a wallet output that was either received or spent
+// by some transaction
+type historyOutput struct {
+	TransactionID string `json:"transactionId"`
+	Index         uint32 `json:"index"`
+	Address       string `json:"address"`
+	Amount        uint64 `json:"amount"`
+}
+
+func (ho *historyOutput) outpointString() string {
+	return outpointString(ho.TransactionID, ho.Index)
+}
+
+// historyTransaction is a transaction that either paid to the wallet
+// or spent some of its outputs. TransactionID is empty for spends
+// that were not broadcast by this daemon, because the spending
+// transaction can't be inferred from the UTXO set alone. Each such
+// spend gets its own historyTransaction, identified by SpentOutpoint.
+type historyTransaction struct {
+	TransactionID string           `json:"transactionId"`
+	SpentOutpoint string           `json:"spentOutpoint,omitempty"`
+	Timestamp     int64            `json:"timestamp"`
+	DAAScore      uint64           `json:"daaScore"`
+	Received      []*historyOutput `json:"received"`
+	Spent         []*historyOutput `json:"spent"`
+}
+
+// key returns the key that identifies the transaction in the history
+// and in the transaction labels. Outpoints can't collide with transaction
+// IDs since they contain a colon.
+func (ht *historyTransaction) key() string {
+	if ht.TransactionID != "" {
+		return ht.TransactionID
+	}
+	return ht.SpentOutpoint
+}
+
+func (ht *historyTransaction) receivedAmount() uint64 {
+	amount := uint64(0)
+	for _, output := range ht.Received {
+		amount += output.Amount
+	}
+	return amount
+}
+
+func (ht *historyTransaction) spentAmount() uint64 {
+	amount := uint64(0)
+	for _, output := range ht.Spent {
+		amount += output.Amount
+	}
+	return amount
+}
+
+func (ht *historyTransaction) addresses() []string {
+	addresses := make([]string, 0, len(ht.Received)+len(ht.Spent))
+	seen := make(map[string]struct{})
+	for _, outputs := range [][]*historyOutput{ht.Spent, ht.Received} {
+		for _, output := range outputs {
+			if _, ok := seen[output.Address]; ok {
+				continue
+			}
+			seen[output.Address] = struct{}{}
+			addresses = append(addresses, output.Address)
+		}
+	}
+	return addresses
+}
+
+// pendingSpend is a spend of a wallet output by a transaction this daemon
+// broadcast, which wasn't accepted yet
+type pendingSpend struct {
+	TransactionID string `json:"transactionId"`
+	Timestamp     int64  `json:"timestamp"`
+}
+
+type historyFileJSON struct {
+	Version           uint32                   `json:"version"`
+	Transactions      []*historyTransaction    `json:"transactions"`
+	PendingSpends     map[string]*pendingSpend `json:"pendingSpends"`
+	TransactionLabels map[string]string        `json:"transactionLabels"`
+	AddressLabels     map[string]string        `json:"addressLabels"`
+}
+
+// transactionHistory tracks the transactions relevant to the wallet by
+// comparing consecutive snapshots of the wallet's UTXO set, and persists
+// them together with the user's labels
+type transactionHistory struct {
+	path string
+
+	transactions []*historyTransaction
+
+	// pendingSpends maps outpoints spent by transactions this daemon
+	// broadcast to the spending transactions
+	pendingSpends     map[string]*pendingSpend
+	transactionLabels map[string]string
+	addressLabels     map[string]string
+
+	// unspentOutputs maps the outpoints received by the wallet that
+	// weren't spent yet to the outputs themselves
+	unspentOutputs map[string]*historyOutput
+
+	// transactionsByKey and spendingTransactions index transactions by
+	// their keys and by the outpoints they spend respectively
+	transactionsByKey    map[string]*historyTransaction
+	spendingTransactions map[string]*historyTransaction
+}
+
+// historyFilePath returns the path of the history file that belongs to
+// the given keys file. It is kept next to the keys file.
+func historyFilePath(keysFilePath string) string {
+	extension := filepath.Ext(keysFilePath)
+	return strings.TrimSuffix(keysFilePath, extension) + "-history.json"
+}
+
+func newTransactionHistory(path string) *transactionHistory {
+	return &transactionHistory{
+		path:              path,
+		transactions:      []*historyTransaction{},
+		pendingSpends:     make(map[string]*pendingSpend),
+		transactionLabels: make(map[string]string),
+		addressLabels:     make(map[string]string),
+		unspentOutputs:    make(map[string]*historyOutput),
+
+		transactionsByKey:    make(map[string]*historyTransaction),
+		spendingTransactions: make(map[string]*historyTransaction),
+	}
+}
+
+// readTransactionHistory reads the history file at the given path. If
+// the file doesn't exist yet, an empty history is returned.
+func readTransactionHistory(path string) (*transactionHistory, error) {
+	history := newTransactionHistory(path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	decodedFile := &historyFileJSON{}
+	err = json.NewDecoder(file).Decode(decodedFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding history file %s", path)
+	}
+	if decodedFile.Version != historyFileVersion {
+		return nil, errors.Errorf("unsupported history file version %d", decodedFile.Version)
+	}
+
+	for _, transaction := range decodedFile.Transactions {
+		history.addTransaction(transaction)
+	}
+	if decodedFile.PendingSpends != nil {
+		history.pendingSpends = decodedFile.PendingSpends
+	}
+	if decodedFile.TransactionLabels != nil {
+		history.transactionLabels = decodedFile.TransactionLabels
+	}
+	if decodedFile.AddressLabels != nil {
+		history.addressLabels = decodedFile.AddressLabels
+	}
+
+	for _, transaction := range history.transactions {
+		for _, output := range transaction.Received {
+			history.unspentOutputs[output.outpointString()] = output
+		}
+	}
+	for outpoint := range history.spendingTransactions {
+		delete(history.unspentOutputs, outpoint)
+	}
+
+	return history, nil
+}
+
+func (th *transactionHistory) save() error {
+	err := os.MkdirAll(filepath.Dir(th.path), 0700)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that a crash in the
+	// middle of writing won't corrupt the existing history
+	tempPath := th.path + ".tmp"
+	file, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(file).Encode(&historyFileJSON{
+		Version:           historyFileVersion,
+		Transactions:      th.transactions,
+		PendingSpends:     th.pendingSpends,
+		TransactionLabels: th.transactionLabels,
+		AddressLabels:     th.addressLabels,
+	})
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, th.path)
+}
+
+// addPendingSpends records that the given outpoints are spent by the
+// transaction with the given ID, so that once they disappear from the UTXO
+// set the spend is attributed to that transaction
+func (th *transactionHistory) addPendingSpends(transactionID string, outpoints []string, now time.Time) error {
+	for _, outpoint := range outpoints {
+		th.pendingSpends[outpoint] = &pendingSpend{
+			TransactionID: transactionID,
+			Timestamp:     now.UnixMilli(),
+		}
+	}
+	return th.save()
+}
+
+// transactionByKey returns the transaction with the given key, as returned
+// by historyTransaction.key, or nil if there's no such transaction
+func (th *transactionHistory) transactionByKey(key string) *historyTransaction {
+	return th.transactionsByKey[key]
+}
+
+// addTransaction appends the given transaction to the history and indexes it
+func (th *transactionHistory) addTransaction(transaction *historyTransaction) {
+	th.transactions = append(th.transactions, transaction)
+	th.transactionsByKey[transaction.key()] = transaction
+	for _, output := range transaction.Spent {
+		th.spendingTransactions[output.outpointString()] = transaction
+	}
+}
+
+// update compares the given UTXO set of the wallet with the outputs
+// known to be unspent, records any received and spent outputs, and
+// saves the history if anything changed. The history is made of accepted
+// transactions only, so unconfirmed entries are used only to reconcile
+// the pending spends.
+func (th *transactionHistory) update(entries []*appmessage.UTXOsByAddressesEntry, now time.Time) error {
+	timestamp := now.UnixMilli()
+	hasChanged := false
+
+	utxoSet := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.IsUnconfirmed {
+			continue
+		}
+		outpoint := outpointString(entry.Outpoint.TransactionID, entry.Outpoint.Index)
+		utxoSet[outpoint] = struct{}{}
+		if _, ok := th.unspentOutputs[outpoint]; ok {
+			continue
+		}
+
+		// The output might have been marked as spent before, and re-appeared
+		// due to a reorg. In that case we remove its spend from the history.
+		if output, ok := th.removeSpend(outpoint); ok {
+			th.unspentOutputs[outpoint] = output
+			hasChanged = true
+			continue
+		}
+
+		output := &historyOutput{
+			TransactionID: entry.Outpoint.TransactionID,
+			Index:         entry.Outpoint.Index,
+			Address:       entry.Address,
+			Amount:        entry.UTXOEntry.Amount,
+		}
+		transaction := th.transactionByKey(output.TransactionID)
+		if transaction == nil {
+			transaction = &historyTransaction{
+				TransactionID: output.TransactionID,
+				Timestamp:     timestamp,
+				DAAScore:      entry.UTXOEntry.BlockDAAScore,
+			}
+			th.addTransaction(transaction)
+		}
+		if transaction.DAAScore == 0 || entry.UTXOEntry.BlockDAAScore < transaction.DAAScore {
+			transaction.DAAScore = entry.UTXOEntry.BlockDAAScore
+		}
+		transaction.Received = append(transaction.Received, output)
+		th.unspentOutputs[outpoint] = output
+		hasChanged = true
+	}
+
+	for outpoint, output := range th.unspentOutputs {
+		if _, ok := utxoSet[outpoint]; ok {
+			continue
+		}
+
+		spend, ok := th.pendingSpends[outpoint]
+		if ok {
+			delete(th.pendingSpends, outpoint)
+			transaction := th.transactionByKey(spend.TransactionID)
+			if transaction == nil {
+				transaction = &historyTransaction{
+					TransactionID: spend.TransactionID,
+					Timestamp:     timestamp,
+				}
+				th.addTransaction(transaction)
+			}
+			transaction.Spent = append(transaction.Spent, output)
+			th.spendingTransactions[outpoint] = transaction
+		} else {
+			// It's unknown which outputs were spent together, so every
+			// unknown spend is recorded separately
+			th.addTransaction(&historyTransaction{
+				SpentOutpoint: outpoint,
+				Timestamp:     timestamp,
+				Spent:         []*historyOutput{output},
+			})
+		}
+
+		delete(th.unspentOutputs, outpoint)
+		hasChanged = true
+	}
+
+	if th.reconcilePendingSpends(entries, now) {
+		hasChanged = true
+	}
+
+	if !hasChanged {
+		return nil
+	}
+	return th.save()
+}
+
+// reconcilePendingSpends removes the pending spends whose transactions can
+// no longer be accepted. It must be called after the spent outputs were
+// attributed, and returns whether any pending spend was removed.
+func (th *transactionHistory) reconcilePendingSpends(entries []*appmessage.UTXOsByAddressesEntry, now time.Time) bool {
+	if len(th.pendingSpends) == 0 {
+		return false
+	}
+
+	hasChanged := false
+	presentOutpoints := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		outpoint := outpointString(entry.Outpoint.TransactionID, entry.Outpoint.Index)
+		presentOutpoints[outpoint] = struct{}{}
+
+		// An outpoint that is still unspent in both the DAG and the mempool
+		// means that the spending transaction was dropped from the mempool
+		if _, ok := th.pendingSpends[outpoint]; ok && !entry.IsSpentByUnconfirmed {
+			delete(th.pendingSpends, outpoint)
+			hasChanged = true
+		}
+	}
+
+	expiryTimestamp := now.Add(-pendingSpendExpiry).UnixMilli()
+	for outpoint, spend := range th.pendingSpends {
+		if _, ok := presentOutpoints[outpoint]; ok {
+			continue
+		}
+		if _, ok := th.unspentOutputs[outpoint]; ok {
+			continue
+		}
+		if spend.Timestamp < expiryTimestamp {
+			delete(th.pendingSpends, outpoint)
+			hasChanged = true
+		}
+	}
+	return hasChanged
+}
+
+// removeSpend removes the spend of the given outpoint from the history,
+// together with the spending transaction if nothing else is left in it.
+// It returns the spent output, if found.
+func (th *transactionHistory) removeSpend(outpoint string) (*historyOutput, bool) {
+	transaction, ok := th.spendingTransactions[outpoint]
+	if !ok {
+		return nil, false
+	}
+	delete(th.spendingTransactions, outpoint)
+
+	var spentOutput *historyOutput
+	for i, output := range transaction.Spent {
+		if output.outpointString() == outpoint {
+			spentOutput = output
+			transaction.Spent = append(transaction.Spent[:i], transaction.Spent[i+1:]...)
+			break
+		}
+	}
+
+	if len(transaction.Spent) == 0 && len(transaction.Received) == 0 {
+		th.removeTransaction(transaction)
+	}
+	return spentOutput, true
+}
+
+// removeTransaction removes the given transaction from the history.
+// This only happens on reorgs, so the linear scan over the transactions
+// is acceptable.
+func (th *transactionHistory) removeTransaction(transaction *historyTransaction) {
+	delete(th.transactionsByKey, transaction.key())
+	for i, other := range th.transactions {
+		if other == transaction {
+			th.transactions = append(th.transactions[:i], th.transactions[i+1:]...)
+			return
+		}
+	}
+}
+
+func (th *transactionHistory) setTransactionLabel(transactionID string, label string) error {
+	setOrDeleteLabel(th.transactionLabels, transactionID, label)
+	return th.save()
+}
+
+func (th *transactionHistory) setAddressLabel(address string, label string) error {
+	setOrDeleteLabel(th.addressLabels, address, label)
+	return th.save()
+}
+
+func setOrDeleteLabel(labels map[string]string, key string, label string) {
+	if label == "" {
+		delete(labels, key)
+		return
+	}
+	labels[key] = label
+}
+
+func outpointString(transactionID string, index uint32) string {
+	return fmt.Sprintf("%s:%d", transactionID, index)
+}
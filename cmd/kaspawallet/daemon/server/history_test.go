@@ -0,0 +1,217 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func historyTestUTXO(transactionID string, index uint32, address string, amount uint64,
+	daaScore uint64) *appmessage.UTXOsByAddressesEntry {
+
+	return &appmessage.UTXOsByAddressesEntry{
+		Address:  address,
+		Outpoint: &appmessage.RPCOutpoint{TransactionID: transactionID, Index: index},
+		UTXOEntry: &appmessage.RPCUTXOEntry{
+			Amount:        amount,
+			BlockDAAScore: daaScore,
+		},
+	}
+}
+
+func TestTransactionHistory(t *testing.T) {
+	dir, err := os.MkdirTemp("", "TestTransactionHistory")
+	if err != nil {
+		t.Fatalf("MkdirTemp: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := historyFilePath(filepath.Join(dir, "keys.json"))
+	history, err := readTransactionHistory(path)
+	if err != nil {
+		t.Fatalf("readTransactionHistory: %s", err)
+	}
+
+	now := time.Now()
+	received1 := historyTestUTXO("aa", 0, "address1", 100, 10)
+	received2 := historyTestUTXO("aa", 1, "address2", 50, 10)
+	received3 := historyTestUTXO("bb", 0, "address1", 70, 20)
+	received4 := historyTestUTXO("bb", 1, "address1", 30, 20)
+
+	// Two transactions pay to the wallet
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received1, received2, received3, received4}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if len(history.transactions) != 2 {
+		t.Fatalf("Unexpected amount of transactions. Want: 2, got: %d", len(history.transactions))
+	}
+	if history.transactions[0].receivedAmount() != 150 {
+		t.Fatalf("Unexpected received amount. Want: 150, got: %d", history.transactions[0].receivedAmount())
+	}
+
+	// Updating with the same UTXO set shouldn't change anything
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received1, received2, received3, received4}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if len(history.transactions) != 2 {
+		t.Fatalf("Unexpected amount of transactions. Want: 2, got: %d", len(history.transactions))
+	}
+
+	// The wallet broadcasts a transaction that spends received1 and sends
+	// change back to the wallet, and received3 and received4 are spent by
+	// someone else
+	err = history.addPendingSpends("cc", []string{outpointString("aa", 0)}, now)
+	if err != nil {
+		t.Fatalf("addPendingSpends: %s", err)
+	}
+	change := historyTestUTXO("cc", 1, "address3", 60, 30)
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received2, change}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if len(history.transactions) != 5 {
+		t.Fatalf("Unexpected amount of transactions. Want: 5, got: %d", len(history.transactions))
+	}
+	sendTransaction := history.transactionByKey("cc")
+	if sendTransaction == nil {
+		t.Fatalf("Missing the sending transaction")
+	}
+	if sendTransaction.spentAmount() != 100 || sendTransaction.receivedAmount() != 60 {
+		t.Fatalf("Unexpected amounts of the sending transaction. Want: 100 spent and 60 received, "+
+			"got: %d spent and %d received", sendTransaction.spentAmount(), sendTransaction.receivedAmount())
+	}
+	// Unknown spends are recorded separately, keyed by the spent outpoint
+	if history.transactionByKey("") != nil {
+		t.Fatalf("Expected no transaction with an empty key")
+	}
+	unknownSpendTransaction := history.transactionByKey(outpointString("bb", 0))
+	if unknownSpendTransaction == nil || unknownSpendTransaction.spentAmount() != 70 {
+		t.Fatalf("Expected an unknown transaction spending 70, got: %+v", unknownSpendTransaction)
+	}
+	otherUnknownSpendTransaction := history.transactionByKey(outpointString("bb", 1))
+	if otherUnknownSpendTransaction == nil || otherUnknownSpendTransaction.spentAmount() != 30 {
+		t.Fatalf("Expected an unknown transaction spending 30, got: %+v", otherUnknownSpendTransaction)
+	}
+	if len(history.pendingSpends) != 0 {
+		t.Fatalf("Expected no pending spends, got: %v", history.pendingSpends)
+	}
+
+	// received3 re-appears due to a reorg, so the unknown spend should be removed
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received2, received3, change}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if history.transactionByKey(outpointString("bb", 0)) != nil {
+		t.Fatalf("Expected the unknown spend of received3 to be removed")
+	}
+	if history.transactionByKey(outpointString("bb", 1)) != otherUnknownSpendTransaction {
+		t.Fatalf("Expected the unknown spend of received4 to be kept")
+	}
+	if _, ok := history.unspentOutputs[outpointString("bb", 0)]; !ok {
+		t.Fatalf("Expected received3 to be unspent")
+	}
+	if len(history.transactions[1].Received) != 2 {
+		t.Fatalf("Expected received3 and received4 to be recorded exactly once, got: %d outputs",
+			len(history.transactions[1].Received))
+	}
+
+	err = history.setTransactionLabel("cc", "rent")
+	if err != nil {
+		t.Fatalf("setTransactionLabel: %s", err)
+	}
+	err = history.setTransactionLabel(outpointString("bb", 1), "groceries")
+	if err != nil {
+		t.Fatalf("setTransactionLabel: %s", err)
+	}
+	err = history.setAddressLabel("address1", "savings")
+	if err != nil {
+		t.Fatalf("setAddressLabel: %s", err)
+	}
+
+	// Make sure the history survives a restart
+	readHistory, err := readTransactionHistory(path)
+	if err != nil {
+		t.Fatalf("readTransactionHistory: %s", err)
+	}
+	if !reflect.DeepEqual(readHistory, history) {
+		t.Fatalf("Read history is not equal to the saved one. Want: %+v, got: %+v", history, readHistory)
+	}
+
+	err = readHistory.setAddressLabel("address1", "")
+	if err != nil {
+		t.Fatalf("setAddressLabel: %s", err)
+	}
+	if _, ok := readHistory.addressLabels["address1"]; ok {
+		t.Fatalf("Expected the label of address1 to be removed")
+	}
+}
+
+func TestReconcilePendingSpends(t *testing.T) {
+	history := newTransactionHistory(filepath.Join(t.TempDir(), "keys-history.json"))
+
+	now := time.Now()
+	received := historyTestUTXO("aa", 0, "address1", 100, 10)
+	err := history.update([]*appmessage.UTXOsByAddressesEntry{received}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+
+	// The wallet broadcasts a transaction that spends received, and one that
+	// spends the unconfirmed change of another transaction
+	unconfirmedChange := outpointString("bb", 1)
+	err = history.addPendingSpends("cc", []string{outpointString("aa", 0)}, now)
+	if err != nil {
+		t.Fatalf("addPendingSpends: %s", err)
+	}
+	err = history.addPendingSpends("dd", []string{unconfirmedChange}, now)
+	if err != nil {
+		t.Fatalf("addPendingSpends: %s", err)
+	}
+
+	// Both transactions are in the mempool, so nothing should be removed
+	spentReceived := historyTestUTXO("aa", 0, "address1", 100, 10)
+	spentReceived.IsSpentByUnconfirmed = true
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{spentReceived}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if len(history.pendingSpends) != 2 {
+		t.Fatalf("Expected 2 pending spends, got: %v", history.pendingSpends)
+	}
+
+	// The first transaction is dropped from the mempool, so its outpoint is unspent again
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received}, now)
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if _, ok := history.pendingSpends[outpointString("aa", 0)]; ok {
+		t.Fatalf("Expected the pending spend of the dropped transaction to be removed")
+	}
+
+	// The unconfirmed change is never seen in the UTXO set, so its pending
+	// spend is kept only until it expires
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received}, now.Add(pendingSpendExpiry/2))
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if _, ok := history.pendingSpends[unconfirmedChange]; !ok {
+		t.Fatalf("Expected the pending spend of the unconfirmed change to be kept")
+	}
+	err = history.update([]*appmessage.UTXOsByAddressesEntry{received}, now.Add(2*pendingSpendExpiry))
+	if err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if len(history.pendingSpends) != 0 {
+		t.Fatalf("Expected no pending spends, got: %v", history.pendingSpends)
+	}
+	if len(history.transactions) != 1 {
+		t.Fatalf("Expected the history to contain only the receiving transaction, got: %d transactions",
+			len(history.transactions))
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 	"github.com/kaspanet/kaspad/infrastructure/os/signal"
 	"github.com/kaspanet/kaspad/util/panics"
 	"github.com/pkg/errors"
@@ -35,10 +36,13 @@ type server struct {
 	shutdown            chan struct{}
 	addressSet          walletAddressSet
 	txMassCalculator    *txmass.Calculator
+	transactionHistory  *transactionHistory
 }
 
 // Start starts the kaspawalletd server
-func Start(params *dagconfig.Params, listen, rpcServer string, keysFilePath string, profile string) error {
+func Start(params *dagconfig.Params, listen, rpcServer string, rpcConnectOptions *grpcclient.ConnectOptions,
+	keysFilePath string, profile string) error {
+
 	initLog(defaultLogFile, defaultErrLogFile)
 
 	defer panics.HandlePanic(log, "MAIN", nil)
@@ -54,7 +58,7 @@ func Start(params *dagconfig.Params, listen, rpcServer string, keysFilePath stri
 	}
 	log.Infof("Listening on %s", listen)
 
-	rpcClient, err := connectToRPC(params, rpcServer)
+	rpcClient, err := connectToRPC(params, rpcServer, rpcConnectOptions)
 	if err != nil {
 		return (errors.Wrapf(err, "Error connecting to RPC server %s", rpcServer))
 	}
@@ -63,6 +67,14 @@ func Start(params *dagconfig.Params, listen, rpcServer string, keysFilePath stri
 	if err != nil {
 		return (errors.Wrapf(err, "Error connecting to RPC server %s", rpcServer))
 	}
+	if keysFile.IsWatchOnly() {
+		log.Infof("The wallet is watch-only. Transactions created by it must be signed elsewhere")
+	}
+
+	transactionHistory, err := readTransactionHistory(historyFilePath(keysFile.Path()))
+	if err != nil {
+		return (errors.Wrapf(err, "Error reading the transaction history"))
+	}
 
 	serverInstance := &server{
 		rpcClient:           rpcClient,
@@ -73,6 +85,7 @@ func Start(params *dagconfig.Params, listen, rpcServer string, keysFilePath stri
 		shutdown:            make(chan struct{}),
 		addressSet:          make(walletAddressSet),
 		txMassCalculator:    txmass.NewCalculator(params.MassPerTxByte, params.MassPerScriptPubKeyByte, params.MassPerSigOp),
+		transactionHistory:  transactionHistory,
 	}
 
 	spawn("serverInstance.sync", func() {
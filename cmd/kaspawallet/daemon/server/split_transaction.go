@@ -1,7 +1,6 @@
 package server
 
 import (
-	"github.com/kaspanet/go-secp256k1"
 	"github.com/pkg/errors"
 
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
@@ -21,18 +20,21 @@ import (
 // An additional `mergeTransaction` is generated - which merges the outputs of the above splits into a single output
 // paying to the original transaction's payee.
 func (s *server) maybeAutoCompoundTransaction(transactionBytes []byte, toAddress util.Address,
-	changeAddress util.Address, changeWalletAddress *walletAddress) ([][]byte, error) {
+	changeAddress util.Address, changeWalletAddress *walletAddress, fees *transactionFees,
+	selection *utxoSelection) ([][]byte, error) {
+
 	transaction, err := serialization.DeserializePartiallySignedTransaction(transactionBytes)
 	if err != nil {
 		return nil, err
 	}
 
-	splitTransactions, err := s.maybeSplitTransaction(transaction, changeAddress)
+	splitTransactions, err := s.maybeSplitTransaction(transaction, changeAddress, fees)
 	if err != nil {
 		return nil, err
 	}
 	if len(splitTransactions) > 1 {
-		mergeTransaction, err := s.mergeTransaction(splitTransactions, transaction, toAddress, changeAddress, changeWalletAddress)
+		mergeTransaction, err := s.mergeTransaction(splitTransactions, transaction, toAddress, changeAddress,
+			changeWalletAddress, fees, selection)
 		if err != nil {
 			return nil, err
 		}
@@ -55,6 +57,8 @@ func (s *server) mergeTransaction(
 	toAddress util.Address,
 	changeAddress util.Address,
 	changeWalletAddress *walletAddress,
+	fees *transactionFees,
+	selection *utxoSelection,
 ) (*serialization.PartiallySignedTransaction, error) {
 	numOutputs := len(originalTransaction.Tx.Outputs)
 	if numOutputs > 2 || numOutputs == 0 {
@@ -66,6 +70,7 @@ func (s *server) mergeTransaction(
 	}
 
 	totalValue := uint64(0)
+	estimatedFee := fees.baseFee
 	sentValue := originalTransaction.Tx.Outputs[0].Value
 	utxos := make([]*libkaspawallet.UTXO, len(splitTransactions))
 	for i, splitTransaction := range splitTransactions {
@@ -79,33 +84,27 @@ func (s *server) mergeTransaction(
 			DerivationPath: s.walletAddressPath(changeWalletAddress),
 		}
 		totalValue += output.Value
-		totalValue -= feePerInput
+		estimatedFee += fees.feePerInput
 	}
 
-	if totalValue < sentValue {
+	if totalValue < sentValue+estimatedFee {
 		// sometimes the fees from compound transactions make the total output higher than what's available from selected
 		// utxos, in such cases - find one more UTXO and use it.
-		additionalUTXOs, totalValueAdded, err := s.moreUTXOsForMergeTransaction(utxos, sentValue-totalValue)
+		additionalUTXOs, err := s.moreUTXOsForMergeTransaction(originalTransaction, utxos,
+			sentValue+estimatedFee-totalValue, fees.feePerInput, selection)
 		if err != nil {
 			return nil, err
 		}
 		utxos = append(utxos, additionalUTXOs...)
-		totalValue += totalValueAdded
 	}
 
+	// The change is calculated from the mass of the actual merge transaction
 	payments := []*libkaspawallet.Payment{{
 		Address: toAddress,
 		Amount:  sentValue,
 	}}
-	if totalValue > sentValue {
-		payments = append(payments, &libkaspawallet.Payment{
-			Address: changeAddress,
-			Amount:  totalValue - sentValue,
-		})
-	}
-
 	mergeTransactionBytes, err := libkaspawallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
-		s.keysFile.MinimumSignatures, payments, utxos)
+		s.keysFile.MinimumSignatures, payments, utxos, s.fee(fees, changeAddress))
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +113,7 @@ func (s *server) mergeTransaction(
 }
 
 func (s *server) maybeSplitTransaction(transaction *serialization.PartiallySignedTransaction,
-	changeAddress util.Address) ([]*serialization.PartiallySignedTransaction, error) {
+	changeAddress util.Address, fees *transactionFees) ([]*serialization.PartiallySignedTransaction, error) {
 
 	transactionMass, err := s.estimateMassAfterSignatures(transaction)
 	if err != nil {
@@ -135,7 +134,7 @@ func (s *server) maybeSplitTransaction(transaction *serialization.PartiallySigne
 		startIndex := i * inputCountPerSplit
 		endIndex := startIndex + inputCountPerSplit
 		var err error
-		splitTransactions[i], err = s.createSplitTransaction(transaction, changeAddress, startIndex, endIndex)
+		splitTransactions[i], err = s.createSplitTransaction(transaction, changeAddress, startIndex, endIndex, fees)
 		if err != nil {
 			return nil, err
 		}
@@ -166,7 +165,16 @@ func (s *server) splitAndInputPerSplitCounts(transaction *serialization.Partiall
 
 	// Create another dummy transaction, this time one similar to the split transactions we wish to generate,
 	// but with 0 inputs, to calculate how much mass for inputs do we have available in the split transactions
-	splitTransactionWithoutInputs, err := s.createSplitTransaction(transaction, changeAddress, 0, 0)
+	splitTransactionWithoutInputsBytes, err := libkaspawallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
+		s.keysFile.MinimumSignatures,
+		[]*libkaspawallet.Payment{{
+			Address: changeAddress,
+			Amount:  0,
+		}}, nil, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	splitTransactionWithoutInputs, err := serialization.DeserializePartiallySignedTransaction(splitTransactionWithoutInputsBytes)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -184,10 +192,9 @@ func (s *server) splitAndInputPerSplitCounts(transaction *serialization.Partiall
 }
 
 func (s *server) createSplitTransaction(transaction *serialization.PartiallySignedTransaction,
-	changeAddress util.Address, startIndex int, endIndex int) (*serialization.PartiallySignedTransaction, error) {
+	changeAddress util.Address, startIndex int, endIndex int, fees *transactionFees) (*serialization.PartiallySignedTransaction, error) {
 
 	selectedUTXOs := make([]*libkaspawallet.UTXO, 0, endIndex-startIndex)
-	totalSompi := uint64(0)
 
 	for i := startIndex; i < endIndex && i < len(transaction.PartiallySignedInputs); i++ {
 		partiallySignedInput := transaction.PartiallySignedInputs[i]
@@ -198,16 +205,11 @@ func (s *server) createSplitTransaction(transaction *serialization.PartiallySign
 				false, constants.UnacceptedDAAScore),
 			DerivationPath: partiallySignedInput.DerivationPath,
 		})
-
-		totalSompi += selectedUTXOs[i-startIndex].UTXOEntry.Amount()
-		totalSompi -= feePerInput
 	}
+
+	// The split transaction has no payments, so everything but the fee goes to the change address
 	unsignedTransactionBytes, err := libkaspawallet.CreateUnsignedTransaction(s.keysFile.ExtendedPublicKeys,
-		s.keysFile.MinimumSignatures,
-		[]*libkaspawallet.Payment{{
-			Address: changeAddress,
-			Amount:  totalSompi,
-		}}, selectedUTXOs)
+		s.keysFile.MinimumSignatures, nil, selectedUTXOs, s.fee(fees, changeAddress))
 	if err != nil {
 		return nil, err
 	}
@@ -216,51 +218,36 @@ func (s *server) createSplitTransaction(transaction *serialization.PartiallySign
 }
 
 func (s *server) estimateMassAfterSignatures(transaction *serialization.PartiallySignedTransaction) (uint64, error) {
-	transaction = transaction.Clone()
-	var signatureSize uint64
-	if s.keysFile.ECDSA {
-		signatureSize = secp256k1.SerializedECDSASignatureSize
-	} else {
-		signatureSize = secp256k1.SerializedSchnorrSignatureSize
-	}
-
-	for i, input := range transaction.PartiallySignedInputs {
-		for j, pubKeyPair := range input.PubKeySignaturePairs {
-			if uint32(j) >= s.keysFile.MinimumSignatures {
-				break
-			}
-			pubKeyPair.Signature = make([]byte, signatureSize+1) // +1 for SigHashType
-		}
-		transaction.Tx.Inputs[i].SigOpCount = byte(len(input.PubKeySignaturePairs))
-	}
-
-	transactionWithSignatures, err := libkaspawallet.ExtractTransactionDeserialized(transaction, s.keysFile.ECDSA)
-	if err != nil {
-		return 0, err
-	}
-
-	return s.txMassCalculator.CalculateTransactionMass(transactionWithSignatures), nil
+	return libkaspawallet.EstimateMassAfterSignatures(transaction, s.keysFile.ECDSA, s.txMassCalculator)
 }
 
-func (s *server) moreUTXOsForMergeTransaction(alreadySelectedUTXOs []*libkaspawallet.UTXO, requiredAmount uint64) (
-	additionalUTXOs []*libkaspawallet.UTXO, totalValueAdded uint64, err error) {
+func (s *server) moreUTXOsForMergeTransaction(originalTransaction *serialization.PartiallySignedTransaction,
+	alreadySelectedUTXOs []*libkaspawallet.UTXO, requiredAmount uint64, feePerInput uint64, selection *utxoSelection) (
+	[]*libkaspawallet.UTXO, error) {
 
 	dagInfo, err := s.rpcClient.GetBlockDAGInfo()
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	alreadySelectedUTXOsMap := make(map[externalapi.DomainOutpoint]struct{}, len(alreadySelectedUTXOs))
 	for _, alreadySelectedUTXO := range alreadySelectedUTXOs {
 		alreadySelectedUTXOsMap[*alreadySelectedUTXO.Outpoint] = struct{}{}
 	}
+	// The inputs of the original transaction are already spent by the split transactions
+	for _, input := range originalTransaction.Tx.Inputs {
+		alreadySelectedUTXOsMap[input.PreviousOutpoint] = struct{}{}
+	}
 
-	for _, utxo := range s.utxosSortedByAmount {
+	_, candidates, err := s.candidateUTXOs(selection, dagInfo.VirtualDAAScore)
+	if err != nil {
+		return nil, err
+	}
+	var additionalUTXOs []*libkaspawallet.UTXO
+	totalValueAdded := uint64(0)
+	for _, utxo := range candidates {
 		if _, ok := alreadySelectedUTXOsMap[*utxo.Outpoint]; ok {
 			continue
 		}
-		if !isUTXOSpendable(utxo, dagInfo.VirtualDAAScore, s.params.BlockCoinbaseMaturity) {
-			continue
-		}
 		additionalUTXOs = append(additionalUTXOs, &libkaspawallet.UTXO{
 			Outpoint:       utxo.Outpoint,
 			UTXOEntry:      utxo.UTXOEntry,
@@ -271,8 +258,8 @@ func (s *server) moreUTXOsForMergeTransaction(alreadySelectedUTXOs []*libkaspawa
 		}
 	}
 	if totalValueAdded < requiredAmount {
-		return nil, 0, errors.Errorf("Insufficient funds for merge transaction")
+		return nil, errors.Errorf("Insufficient funds for merge transaction")
 	}
 
-	return additionalUTXOs, totalValueAdded, nil
+	return additionalUTXOs, nil
 }
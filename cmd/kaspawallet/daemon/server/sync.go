@@ -52,11 +52,17 @@ const numIndexesToQuery = 100
 // addressesToQuery scans the addresses in the given range. Because
 // each cosigner in a multisig has its own unique path for generating
 // addresses it goes over all the cosigners and add their addresses
-// for each key chain.
+// for each key chain. A watch-only multisig wallet derives its addresses
+// from a path past those of all the cosigners, so that path is scanned too.
 func (s *server) addressesToQuery(start, end uint32) (walletAddressSet, error) {
+	cosignerPathCount := uint32(len(s.keysFile.ExtendedPublicKeys))
+	if s.keysFile.CosignerIndex >= cosignerPathCount {
+		cosignerPathCount = s.keysFile.CosignerIndex + 1
+	}
+
 	addresses := make(walletAddressSet)
 	for index := start; index < end; index++ {
-		for cosignerIndex := uint32(0); cosignerIndex < uint32(len(s.keysFile.ExtendedPublicKeys)); cosignerIndex++ {
+		for cosignerIndex := uint32(0); cosignerIndex < cosignerPathCount; cosignerIndex++ {
 			for _, keychain := range keyChains {
 				address := &walletAddress{
 					index:         index,
@@ -130,7 +136,7 @@ func (s *server) collectAddresses(start, end uint32) error {
 		return err
 	}
 
-	getBalancesByAddressesResponse, err := s.rpcClient.GetBalancesByAddresses(addressSet.strings())
+	getBalancesByAddressesResponse, err := s.rpcClient.GetBalancesByAddresses(addressSet.strings(), false)
 	if err != nil {
 		return err
 	}
@@ -208,9 +214,11 @@ func (s *server) updateUTXOSet(entries []*appmessage.UTXOsByAddressesEntry) erro
 			return errors.Errorf("Got result from address %s even though it wasn't requested", entry.Address)
 		}
 		utxos[i] = &walletUTXO{
-			Outpoint:  outpoint,
-			UTXOEntry: utxoEntry,
-			address:   address,
+			Outpoint:             outpoint,
+			UTXOEntry:            utxoEntry,
+			address:              address,
+			isUnconfirmed:        entry.IsUnconfirmed,
+			isSpentByUnconfirmed: entry.IsSpentByUnconfirmed,
 		}
 	}
 
@@ -222,12 +230,19 @@ func (s *server) updateUTXOSet(entries []*appmessage.UTXOsByAddressesEntry) erro
 }
 
 func (s *server) refreshUTXOs() error {
-	getUTXOsByAddressesResponse, err := s.rpcClient.GetUTXOsByAddresses(s.addressSet.strings())
+	// The mempool is included so that UTXOs that were already spent by a
+	// transaction that wasn't accepted yet aren't selected again
+	getUTXOsByAddressesResponse, err := s.rpcClient.GetUTXOsByAddresses(s.addressSet.strings(), true)
+	if err != nil {
+		return err
+	}
+
+	err = s.updateUTXOSet(getUTXOsByAddressesResponse.Entries)
 	if err != nil {
 		return err
 	}
 
-	return s.updateUTXOSet(getUTXOsByAddressesResponse.Entries)
+	return s.transactionHistory.update(getUTXOsByAddressesResponse.Entries, time.Now())
 }
 
 func (s *server) isSynced() bool {
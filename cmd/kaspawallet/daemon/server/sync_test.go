@@ -0,0 +1,70 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+func TestWatchOnlyMultisigAddresses(t *testing.T) {
+	params := &dagconfig.DevnetParams
+	const numKeys = 3
+	extendedPublicKeys := make([]string, numKeys)
+	for i := range extendedPublicKeys {
+		mnemonic, err := libkaspawallet.CreateMnemonic()
+		if err != nil {
+			t.Fatalf("CreateMnemonic: %s", err)
+		}
+		extendedPublicKeys[i], err = libkaspawallet.MasterPublicKeyFromMnemonic(params, mnemonic, true)
+		if err != nil {
+			t.Fatalf("MasterPublicKeyFromMnemonic: %s", err)
+		}
+	}
+
+	serverInstance := &server{
+		params: params,
+		keysFile: &keys.File{
+			ExtendedPublicKeys: extendedPublicKeys,
+			MinimumSignatures:  2,
+			CosignerIndex:      libkaspawallet.WatchOnlyCosignerIndex(extendedPublicKeys),
+		},
+	}
+
+	// The addresses of the watch-only wallet must not collide with the addresses of any cosigner
+	watchOnlyAddress, err := serverInstance.walletAddressString(&walletAddress{
+		index:         0,
+		cosignerIndex: serverInstance.keysFile.CosignerIndex,
+		keyChain:      libkaspawallet.ExternalKeychain,
+	})
+	if err != nil {
+		t.Fatalf("walletAddressString: %s", err)
+	}
+	for cosignerIndex := uint32(0); cosignerIndex < numKeys; cosignerIndex++ {
+		cosignerAddress, err := serverInstance.walletAddressString(&walletAddress{
+			index:         0,
+			cosignerIndex: cosignerIndex,
+			keyChain:      libkaspawallet.ExternalKeychain,
+		})
+		if err != nil {
+			t.Fatalf("walletAddressString: %s", err)
+		}
+		if cosignerAddress == watchOnlyAddress {
+			t.Fatalf("The address of the watch-only wallet collides with the address of cosigner %d", cosignerIndex)
+		}
+	}
+
+	// The addresses of the watch-only wallet must be scanned together with those of the cosigners
+	addresses, err := serverInstance.addressesToQuery(0, 1)
+	if err != nil {
+		t.Fatalf("addressesToQuery: %s", err)
+	}
+	if _, ok := addresses[watchOnlyAddress]; !ok {
+		t.Fatalf("The address of the watch-only wallet is not scanned")
+	}
+	expectedAddressCount := (numKeys + 1) * len(keyChains)
+	if len(addresses) != expectedAddressCount {
+		t.Fatalf("Unexpected amount of scanned addresses. Want: %d, got: %d", expectedAddressCount, len(addresses))
+	}
+}
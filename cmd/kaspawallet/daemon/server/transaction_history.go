@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+func (s *server) GetTransactionHistory(_ context.Context, _ *pb.GetTransactionHistoryRequest) (
+	*pb.GetTransactionHistoryResponse, error) {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	transactions := make([]*pb.TransactionHistoryEntry, len(s.transactionHistory.transactions))
+	for i, transaction := range s.transactionHistory.transactions {
+		transactions[i] = &pb.TransactionHistoryEntry{
+			TransactionId:  transaction.TransactionID,
+			SpentOutpoint:  transaction.SpentOutpoint,
+			Timestamp:      transaction.Timestamp,
+			DaaScore:       transaction.DAAScore,
+			ReceivedAmount: transaction.receivedAmount(),
+			SpentAmount:    transaction.spentAmount(),
+			Addresses:      transaction.addresses(),
+			Label:          s.transactionHistory.transactionLabels[transaction.key()],
+		}
+	}
+
+	addressLabels := make([]*pb.AddressLabel, 0, len(s.transactionHistory.addressLabels))
+	for address, label := range s.transactionHistory.addressLabels {
+		addressLabels = append(addressLabels, &pb.AddressLabel{
+			Address: address,
+			Label:   label,
+		})
+	}
+	sort.Slice(addressLabels, func(i, j int) bool { return addressLabels[i].Address < addressLabels[j].Address })
+
+	return &pb.GetTransactionHistoryResponse{
+		Transactions:  transactions,
+		AddressLabels: addressLabels,
+	}, nil
+}
+
+func (s *server) SetLabel(_ context.Context, request *pb.SetLabelRequest) (*pb.SetLabelResponse, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	setFieldCount := 0
+	for _, field := range []string{request.TransactionId, request.SpentOutpoint, request.Address} {
+		if field != "" {
+			setFieldCount++
+		}
+	}
+	if setFieldCount != 1 {
+		return nil, errors.New("exactly one of transaction ID, spent outpoint and address must be set")
+	}
+
+	transactionKey := request.TransactionId
+	if transactionKey == "" {
+		transactionKey = request.SpentOutpoint
+	}
+	if transactionKey != "" {
+		if s.transactionHistory.transactionByKey(transactionKey) == nil {
+			return nil, errors.Errorf("%s is not in the wallet's history", transactionKey)
+		}
+		err := s.transactionHistory.setTransactionLabel(transactionKey, request.Label)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.SetLabelResponse{}, nil
+	}
+
+	_, err := util.DecodeAddress(request.Address, s.params.Prefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid address %s", request.Address)
+	}
+	err = s.transactionHistory.setAddressLabel(request.Address, request.Label)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetLabelResponse{}, nil
+}
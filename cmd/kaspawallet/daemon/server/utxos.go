@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+func (s *server) GetUTXOs(_ context.Context, request *pb.GetUTXOsRequest) (*pb.GetUTXOsResponse, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if !s.isSynced() {
+		return nil, errors.New("server is not synced")
+	}
+
+	requestedAddresses := make(map[string]struct{}, len(request.Addresses))
+	for _, addressString := range request.Addresses {
+		address, err := util.DecodeAddress(addressString, s.params.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		requestedAddresses[address.String()] = struct{}{}
+	}
+
+	dagInfo, err := s.rpcClient.GetBlockDAGInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	utxos := make([]*pb.UTXO, 0, len(s.utxosSortedByAmount))
+	for _, utxo := range s.utxosSortedByAmount {
+		if utxo.isUnconfirmed {
+			continue
+		}
+		address, err := s.walletAddressString(utxo.address)
+		if err != nil {
+			return nil, err
+		}
+		if len(requestedAddresses) > 0 {
+			if _, ok := requestedAddresses[address]; !ok {
+				continue
+			}
+		}
+
+		utxos = append(utxos, &pb.UTXO{
+			Outpoint:      outpointToProto(utxo.Outpoint),
+			Address:       address,
+			Amount:        utxo.UTXOEntry.Amount(),
+			BlockDaaScore: utxo.UTXOEntry.BlockDAAScore(),
+			IsCoinbase:    utxo.UTXOEntry.IsCoinbase(),
+			IsSpendable:   isUTXOSpendable(utxo, dagInfo.VirtualDAAScore, s.params.BlockCoinbaseMaturity),
+		})
+	}
+
+	return &pb.GetUTXOsResponse{
+		Utxos:           utxos,
+		VirtualDaaScore: dagInfo.VirtualDAAScore,
+	}, nil
+}
+
+func outpointToProto(outpoint *externalapi.DomainOutpoint) *pb.Outpoint {
+	return &pb.Outpoint{
+		TransactionId: outpoint.TransactionID.String(),
+		Index:         outpoint.Index,
+	}
+}
+
+func outpointFromProto(protoOutpoint *pb.Outpoint) (*externalapi.DomainOutpoint, error) {
+	transactionID, err := transactionid.FromString(protoOutpoint.TransactionId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid transaction ID %s", protoOutpoint.TransactionId)
+	}
+	return &externalapi.DomainOutpoint{
+		TransactionID: *transactionID,
+		Index:         protoOutpoint.Index,
+	}, nil
+}
@@ -24,9 +24,12 @@ func dumpUnencryptedData(conf *dumpUnencryptedDataConfig) error {
 		return err
 	}
 
-	mnemonics, err := keysFile.DecryptMnemonics(conf.Password)
-	if err != nil {
-		return err
+	var mnemonics []string
+	if !keysFile.IsWatchOnly() {
+		mnemonics, err = keysFile.DecryptMnemonics(conf.Password)
+		if err != nil {
+			return err
+		}
 	}
 
 	mnemonicPublicKeys := make(map[string]struct{})
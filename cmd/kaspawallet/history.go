@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/client"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+)
+
+func history(conf *historyConfig) error {
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+	response, err := daemonClient.GetTransactionHistory(ctx, &pb.GetTransactionHistoryRequest{})
+	if err != nil {
+		return err
+	}
+
+	addressLabels := make(map[string]string, len(response.AddressLabels))
+	for _, addressLabel := range response.AddressLabels {
+		addressLabels[addressLabel.Address] = addressLabel.Label
+	}
+
+	fmt.Printf("Transactions (%d):\n", len(response.Transactions))
+	for _, transaction := range response.Transactions {
+		transactionID := transaction.TransactionId
+		if transactionID == "" {
+			transactionID = fmt.Sprintf("(%s spent by an unknown transaction)", transaction.SpentOutpoint)
+		}
+		timestamp := time.UnixMilli(transaction.Timestamp).Format("2006-01-02 15:04:05")
+		fmt.Printf("%s %-64s %s KAS %s\n", timestamp, transactionID,
+			formatSignedKas(transaction.ReceivedAmount, transaction.SpentAmount), transaction.Label)
+
+		if conf.Verbose {
+			for _, address := range transaction.Addresses {
+				fmt.Printf("\t%s %s\n", address, addressLabels[address])
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatSignedKas(received, spent uint64) string {
+	if spent > received {
+		return fmt.Sprintf("-%.8f", float64(spent-received)/constants.SompiPerKaspa)
+	}
+	return fmt.Sprintf("+%.8f", float64(received-spent)/constants.SompiPerKaspa)
+}
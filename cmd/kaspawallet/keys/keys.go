@@ -204,9 +204,19 @@ func (d *File) LastUsedInternalIndex() uint32 {
 	return d.lastUsedInternalIndex
 }
 
+// IsWatchOnly returns whether the wallet holds no private keys, in which case it can
+// be used to track balances and create unsigned transactions, but not to sign them.
+func (d *File) IsWatchOnly() bool {
+	return len(d.EncryptedMnemonics) == 0
+}
+
 // DecryptMnemonics asks the user to enter the password for the private keys and
 // returns the decrypted private keys.
 func (d *File) DecryptMnemonics(cmdLinePassword string) ([]string, error) {
+	if d.IsWatchOnly() {
+		return nil, errors.New("the wallet is watch-only and has no private keys")
+	}
+
 	password := []byte(cmdLinePassword)
 	if len(password) == 0 {
 		password = getPassword("Password:")
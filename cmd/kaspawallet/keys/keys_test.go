@@ -0,0 +1,57 @@
+package keys
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+func TestWatchOnlyFile(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	mnemonic, err := libkaspawallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %+v", err)
+	}
+
+	file, err := NewFileFromMnemonic(params, mnemonic, "password")
+	if err != nil {
+		t.Fatalf("NewFileFromMnemonic: %+v", err)
+	}
+	if file.IsWatchOnly() {
+		t.Fatalf("A wallet with a mnemonic is unexpectedly watch-only")
+	}
+
+	watchOnlyFile := &File{
+		Version:            LastVersion,
+		EncryptedMnemonics: []*EncryptedMnemonic{},
+		ExtendedPublicKeys: file.ExtendedPublicKeys,
+		MinimumSignatures:  1,
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	err = watchOnlyFile.SetPath(params, path, true)
+	if err != nil {
+		t.Fatalf("SetPath: %+v", err)
+	}
+	err = watchOnlyFile.Save()
+	if err != nil {
+		t.Fatalf("Save: %+v", err)
+	}
+
+	readFile, err := ReadKeysFile(params, path)
+	if err != nil {
+		t.Fatalf("ReadKeysFile: %+v", err)
+	}
+	if !readFile.IsWatchOnly() {
+		t.Fatalf("The read wallet is expected to be watch-only")
+	}
+	if len(readFile.ExtendedPublicKeys) != 1 || readFile.ExtendedPublicKeys[0] != file.ExtendedPublicKeys[0] {
+		t.Fatalf("Unexpected extended public keys %v", readFile.ExtendedPublicKeys)
+	}
+
+	_, err = readFile.DecryptMnemonics("password")
+	if err == nil {
+		t.Fatalf("DecryptMnemonics unexpectedly succeeded for a watch-only wallet")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/client"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+	"github.com/pkg/errors"
+)
+
+func label(conf *labelConfig) error {
+	setFlagCount := 0
+	for _, flag := range []string{conf.TransactionID, conf.SpentOutpoint, conf.Address} {
+		if flag != "" {
+			setFlagCount++
+		}
+	}
+	if setFlagCount != 1 {
+		return errors.New("Exactly one of --transaction-id, --spent-outpoint and --address must be specified")
+	}
+
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+	_, err = daemonClient.SetLabel(ctx, &pb.SetLabelRequest{
+		TransactionId: conf.TransactionID,
+		SpentOutpoint: conf.SpentOutpoint,
+		Address:       conf.Address,
+		Label:         conf.Label,
+	})
+	if err != nil {
+		return err
+	}
+
+	if conf.Label == "" {
+		fmt.Println("Label removed")
+	} else {
+		fmt.Println("Label set")
+	}
+	return nil
+}
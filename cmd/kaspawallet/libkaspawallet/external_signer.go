@@ -0,0 +1,285 @@
+package libkaspawallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/bip32"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+// ExternalSignerProtocolVersion is the version of the external signer protocol
+// that is spoken by this package
+const ExternalSignerProtocolVersion = 1
+
+// ExternalSignerRequest is a request to sign a transaction that is sent to an external signer
+type ExternalSignerRequest struct {
+	// Version is the version of the external signer protocol the request uses
+	Version uint32 `json:"version"`
+
+	// Network is the name of the network the transaction belongs to, e.g. kaspa-mainnet
+	Network string `json:"network"`
+
+	// ECDSA is whether the signatures should be ECDSA signatures rather than Schnorr signatures
+	ECDSA bool `json:"ecdsa"`
+
+	// Transaction is the hex encoding of the serialized partially signed transaction
+	Transaction string `json:"transaction"`
+}
+
+// ExternalSignerSignature is a signature of a single transaction input, made by an external signer
+type ExternalSignerSignature struct {
+	// InputIndex is the index of the signed input
+	InputIndex uint32 `json:"inputIndex"`
+
+	// ExtendedPublicKey is the extended public key of the partially signed input
+	// that the signature belongs to
+	ExtendedPublicKey string `json:"extendedPublicKey"`
+
+	// Signature is the hex encoding of the signature, with its sighash type appended
+	Signature string `json:"signature"`
+}
+
+// ExternalSignerResponse is the response of an external signer to an ExternalSignerRequest
+type ExternalSignerResponse struct {
+	Signatures []*ExternalSignerSignature `json:"signatures"`
+
+	// Error is set if the signer could not sign the transaction, in which case Signatures is ignored
+	Error string `json:"error,omitempty"`
+}
+
+// ExternalSigner signs transactions with keys that are held outside of the wallet
+type ExternalSigner interface {
+	Sign(request *ExternalSignerRequest) (*ExternalSignerResponse, error)
+}
+
+type subprocessExternalSigner struct {
+	command string
+	args    []string
+}
+
+// NewSubprocessExternalSigner returns an ExternalSigner that runs the given command with the given
+// arguments for every request. The arguments are passed as is, without being interpreted by a shell.
+// The command receives the ExternalSignerRequest encoded in JSON on its standard input, and
+// is expected to write the ExternalSignerResponse encoded in JSON to its standard output and exit.
+// The standard error of the command is passed through, so that it may be used to interact with the user.
+func NewSubprocessExternalSigner(command string, args []string) (ExternalSigner, error) {
+	if command == "" {
+		return nil, errors.New("the external signer command is empty")
+	}
+	return &subprocessExternalSigner{
+		command: command,
+		args:    args,
+	}, nil
+}
+
+func (s *subprocessExternalSigner) Sign(request *ExternalSignerRequest) (*ExternalSignerResponse, error) {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	stdout := &bytes.Buffer{}
+	cmd := exec.Command(s.command, s.args...)
+	cmd.Stdin = bytes.NewReader(requestJSON)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	response := &ExternalSignerResponse{}
+	err = json.Unmarshal(stdout.Bytes(), response)
+	if err != nil {
+		if runErr != nil {
+			return nil, errors.Wrapf(runErr, "external signer %s failed", s.command)
+		}
+		return nil, errors.Wrapf(err, "could not parse the response of external signer %s", s.command)
+	}
+	if response.Error == "" && runErr != nil {
+		return nil, errors.Wrapf(runErr, "external signer %s failed", s.command)
+	}
+	return response, nil
+}
+
+// SignWithExternalSigner signs the transaction with the given external signer. Every signature
+// returned by the signer is verified before it's added to the transaction.
+func SignWithExternalSigner(params *dagconfig.Params, signer ExternalSigner, serializedPSTx []byte, ecdsa bool) ([]byte, error) {
+	partiallySignedTransaction, err := serialization.DeserializePartiallySignedTransaction(serializedPSTx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := signer.Sign(&ExternalSignerRequest{
+		Version:     ExternalSignerProtocolVersion,
+		Network:     params.Name,
+		ECDSA:       ecdsa,
+		Transaction: hex.EncodeToString(serializedPSTx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.Errorf("the external signer failed to sign the transaction: %s", response.Error)
+	}
+
+	err = applyExternalSignatures(partiallySignedTransaction, response.Signatures, ecdsa)
+	if err != nil {
+		return nil, err
+	}
+	return serialization.SerializePartiallySignedTransaction(partiallySignedTransaction)
+}
+
+func applyExternalSignatures(partiallySignedTransaction *serialization.PartiallySignedTransaction,
+	signatures []*ExternalSignerSignature, ecdsa bool) error {
+
+	if len(signatures) == 0 {
+		return errors.New("the external signer didn't return any signatures")
+	}
+
+	populateTransactionForSigning(partiallySignedTransaction)
+	sighashReusedValues := &consensushashing.SighashReusedValues{}
+	for _, externalSignature := range signatures {
+		inputIndex := int(externalSignature.InputIndex)
+		if inputIndex >= len(partiallySignedTransaction.PartiallySignedInputs) {
+			return errors.Errorf("the external signer returned a signature for input %d, "+
+				"but the transaction has only %d inputs", inputIndex, len(partiallySignedTransaction.PartiallySignedInputs))
+		}
+
+		var signedPair *serialization.PubKeySignaturePair
+		for _, pair := range partiallySignedTransaction.PartiallySignedInputs[inputIndex].PubKeySignaturePairs {
+			if pair.ExtendedPublicKey == externalSignature.ExtendedPublicKey {
+				signedPair = pair
+				break
+			}
+		}
+		if signedPair == nil {
+			return errors.Errorf("the external signer returned a signature for input %d "+
+				"with a public key that doesn't match any of its public keys", inputIndex)
+		}
+
+		signature, err := hex.DecodeString(externalSignature.Signature)
+		if err != nil {
+			return errors.Wrapf(err, "the external signer returned a malformed signature for input %d", inputIndex)
+		}
+		err = verifySignature(partiallySignedTransaction.Tx, inputIndex, signedPair.ExtendedPublicKey,
+			signature, sighashReusedValues, ecdsa)
+		if err != nil {
+			return errors.Wrapf(err, "the external signer returned an invalid signature for input %d", inputIndex)
+		}
+
+		signedPair.Signature = signature
+	}
+
+	return nil
+}
+
+func verifySignature(tx *externalapi.DomainTransaction, inputIndex int, extendedPublicKey string, signature []byte,
+	sighashReusedValues *consensushashing.SighashReusedValues, ecdsa bool) error {
+
+	if len(signature) == 0 {
+		return errors.New("the signature is empty")
+	}
+	hashType := consensushashing.SigHashType(signature[len(signature)-1])
+	if hashType != consensushashing.SigHashAll {
+		return errors.Errorf("unexpected sighash type %d", hashType)
+	}
+	signatureWithoutHashType := signature[:len(signature)-1]
+
+	extendedKey, err := bip32.DeserializeExtendedKey(extendedPublicKey)
+	if err != nil {
+		return err
+	}
+	publicKey, err := extendedKey.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	if ecdsa {
+		hash, err := consensushashing.CalculateSignatureHashECDSA(tx, inputIndex, hashType, sighashReusedValues)
+		if err != nil {
+			return err
+		}
+		ecdsaSignature, err := secp256k1.DeserializeECDSASignatureFromSlice(signatureWithoutHashType)
+		if err != nil {
+			return err
+		}
+		secpHash := secp256k1.Hash(*hash.ByteArray())
+		if !publicKey.ECDSAVerify(&secpHash, ecdsaSignature) {
+			return errors.New("signature verification failed")
+		}
+		return nil
+	}
+
+	hash, err := consensushashing.CalculateSignatureHashSchnorr(tx, inputIndex, hashType, sighashReusedValues)
+	if err != nil {
+		return err
+	}
+	schnorrPublicKey, err := publicKey.ToSchnorr()
+	if err != nil {
+		return err
+	}
+	schnorrSignature, err := secp256k1.DeserializeSchnorrSignatureFromSlice(signatureWithoutHashType)
+	if err != nil {
+		return err
+	}
+	secpHash := secp256k1.Hash(*hash.ByteArray())
+	if !schnorrPublicKey.SchnorrVerify(&secpHash, schnorrSignature) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+// SignExternalSignerRequest signs the transaction in the given request with the given mnemonics,
+// and returns the signatures it made. It implements the signer side of the external signer protocol.
+func SignExternalSignerRequest(params *dagconfig.Params, mnemonics []string,
+	request *ExternalSignerRequest) (*ExternalSignerResponse, error) {
+
+	if request.Version != ExternalSignerProtocolVersion {
+		return nil, errors.Errorf("unsupported external signer protocol version %d", request.Version)
+	}
+	if request.Network != params.Name {
+		return nil, errors.Errorf("the transaction belongs to %s, but the signer is configured for %s",
+			request.Network, params.Name)
+	}
+
+	serializedPSTx, err := hex.DecodeString(request.Transaction)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	partiallySignedTransaction, err := serialization.DeserializePartiallySignedTransaction(serializedPSTx)
+	if err != nil {
+		return nil, err
+	}
+	unsignedPartiallySignedTransaction := partiallySignedTransaction.Clone()
+
+	for _, mnemonic := range mnemonics {
+		err = sign(params, mnemonic, partiallySignedTransaction, request.ECDSA)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signatures := []*ExternalSignerSignature{}
+	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
+		for j, pair := range partiallySignedInput.PubKeySignaturePairs {
+			unsignedPair := unsignedPartiallySignedTransaction.PartiallySignedInputs[i].PubKeySignaturePairs[j]
+			if pair.Signature == nil || unsignedPair.Signature != nil {
+				continue
+			}
+			signatures = append(signatures, &ExternalSignerSignature{
+				InputIndex:        uint32(i),
+				ExtendedPublicKey: pair.ExtendedPublicKey,
+				Signature:         hex.EncodeToString(pair.Signature),
+			})
+		}
+	}
+
+	return &ExternalSignerResponse{Signatures: signatures}, nil
+}
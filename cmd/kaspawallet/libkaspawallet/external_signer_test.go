@@ -0,0 +1,213 @@
+package libkaspawallet_test
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+type testExternalSigner struct {
+	params    *dagconfig.Params
+	mnemonics []string
+	tamper    func(response *libkaspawallet.ExternalSignerResponse)
+}
+
+func (s *testExternalSigner) Sign(request *libkaspawallet.ExternalSignerRequest) (*libkaspawallet.ExternalSignerResponse, error) {
+	response, err := libkaspawallet.SignExternalSignerRequest(s.params, s.mnemonics, request)
+	if err != nil {
+		return &libkaspawallet.ExternalSignerResponse{Error: err.Error()}, nil
+	}
+	if s.tamper != nil {
+		s.tamper(response)
+	}
+	return response, nil
+}
+
+func TestExternalSigner(t *testing.T) {
+	params := &dagconfig.SimnetParams
+	forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+		const numKeys = 3
+		mnemonics := make([]string, numKeys)
+		publicKeys := make([]string, numKeys)
+		for i := 0; i < numKeys; i++ {
+			var err error
+			mnemonics[i], err = libkaspawallet.CreateMnemonic()
+			if err != nil {
+				t.Fatalf("CreateMnemonic: %+v", err)
+			}
+
+			publicKeys[i], err = libkaspawallet.MasterPublicKeyFromMnemonic(params, mnemonics[i], true)
+			if err != nil {
+				t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+			}
+		}
+
+		const minimumSignatures = 2
+		path := "m/1/2/3"
+		address, err := libkaspawallet.Address(params, publicKeys, minimumSignatures, path, ecdsa)
+		if err != nil {
+			t.Fatalf("Address: %+v", err)
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			t.Fatalf("PayToAddrScript: %+v", err)
+		}
+
+		selectedUTXOs := []*libkaspawallet.UTXO{
+			{
+				Outpoint: &externalapi.DomainOutpoint{
+					TransactionID: externalapi.DomainTransactionID{},
+					Index:         0,
+				},
+				UTXOEntry:      utxo.NewUTXOEntry(100000000, scriptPublicKey, false, 0),
+				DerivationPath: path,
+			},
+		}
+		unsignedTransaction, err := libkaspawallet.CreateUnsignedTransaction(publicKeys, minimumSignatures,
+			[]*libkaspawallet.Payment{{
+				Address: address,
+				Amount:  10,
+			}}, selectedUTXOs, nil)
+		if err != nil {
+			t.Fatalf("CreateUnsignedTransactions: %+v", err)
+		}
+
+		signer := &testExternalSigner{params: params, mnemonics: mnemonics[:1]}
+		signedTxStep1, err := libkaspawallet.SignWithExternalSigner(params, signer, unsignedTransaction, ecdsa)
+		if err != nil {
+			t.Fatalf("SignWithExternalSigner: %+v", err)
+		}
+
+		isFullySigned, err := libkaspawallet.IsTransactionFullySigned(signedTxStep1)
+		if err != nil {
+			t.Fatalf("IsTransactionFullySigned: %+v", err)
+		}
+		if isFullySigned {
+			t.Fatalf("Transaction is not expected to be fully signed")
+		}
+
+		// Signatures made by the external signer and by the keys file should be combinable
+		signedTxStep2, err := libkaspawallet.Sign(params, mnemonics[1:2], signedTxStep1, ecdsa)
+		if err != nil {
+			t.Fatalf("Sign: %+v", err)
+		}
+		_, err = libkaspawallet.ExtractTransaction(signedTxStep2, ecdsa)
+		if err != nil {
+			t.Fatalf("ExtractTransaction: %+v", err)
+		}
+
+		tests := []struct {
+			name          string
+			signer        *testExternalSigner
+			expectedError string
+		}{
+			{
+				name: "tampered signature",
+				signer: &testExternalSigner{params: params, mnemonics: mnemonics[:1],
+					tamper: func(response *libkaspawallet.ExternalSignerResponse) {
+						signature := []byte(response.Signatures[0].Signature)
+						if signature[0] == '0' {
+							signature[0] = '1'
+						} else {
+							signature[0] = '0'
+						}
+						response.Signatures[0].Signature = string(signature)
+					}},
+				expectedError: "invalid signature",
+			},
+			{
+				name: "wrong public key",
+				signer: &testExternalSigner{params: params, mnemonics: mnemonics[:1],
+					tamper: func(response *libkaspawallet.ExternalSignerResponse) {
+						response.Signatures[0].ExtendedPublicKey = publicKeys[1]
+					}},
+				expectedError: "doesn't match any of its public keys",
+			},
+			{
+				name: "wrong input index",
+				signer: &testExternalSigner{params: params, mnemonics: mnemonics[:1],
+					tamper: func(response *libkaspawallet.ExternalSignerResponse) {
+						response.Signatures[0].InputIndex = 1
+					}},
+				expectedError: "the transaction has only 1 inputs",
+			},
+			{
+				name:          "foreign keys",
+				signer:        &testExternalSigner{params: params, mnemonics: []string{createMnemonic(t)}},
+				expectedError: "Public key doesn't match any of the transaction public keys",
+			},
+			{
+				name:          "wrong network",
+				signer:        &testExternalSigner{params: &dagconfig.MainnetParams, mnemonics: mnemonics[:1]},
+				expectedError: "the signer is configured for kaspa-mainnet",
+			},
+		}
+		for _, test := range tests {
+			_, err := libkaspawallet.SignWithExternalSigner(params, test.signer, unsignedTransaction, ecdsa)
+			if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Fatalf("%s: expected an error containing %q, got: %v", test.name, test.expectedError, err)
+			}
+		}
+	})
+}
+
+func createMnemonic(t *testing.T) string {
+	mnemonic, err := libkaspawallet.CreateMnemonic()
+	if err != nil {
+		t.Fatalf("CreateMnemonic: %+v", err)
+	}
+	return mnemonic
+}
+
+// TestSubprocessExternalSigner runs the test binary itself as the external signer, in
+// TestSubprocessExternalSignerHelper, and verifies that the request and the arguments reach it intact
+func TestSubprocessExternalSigner(t *testing.T) {
+	t.Setenv("KASPAWALLET_TEST_EXTERNAL_SIGNER", "1")
+
+	const argWithSpaces = "/path with spaces/keys.json"
+	signer, err := libkaspawallet.NewSubprocessExternalSigner(os.Args[0],
+		[]string{"-test.run=^TestSubprocessExternalSignerHelper$", "--", argWithSpaces})
+	if err != nil {
+		t.Fatalf("NewSubprocessExternalSigner: %+v", err)
+	}
+	response, err := signer.Sign(&libkaspawallet.ExternalSignerRequest{Version: 1, Network: "kaspa-devnet"})
+	if err != nil {
+		t.Fatalf("Sign: %+v", err)
+	}
+
+	// The helper echoes the network of the request and its last argument in the error field
+	expectedError := "kaspa-devnet " + argWithSpaces
+	if response.Error != expectedError {
+		t.Fatalf("Unexpected response. Want: %s, got: %s", expectedError, response.Error)
+	}
+
+	_, err = libkaspawallet.NewSubprocessExternalSigner("", nil)
+	if err == nil {
+		t.Fatalf("Expected an error for an empty command")
+	}
+}
+
+func TestSubprocessExternalSignerHelper(t *testing.T) {
+	if os.Getenv("KASPAWALLET_TEST_EXTERNAL_SIGNER") != "1" {
+		return
+	}
+
+	request := &libkaspawallet.ExternalSignerRequest{}
+	err := json.NewDecoder(os.Stdin).Decode(request)
+	if err != nil {
+		t.Fatalf("Decode: %+v", err)
+	}
+	response := &libkaspawallet.ExternalSignerResponse{Error: request.Network + " " + os.Args[len(os.Args)-1]}
+	err = json.NewEncoder(os.Stdout).Encode(response)
+	if err != nil {
+		t.Fatalf("Encode: %+v", err)
+	}
+	os.Exit(0)
+}
@@ -147,6 +147,17 @@ func cosignerIndex(extendedPublicKey string, sortedExtendedPublicKeys []string)
 	return uint32(cosignerIndex), nil
 }
 
+// WatchOnlyCosignerIndex returns the cosigner index a watch-only wallet with the given extended public
+// keys derives its new addresses from. In a multisig wallet it's an index past those of all the cosigners,
+// so that its addresses never collide with addresses a cosigner derives independently.
+func WatchOnlyCosignerIndex(allExtendedPublicKeys []string) uint32 {
+	if len(allExtendedPublicKeys) <= 1 {
+		// Addresses of a single key wallet don't depend on the cosigner index
+		return 0
+	}
+	return uint32(len(allExtendedPublicKeys))
+}
+
 // MinimumCosignerIndex returns the minimum index for the cosigner from the set of all extended public keys.
 func MinimumCosignerIndex(cosignerExtendedPublicKeys, allExtendedPublicKeys []string) (uint32, error) {
 	allExtendedPublicKeysCopy := make([]string, len(allExtendedPublicKeys))
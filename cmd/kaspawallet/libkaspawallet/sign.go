@@ -49,16 +49,7 @@ func sign(params *dagconfig.Params, mnemonic string, partiallySignedTransaction
 	}
 
 	sighashReusedValues := &consensushashing.SighashReusedValues{}
-	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
-		prevOut := partiallySignedInput.PrevOutput
-		partiallySignedTransaction.Tx.Inputs[i].UTXOEntry = utxo.NewUTXOEntry(
-			prevOut.Value,
-			prevOut.ScriptPublicKey,
-			false, // This is a fake value, because it's irrelevant for the signature
-			0,     // This is a fake value, because it's irrelevant for the signature
-		)
-		partiallySignedTransaction.Tx.Inputs[i].SigOpCount = byte(len(partiallySignedInput.PubKeySignaturePairs))
-	}
+	populateTransactionForSigning(partiallySignedTransaction)
 
 	signed := false
 	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
@@ -97,3 +88,18 @@ func sign(params *dagconfig.Params, mnemonic string, partiallySignedTransaction
 
 	return nil
 }
+
+// populateTransactionForSigning fills the transaction inputs with the data
+// that is required in order to calculate their signature hashes
+func populateTransactionForSigning(partiallySignedTransaction *serialization.PartiallySignedTransaction) {
+	for i, partiallySignedInput := range partiallySignedTransaction.PartiallySignedInputs {
+		prevOut := partiallySignedInput.PrevOutput
+		partiallySignedTransaction.Tx.Inputs[i].UTXOEntry = utxo.NewUTXOEntry(
+			prevOut.Value,
+			prevOut.ScriptPublicKey,
+			false, // This is a fake value, because it's irrelevant for the signature
+			0,     // This is a fake value, because it's irrelevant for the signature
+		)
+		partiallySignedTransaction.Tx.Inputs[i].SigOpCount = byte(len(partiallySignedInput.PubKeySignaturePairs))
+	}
+}
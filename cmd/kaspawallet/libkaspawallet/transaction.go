@@ -1,6 +1,9 @@
 package libkaspawallet
 
 import (
+	"math"
+
+	"github.com/kaspanet/go-secp256k1"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/bip32"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet/serialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
@@ -8,6 +11,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/kaspanet/kaspad/util"
+	"github.com/kaspanet/kaspad/util/txmass"
 	"github.com/pkg/errors"
 )
 
@@ -26,12 +30,24 @@ type UTXO struct {
 	DerivationPath string
 }
 
-// CreateUnsignedTransaction creates an unsigned transaction
+// Fee describes the fee a transaction pays: Rate sompi for every gram of the mass
+// the transaction will have once it's signed. Whatever the selected UTXOs are left
+// with after the payments and the fee is sent to ChangeAddress.
+type Fee struct {
+	Rate             float64
+	ChangeAddress    util.Address
+	ECDSA            bool
+	TxMassCalculator *txmass.Calculator
+}
+
+// CreateUnsignedTransaction creates an unsigned transaction. If fee is nil, whatever
+// the selected UTXOs are left with after the payments is paid as fee.
 func CreateUnsignedTransaction(
 	extendedPublicKeys []string,
 	minimumSignatures uint32,
 	payments []*Payment,
-	selectedUTXOs []*UTXO) ([]byte, error) {
+	selectedUTXOs []*UTXO,
+	fee *Fee) ([]byte, error) {
 
 	sortPublicKeys(extendedPublicKeys)
 	unsignedTransaction, err := createUnsignedTransaction(extendedPublicKeys, minimumSignatures, payments, selectedUTXOs)
@@ -39,9 +55,93 @@ func CreateUnsignedTransaction(
 		return nil, err
 	}
 
+	if fee != nil {
+		err = addChangeOutput(unsignedTransaction, fee)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return serialization.SerializePartiallySignedTransaction(unsignedTransaction)
 }
 
+// addChangeOutput adds an output paying the change to the given transaction, after
+// deducting the fee for the mass of the transaction including that output. If no
+// change is left, the output is not added.
+func addChangeOutput(transaction *serialization.PartiallySignedTransaction, fee *Fee) error {
+	if fee.Rate < 0 {
+		return errors.Errorf("fee rate cannot be negative, but got %f", fee.Rate)
+	}
+
+	inputsValue := uint64(0)
+	for _, input := range transaction.PartiallySignedInputs {
+		inputsValue += input.PrevOutput.Value
+	}
+	outputsValue := uint64(0)
+	for _, output := range transaction.Tx.Outputs {
+		outputsValue += output.Value
+	}
+	if inputsValue < outputsValue {
+		return errors.Errorf("the selected UTXOs are worth %d sompi, while the payments are worth %d sompi",
+			inputsValue, outputsValue)
+	}
+
+	changeScriptPublicKey, err := txscript.PayToAddrScript(fee.ChangeAddress)
+	if err != nil {
+		return err
+	}
+	changeOutput := &externalapi.DomainTransactionOutput{ScriptPublicKey: changeScriptPublicKey}
+	transaction.Tx.Outputs = append(transaction.Tx.Outputs, changeOutput)
+
+	// The value of the change output doesn't affect the mass
+	mass, err := EstimateMassAfterSignatures(transaction, fee.ECDSA, fee.TxMassCalculator)
+	if err != nil {
+		return err
+	}
+	feeSompi := uint64(math.Ceil(fee.Rate * float64(mass)))
+	if inputsValue-outputsValue < feeSompi {
+		return errors.Errorf("the selected UTXOs are %d sompi short of paying a fee of %d sompi",
+			feeSompi-(inputsValue-outputsValue), feeSompi)
+	}
+
+	changeOutput.Value = inputsValue - outputsValue - feeSompi
+	if changeOutput.Value == 0 {
+		transaction.Tx.Outputs = transaction.Tx.Outputs[:len(transaction.Tx.Outputs)-1]
+	}
+	return nil
+}
+
+// EstimateMassAfterSignatures returns the mass the given transaction will have
+// once all of its inputs have the minimum amount of signatures they require
+func EstimateMassAfterSignatures(transaction *serialization.PartiallySignedTransaction, ecdsa bool,
+	txMassCalculator *txmass.Calculator) (uint64, error) {
+
+	transaction = transaction.Clone()
+	var signatureSize uint64
+	if ecdsa {
+		signatureSize = secp256k1.SerializedECDSASignatureSize
+	} else {
+		signatureSize = secp256k1.SerializedSchnorrSignatureSize
+	}
+
+	for i, input := range transaction.PartiallySignedInputs {
+		for j, pubKeyPair := range input.PubKeySignaturePairs {
+			if uint32(j) >= input.MinimumSignatures {
+				break
+			}
+			pubKeyPair.Signature = make([]byte, signatureSize+1) // +1 for SigHashType
+		}
+		transaction.Tx.Inputs[i].SigOpCount = byte(len(input.PubKeySignaturePairs))
+	}
+
+	transactionWithSignatures, err := ExtractTransactionDeserialized(transaction, ecdsa)
+	if err != nil {
+		return 0, err
+	}
+
+	return txMassCalculator.CalculateTransactionMass(transactionWithSignatures), nil
+}
+
 func multiSigRedeemScript(extendedPublicKeys []string, minimumSignatures uint32, path string, ecdsa bool) ([]byte, error) {
 	scriptBuilder := txscript.NewScriptBuilder()
 	scriptBuilder.AddInt64(int64(minimumSignatures))
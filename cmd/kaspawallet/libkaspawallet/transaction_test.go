@@ -2,6 +2,7 @@ package libkaspawallet_test
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/kaspanet/kaspad/util"
+	"github.com/kaspanet/kaspad/util/txmass"
 )
 
 func forSchnorrAndECDSA(t *testing.T, testFunc func(t *testing.T, ecdsa bool)) {
@@ -105,7 +108,7 @@ func TestMultisig(t *testing.T) {
 				[]*libkaspawallet.Payment{{
 					Address: address,
 					Amount:  10,
-				}}, selectedUTXOs)
+				}}, selectedUTXOs, nil)
 			if err != nil {
 				t.Fatalf("CreateUnsignedTransactions: %+v", err)
 			}
@@ -266,7 +269,7 @@ func TestP2PK(t *testing.T) {
 				[]*libkaspawallet.Payment{{
 					Address: address,
 					Amount:  10,
-				}}, selectedUTXOs)
+				}}, selectedUTXOs, nil)
 			if err != nil {
 				t.Fatalf("CreateUnsignedTransactions: %+v", err)
 			}
@@ -310,3 +313,74 @@ func TestP2PK(t *testing.T) {
 		})
 	})
 }
+
+func TestCreateUnsignedTransactionWithFee(t *testing.T) {
+	params := &dagconfig.MainnetParams
+	txMassCalculator := txmass.NewCalculator(params.MassPerTxByte, params.MassPerScriptPubKeyByte, params.MassPerSigOp)
+	forSchnorrAndECDSA(t, func(t *testing.T, ecdsa bool) {
+		mnemonic, err := libkaspawallet.CreateMnemonic()
+		if err != nil {
+			t.Fatalf("CreateMnemonic: %+v", err)
+		}
+		publicKey, err := libkaspawallet.MasterPublicKeyFromMnemonic(params, mnemonic, false)
+		if err != nil {
+			t.Fatalf("MasterPublicKeyFromMnemonic: %+v", err)
+		}
+
+		path := "m/1/2/3"
+		address, err := libkaspawallet.Address(params, []string{publicKey}, 1, path, ecdsa)
+		if err != nil {
+			t.Fatalf("Address: %+v", err)
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			t.Fatalf("PayToAddrScript: %+v", err)
+		}
+
+		selectedUTXOs := make([]*libkaspawallet.UTXO, 3)
+		for i := range selectedUTXOs {
+			selectedUTXOs[i] = &libkaspawallet.UTXO{
+				Outpoint:       &externalapi.DomainOutpoint{Index: uint32(i)},
+				UTXOEntry:      utxo.NewUTXOEntry(100_000, scriptPublicKey, false, 0),
+				DerivationPath: path,
+			}
+		}
+		payments := []*libkaspawallet.Payment{{Address: address, Amount: 150_000}}
+		fee := &libkaspawallet.Fee{
+			Rate:             10,
+			ChangeAddress:    address,
+			ECDSA:            ecdsa,
+			TxMassCalculator: txMassCalculator,
+		}
+
+		unsignedTransaction, err := libkaspawallet.CreateUnsignedTransaction([]string{publicKey}, 1, payments,
+			selectedUTXOs, fee)
+		if err != nil {
+			t.Fatalf("CreateUnsignedTransaction: %+v", err)
+		}
+		signedTransaction, err := libkaspawallet.Sign(params, []string{mnemonic}, unsignedTransaction, ecdsa)
+		if err != nil {
+			t.Fatalf("Sign: %+v", err)
+		}
+		transaction, err := libkaspawallet.ExtractTransaction(signedTransaction, ecdsa)
+		if err != nil {
+			t.Fatalf("ExtractTransaction: %+v", err)
+		}
+
+		if len(transaction.Outputs) != 2 {
+			t.Fatalf("Expected a payment and a change output, got %d outputs", len(transaction.Outputs))
+		}
+		paidFee := 300_000 - transaction.Outputs[0].Value - transaction.Outputs[1].Value
+		expectedFee := uint64(math.Ceil(fee.Rate * float64(txMassCalculator.CalculateTransactionMass(transaction))))
+		if paidFee != expectedFee {
+			t.Fatalf("Unexpected fee. Want: %d, got: %d", expectedFee, paidFee)
+		}
+
+		// The UTXOs can't pay for a payment of all of their value and the fee
+		payments[0].Amount = 300_000
+		_, err = libkaspawallet.CreateUnsignedTransaction([]string{publicKey}, 1, payments, selectedUTXOs, fee)
+		if err == nil || !strings.Contains(err.Error(), "short of paying a fee") {
+			t.Fatalf("Expected an error for UTXOs that can't pay the fee, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/client"
+	"github.com/kaspanet/kaspad/cmd/kaspawallet/daemon/pb"
+)
+
+func listUTXOs(conf *listUTXOsConfig) error {
+	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
+	if err != nil {
+		return err
+	}
+	defer tearDown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), daemonTimeout)
+	defer cancel()
+
+	response, err := daemonClient.GetUTXOs(ctx, &pb.GetUTXOsRequest{Addresses: conf.Addresses})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("UTXOs (%d):\n", len(response.Utxos))
+	for _, utxo := range response.Utxos {
+		maturity := "spendable"
+		if !utxo.IsSpendable {
+			maturityDAAScore := utxo.BlockDaaScore + conf.NetParams().BlockCoinbaseMaturity + 1
+			maturity = fmt.Sprintf("immature coinbase, spendable at DAA score %d", maturityDAAScore)
+			if maturityDAAScore > response.VirtualDaaScore {
+				maturity += fmt.Sprintf(" (in %d)", maturityDAAScore-response.VirtualDaaScore)
+			}
+		} else if utxo.IsCoinbase {
+			maturity = "spendable coinbase"
+		}
+		fmt.Printf("%s:%d %s %s KAS (DAA score %d, %s)\n", utxo.Outpoint.TransactionId, utxo.Outpoint.Index,
+			utxo.Address, formatKas(utxo.Amount), utxo.BlockDaaScore, maturity)
+	}
+	return nil
+}
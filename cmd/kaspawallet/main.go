@@ -27,6 +27,12 @@ func main() {
 		err = newAddress(config.(*newAddressConfig))
 	case dumpUnencryptedDataSubCmd:
 		err = dumpUnencryptedData(config.(*dumpUnencryptedDataConfig))
+	case historySubCmd:
+		err = history(config.(*historyConfig))
+	case labelSubCmd:
+		err = label(config.(*labelConfig))
+	case listUTXOsSubCmd:
+		err = listUTXOs(config.(*listUTXOsConfig))
 	case startDaemonSubCmd:
 		err = startDaemon(config.(*startDaemonConfig))
 	default:
@@ -18,8 +18,14 @@ func send(conf *sendConfig) error {
 		return err
 	}
 
-	if len(keysFile.ExtendedPublicKeys) > len(keysFile.EncryptedMnemonics) {
-		return errors.Errorf("Cannot use 'send' command for multisig wallet without all of the keys")
+	if conf.ExternalSigner == "" {
+		if keysFile.IsWatchOnly() {
+			return errors.Errorf("Cannot use 'send' command for a watch-only wallet without an external signer. " +
+				"Use 'create-unsigned-transaction' and sign the transaction elsewhere")
+		}
+		if len(keysFile.ExtendedPublicKeys) > len(keysFile.EncryptedMnemonics) {
+			return errors.Errorf("Cannot use 'send' command for multisig wallet without all of the keys")
+		}
 	}
 
 	daemonClient, tearDown, err := client.Connect(conf.DaemonAddress)
@@ -32,27 +38,36 @@ func send(conf *sendConfig) error {
 	defer cancel()
 
 	sendAmountSompi := uint64(conf.SendAmount * constants.SompiPerKaspa)
+	createUnsignedTransactionsRequest := &pb.CreateUnsignedTransactionsRequest{
+		Address: conf.ToAddress,
+		Amount:  sendAmountSompi,
+		FeeRate: conf.FeeRate,
+	}
+	err = conf.coinSelectionFlags.fillRequest(createUnsignedTransactionsRequest)
+	if err != nil {
+		return err
+	}
 	createUnsignedTransactionsResponse, err :=
-		daemonClient.CreateUnsignedTransactions(ctx, &pb.CreateUnsignedTransactionsRequest{
-			Address: conf.ToAddress,
-			Amount:  sendAmountSompi,
-		})
+		daemonClient.CreateUnsignedTransactions(ctx, createUnsignedTransactionsRequest)
 	if err != nil {
 		return err
 	}
+	printExtraFee(createUnsignedTransactionsResponse.ExtraFee)
 
-	mnemonics, err := keysFile.DecryptMnemonics(conf.Password)
+	signedTransactions, err := signTransactions(conf.NetParams(), keysFile, conf.Password, &conf.externalSignerFlags,
+		createUnsignedTransactionsResponse.UnsignedTransactions)
 	if err != nil {
 		return err
 	}
-
-	signedTransactions := make([][]byte, len(createUnsignedTransactionsResponse.UnsignedTransactions))
-	for i, unsignedTransaction := range createUnsignedTransactionsResponse.UnsignedTransactions {
-		signedTransaction, err := libkaspawallet.Sign(conf.NetParams(), mnemonics, unsignedTransaction, keysFile.ECDSA)
+	for _, signedTransaction := range signedTransactions {
+		isFullySigned, err := libkaspawallet.IsTransactionFullySigned(signedTransaction)
 		if err != nil {
 			return err
 		}
-		signedTransactions[i] = signedTransaction
+		if !isFullySigned {
+			return errors.Errorf("The transaction is not fully signed. Use 'create-unsigned-transaction' and " +
+				"'sign' to collect the signatures of all the cosigners")
+		}
 	}
 
 	if len(signedTransactions) > 1 {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/keys"
 	"github.com/kaspanet/kaspad/cmd/kaspawallet/libkaspawallet"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
 	"github.com/pkg/errors"
 )
 
@@ -22,10 +23,6 @@ func sign(conf *signConfig) error {
 	if err != nil {
 		return err
 	}
-	privateKeys, err := keysFile.DecryptMnemonics(conf.Password)
-	if err != nil {
-		return err
-	}
 
 	transactionsHex := conf.Transaction
 	if conf.TransactionFile != "" {
@@ -40,13 +37,10 @@ func sign(conf *signConfig) error {
 		return err
 	}
 
-	updatedPartiallySignedTransactions := make([][]byte, len(partiallySignedTransactions))
-	for i, partiallySignedTransaction := range partiallySignedTransactions {
-		updatedPartiallySignedTransactions[i], err =
-			libkaspawallet.Sign(conf.NetParams(), privateKeys, partiallySignedTransaction, keysFile.ECDSA)
-		if err != nil {
-			return err
-		}
+	updatedPartiallySignedTransactions, err := signTransactions(conf.NetParams(), keysFile, conf.Password,
+		&conf.externalSignerFlags, partiallySignedTransactions)
+	if err != nil {
+		return err
 	}
 
 	areAllTransactionsFullySigned := true
@@ -71,3 +65,39 @@ func sign(conf *signConfig) error {
 	fmt.Println(encodeTransactionsToHex(updatedPartiallySignedTransactions))
 	return nil
 }
+
+// signTransactions signs the given partially signed transactions with the external signer
+// if one is set, or with the mnemonics in the keys file otherwise
+func signTransactions(params *dagconfig.Params, keysFile *keys.File, password string, signerFlags *externalSignerFlags,
+	partiallySignedTransactions [][]byte) ([][]byte, error) {
+
+	signedTransactions := make([][]byte, len(partiallySignedTransactions))
+
+	if signerFlags.ExternalSigner != "" {
+		externalSigner, err := libkaspawallet.NewSubprocessExternalSigner(signerFlags.ExternalSigner,
+			signerFlags.ExternalSignerArgs)
+		if err != nil {
+			return nil, err
+		}
+		for i, partiallySignedTransaction := range partiallySignedTransactions {
+			signedTransactions[i], err = libkaspawallet.SignWithExternalSigner(
+				params, externalSigner, partiallySignedTransaction, keysFile.ECDSA)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return signedTransactions, nil
+	}
+
+	mnemonics, err := keysFile.DecryptMnemonics(password)
+	if err != nil {
+		return nil, err
+	}
+	for i, partiallySignedTransaction := range partiallySignedTransactions {
+		signedTransactions[i], err = libkaspawallet.Sign(params, mnemonics, partiallySignedTransaction, keysFile.ECDSA)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return signedTransactions, nil
+}
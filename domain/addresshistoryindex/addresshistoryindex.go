@@ -0,0 +1,246 @@
+package addresshistoryindex
+
+import (
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"sync"
+)
+
+// AddressHistoryIndex maintains an index between script public keys
+// and every credit and debit made to them by the virtual selected parent chain
+type AddressHistoryIndex struct {
+	domain domain.Domain
+	store  *addressHistoryIndexStore
+
+	mutex sync.Mutex
+}
+
+// New creates a new address history index.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(domain domain.Domain, database database.Database) (*AddressHistoryIndex, error) {
+	addressHistoryIndex := &AddressHistoryIndex{
+		domain: domain,
+		store:  newAddressHistoryIndexStore(database),
+	}
+
+	isSynced, err := addressHistoryIndex.isSynced()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSynced {
+		err = addressHistoryIndex.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return addressHistoryIndex, nil
+}
+
+// Reset deletes the whole address history index and resyncs it from consensus,
+// starting from the current pruning point. History that predates the pruning
+// point is not available after a reset.
+func (ahi *AddressHistoryIndex) Reset() error {
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	err := ahi.reset()
+	if err != nil {
+		// Staged data is left behind when an error occurs mid-way. Discard it,
+		// since the store refuses to be queried while anything is staged
+		ahi.store.discard()
+		return err
+	}
+	return nil
+}
+
+func (ahi *AddressHistoryIndex) reset() error {
+	err := ahi.store.deleteAll()
+	if err != nil {
+		return err
+	}
+
+	virtualInfo, err := ahi.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+
+	pruningPoint, err := ahi.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	chainPath, err := ahi.domain.Consensus().GetVirtualSelectedParentChainFromBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	const step = 1000
+	for i, chainBlockHash := range chainPath.Added {
+		err = ahi.addChainBlock(chainBlockHash)
+		if err != nil {
+			return err
+		}
+
+		if (i+1)%step == 0 {
+			err = ahi.store.commitWithoutTransaction()
+			if err != nil {
+				return err
+			}
+			log.Infof("Indexed address history of %d out of %d chain blocks", i+1, len(chainPath.Added))
+		}
+	}
+
+	err = ahi.store.commitWithoutTransaction()
+	if err != nil {
+		return err
+	}
+
+	// This has to be done last to mark that the reset went smoothly and no reset has to be called next time.
+	return ahi.store.updateAndCommitVirtualParentsWithoutTransaction(virtualInfo.ParentHashes)
+}
+
+func (ahi *AddressHistoryIndex) isSynced() (bool, error) {
+	addressHistoryIndexVirtualParents, err := ahi.store.getVirtualParents()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	virtualInfo, err := ahi.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return false, err
+	}
+
+	return externalapi.HashesEqual(virtualInfo.ParentHashes, addressHistoryIndexVirtualParents), nil
+}
+
+// Update updates the address history index with the given DAG selected parent chain changes
+func (ahi *AddressHistoryIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressHistoryIndex.Update")
+	defer onEnd()
+
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	err := ahi.update(virtualChangeSet)
+	if err != nil {
+		ahi.store.discard()
+		return err
+	}
+	return nil
+}
+
+func (ahi *AddressHistoryIndex) update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	log.Tracef("Updating address history index with VirtualSelectedParentChainChanges: %+v",
+		virtualChangeSet.VirtualSelectedParentChainChanges)
+
+	for _, removedChainBlockHash := range virtualChangeSet.VirtualSelectedParentChainChanges.Removed {
+		err := ahi.removeChainBlock(removedChainBlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, addedChainBlockHash := range virtualChangeSet.VirtualSelectedParentChainChanges.Added {
+		err := ahi.addChainBlock(addedChainBlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	ahi.store.updateVirtualParents(virtualChangeSet.VirtualParents)
+
+	return ahi.store.commit()
+}
+
+func (ahi *AddressHistoryIndex) addChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	return ahi.forEachChainBlockEntry(chainBlockHash, ahi.store.add)
+}
+
+func (ahi *AddressHistoryIndex) removeChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	return ahi.forEachChainBlockEntry(chainBlockHash, ahi.store.remove)
+}
+
+// forEachChainBlockEntry calls the given function for every credit and debit
+// made by the transactions accepted by the given chain block
+func (ahi *AddressHistoryIndex) forEachChainBlockEntry(chainBlockHash *externalapi.DomainHash,
+	function func(scriptPublicKey *externalapi.ScriptPublicKey, entry *AddressHistoryEntry)) error {
+
+	acceptanceData, err := ahi.domain.Consensus().GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	chainBlockHeader, err := ahi.domain.Consensus().GetBlockHeader(chainBlockHash)
+	if err != nil {
+		return err
+	}
+	daaScore := chainBlockHeader.DAAScore()
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+
+			transaction := transactionAcceptanceData.Transaction
+			transactionID := consensushashing.TransactionID(transaction)
+
+			for i, input := range transaction.Inputs {
+				utxoEntry := transactionAcceptanceData.TransactionInputUTXOEntries[i]
+				function(utxoEntry.ScriptPublicKey(), &AddressHistoryEntry{
+					Outpoint:           input.PreviousOutpoint,
+					TransactionID:      *transactionID,
+					Amount:             utxoEntry.Amount(),
+					IsDebit:            true,
+					AcceptingBlockHash: chainBlockHash,
+					DAAScore:           daaScore,
+				})
+			}
+
+			for i, output := range transaction.Outputs {
+				function(output.ScriptPublicKey, &AddressHistoryEntry{
+					Outpoint:           *externalapi.NewDomainOutpoint(transactionID, uint32(i)),
+					TransactionID:      *transactionID,
+					Amount:             output.Value,
+					IsDebit:            false,
+					AcceptingBlockHash: chainBlockHash,
+					DAAScore:           daaScore,
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// History returns up to `limit` credits and debits of the given script public key,
+// ordered by DAA score. If afterCursor is not empty, only the entries that follow
+// the entry with that cursor are returned. See AddressHistoryEntry.Cursor.
+func (ahi *AddressHistoryIndex) History(scriptPublicKey *externalapi.ScriptPublicKey,
+	afterCursor string, limit uint64) ([]*AddressHistoryEntry, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "AddressHistoryIndex.History")
+	defer onEnd()
+
+	ahi.mutex.Lock()
+	defer ahi.mutex.Unlock()
+
+	var after *historyKey
+	if afterCursor != "" {
+		key, err := parseCursor(afterCursor)
+		if err != nil {
+			return nil, err
+		}
+		after = &key
+	}
+
+	return ahi.store.getHistory(scriptPublicKey, after, limit)
+}
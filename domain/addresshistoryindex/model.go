@@ -0,0 +1,82 @@
+package addresshistoryindex
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidCursor is returned by AddressHistoryIndex.History when it's
+// given a cursor that was not returned by AddressHistoryEntry.Cursor
+var ErrInvalidCursor = errors.New("invalid address history cursor")
+
+// ScriptPublicKeyString is a script public key represented as a string
+// We use this type rather than just a byte slice because Go maps don't
+// support slices as keys
+type ScriptPublicKeyString string
+
+// AddressHistoryEntry is a single credit or debit of some script public key
+type AddressHistoryEntry struct {
+	// Outpoint is the outpoint that was either created (credit) or spent (debit)
+	Outpoint externalapi.DomainOutpoint
+
+	// TransactionID is the ID of the transaction that created or spent the outpoint
+	TransactionID externalapi.DomainTransactionID
+
+	Amount  uint64
+	IsDebit bool
+
+	// AcceptingBlockHash is the hash of the chain block whose merge set
+	// accepted the transaction
+	AcceptingBlockHash *externalapi.DomainHash
+
+	// DAAScore is the DAA score of the accepting block
+	DAAScore uint64
+}
+
+// historyKey uniquely identifies an AddressHistoryEntry within the
+// history of its script public key. An outpoint can only be credited
+// once and debited once by the selected parent chain.
+type historyKey struct {
+	daaScore uint64
+	outpoint externalapi.DomainOutpoint
+	isDebit  bool
+}
+
+func (entry *AddressHistoryEntry) key() historyKey {
+	return historyKey{
+		daaScore: entry.DAAScore,
+		outpoint: entry.Outpoint,
+		isDebit:  entry.IsDebit,
+	}
+}
+
+// Cursor returns an opaque string that identifies the position of the entry
+// within the history of its script public key. Since the history is ordered by
+// DAA score and then by outpoint, a cursor keeps its position across reorgs,
+// even if its own entry is removed.
+func (entry *AddressHistoryEntry) Cursor() string {
+	return hex.EncodeToString(serializeHistoryKey(entry.key()))
+}
+
+func parseCursor(cursor string) (historyKey, error) {
+	serializedKey, err := hex.DecodeString(cursor)
+	if err != nil {
+		return historyKey{}, errors.Wrapf(ErrInvalidCursor, "%s", err)
+	}
+	key, err := deserializeHistoryKey(serializedKey)
+	if err != nil {
+		return historyKey{}, errors.Wrapf(ErrInvalidCursor, "%s", err)
+	}
+	return key, nil
+}
+
+// ConvertScriptPublicKeyToString converts the given scriptPublicKey to a string
+func ConvertScriptPublicKeyToString(scriptPublicKey *externalapi.ScriptPublicKey) ScriptPublicKeyString {
+	var versionBytes = make([]byte, 2) // uint16
+	binary.LittleEndian.PutUint16(versionBytes, scriptPublicKey.Version)
+	versionString := ScriptPublicKeyString(versionBytes)
+	scriptString := ScriptPublicKeyString(scriptPublicKey.Script)
+	return versionString + scriptString
+}
@@ -0,0 +1,101 @@
+package addresshistoryindex
+
+import (
+	"encoding/binary"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+)
+
+const (
+	daaScoreSize      = 8
+	outpointIndexSize = 4
+	amountSize        = 8
+
+	// History keys are serialized with big endian integers and the DAA score
+	// first, so that the database keeps each history ordered by DAA score
+	serializedHistoryKeySize   = daaScoreSize + externalapi.DomainHashSize + outpointIndexSize + 1
+	serializedHistoryValueSize = externalapi.DomainHashSize + amountSize + externalapi.DomainHashSize
+)
+
+func serializeHistoryKey(key historyKey) []byte {
+	serializedKey := make([]byte, serializedHistoryKeySize)
+	binary.BigEndian.PutUint64(serializedKey[:daaScoreSize], key.daaScore)
+	offset := daaScoreSize
+	copy(serializedKey[offset:offset+externalapi.DomainHashSize], key.outpoint.TransactionID.ByteSlice())
+	offset += externalapi.DomainHashSize
+	binary.BigEndian.PutUint32(serializedKey[offset:offset+outpointIndexSize], key.outpoint.Index)
+	offset += outpointIndexSize
+	if key.isDebit {
+		serializedKey[offset] = 1
+	}
+	return serializedKey
+}
+
+func deserializeHistoryKey(serializedKey []byte) (historyKey, error) {
+	if len(serializedKey) != serializedHistoryKeySize {
+		return historyKey{}, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing "+
+			"address history key", len(serializedKey))
+	}
+
+	daaScore := binary.BigEndian.Uint64(serializedKey[:daaScoreSize])
+	offset := daaScoreSize
+	transactionID, err := externalapi.NewDomainTransactionIDFromByteSlice(
+		serializedKey[offset : offset+externalapi.DomainHashSize])
+	if err != nil {
+		return historyKey{}, err
+	}
+	offset += externalapi.DomainHashSize
+	index := binary.BigEndian.Uint32(serializedKey[offset : offset+outpointIndexSize])
+	offset += outpointIndexSize
+
+	return historyKey{
+		daaScore: daaScore,
+		outpoint: *externalapi.NewDomainOutpoint(transactionID, index),
+		isDebit:  serializedKey[offset] != 0,
+	}, nil
+}
+
+func serializeHistoryValue(entry *AddressHistoryEntry) []byte {
+	serializedValue := make([]byte, serializedHistoryValueSize)
+	copy(serializedValue[:externalapi.DomainHashSize], entry.TransactionID.ByteSlice())
+	offset := externalapi.DomainHashSize
+	binary.LittleEndian.PutUint64(serializedValue[offset:offset+amountSize], entry.Amount)
+	offset += amountSize
+	copy(serializedValue[offset:], entry.AcceptingBlockHash.ByteSlice())
+	return serializedValue
+}
+
+func deserializeAddressHistoryEntry(serializedKey []byte, serializedValue []byte) (*AddressHistoryEntry, error) {
+	key, err := deserializeHistoryKey(serializedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(serializedValue) != serializedHistoryValueSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing "+
+			"address history value", len(serializedValue))
+	}
+
+	transactionID, err := externalapi.NewDomainTransactionIDFromByteSlice(
+		serializedValue[:externalapi.DomainHashSize])
+	if err != nil {
+		return nil, err
+	}
+	offset := externalapi.DomainHashSize
+	amount := binary.LittleEndian.Uint64(serializedValue[offset : offset+amountSize])
+	offset += amountSize
+	acceptingBlockHash, err := externalapi.NewDomainHashFromByteSlice(serializedValue[offset:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressHistoryEntry{
+		Outpoint:           key.outpoint,
+		TransactionID:      *transactionID,
+		Amount:             amount,
+		IsDebit:            key.isDebit,
+		AcceptingBlockHash: acceptingBlockHash,
+		DAAScore:           key.daaScore,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package addresshistoryindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func randomAddressHistoryEntry(r *rand.Rand) *AddressHistoryEntry {
+	var outpointTransactionIDBytes, transactionIDBytes, acceptingBlockHashBytes [externalapi.DomainHashSize]byte
+	r.Read(outpointTransactionIDBytes[:])
+	r.Read(transactionIDBytes[:])
+	r.Read(acceptingBlockHashBytes[:])
+	return &AddressHistoryEntry{
+		Outpoint: externalapi.DomainOutpoint{
+			TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&outpointTransactionIDBytes),
+			Index:         r.Uint32(),
+		},
+		TransactionID:      *externalapi.NewDomainTransactionIDFromByteArray(&transactionIDBytes),
+		Amount:             r.Uint64(),
+		IsDebit:            r.Intn(2) == 0,
+		AcceptingBlockHash: externalapi.NewDomainHashFromByteArray(&acceptingBlockHashBytes),
+		DAAScore:           r.Uint64(),
+	}
+}
+
+func Test_serializeAddressHistoryEntry(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 32; i++ {
+		entry := randomAddressHistoryEntry(r)
+		result, err := deserializeAddressHistoryEntry(serializeHistoryKey(entry.key()), serializeHistoryValue(entry))
+		if err != nil {
+			t.Fatalf("Failed deserializing address history entry: %v", err)
+		}
+		if result.Outpoint != entry.Outpoint ||
+			result.TransactionID != entry.TransactionID ||
+			result.Amount != entry.Amount ||
+			result.IsDebit != entry.IsDebit ||
+			!result.AcceptingBlockHash.Equal(entry.AcceptingBlockHash) ||
+			result.DAAScore != entry.DAAScore {
+			t.Fatalf("Expected \n %+v \n==\n %+v\n", entry, result)
+		}
+	}
+}
+
+func Test_serializeHistoryKeyOrdering(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 32; i++ {
+		lowerEntry := randomAddressHistoryEntry(r)
+		higherEntry := randomAddressHistoryEntry(r)
+		lowerEntry.DAAScore = uint64(r.Uint32())
+		higherEntry.DAAScore = lowerEntry.DAAScore + 1 + uint64(r.Uint32())
+
+		serializedLowerKey := string(serializeHistoryKey(lowerEntry.key()))
+		serializedHigherKey := string(serializeHistoryKey(higherEntry.key()))
+		if serializedLowerKey >= serializedHigherKey {
+			t.Fatalf("Expected the key of DAA score %d to be ordered before the key of DAA score %d",
+				lowerEntry.DAAScore, higherEntry.DAAScore)
+		}
+	}
+}
+
+func Test_deserializeAddressHistoryEntryFailure(t *testing.T) {
+	entry := randomAddressHistoryEntry(rand.New(rand.NewSource(0)))
+	serializedKey := serializeHistoryKey(entry.key())
+	serializedValue := serializeHistoryValue(entry)
+
+	_, err := deserializeAddressHistoryEntry(serializedKey[:len(serializedKey)-1], serializedValue)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+	_, err = deserializeAddressHistoryEntry(serializedKey, serializedValue[:len(serializedValue)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package addresshistoryindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/database/binaryserialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+var addressHistoryIndexBucket = database.MakeBucket([]byte("address-history-index"))
+var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("address-history-index-virtual-parents"))
+
+type addressHistoryIndexStore struct {
+	database       database.Database
+	toAdd          map[ScriptPublicKeyString]map[historyKey]*AddressHistoryEntry
+	toRemove       map[ScriptPublicKeyString]map[historyKey]struct{}
+	virtualParents []*externalapi.DomainHash
+}
+
+func newAddressHistoryIndexStore(database database.Database) *addressHistoryIndexStore {
+	return &addressHistoryIndexStore{
+		database: database,
+		toAdd:    make(map[ScriptPublicKeyString]map[historyKey]*AddressHistoryEntry),
+		toRemove: make(map[ScriptPublicKeyString]map[historyKey]struct{}),
+	}
+}
+
+func (ahis *addressHistoryIndexStore) add(scriptPublicKey *externalapi.ScriptPublicKey, entry *AddressHistoryEntry) {
+	key := ConvertScriptPublicKeyToString(scriptPublicKey)
+	log.Tracef("Adding history entry of outpoint %s (debit: %t) to script public key %s",
+		entry.Outpoint, entry.IsDebit, key)
+
+	entryKey := entry.key()
+
+	// The last staged operation on an entry is the one that counts,
+	// so adding an entry cancels any staged removal of it
+	if toRemoveEntriesOfKey, found := ahis.toRemove[key]; found {
+		delete(toRemoveEntriesOfKey, entryKey)
+		if len(toRemoveEntriesOfKey) == 0 {
+			delete(ahis.toRemove, key)
+		}
+	}
+
+	if _, found := ahis.toAdd[key]; !found {
+		ahis.toAdd[key] = make(map[historyKey]*AddressHistoryEntry)
+	}
+	ahis.toAdd[key][entryKey] = entry
+}
+
+func (ahis *addressHistoryIndexStore) remove(scriptPublicKey *externalapi.ScriptPublicKey, entry *AddressHistoryEntry) {
+	key := ConvertScriptPublicKeyToString(scriptPublicKey)
+	log.Tracef("Removing history entry of outpoint %s (debit: %t) from script public key %s",
+		entry.Outpoint, entry.IsDebit, key)
+
+	entryKey := entry.key()
+
+	// The entry might have been both committed previously and staged for addition
+	// since, so we cancel the staged addition and stage a removal as well
+	if toAddEntriesOfKey, found := ahis.toAdd[key]; found {
+		delete(toAddEntriesOfKey, entryKey)
+		if len(toAddEntriesOfKey) == 0 {
+			delete(ahis.toAdd, key)
+		}
+	}
+
+	if _, found := ahis.toRemove[key]; !found {
+		ahis.toRemove[key] = make(map[historyKey]struct{})
+	}
+	ahis.toRemove[key][entryKey] = struct{}{}
+}
+
+func (ahis *addressHistoryIndexStore) updateVirtualParents(virtualParents []*externalapi.DomainHash) {
+	ahis.virtualParents = virtualParents
+}
+
+func (ahis *addressHistoryIndexStore) discard() {
+	ahis.toAdd = make(map[ScriptPublicKeyString]map[historyKey]*AddressHistoryEntry)
+	ahis.toRemove = make(map[ScriptPublicKeyString]map[historyKey]struct{})
+	ahis.virtualParents = nil
+}
+
+func (ahis *addressHistoryIndexStore) commit() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "addressHistoryIndexStore.commit")
+	defer onEnd()
+
+	dbTransaction, err := ahis.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = ahis.commitStagedData(dbTransaction)
+	if err != nil {
+		return err
+	}
+
+	if ahis.virtualParents != nil {
+		err = dbTransaction.Put(virtualParentsKey, binaryserialization.SerializeLengthPrefixedHashes(ahis.virtualParents))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dbTransaction.Commit()
+	if err != nil {
+		return err
+	}
+
+	ahis.discard()
+	return nil
+}
+
+func (ahis *addressHistoryIndexStore) commitStagedData(dataAccessor database.DataAccessor) error {
+	for scriptPublicKeyString, toRemoveEntriesOfKey := range ahis.toRemove {
+		bucket := ahis.bucketForScriptPublicKeyString(scriptPublicKeyString)
+		for entryKey := range toRemoveEntriesOfKey {
+			err := dataAccessor.Delete(bucket.Key(serializeHistoryKey(entryKey)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for scriptPublicKeyString, toAddEntriesOfKey := range ahis.toAdd {
+		bucket := ahis.bucketForScriptPublicKeyString(scriptPublicKeyString)
+		for entryKey, entry := range toAddEntriesOfKey {
+			err := dataAccessor.Put(bucket.Key(serializeHistoryKey(entryKey)), serializeHistoryValue(entry))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ahis *addressHistoryIndexStore) commitWithoutTransaction() error {
+	err := ahis.commitStagedData(ahis.database)
+	if err != nil {
+		return err
+	}
+
+	ahis.discard()
+	return nil
+}
+
+func (ahis *addressHistoryIndexStore) updateAndCommitVirtualParentsWithoutTransaction(virtualParents []*externalapi.DomainHash) error {
+	serializeParentHashes := binaryserialization.SerializeLengthPrefixedHashes(virtualParents)
+	return ahis.database.Put(virtualParentsKey, serializeParentHashes)
+}
+
+func (ahis *addressHistoryIndexStore) bucketForScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Bucket {
+	return ahis.bucketForScriptPublicKeyString(ConvertScriptPublicKeyToString(scriptPublicKey))
+}
+
+func (ahis *addressHistoryIndexStore) bucketForScriptPublicKeyString(
+	scriptPublicKeyString ScriptPublicKeyString) *database.Bucket {
+
+	return addressHistoryIndexBucket.Bucket([]byte(scriptPublicKeyString))
+}
+
+func (ahis *addressHistoryIndexStore) isAnythingStaged() bool {
+	return len(ahis.toAdd) > 0 || len(ahis.toRemove) > 0
+}
+
+// getHistory returns up to `limit` history entries of the given script public key,
+// ordered by DAA score. If `after` is not nil, only the entries whose keys follow
+// it are returned
+func (ahis *addressHistoryIndexStore) getHistory(scriptPublicKey *externalapi.ScriptPublicKey,
+	after *historyKey, limit uint64) ([]*AddressHistoryEntry, error) {
+
+	if ahis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get address history while staging isn't empty")
+	}
+
+	bucket := ahis.bucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := ahis.database.Cursor(bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	// isOnUnreadEntry is true when the cursor is already positioned on
+	// an entry that should be returned, so Next must not be called first
+	isOnUnreadEntry := false
+	if after != nil {
+		err = cursor.Seek(bucket.Key(serializeHistoryKey(*after)))
+		if err != nil {
+			if !database.IsNotFoundError(err) {
+				return nil, err
+			}
+			// The entry of the given key doesn't exist, for example because it was
+			// removed by a reorg. Seek still moves the cursor to the first entry
+			// that follows it, unless there's none
+			_, err = cursor.Key()
+			if err != nil {
+				if database.IsNotFoundError(err) {
+					return []*AddressHistoryEntry{}, nil
+				}
+				return nil, err
+			}
+			isOnUnreadEntry = true
+		}
+	}
+
+	entries := make([]*AddressHistoryEntry, 0)
+	for uint64(len(entries)) < limit {
+		if !isOnUnreadEntry && !cursor.Next() {
+			break
+		}
+		isOnUnreadEntry = false
+
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		serializedValue, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		entry, err := deserializeAddressHistoryEntry(key.Suffix(), serializedValue)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (ahis *addressHistoryIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
+	if ahis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the virtual parents while staging isn't empty")
+	}
+
+	serializedHashes, err := ahis.database.Get(virtualParentsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryserialization.DeserializeLengthPrefixedHashes(serializedHashes)
+}
+
+func (ahis *addressHistoryIndexStore) deleteAll() error {
+	// First we delete the virtual parents, so if anything goes wrong, the address history index will be marked
+	// as "not synced" and will be reset.
+	err := ahis.database.Delete(virtualParentsKey)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := ahis.database.Cursor(addressHistoryIndexBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+
+		err = ahis.database.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
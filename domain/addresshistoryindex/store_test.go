@@ -0,0 +1,105 @@
+package addresshistoryindex
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+func TestGetHistoryWithCursor(t *testing.T) {
+	database, err := ldb.NewLevelDB(t.TempDir(), 8)
+	if err != nil {
+		t.Fatalf("NewLevelDB: %s", err)
+	}
+	defer database.Close()
+
+	store := newAddressHistoryIndexStore(database)
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1, 2, 3}, Version: 0}
+
+	r := rand.New(rand.NewSource(0))
+	const entryCount = 25
+	entries := make([]*AddressHistoryEntry, entryCount)
+	for i := range entries {
+		entries[i] = randomAddressHistoryEntry(r)
+		entries[i].DAAScore = uint64(r.Intn(10))
+		store.add(scriptPublicKey, entries[i])
+	}
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %s", err)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Cursor() < entries[j].Cursor()
+	})
+
+	getPage := func(afterCursor string, limit uint64) []*AddressHistoryEntry {
+		var after *historyKey
+		if afterCursor != "" {
+			key, err := parseCursor(afterCursor)
+			if err != nil {
+				t.Fatalf("parseCursor: %s", err)
+			}
+			after = &key
+		}
+		page, err := store.getHistory(scriptPublicKey, after, limit)
+		if err != nil {
+			t.Fatalf("getHistory: %s", err)
+		}
+		return page
+	}
+
+	// Paging through the whole history returns every entry exactly once, in order
+	const pageSize = 7
+	var history []*AddressHistoryEntry
+	cursor := ""
+	for {
+		page := getPage(cursor, pageSize)
+		history = append(history, page...)
+		if len(page) < pageSize {
+			break
+		}
+		cursor = page[len(page)-1].Cursor()
+	}
+	if !reflect.DeepEqual(history, entries) {
+		t.Fatalf("unexpected history. Want: %+v, got: %+v", entries, history)
+	}
+
+	// Removing the entry a cursor points to, like a reorg might, doesn't move the cursor
+	const cursorIndex = 10
+	cursor = entries[cursorIndex].Cursor()
+	store.remove(scriptPublicKey, entries[cursorIndex])
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %s", err)
+	}
+	page := getPage(cursor, pageSize)
+	if !reflect.DeepEqual(page, entries[cursorIndex+1:cursorIndex+1+pageSize]) {
+		t.Fatalf("unexpected page after a removed cursor entry. Want: %+v, got: %+v",
+			entries[cursorIndex+1:cursorIndex+1+pageSize], page)
+	}
+
+	// A cursor past the last entry returns no entries
+	store.remove(scriptPublicKey, entries[entryCount-1])
+	err = store.commit()
+	if err != nil {
+		t.Fatalf("commit: %s", err)
+	}
+	page = getPage(entries[entryCount-1].Cursor(), pageSize)
+	if len(page) != 0 {
+		t.Fatalf("expected no entries after the last cursor, got %+v", page)
+	}
+}
+
+func TestParseInvalidCursor(t *testing.T) {
+	for _, cursor := range []string{"not hex", "0102"} {
+		_, err := parseCursor(cursor)
+		if !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("parseCursor(%s): expected ErrInvalidCursor, got: %v", cursor, err)
+		}
+	}
+}
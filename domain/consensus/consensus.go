@@ -771,6 +771,19 @@ func (s *consensus) ResolveVirtual() (*externalapi.VirtualChangeSet, bool, error
 	return virtualChangeSet, isCompletelyResolved, nil
 }
 
+func (s *consensus) RollbackVirtual(chainBlockHash *externalapi.DomainHash) (*externalapi.VirtualChangeSet, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stagingArea := model.NewStagingArea()
+	err := s.validateBlockHashExists(stagingArea, chainBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.consensusStateManager.RollbackVirtual(chainBlockHash)
+}
+
 func (s *consensus) BuildPruningPointProof() (*externalapi.PruningPointProof, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
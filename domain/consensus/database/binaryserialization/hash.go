@@ -1,6 +1,9 @@
 package binaryserialization
 
 import (
+	"encoding/binary"
+	"io"
+
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/pkg/errors"
 )
@@ -45,3 +48,30 @@ func DeserializeHashes(hashesBytes []byte) ([]*externalapi.DomainHash, error) {
 	}
 	return hashes, nil
 }
+
+const hashesLengthSize = 8
+
+// SerializeLengthPrefixedHashes serializes a slice of hashes to a slice of
+// bytes, prefixed by the amount of hashes
+func SerializeLengthPrefixedHashes(hashes []*externalapi.DomainHash) []byte {
+	serializedHashes := make([]byte, hashesLengthSize+externalapi.DomainHashSize*len(hashes))
+	binary.LittleEndian.PutUint64(serializedHashes[:hashesLengthSize], uint64(len(hashes)))
+	copy(serializedHashes[hashesLengthSize:], SerializeHashes(hashes))
+	return serializedHashes
+}
+
+// DeserializeLengthPrefixedHashes deserializes a slice of bytes that was
+// serialized by SerializeLengthPrefixedHashes to a slice of hashes
+func DeserializeLengthPrefixedHashes(serializedHashes []byte) ([]*externalapi.DomainHash, error) {
+	if len(serializedHashes) < hashesLengthSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing hashes length")
+	}
+
+	length := binary.LittleEndian.Uint64(serializedHashes[:hashesLengthSize])
+	serializedHashes = serializedHashes[hashesLengthSize:]
+	if length > uint64(len(serializedHashes)/externalapi.DomainHashSize) {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected EOF while deserializing %d hashes", length)
+	}
+
+	return DeserializeHashes(serializedHashes[:length*externalapi.DomainHashSize])
+}
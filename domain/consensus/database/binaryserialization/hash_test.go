@@ -0,0 +1,58 @@
+package binaryserialization
+
+import (
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+)
+
+func TestSerializeLengthPrefixedHashes(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	for length := 0; length < 32; length++ {
+		hashes := make([]*externalapi.DomainHash, length)
+		for i := range hashes {
+			var hashBytes [externalapi.DomainHashSize]byte
+			r.Read(hashBytes[:])
+			hashes[i] = externalapi.NewDomainHashFromByteArray(&hashBytes)
+		}
+		result, err := DeserializeLengthPrefixedHashes(SerializeLengthPrefixedHashes(hashes))
+		if err != nil {
+			t.Fatalf("Failed deserializing hashes: %v", err)
+		}
+		if !externalapi.HashesEqual(hashes, result) {
+			t.Fatalf("Expected \n %s \n==\n %s\n", hashes, result)
+		}
+	}
+}
+
+func TestDeserializeLengthPrefixedHashesFailure(t *testing.T) {
+	hashes := []*externalapi.DomainHash{
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+		externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{3}),
+	}
+
+	tooLong := SerializeLengthPrefixedHashes(hashes)
+	binary.LittleEndian.PutUint64(tooLong[:hashesLengthSize], uint64(len(hashes)+1))
+
+	// A corrupted length must not make the deserializer allocate it upfront
+	huge := SerializeLengthPrefixedHashes(hashes)
+	binary.LittleEndian.PutUint64(huge[:hashesLengthSize], 1<<62)
+
+	tests := map[string][]byte{
+		"missing length": {1, 2, 3},
+		"too long":       tooLong,
+		"huge length":    huge,
+	}
+	for name, serialized := range tests {
+		_, err := DeserializeLengthPrefixedHashes(serialized)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("%s: expected error to be EOF, instead got: %v", name, err)
+		}
+	}
+}
@@ -83,5 +83,5 @@ func (hscss *headersSelectedChainStagingShard) isStaged() bool {
 	return len(hscss.addedByHash) != 0 ||
 		len(hscss.removedByHash) != 0 ||
 		len(hscss.addedByIndex) != 0 ||
-		len(hscss.addedByIndex) != 0
+		len(hscss.removedByIndex) != 0
 }
@@ -0,0 +1,54 @@
+package headersselectedchainstore
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/database"
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+func TestIsStaged(t *testing.T) {
+	store := New(database.MakeBucket(nil), 0, false).(*headersSelectedChainStore)
+
+	tests := []struct {
+		name  string
+		stage func(shard *headersSelectedChainStagingShard)
+	}{
+		{
+			name: "added by hash",
+			stage: func(shard *headersSelectedChainStagingShard) {
+				shard.addedByHash[externalapi.DomainHash{}] = 0
+			},
+		},
+		{
+			name: "removed by hash",
+			stage: func(shard *headersSelectedChainStagingShard) {
+				shard.removedByHash[externalapi.DomainHash{}] = struct{}{}
+			},
+		},
+		{
+			name: "added by index",
+			stage: func(shard *headersSelectedChainStagingShard) {
+				shard.addedByIndex[0] = &externalapi.DomainHash{}
+			},
+		},
+		{
+			name: "removed by index",
+			stage: func(shard *headersSelectedChainStagingShard) {
+				shard.removedByIndex[0] = struct{}{}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		stagingArea := model.NewStagingArea()
+		if store.IsStaged(stagingArea) {
+			t.Fatalf("%s: IsStaged unexpectedly returned true for an empty staging area", test.name)
+		}
+		test.stage(store.stagingShard(stagingArea))
+		if !store.IsStaged(stagingArea) {
+			t.Errorf("%s: IsStaged unexpectedly returned false", test.name)
+		}
+	}
+}
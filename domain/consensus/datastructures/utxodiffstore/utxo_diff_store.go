@@ -2,6 +2,7 @@ package utxodiffstore
 
 import (
 	"github.com/golang/protobuf/proto"
+	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
@@ -43,9 +44,19 @@ func (uds *utxoDiffStore) Stage(stagingArea *model.StagingArea, blockHash *exter
 
 	if utxoDiffChild != nil {
 		stagingShard.utxoDiffChildToAdd[*blockHash] = utxoDiffChild
+		delete(stagingShard.utxoDiffChildToDelete, *blockHash)
 	}
 }
 
+// DeleteUTXODiffChild deletes the utxoDiff child associated with the given blockHash,
+// and keeps its utxoDiff
+func (uds *utxoDiffStore) DeleteUTXODiffChild(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) {
+	stagingShard := uds.stagingShard(stagingArea)
+
+	delete(stagingShard.utxoDiffChildToAdd, *blockHash)
+	stagingShard.utxoDiffChildToDelete[*blockHash] = struct{}{}
+}
+
 func (uds *utxoDiffStore) IsStaged(stagingArea *model.StagingArea) bool {
 	return uds.stagingShard(stagingArea).isStaged()
 }
@@ -91,6 +102,10 @@ func (uds *utxoDiffStore) UTXODiffChild(dbContext model.DBReader, stagingArea *m
 		return utxoDiffChild, nil
 	}
 
+	if _, ok := stagingShard.utxoDiffChildToDelete[*blockHash]; ok {
+		return nil, errors.Wrapf(database.ErrNotFound, "utxoDiff child of block %s is staged for deletion", blockHash)
+	}
+
 	if utxoDiffChild, ok := uds.utxoDiffChildCache.Get(blockHash); ok {
 		return utxoDiffChild.(*externalapi.DomainHash), nil
 	}
@@ -116,6 +131,10 @@ func (uds *utxoDiffStore) HasUTXODiffChild(dbContext model.DBReader, stagingArea
 		return true, nil
 	}
 
+	if _, ok := stagingShard.utxoDiffChildToDelete[*blockHash]; ok {
+		return false, nil
+	}
+
 	if uds.utxoDiffChildCache.Has(blockHash) {
 		return true, nil
 	}
@@ -123,6 +142,61 @@ func (uds *utxoDiffStore) HasUTXODiffChild(dbContext model.DBReader, stagingArea
 	return dbContext.Has(uds.utxoDiffChildHashAsKey(blockHash))
 }
 
+// BlocksWithUTXODiffChildIterator returns an iterator over the hashes of all the blocks
+// that have a UTXODiffChild in the database. Staged changes are not taken into account.
+func (uds *utxoDiffStore) BlocksWithUTXODiffChildIterator(dbContext model.DBReader) (model.BlockIterator, error) {
+	cursor, err := dbContext.Cursor(uds.utxoDiffChildBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blocksWithUTXODiffChildIterator{cursor: cursor}, nil
+}
+
+type blocksWithUTXODiffChildIterator struct {
+	cursor   model.DBCursor
+	isClosed bool
+}
+
+func (b *blocksWithUTXODiffChildIterator) First() bool {
+	if b.isClosed {
+		panic("Tried using a closed BlocksWithUTXODiffChildIterator")
+	}
+	return b.cursor.First()
+}
+
+func (b *blocksWithUTXODiffChildIterator) Next() bool {
+	if b.isClosed {
+		panic("Tried using a closed BlocksWithUTXODiffChildIterator")
+	}
+	return b.cursor.Next()
+}
+
+func (b *blocksWithUTXODiffChildIterator) Get() (*externalapi.DomainHash, error) {
+	if b.isClosed {
+		return nil, errors.New("Tried using a closed BlocksWithUTXODiffChildIterator")
+	}
+	key, err := b.cursor.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	return externalapi.NewDomainHashFromByteSlice(key.Suffix())
+}
+
+func (b *blocksWithUTXODiffChildIterator) Close() error {
+	if b.isClosed {
+		return errors.New("Tried using a closed BlocksWithUTXODiffChildIterator")
+	}
+	b.isClosed = true
+	err := b.cursor.Close()
+	if err != nil {
+		return err
+	}
+	b.cursor = nil
+	return nil
+}
+
 // Delete deletes the utxoDiff associated with the given blockHash
 func (uds *utxoDiffStore) Delete(stagingArea *model.StagingArea, blockHash *externalapi.DomainHash) {
 	stagingShard := uds.stagingShard(stagingArea)
@@ -134,6 +208,7 @@ func (uds *utxoDiffStore) Delete(stagingArea *model.StagingArea, blockHash *exte
 		if _, ok := stagingShard.utxoDiffChildToAdd[*blockHash]; ok {
 			delete(stagingShard.utxoDiffChildToAdd, *blockHash)
 		}
+		delete(stagingShard.utxoDiffChildToDelete, *blockHash)
 		return
 	}
 	stagingShard.toDelete[*blockHash] = struct{}{}
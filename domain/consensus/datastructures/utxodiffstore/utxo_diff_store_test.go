@@ -0,0 +1,55 @@
+package utxodiffstore
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/database"
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/pkg/errors"
+)
+
+func TestDeleteUTXODiffChild(t *testing.T) {
+	store := New(database.MakeBucket(nil), 0, false)
+	stagingArea := model.NewStagingArea()
+	blockHash := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1})
+	utxoDiffChild := externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2})
+
+	// None of the calls below reach the database, so a nil dbContext is used
+	store.Stage(stagingArea, blockHash, utxo.NewUTXODiff(), utxoDiffChild)
+	store.Stage(stagingArea, blockHash, utxo.NewUTXODiff(), nil)
+	hasUTXODiffChild, err := store.HasUTXODiffChild(nil, stagingArea, blockHash)
+	if err != nil {
+		t.Fatalf("HasUTXODiffChild: %+v", err)
+	}
+	if !hasUTXODiffChild {
+		t.Fatalf("Staging a nil utxoDiff child unexpectedly removed the staged utxoDiff child")
+	}
+
+	store.DeleteUTXODiffChild(stagingArea, blockHash)
+	hasUTXODiffChild, err = store.HasUTXODiffChild(nil, stagingArea, blockHash)
+	if err != nil {
+		t.Fatalf("HasUTXODiffChild: %+v", err)
+	}
+	if hasUTXODiffChild {
+		t.Fatalf("HasUTXODiffChild unexpectedly returned true after DeleteUTXODiffChild")
+	}
+	_, err = store.UTXODiffChild(nil, stagingArea, blockHash)
+	if !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("UTXODiffChild: expected ErrNotFound but got %+v", err)
+	}
+	_, err = store.UTXODiff(nil, stagingArea, blockHash)
+	if err != nil {
+		t.Fatalf("DeleteUTXODiffChild unexpectedly removed the utxoDiff: %+v", err)
+	}
+
+	store.Stage(stagingArea, blockHash, utxo.NewUTXODiff(), utxoDiffChild)
+	hasUTXODiffChild, err = store.HasUTXODiffChild(nil, stagingArea, blockHash)
+	if err != nil {
+		t.Fatalf("HasUTXODiffChild: %+v", err)
+	}
+	if !hasUTXODiffChild {
+		t.Fatalf("Staging a utxoDiff child didn't cancel its staged deletion")
+	}
+}
@@ -10,5 +10,7 @@ type UTXODiffStore interface {
 	UTXODiff(dbContext DBReader, stagingArea *StagingArea, blockHash *externalapi.DomainHash) (externalapi.UTXODiff, error)
 	UTXODiffChild(dbContext DBReader, stagingArea *StagingArea, blockHash *externalapi.DomainHash) (*externalapi.DomainHash, error)
 	HasUTXODiffChild(dbContext DBReader, stagingArea *StagingArea, blockHash *externalapi.DomainHash) (bool, error)
+	DeleteUTXODiffChild(stagingArea *StagingArea, blockHash *externalapi.DomainHash)
+	BlocksWithUTXODiffChildIterator(dbContext DBReader) (BlockIterator, error)
 	Delete(stagingArea *StagingArea, blockHash *externalapi.DomainHash)
 }
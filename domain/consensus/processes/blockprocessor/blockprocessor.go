@@ -147,8 +147,11 @@ func (bp *blockProcessor) ValidateAndInsertBlock(block *externalapi.DomainBlock,
 	onEnd := logger.LogAndMeasureExecutionTime(log, "ValidateAndInsertBlock")
 	defer onEnd()
 
+	start := time.Now()
 	stagingArea := model.NewStagingArea()
-	return bp.validateAndInsertBlock(stagingArea, block, false, shouldValidateAgainstUTXO, false)
+	virtualChangeSet, err := bp.validateAndInsertBlock(stagingArea, block, false, shouldValidateAgainstUTXO, false)
+	observeBlockProcessing(block, start, err)
+	return virtualChangeSet, err
 }
 
 func (bp *blockProcessor) ValidateAndInsertImportedPruningPoint(newPruningPoint *externalapi.DomainHash) error {
@@ -163,7 +166,9 @@ func (bp *blockProcessor) ValidateAndInsertBlockWithTrustedData(block *externala
 	onEnd := logger.LogAndMeasureExecutionTime(log, "ValidateAndInsertBlockWithTrustedData")
 	defer onEnd()
 
+	start := time.Now()
 	stagingArea := model.NewStagingArea()
-
-	return bp.validateAndInsertBlockWithTrustedData(stagingArea, block, shouldValidateAgainstUTXO)
+	virtualChangeSet, err := bp.validateAndInsertBlockWithTrustedData(stagingArea, block, shouldValidateAgainstUTXO)
+	observeBlockProcessing(block.Block, start, err)
+	return virtualChangeSet, err
 }
@@ -0,0 +1,45 @@
+package blockprocessor
+
+import (
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	processedBlocks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kaspad_blocks_processed_total",
+		Help: "Number of blocks and headers that were validated and inserted into the DAG",
+	}, []string{"type"})
+	rejectedBlocks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kaspad_blocks_rejected_total",
+		Help: "Number of blocks and headers that failed validation",
+	}, []string{"type"})
+	blockProcessingDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kaspad_block_processing_duration_seconds",
+		Help:    "Time it took to validate and insert blocks and headers",
+		Buckets: metrics.DefaultDurationBuckets,
+	}, []string{"type"})
+)
+
+func init() {
+	metrics.MustRegister(processedBlocks, rejectedBlocks, blockProcessingDuration)
+}
+
+// observeBlockProcessing records the outcome and latency of processing the given block.
+// Header-only blocks are recorded separately from blocks with bodies
+func observeBlockProcessing(block *externalapi.DomainBlock, start time.Time, err error) {
+	blockType := "block"
+	if isHeaderOnlyBlock(block) {
+		blockType = "header"
+	}
+
+	blockProcessingDuration.WithLabelValues(blockType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		rejectedBlocks.WithLabelValues(blockType).Inc()
+		return
+	}
+	processedBlocks.WithLabelValues(blockType).Inc()
+}
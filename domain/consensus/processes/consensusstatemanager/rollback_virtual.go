@@ -0,0 +1,205 @@
+package consensusstatemanager
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/hashset"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/util/staging"
+	"github.com/pkg/errors"
+)
+
+// RollbackVirtual sets the given block of the virtual selected parent chain as the only parent
+// of the virtual, and reverts the virtual UTXO set accordingly.
+// The chain blocks above the given block are set back to UTXOPendingVerification, so they're
+// verified again once they're candidates to be the virtual selected parent. Their UTXO diffs are
+// removed, so that they get new ones once they're verified again, same as blocks that were never verified.
+func (csm *consensusStateManager) RollbackVirtual(chainBlockHash *externalapi.DomainHash) (
+	*externalapi.VirtualChangeSet, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "csm.RollbackVirtual")
+	defer onEnd()
+
+	stagingArea := model.NewStagingArea()
+
+	oldVirtualGHOSTDAGData, err := csm.ghostdagDataStore.Get(csm.databaseContext, stagingArea, model.VirtualBlockHash, false)
+	if err != nil {
+		return nil, err
+	}
+	oldVirtualSelectedParent := oldVirtualGHOSTDAGData.SelectedParent()
+
+	err = csm.validateRollbackTarget(stagingArea, chainBlockHash, oldVirtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Setting the chain blocks above %s as pending verification", chainBlockHash)
+	rolledBackBlocks := hashset.New()
+	currentHash := oldVirtualSelectedParent
+	for !currentHash.Equal(chainBlockHash) {
+		csm.blockStatusStore.Stage(stagingArea, currentHash, externalapi.StatusUTXOPendingVerification)
+		rolledBackBlocks.Add(currentHash)
+
+		currentGHOSTDAGData, err := csm.ghostdagDataStore.Get(csm.databaseContext, stagingArea, currentHash, false)
+		if err != nil {
+			return nil, err
+		}
+		currentHash = currentGHOSTDAGData.SelectedParent()
+	}
+
+	log.Debugf("Making %s the root of the UTXO diff tree", chainBlockHash)
+	err = csm.makeRootOfUTXODiffTree(stagingArea, chainBlockHash, rolledBackBlocks)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Setting %s as the only virtual parent", chainBlockHash)
+	virtualUTXODiff, err := csm.updateVirtualWithParents(stagingArea, []*externalapi.DomainHash{chainBlockHash})
+	if err != nil {
+		return nil, err
+	}
+
+	err = staging.CommitAllChanges(csm.databaseContext, stagingArea)
+	if err != nil {
+		return nil, err
+	}
+
+	readStagingArea := model.NewStagingArea()
+	selectedParentChainChanges, err := csm.dagTraversalManager.
+		CalculateChainPath(readStagingArea, oldVirtualSelectedParent, chainBlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalapi.VirtualChangeSet{
+		VirtualSelectedParentChainChanges: selectedParentChainChanges,
+		VirtualUTXODiff:                   virtualUTXODiff,
+		VirtualParents:                    []*externalapi.DomainHash{chainBlockHash},
+	}, nil
+}
+
+func (csm *consensusStateManager) validateRollbackTarget(stagingArea *model.StagingArea,
+	chainBlockHash, virtualSelectedParent *externalapi.DomainHash) error {
+
+	if chainBlockHash.Equal(virtualSelectedParent) {
+		return errors.Errorf("block %s is already the virtual selected parent", chainBlockHash)
+	}
+
+	isInVirtualSelectedParentChain, err := csm.dagTopologyManager.IsInSelectedParentChainOf(
+		stagingArea, chainBlockHash, virtualSelectedParent)
+	if err != nil {
+		return err
+	}
+	if !isInVirtualSelectedParentChain {
+		return errors.Errorf("block %s is not in the virtual selected parent chain", chainBlockHash)
+	}
+
+	// The UTXO diffs of the blocks below the pruning point are deleted, so the UTXO set of such
+	// blocks can't be restored
+	pruningPoint, err := csm.pruningStore.PruningPoint(csm.databaseContext, stagingArea)
+	if err != nil {
+		return err
+	}
+	isInFutureOfPruningPoint, err := csm.dagTopologyManager.IsInSelectedParentChainOf(
+		stagingArea, pruningPoint, chainBlockHash)
+	if err != nil {
+		return err
+	}
+	if !isInFutureOfPruningPoint {
+		return errors.Errorf("block %s is below the pruning point %s", chainBlockHash, pruningPoint)
+	}
+
+	status, err := csm.blockStatusStore.Get(csm.databaseContext, stagingArea, chainBlockHash)
+	if err != nil {
+		return err
+	}
+	if status != externalapi.StatusUTXOValid {
+		return errors.Errorf("block %s has status %s rather than %s",
+			chainBlockHash, status, externalapi.StatusUTXOValid)
+	}
+
+	return nil
+}
+
+// makeRootOfUTXODiffTree stages UTXO diffs that make the given block the root of the UTXO diff tree,
+// that is - the block whose diff is from the virtual UTXO set, in place of the given rolled back blocks.
+// The UTXO diffs of the rolled back blocks are deleted, and every other block whose UTXO diff child
+// is one of them gets the new root as its UTXO diff child instead.
+func (csm *consensusStateManager) makeRootOfUTXODiffTree(stagingArea *model.StagingArea,
+	rootHash *externalapi.DomainHash, rolledBackBlocks hashset.HashSet) error {
+
+	// The root itself has one of the rolled back blocks as its UTXO diff child
+	blocksToRepoint, err := csm.blocksWithUTXODiffChildIn(stagingArea, rolledBackBlocks, rootHash)
+	if err != nil {
+		return err
+	}
+
+	// All the new UTXO diffs are calculated before any of them is staged, because restoring
+	// past UTXO sets goes through the current UTXO diffs of the rolled back blocks
+	rootPastUTXO, err := csm.restorePastUTXO(stagingArea, rootHash)
+	if err != nil {
+		return err
+	}
+	utxoDiffs := make([]externalapi.UTXODiff, len(blocksToRepoint))
+	for i, blockHash := range blocksToRepoint {
+		pastUTXO, err := csm.restorePastUTXO(stagingArea, blockHash)
+		if err != nil {
+			return err
+		}
+		utxoDiffs[i], err = rootPastUTXO.DiffFrom(pastUTXO)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, blockHash := range blocksToRepoint {
+		log.Tracef("Setting the UTXO diff child of block %s to %s", blockHash, rootHash)
+		csm.stageDiff(stagingArea, blockHash, utxoDiffs[i], rootHash)
+	}
+	csm.stageDiff(stagingArea, rootHash, rootPastUTXO, nil)
+	csm.utxoDiffStore.DeleteUTXODiffChild(stagingArea, rootHash)
+
+	for _, blockHash := range rolledBackBlocks.ToSlice() {
+		csm.utxoDiffStore.Delete(stagingArea, blockHash)
+	}
+	return nil
+}
+
+// blocksWithUTXODiffChildIn returns the blocks outside the given set whose UTXO diff child is in it,
+// except for the given excluded block
+func (csm *consensusStateManager) blocksWithUTXODiffChildIn(stagingArea *model.StagingArea,
+	blocks hashset.HashSet, excludedBlock *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
+
+	iterator, err := csm.utxoDiffStore.BlocksWithUTXODiffChildIterator(csm.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var result []*externalapi.DomainHash
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		blockHash, err := iterator.Get()
+		if err != nil {
+			return nil, err
+		}
+		if blocks.Contains(blockHash) || blockHash.Equal(excludedBlock) {
+			continue
+		}
+
+		hasUTXODiffChild, err := csm.utxoDiffStore.HasUTXODiffChild(csm.databaseContext, stagingArea, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !hasUTXODiffChild {
+			continue
+		}
+		utxoDiffChild, err := csm.utxoDiffStore.UTXODiffChild(csm.databaseContext, stagingArea, blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if blocks.Contains(utxoDiffChild) {
+			result = append(result, blockHash)
+		}
+	}
+	return result, nil
+}
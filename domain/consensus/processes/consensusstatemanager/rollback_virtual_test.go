@@ -0,0 +1,169 @@
+package consensusstatemanager_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+)
+
+func TestRollbackVirtual(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		factory := consensus.NewFactory()
+
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestRollbackVirtual")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		// Create a chain of 10 blocks. The fourth block merges a side block, so that
+		// the UTXO diff tree has a branch outside the chain.
+		const chainLength = 10
+		const rollbackIndex = 4
+		chain := make([]*externalapi.DomainHash, chainLength)
+		var sideBlockHash *externalapi.DomainHash
+		var rollbackUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry
+		for i := 0; i < chainLength; i++ {
+			parents := []*externalapi.DomainHash{consensusConfig.GenesisHash}
+			if i > 0 {
+				parents = []*externalapi.DomainHash{chain[i-1]}
+			}
+			if i == 3 {
+				parents = append(parents, sideBlockHash)
+			}
+			chain[i], _, err = tc.AddBlock(parents, nil, nil)
+			if err != nil {
+				t.Fatalf("Error mining block no. %d: %+v", i, err)
+			}
+
+			if i == 1 {
+				sideBlockHash, _, err = tc.AddBlock([]*externalapi.DomainHash{chain[1]}, nil, nil)
+				if err != nil {
+					t.Fatalf("Error mining the side block: %+v", err)
+				}
+			}
+			if i == rollbackIndex {
+				rollbackUTXOs = virtualUTXOs(t, tc)
+			}
+		}
+		tipUTXOs := virtualUTXOs(t, tc)
+
+		// A tip above the rollback target that isn't in the chain gets the virtual selected parent
+		// as its UTXO diff child, so its UTXO diff has to outlive the rollback
+		sideTipHash, _, err := tc.AddBlock([]*externalapi.DomainHash{chain[rollbackIndex+2]}, nil, nil)
+		if err != nil {
+			t.Fatalf("Error mining the side tip: %+v", err)
+		}
+		sideTipPastUTXOs := pastUTXOs(t, tc, sideTipHash)
+
+		_, err = tc.RollbackVirtual(chain[chainLength-1])
+		if err == nil {
+			t.Fatalf("RollbackVirtual to the virtual selected parent unexpectedly succeeded")
+		}
+
+		_, err = tc.RollbackVirtual(chain[rollbackIndex])
+		if err != nil {
+			t.Fatalf("RollbackVirtual: %+v", err)
+		}
+
+		virtualSelectedParent, err := tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(chain[rollbackIndex]) {
+			t.Fatalf("Expected the virtual selected parent to be %s but got %s",
+				chain[rollbackIndex], virtualSelectedParent)
+		}
+		compareUTXOs(t, "after the rollback", virtualUTXOs(t, tc), rollbackUTXOs)
+		for _, blockHash := range chain[rollbackIndex+1:] {
+			blockInfo, err := tc.GetBlockInfo(blockHash)
+			if err != nil {
+				t.Fatalf("GetBlockInfo: %+v", err)
+			}
+			if blockInfo.BlockStatus != externalapi.StatusUTXOPendingVerification {
+				t.Fatalf("Expected block %s to be pending verification but got %s", blockHash, blockInfo.BlockStatus)
+			}
+			hasUTXODiffChild, err := tc.UTXODiffStore().HasUTXODiffChild(tc.DatabaseContext(), model.NewStagingArea(), blockHash)
+			if err != nil {
+				t.Fatalf("HasUTXODiffChild: %+v", err)
+			}
+			if hasUTXODiffChild {
+				t.Fatalf("Expected the UTXO diff of block %s to be deleted", blockHash)
+			}
+		}
+		compareUTXOs(t, "of the side tip after the rollback", pastUTXOs(t, tc, sideTipHash), sideTipPastUTXOs)
+
+		// Resolving the virtual verifies the rolled back blocks again, and brings back the same UTXO set
+		_, _, err = tc.ResolveVirtual()
+		if err != nil {
+			t.Fatalf("ResolveVirtual: %+v", err)
+		}
+		virtualSelectedParent, err = tc.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(chain[chainLength-1]) {
+			t.Fatalf("Expected the virtual selected parent to be %s but got %s",
+				chain[chainLength-1], virtualSelectedParent)
+		}
+		compareUTXOs(t, "after resolving the virtual", virtualUTXOs(t, tc), tipUTXOs)
+
+		// The DAG keeps growing on top of the verified chain, and merges the side tip
+		_, _, err = tc.AddBlock([]*externalapi.DomainHash{chain[chainLength-1], sideTipHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("Error mining a block after the rollback: %+v", err)
+		}
+	})
+}
+
+func virtualUTXOs(t *testing.T, tc testapi.TestConsensus) map[externalapi.DomainOutpoint]externalapi.UTXOEntry {
+	virtualInfo, err := tc.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	pairs, err := tc.GetVirtualUTXOs(virtualInfo.ParentHashes, nil, 1000)
+	if err != nil {
+		t.Fatalf("GetVirtualUTXOs: %+v", err)
+	}
+	utxos := make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry, len(pairs))
+	for _, pair := range pairs {
+		utxos[*pair.Outpoint] = pair.UTXOEntry
+	}
+	return utxos
+}
+
+func pastUTXOs(t *testing.T, tc testapi.TestConsensus,
+	blockHash *externalapi.DomainHash) map[externalapi.DomainOutpoint]externalapi.UTXOEntry {
+
+	iterator, err := tc.ConsensusStateManager().RestorePastUTXOSetIterator(model.NewStagingArea(), blockHash)
+	if err != nil {
+		t.Fatalf("RestorePastUTXOSetIterator: %+v", err)
+	}
+	defer iterator.Close()
+
+	utxos := make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry)
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		outpoint, entry, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("Get: %+v", err)
+		}
+		utxos[*outpoint] = entry
+	}
+	return utxos
+}
+
+func compareUTXOs(t *testing.T, when string, actual, expected map[externalapi.DomainOutpoint]externalapi.UTXOEntry) {
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d UTXOs %s but got %d", len(expected), when, len(actual))
+	}
+	for outpoint, expectedEntry := range expected {
+		actualEntry, ok := actual[outpoint]
+		if !ok || !actualEntry.Equal(expectedEntry) {
+			t.Fatalf("UTXO %s is missing or different %s", outpoint, when)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package txscript
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/pkg/errors"
+)
+
+// ErrTraceTooLarge is returned by TraceExecution and TraceTransactionInput when
+// the trace exceeds the size they were limited to.
+var ErrTraceTooLarge = errors.New("the trace is too large")
+
+// TraceStep describes a single opcode executed by TraceExecution along with
+// the contents of the stacks right after it was executed.
+type TraceStep struct {
+	// ScriptIndex is the index of the script the opcode belongs to. Index 0
+	// is the signature script, 1 is the public key script and 2 is the
+	// redeem script of a pay-to-script-hash input.
+	ScriptIndex int
+
+	// ScriptOffset is the index of the opcode within its script.
+	ScriptOffset int
+
+	// Opcode is the disassembly of the opcode, including any pushed data.
+	Opcode string
+
+	// IsExecuted is false for opcodes that were skipped because they are
+	// inside a conditional branch that is not executing.
+	IsExecuted bool
+
+	DataStack [][]byte
+	AltStack  [][]byte
+}
+
+// PC returns the index of the script and the offset within it of the opcode
+// that will be executed next when Step() is called.
+func (vm *Engine) PC() (scriptIdx int, scriptOff int, err error) {
+	return vm.curPC()
+}
+
+// NumScripts returns the number of scripts known to the engine. The redeem
+// script of a pay-to-script-hash input is only known once the public key
+// script had finished executing.
+func (vm *Engine) NumScripts() int {
+	return len(vm.scripts)
+}
+
+// CurrentOpcode returns the disassembly of the opcode that will be executed
+// next when Step() is called. Unlike DisasmPC, it is not prefixed by the
+// program counter.
+func (vm *Engine) CurrentOpcode() (string, error) {
+	scriptIdx, scriptOff, err := vm.curPC()
+	if err != nil {
+		return "", err
+	}
+	return vm.scripts[scriptIdx][scriptOff].print(false), nil
+}
+
+// IsBranchExecuting returns whether the opcode that will be executed next is
+// in an executing branch of the current conditional, if any.
+func (vm *Engine) IsBranchExecuting() bool {
+	return vm.isBranchExecuting()
+}
+
+// IsDone returns whether all scripts have been executed.
+func (vm *Engine) IsDone() bool {
+	return vm.scriptIdx >= len(vm.scripts)
+}
+
+// TraceExecution executes all scripts in the script engine like Execute, and
+// additionally returns a TraceStep for every executed opcode. If an opcode
+// fails, its step is the last one returned along with the error.
+// If maxTraceSize is not 0 and the total Size of the steps exceeds it,
+// execution stops and ErrTraceTooLarge is returned along with the steps so far.
+func (vm *Engine) TraceExecution(maxTraceSize int) ([]*TraceStep, error) {
+	if vm.scriptVersion > constants.MaxScriptPublicKeyVersion {
+		log.Tracef("The version of the scriptPublicKey is higher than the known version - " +
+			"the scripts aren't executed and no steps are traced.")
+		return nil, nil
+	}
+
+	var steps []*TraceStep
+	traceSize := 0
+	done := vm.IsDone()
+	for !done {
+		scriptIdx, scriptOff, err := vm.curPC()
+		if err != nil {
+			return steps, err
+		}
+		pop := &vm.scripts[scriptIdx][scriptOff]
+		step := &TraceStep{
+			ScriptIndex:  scriptIdx,
+			ScriptOffset: scriptOff,
+			Opcode:       pop.print(false),
+			IsExecuted:   vm.isBranchExecuting() || pop.isConditional(),
+		}
+
+		done, err = vm.Step()
+		step.DataStack = copyStackItems(vm.GetStack())
+		step.AltStack = copyStackItems(vm.GetAltStack())
+		steps = append(steps, step)
+		if err != nil {
+			return steps, err
+		}
+
+		traceSize += step.Size()
+		if maxTraceSize != 0 && traceSize > maxTraceSize {
+			return steps, errors.Wrapf(ErrTraceTooLarge, "the trace exceeds %d bytes", maxTraceSize)
+		}
+	}
+
+	return steps, vm.CheckErrorCondition(true)
+}
+
+// InputTrace is the result of tracing the scripts of a single transaction input
+type InputTrace struct {
+	// Scripts holds the disassembly of every script known to the engine
+	// once execution had stopped. See TraceStep.ScriptIndex.
+	Scripts []string
+
+	Steps []*TraceStep
+
+	// Err is nil if the input scripts executed successfully.
+	Err error
+}
+
+// TraceTransactionInput executes the scripts of the input at the given index of
+// tx and returns their trace. The UTXOEntry of the input must be populated.
+// Script failures are returned in InputTrace.Err rather than as an error.
+// maxTraceSize limits the trace like it does in TraceExecution, and exceeding
+// it is returned as an error.
+func TraceTransactionInput(tx *externalapi.DomainTransaction, inputIndex int,
+	sighashReusedValues *consensushashing.SighashReusedValues, maxTraceSize int) (*InputTrace, error) {
+
+	if inputIndex < 0 || inputIndex >= len(tx.Inputs) {
+		return nil, errors.Errorf("input index %d is out of range: transaction has %d inputs",
+			inputIndex, len(tx.Inputs))
+	}
+	utxoEntry := tx.Inputs[inputIndex].UTXOEntry
+	if utxoEntry == nil {
+		return nil, errors.Errorf("input %d is missing its UTXO entry", inputIndex)
+	}
+
+	vm, err := NewEngine(utxoEntry.ScriptPublicKey(), tx, inputIndex, ScriptNoFlags, nil, nil, sighashReusedValues)
+	if err != nil {
+		return &InputTrace{Err: err}, nil
+	}
+
+	steps, err := vm.TraceExecution(maxTraceSize)
+	if errors.Is(err, ErrTraceTooLarge) {
+		return nil, err
+	}
+	scripts := make([]string, vm.NumScripts())
+	for i := range scripts {
+		scripts[i], _ = vm.DisasmScript(i)
+	}
+	return &InputTrace{
+		Scripts: scripts,
+		Steps:   steps,
+		Err:     err,
+	}, nil
+}
+
+// String returns a human-readable representation of the step
+func (step *TraceStep) String() string {
+	skipped := ""
+	if !step.IsExecuted {
+		skipped = " (skipped)"
+	}
+	return fmt.Sprintf("%02x:%04x: %s%s", step.ScriptIndex, step.ScriptOffset, step.Opcode, skipped)
+}
+
+// Size returns the approximate amount of memory the step takes: the size of its
+// opcode and of the items of its stacks
+func (step *TraceStep) Size() int {
+	size := len(step.Opcode)
+	for _, item := range step.DataStack {
+		size += len(item)
+	}
+	for _, item := range step.AltStack {
+		size += len(item)
+	}
+	return size
+}
+
+func copyStackItems(items [][]byte) [][]byte {
+	itemsCopy := make([][]byte, len(items))
+	for i, item := range items {
+		itemsCopy[i] = make([]byte, len(item))
+		copy(itemsCopy[i], item)
+	}
+	return itemsCopy
+}
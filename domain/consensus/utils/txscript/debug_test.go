@@ -0,0 +1,203 @@
+package txscript
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/pkg/errors"
+)
+
+func TestTraceExecution(t *testing.T) {
+	tests := []struct {
+		name             string
+		signatureScript  string
+		scriptPublicKey  string
+		expectedOpcodes  []string
+		expectedExecuted []bool
+		expectedStacks   [][][]byte
+		expectErr        bool
+		expectedErrCode  ErrorCode
+	}{
+		{
+			name:             "successful conditional",
+			signatureScript:  "OP_1",
+			scriptPublicKey:  "IF OP_2 ELSE OP_3 ENDIF OP_2 EQUAL",
+			expectedOpcodes:  []string{"OP_1", "OP_IF", "OP_2", "OP_ELSE", "OP_3", "OP_ENDIF", "OP_2", "OP_EQUAL"},
+			expectedExecuted: []bool{true, true, true, true, false, true, true, true},
+			expectedStacks: [][][]byte{
+				{{1}},
+				{},
+				{{2}},
+				{{2}},
+				{{2}},
+				{{2}},
+				{{2}, {2}},
+				{{1}},
+			},
+			expectErr: false,
+		},
+		{
+			name:             "failing verify",
+			signatureScript:  "OP_1",
+			scriptPublicKey:  "OP_2 EQUALVERIFY OP_1",
+			expectedOpcodes:  []string{"OP_1", "OP_2", "OP_EQUALVERIFY"},
+			expectedExecuted: []bool{true, true, true},
+			expectedStacks: [][][]byte{
+				{{1}},
+				{{1}, {2}},
+				{},
+			},
+			expectErr:       true,
+			expectedErrCode: ErrEqualVerify,
+		},
+	}
+
+	for _, test := range tests {
+		tx := &externalapi.DomainTransaction{
+			Version: 1,
+			Inputs: []*externalapi.DomainTransactionInput{{
+				SignatureScript: mustParseShortForm(test.signatureScript, 0),
+				Sequence:        4294967295,
+			}},
+			Outputs: []*externalapi.DomainTransactionOutput{{
+				Value:           1000000000,
+				ScriptPublicKey: nil,
+			}},
+		}
+		scriptPublicKey := &externalapi.ScriptPublicKey{Script: mustParseShortForm(test.scriptPublicKey, 0), Version: 0}
+
+		vm, err := NewEngine(scriptPublicKey, tx, 0, 0, nil, nil, &consensushashing.SighashReusedValues{})
+		if err != nil {
+			t.Fatalf("%s: failed to create script: %v", test.name, err)
+		}
+
+		steps, err := vm.TraceExecution(0)
+		if test.expectErr && !IsErrorCode(err, test.expectedErrCode) {
+			t.Fatalf("%s: unexpected error. Want: %s, got: %v", test.name, test.expectedErrCode, err)
+		}
+		if !test.expectErr && err != nil {
+			t.Fatalf("%s: TraceExecution: %s", test.name, err)
+		}
+		if len(steps) != len(test.expectedOpcodes) {
+			t.Fatalf("%s: unexpected amount of steps. Want: %d, got: %d",
+				test.name, len(test.expectedOpcodes), len(steps))
+		}
+		for i, step := range steps {
+			if step.Opcode != test.expectedOpcodes[i] {
+				t.Errorf("%s: unexpected opcode in step %d. Want: %s, got: %s",
+					test.name, i, test.expectedOpcodes[i], step.Opcode)
+			}
+			if step.IsExecuted != test.expectedExecuted[i] {
+				t.Errorf("%s: unexpected IsExecuted in step %d. Want: %t, got: %t",
+					test.name, i, test.expectedExecuted[i], step.IsExecuted)
+			}
+			if !reflect.DeepEqual(step.DataStack, test.expectedStacks[i]) {
+				t.Errorf("%s: unexpected data stack in step %d. Want: %x, got: %x",
+					test.name, i, test.expectedStacks[i], step.DataStack)
+			}
+		}
+	}
+}
+
+func TestTraceExecutionSizeLimit(t *testing.T) {
+	tx := &externalapi.DomainTransaction{
+		Version: 1,
+		Inputs: []*externalapi.DomainTransactionInput{{
+			SignatureScript: mustParseShortForm("OP_1", 0),
+			Sequence:        4294967295,
+		}},
+		Outputs: []*externalapi.DomainTransactionOutput{{Value: 1000000000}},
+	}
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: mustParseShortForm("DUP DUP DUP DUP DROP DROP DROP DROP", 0), Version: 0}
+
+	newEngine := func() *Engine {
+		vm, err := NewEngine(scriptPublicKey, tx, 0, 0, nil, nil, &consensushashing.SighashReusedValues{})
+		if err != nil {
+			t.Fatalf("Failed to create script: %v", err)
+		}
+		return vm
+	}
+
+	steps, err := newEngine().TraceExecution(0)
+	if err != nil {
+		t.Fatalf("TraceExecution: %s", err)
+	}
+	traceSize := 0
+	for _, step := range steps {
+		traceSize += step.Size()
+	}
+
+	steps, err = newEngine().TraceExecution(traceSize)
+	if err != nil {
+		t.Fatalf("TraceExecution unexpectedly failed with a limit of exactly the trace size: %s", err)
+	}
+
+	limitedSteps, err := newEngine().TraceExecution(traceSize / 2)
+	if !errors.Is(err, ErrTraceTooLarge) {
+		t.Fatalf("Unexpected error. Want: %s, got: %v", ErrTraceTooLarge, err)
+	}
+	if len(limitedSteps) >= len(steps) {
+		t.Fatalf("Expected the limited trace to stop early, but it has %d out of %d steps",
+			len(limitedSteps), len(steps))
+	}
+}
+
+func TestStepAPI(t *testing.T) {
+	tx := &externalapi.DomainTransaction{
+		Version: 1,
+		Inputs: []*externalapi.DomainTransactionInput{{
+			SignatureScript: mustParseShortForm("OP_1", 0),
+			Sequence:        4294967295,
+		}},
+		Outputs: []*externalapi.DomainTransactionOutput{{Value: 1000000000}},
+	}
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: mustParseShortForm("OP_1 EQUAL", 0), Version: 0}
+
+	vm, err := NewEngine(scriptPublicKey, tx, 0, 0, nil, nil, &consensushashing.SighashReusedValues{})
+	if err != nil {
+		t.Fatalf("Failed to create script: %v", err)
+	}
+	if vm.NumScripts() != 2 {
+		t.Fatalf("Unexpected number of scripts. Want: %d, got: %d", 2, vm.NumScripts())
+	}
+
+	expectedPCs := [][2]int{{0, 0}, {1, 0}, {1, 1}}
+	expectedOpcodes := []string{"OP_1", "OP_1", "OP_EQUAL"}
+	for i := range expectedPCs {
+		scriptIdx, scriptOff, err := vm.PC()
+		if err != nil {
+			t.Fatalf("PC: %s", err)
+		}
+		if scriptIdx != expectedPCs[i][0] || scriptOff != expectedPCs[i][1] {
+			t.Fatalf("Unexpected PC in step %d. Want: %v, got: %d:%d", i, expectedPCs[i], scriptIdx, scriptOff)
+		}
+		opcode, err := vm.CurrentOpcode()
+		if err != nil {
+			t.Fatalf("CurrentOpcode: %s", err)
+		}
+		if opcode != expectedOpcodes[i] {
+			t.Fatalf("Unexpected opcode in step %d. Want: %s, got: %s", i, expectedOpcodes[i], opcode)
+		}
+		if vm.IsDone() {
+			t.Fatalf("Engine is unexpectedly done in step %d", i)
+		}
+		_, err = vm.Step()
+		if err != nil {
+			t.Fatalf("Step: %s", err)
+		}
+	}
+
+	if !vm.IsDone() {
+		t.Fatalf("Engine is unexpectedly not done")
+	}
+	_, err = vm.CurrentOpcode()
+	if !IsErrorCode(err, ErrInvalidProgramCounter) {
+		t.Fatalf("Unexpected CurrentOpcode error. Want: %s, got: %v", ErrInvalidProgramCounter, err)
+	}
+	err = vm.CheckErrorCondition(true)
+	if err != nil {
+		t.Fatalf("CheckErrorCondition: %s", err)
+	}
+}
@@ -24,6 +24,12 @@ const (
 	PubKeyTy                         // Pay to pubkey.
 	PubKeyECDSATy                    // Pay to pubkey ECDSA.
 	ScriptHashTy                     // Pay to script hash.
+
+	// Contract redeem scripts, see templates.go. These are only recognized
+	// as the redeem script of a pay-to-script-hash input.
+	HTLCTy                // Hash-timelocked contract.
+	TimelockVaultTy       // Relative-timelock vault.
+	MultiSigWithTimeoutTy // Multisig with timeout recovery.
 )
 
 // Script public key versions for address types.
@@ -40,6 +46,10 @@ var scriptClassToName = []string{
 	PubKeyTy:      "pubkey",
 	PubKeyECDSATy: "pubkeyecdsa",
 	ScriptHashTy:  "scripthash",
+
+	HTLCTy:                "htlc",
+	TimelockVaultTy:       "timelockvault",
+	MultiSigWithTimeoutTy: "multisigwithtimeout",
 }
 
 // String implements the Stringer interface by returning the name of
@@ -94,6 +104,33 @@ func GetScriptClass(script []byte) ScriptClass {
 	return typeOfScript(pops)
 }
 
+// typeOfRedeemScript returns the type of the redeem script being inspected
+// from the known contract templates and the standard types.
+func typeOfRedeemScript(pops []parsedOpcode) ScriptClass {
+	switch {
+	case isHTLC(pops):
+		return HTLCTy
+	case isTimelockVault(pops):
+		return TimelockVaultTy
+	case isMultiSigWithTimeout(pops):
+		return MultiSigWithTimeoutTy
+	}
+	return typeOfScript(pops)
+}
+
+// GetRedeemScriptClass returns the class of the pay-to-script-hash redeem
+// script passed. Unlike GetScriptClass, it also recognizes the contract
+// templates.
+//
+// NonStandardTy will be returned when the script does not parse.
+func GetRedeemScriptClass(redeemScript []byte) ScriptClass {
+	pops, err := parseScript(redeemScript)
+	if err != nil {
+		return NonStandardTy
+	}
+	return typeOfRedeemScript(pops)
+}
+
 // expectedInputs returns the number of arguments required by a script.
 // If the script is of unknown type such that the number can not be determined
 // then -1 is returned. We are an internal function and thus assume that class
@@ -121,6 +158,11 @@ type ScriptInfo struct {
 	// to calling GetScriptClass on it.
 	ScriptPubKeyClass ScriptClass
 
+	// RedeemScriptClass is the class of the redeem script of a
+	// pay-to-script-hash input and is equivalent to calling
+	// GetRedeemScriptClass on it. It is NonStandardTy for other inputs.
+	RedeemScriptClass ScriptClass
+
 	// NumInputs is the number of inputs provided by the public key script.
 	NumInputs int
 
@@ -172,7 +214,18 @@ func CalcScriptInfo(sigScript, scriptPubKey []byte, isP2SH bool) (*ScriptInfo, e
 			return nil, err
 		}
 
-		shInputs := expectedInputs(shPops, typeOfScript(shPops))
+		si.RedeemScriptClass = typeOfRedeemScript(shPops)
+		var shInputs int
+		switch si.RedeemScriptClass {
+		case HTLCTy, TimelockVaultTy, MultiSigWithTimeoutTy:
+			var selector *parsedOpcode
+			if len(sigPops) >= 2 {
+				selector = &sigPops[len(sigPops)-2]
+			}
+			shInputs = expectedContractInputs(shPops, si.RedeemScriptClass, selector)
+		default:
+			shInputs = expectedInputs(shPops, si.RedeemScriptClass)
+		}
 		if shInputs == -1 {
 			si.ExpectedInputs = -1
 		} else {
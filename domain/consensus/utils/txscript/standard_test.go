@@ -244,6 +244,7 @@ func TestCalcScriptInfo(t *testing.T) {
 			isP2SH: true,
 			scriptInfo: ScriptInfo{
 				ScriptPubKeyClass: ScriptHashTy,
+				RedeemScriptClass: PubKeyTy,
 				NumInputs:         3,
 				ExpectedInputs:    2, // nonstandard p2sh.
 				SigOps:            1,
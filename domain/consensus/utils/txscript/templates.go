@@ -0,0 +1,458 @@
+package txscript
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+// HTLCSecretSize is the size of the secret of a hash-timelocked contract
+const HTLCSecretSize = 32
+
+// ContractTemplate is a contract script which is paid to through
+// pay-to-script-hash. Its redeem script is revealed only when it's spent.
+type ContractTemplate interface {
+	RedeemScript() ([]byte, error)
+}
+
+// ContractAddress returns the pay-to-script-hash address of the given contract
+func ContractAddress(contract ContractTemplate, prefix util.Bech32Prefix) (*util.AddressScriptHash, error) {
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		return nil, err
+	}
+	return util.NewAddressScriptHash(redeemScript, prefix)
+}
+
+// PayToContractScript returns the pay-to-script-hash script public key of the
+// given contract
+func PayToContractScript(contract ContractTemplate) (*externalapi.ScriptPublicKey, error) {
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		return nil, err
+	}
+	script, err := PayToScriptHashScript(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	return &externalapi.ScriptPublicKey{Script: script, Version: addressScriptHashScriptPublicKeyVersion}, nil
+}
+
+// HTLC is a hash-timelocked contract. The recipient can spend it by revealing
+// the secret whose SHA256 hash is SecretHash, and the refund key can spend it
+// once the transaction lock time reaches LockTime.
+//
+// Its redeem script is of the form:
+//
+//	OP_IF
+//	    OP_SIZE 32 OP_EQUALVERIFY OP_SHA256 <secret hash> OP_EQUALVERIFY <recipient pubkey>
+//	OP_ELSE
+//	    <lock time> OP_CHECKLOCKTIMEVERIFY <refund pubkey>
+//	OP_ENDIF
+//	OP_CHECKSIG
+type HTLC struct {
+	SecretHash      [32]byte
+	RecipientPubKey []byte
+	RefundPubKey    []byte
+	LockTime        uint64
+}
+
+// RedeemScript returns the redeem script of the contract
+func (c *HTLC) RedeemScript() ([]byte, error) {
+	err := validatePubKeys(c.RecipientPubKey, c.RefundPubKey)
+	if err != nil {
+		return nil, err
+	}
+	err = validateLockTime(c.LockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return finalizeRedeemScript(NewScriptBuilder().
+		AddOp(OpIf).
+		AddOp(OpSize).AddInt64(HTLCSecretSize).AddOp(OpEqualVerify).
+		AddOp(OpSHA256).AddData(c.SecretHash[:]).AddOp(OpEqualVerify).
+		AddData(c.RecipientPubKey).
+		AddOp(OpElse).
+		AddLockTimeNumber(c.LockTime).AddOp(OpCheckLockTimeVerify).
+		AddData(c.RefundPubKey).
+		AddOp(OpEndIf).
+		AddOp(OpCheckSig))
+}
+
+// ParseHTLC returns the hash-timelocked contract of the given redeem script. If
+// the script is not a hash-timelocked contract, ParseHTLC returns (nil, nil).
+// Non-nil errors are returned for unparsable scripts.
+func ParseHTLC(redeemScript []byte) (*HTLC, error) {
+	pops, err := parseScript(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	if !isHTLC(pops) {
+		return nil, nil
+	}
+
+	contract := &HTLC{
+		RecipientPubKey: pops[7].data,
+		RefundPubKey:    pops[11].data,
+		LockTime:        lockTimeOrSequenceFromPush(pops[9]),
+	}
+	copy(contract.SecretHash[:], pops[5].data)
+	return contract, nil
+}
+
+// HTLCClaimSignatureScript returns the signature script with which the
+// recipient spends a hash-timelocked contract by revealing its secret
+func HTLCClaimSignatureScript(redeemScript []byte, signature []byte, secret []byte) ([]byte, error) {
+	if len(secret) != HTLCSecretSize {
+		return nil, errors.Errorf("the secret of a hash-timelocked contract must be %d bytes long, "+
+			"but got %d bytes", HTLCSecretSize, len(secret))
+	}
+	return contractSignatureScript(redeemScript, [][]byte{signature, secret}, true)
+}
+
+// HTLCRefundSignatureScript returns the signature script with which the refund
+// key spends a hash-timelocked contract after its lock time
+func HTLCRefundSignatureScript(redeemScript []byte, signature []byte) ([]byte, error) {
+	return contractSignatureScript(redeemScript, [][]byte{signature}, false)
+}
+
+// TimelockVault is a contract that the owner can spend only once RelativeLockTime
+// DAA score has passed since it was accepted, while the recovery key can spend
+// it at any time, e.g. to claw back funds taken by a compromised owner key.
+//
+// Its redeem script is of the form:
+//
+//	OP_IF
+//	    <relative lock time> OP_CHECKSEQUENCEVERIFY <owner pubkey>
+//	OP_ELSE
+//	    <recovery pubkey>
+//	OP_ENDIF
+//	OP_CHECKSIG
+type TimelockVault struct {
+	OwnerPubKey      []byte
+	RecoveryPubKey   []byte
+	RelativeLockTime uint64
+}
+
+// RedeemScript returns the redeem script of the contract
+func (c *TimelockVault) RedeemScript() ([]byte, error) {
+	err := validatePubKeys(c.OwnerPubKey, c.RecoveryPubKey)
+	if err != nil {
+		return nil, err
+	}
+	if c.RelativeLockTime == 0 || c.RelativeLockTime > constants.SequenceLockTimeMask {
+		return nil, errors.Errorf("relative lock time must be between 1 and %d, but got %d",
+			constants.SequenceLockTimeMask, c.RelativeLockTime)
+	}
+
+	return finalizeRedeemScript(NewScriptBuilder().
+		AddOp(OpIf).
+		AddSequenceNumber(c.RelativeLockTime).AddOp(OpCheckSequenceVerify).
+		AddData(c.OwnerPubKey).
+		AddOp(OpElse).
+		AddData(c.RecoveryPubKey).
+		AddOp(OpEndIf).
+		AddOp(OpCheckSig))
+}
+
+// ParseTimelockVault returns the timelock vault of the given redeem script. If
+// the script is not a timelock vault, ParseTimelockVault returns (nil, nil).
+// Non-nil errors are returned for unparsable scripts.
+func ParseTimelockVault(redeemScript []byte) (*TimelockVault, error) {
+	pops, err := parseScript(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	if !isTimelockVault(pops) {
+		return nil, nil
+	}
+
+	return &TimelockVault{
+		OwnerPubKey:      pops[3].data,
+		RecoveryPubKey:   pops[5].data,
+		RelativeLockTime: lockTimeOrSequenceFromPush(pops[1]),
+	}, nil
+}
+
+// TimelockVaultSpendSignatureScript returns the signature script with which the
+// owner spends a timelock vault. The sequence of the spending input must be at
+// least the relative lock time of the vault.
+func TimelockVaultSpendSignatureScript(redeemScript []byte, signature []byte) ([]byte, error) {
+	return contractSignatureScript(redeemScript, [][]byte{signature}, true)
+}
+
+// TimelockVaultRecoverySignatureScript returns the signature script with which
+// the recovery key spends a timelock vault
+func TimelockVaultRecoverySignatureScript(redeemScript []byte, signature []byte) ([]byte, error) {
+	return contractSignatureScript(redeemScript, [][]byte{signature}, false)
+}
+
+// MultiSigWithTimeout is a k-of-n multisig contract that the recovery key can
+// also spend once the transaction lock time reaches LockTime, so that funds
+// aren't lost if the signers become unavailable.
+//
+// Its redeem script is of the form:
+//
+//	OP_IF
+//	    <k> <pubkey 1> ... <pubkey n> <n> OP_CHECKMULTISIG
+//	OP_ELSE
+//	    <lock time> OP_CHECKLOCKTIMEVERIFY <recovery pubkey> OP_CHECKSIG
+//	OP_ENDIF
+type MultiSigWithTimeout struct {
+	MinimumSignatures int
+	PubKeys           [][]byte
+	RecoveryPubKey    []byte
+	LockTime          uint64
+}
+
+// RedeemScript returns the redeem script of the contract
+func (c *MultiSigWithTimeout) RedeemScript() ([]byte, error) {
+	if len(c.PubKeys) == 0 || len(c.PubKeys) > 16 {
+		return nil, errors.Errorf("the number of public keys must be between 1 and 16, but got %d",
+			len(c.PubKeys))
+	}
+	if c.MinimumSignatures < 1 {
+		return nil, errors.Errorf("the minimum number of signatures must be positive, but got %d",
+			c.MinimumSignatures)
+	}
+	if c.MinimumSignatures > len(c.PubKeys) {
+		str := fmt.Sprintf("unable to generate multisig script with %d required signatures when "+
+			"there are only %d public keys available", c.MinimumSignatures, len(c.PubKeys))
+		return nil, scriptError(ErrTooManyRequiredSigs, str)
+	}
+	err := validatePubKeys(append([][]byte{c.RecoveryPubKey}, c.PubKeys...)...)
+	if err != nil {
+		return nil, err
+	}
+	err = validateLockTime(c.LockTime)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := NewScriptBuilder().
+		AddOp(OpIf).
+		AddInt64(int64(c.MinimumSignatures))
+	for _, pubKey := range c.PubKeys {
+		builder.AddData(pubKey)
+	}
+	return finalizeRedeemScript(builder.
+		AddInt64(int64(len(c.PubKeys))).AddOp(OpCheckMultiSig).
+		AddOp(OpElse).
+		AddLockTimeNumber(c.LockTime).AddOp(OpCheckLockTimeVerify).
+		AddData(c.RecoveryPubKey).AddOp(OpCheckSig).
+		AddOp(OpEndIf))
+}
+
+// ParseMultiSigWithTimeout returns the multisig contract of the given redeem
+// script. If the script is not a multisig with timeout contract,
+// ParseMultiSigWithTimeout returns (nil, nil). Non-nil errors are returned for
+// unparsable scripts.
+func ParseMultiSigWithTimeout(redeemScript []byte) (*MultiSigWithTimeout, error) {
+	pops, err := parseScript(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	if !isMultiSigWithTimeout(pops) {
+		return nil, nil
+	}
+
+	numPubKeys := len(pops) - 10
+	pubKeys := make([][]byte, numPubKeys)
+	for i := range pubKeys {
+		pubKeys[i] = pops[2+i].data
+	}
+	return &MultiSigWithTimeout{
+		MinimumSignatures: asSmallInt(pops[1].opcode),
+		PubKeys:           pubKeys,
+		RecoveryPubKey:    pops[numPubKeys+7].data,
+		LockTime:          lockTimeOrSequenceFromPush(pops[numPubKeys+5]),
+	}, nil
+}
+
+// MultiSigWithTimeoutSignatureScript returns the signature script with which the
+// signers spend a multisig with timeout contract. The signatures must be
+// ordered the same as their public keys in the contract.
+func MultiSigWithTimeoutSignatureScript(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+	return contractSignatureScript(redeemScript, signatures, true)
+}
+
+// MultiSigWithTimeoutRecoverySignatureScript returns the signature script with
+// which the recovery key spends a multisig with timeout contract after its
+// lock time
+func MultiSigWithTimeoutRecoverySignatureScript(redeemScript []byte, signature []byte) ([]byte, error) {
+	return contractSignatureScript(redeemScript, [][]byte{signature}, false)
+}
+
+// isHTLC returns true if the script passed is a hash-timelocked contract
+func isHTLC(pops []parsedOpcode) bool {
+	return len(pops) == 14 &&
+		pops[0].opcode.value == OpIf &&
+		pops[1].opcode.value == OpSize &&
+		pops[2].opcode.value == OpData1 && len(pops[2].data) == 1 && pops[2].data[0] == HTLCSecretSize &&
+		pops[3].opcode.value == OpEqualVerify &&
+		pops[4].opcode.value == OpSHA256 &&
+		pops[5].opcode.value == OpData32 &&
+		pops[6].opcode.value == OpEqualVerify &&
+		pops[7].opcode.value == OpData32 &&
+		pops[8].opcode.value == OpElse &&
+		isLockTimeOrSequencePush(pops[9]) &&
+		pops[10].opcode.value == OpCheckLockTimeVerify &&
+		pops[11].opcode.value == OpData32 &&
+		pops[12].opcode.value == OpEndIf &&
+		pops[13].opcode.value == OpCheckSig
+}
+
+// isTimelockVault returns true if the script passed is a timelock vault
+func isTimelockVault(pops []parsedOpcode) bool {
+	return len(pops) == 8 &&
+		pops[0].opcode.value == OpIf &&
+		isLockTimeOrSequencePush(pops[1]) &&
+		pops[2].opcode.value == OpCheckSequenceVerify &&
+		pops[3].opcode.value == OpData32 &&
+		pops[4].opcode.value == OpElse &&
+		pops[5].opcode.value == OpData32 &&
+		pops[6].opcode.value == OpEndIf &&
+		pops[7].opcode.value == OpCheckSig
+}
+
+// isMultiSigWithTimeout returns true if the script passed is a multisig with
+// timeout contract
+func isMultiSigWithTimeout(pops []parsedOpcode) bool {
+	numPubKeys := len(pops) - 10
+	if numPubKeys < 1 || numPubKeys > 16 {
+		return false
+	}
+	if pops[0].opcode.value != OpIf ||
+		!isSmallInt(pops[1].opcode) || asSmallInt(pops[1].opcode) < 1 ||
+		asSmallInt(pops[1].opcode) > numPubKeys {
+		return false
+	}
+	for _, pop := range pops[2 : 2+numPubKeys] {
+		if pop.opcode.value != OpData32 {
+			return false
+		}
+	}
+	rest := pops[2+numPubKeys:]
+	return isSmallInt(rest[0].opcode) && asSmallInt(rest[0].opcode) == numPubKeys &&
+		rest[1].opcode.value == OpCheckMultiSig &&
+		rest[2].opcode.value == OpElse &&
+		isLockTimeOrSequencePush(rest[3]) &&
+		rest[4].opcode.value == OpCheckLockTimeVerify &&
+		rest[5].opcode.value == OpData32 &&
+		rest[6].opcode.value == OpCheckSig &&
+		rest[7].opcode.value == OpEndIf
+}
+
+// expectedContractInputs returns the number of arguments, including the branch
+// selector, required by a contract redeem script of the given class. The branch
+// is determined by the selector, which is the last push before the redeem script
+// in the signature script. If the branch can't be determined then -1 is returned.
+func expectedContractInputs(pops []parsedOpcode, class ScriptClass, selector *parsedOpcode) int {
+	if selector == nil {
+		return -1
+	}
+	var isFirstBranch bool
+	switch selector.opcode.value {
+	case OpTrue:
+		isFirstBranch = true
+	case OpFalse:
+		isFirstBranch = false
+	default:
+		return -1
+	}
+
+	switch class {
+	case HTLCTy:
+		if isFirstBranch {
+			// signature, secret and selector
+			return 3
+		}
+		return 2
+
+	case TimelockVaultTy:
+		return 2
+
+	case MultiSigWithTimeoutTy:
+		if isFirstBranch {
+			return asSmallInt(pops[1].opcode) + 1
+		}
+		return 2
+
+	default:
+		return -1
+	}
+}
+
+func contractSignatureScript(redeemScript []byte, pushes [][]byte, isFirstBranch bool) ([]byte, error) {
+	builder := NewScriptBuilder()
+	for _, data := range pushes {
+		builder.AddData(data)
+	}
+	if isFirstBranch {
+		builder.AddOp(OpTrue)
+	} else {
+		builder.AddOp(OpFalse)
+	}
+	return builder.AddData(redeemScript).Script()
+}
+
+func finalizeRedeemScript(builder *ScriptBuilder) ([]byte, error) {
+	redeemScript, err := builder.Script()
+	if err != nil {
+		return nil, err
+	}
+	if len(redeemScript) > MaxScriptElementSize {
+		str := fmt.Sprintf("redeem script size %d is larger than the max allowed size %d",
+			len(redeemScript), MaxScriptElementSize)
+		return nil, scriptError(ErrElementTooBig, str)
+	}
+	return redeemScript, nil
+}
+
+func validatePubKeys(pubKeys ...[]byte) error {
+	for _, pubKey := range pubKeys {
+		if len(pubKey) != 32 {
+			return errors.Errorf("public keys must be 32-byte Schnorr public keys, "+
+				"but got a %d-byte public key", len(pubKey))
+		}
+	}
+	return nil
+}
+
+func validateLockTime(lockTime uint64) error {
+	if lockTime == 0 {
+		return errors.New("lock time must be positive")
+	}
+	return nil
+}
+
+// isLockTimeOrSequencePush returns true if the opcode pushes a lock time or a
+// sequence, as pushed by ScriptBuilder.AddLockTimeNumber
+func isLockTimeOrSequencePush(pop parsedOpcode) bool {
+	if pop.opcode.value >= Op1 && pop.opcode.value <= Op16 || pop.opcode.value == Op1Negate {
+		return true
+	}
+	return pop.opcode.value >= OpData1 && pop.opcode.value <= OpData8 && pop.data[len(pop.data)-1] != 0
+}
+
+// lockTimeOrSequenceFromPush returns the lock time or sequence pushed by the
+// given opcode, which must satisfy isLockTimeOrSequencePush
+func lockTimeOrSequenceFromPush(pop parsedOpcode) uint64 {
+	var data []byte
+	switch {
+	case pop.opcode.value == Op1Negate:
+		data = []byte{0x81}
+	case pop.opcode.value >= Op1 && pop.opcode.value <= Op16:
+		data = []byte{byte(asSmallInt(pop.opcode))}
+	default:
+		data = pop.data
+	}
+	paddedData := make([]byte, 8)
+	copy(paddedData, data)
+	return binary.LittleEndian.Uint64(paddedData)
+}
@@ -0,0 +1,382 @@
+package txscript
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/util"
+)
+
+type templateKey struct {
+	keyPair *secp256k1.SchnorrKeyPair
+	pubKey  []byte
+}
+
+func newTemplateKey(t *testing.T) *templateKey {
+	keyPair, err := secp256k1.GenerateSchnorrKeyPair()
+	if err != nil {
+		t.Fatalf("GenerateSchnorrKeyPair: %s", err)
+	}
+	pubKey, err := keyPair.SchnorrPublicKey()
+	if err != nil {
+		t.Fatalf("SchnorrPublicKey: %s", err)
+	}
+	serializedPubKey, err := pubKey.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize: %s", err)
+	}
+	return &templateKey{keyPair: keyPair, pubKey: serializedPubKey[:]}
+}
+
+// spendContract builds a transaction spending an output paid to the given
+// contract, signs it with the given keys, executes its scripts and returns the
+// signature script along with the execution error
+func spendContract(t *testing.T, contract ContractTemplate, lockTime uint64, sequence uint64,
+	buildSignatureScript func(redeemScript []byte, signatures [][]byte) ([]byte, error),
+	keys ...*templateKey) ([]byte, error) {
+
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		t.Fatalf("RedeemScript: %s", err)
+	}
+	scriptPublicKey, err := PayToContractScript(contract)
+	if err != nil {
+		t.Fatalf("PayToContractScript: %s", err)
+	}
+
+	tx := &externalapi.DomainTransaction{
+		Version: 0,
+		Inputs: []*externalapi.DomainTransactionInput{{
+			PreviousOutpoint: externalapi.DomainOutpoint{Index: 0},
+			Sequence:         sequence,
+			UTXOEntry:        utxo.NewUTXOEntry(1000, scriptPublicKey, false, 10),
+		}},
+		Outputs: []*externalapi.DomainTransactionOutput{{
+			Value:           900,
+			ScriptPublicKey: scriptPublicKey,
+		}},
+		LockTime: lockTime,
+	}
+
+	signatures := make([][]byte, len(keys))
+	for i, key := range keys {
+		signatures[i], err = RawTxInSignature(tx, 0, consensushashing.SigHashAll, key.keyPair,
+			&consensushashing.SighashReusedValues{})
+		if err != nil {
+			t.Fatalf("RawTxInSignature: %s", err)
+		}
+	}
+	signatureScript, err := buildSignatureScript(redeemScript, signatures)
+	if err != nil {
+		t.Fatalf("failed to build the signature script: %s", err)
+	}
+	tx.Inputs[0].SignatureScript = signatureScript
+
+	vm, err := NewEngine(scriptPublicKey, tx, 0, ScriptNoFlags, nil, nil, &consensushashing.SighashReusedValues{})
+	if err != nil {
+		t.Fatalf("NewEngine: %s", err)
+	}
+	return signatureScript, vm.Execute()
+}
+
+func checkContractScriptInfo(t *testing.T, contract ContractTemplate, signatureScript []byte,
+	expectedClass ScriptClass, expectedInputs int) {
+
+	scriptPublicKey, err := PayToContractScript(contract)
+	if err != nil {
+		t.Fatalf("PayToContractScript: %s", err)
+	}
+	scriptInfo, err := CalcScriptInfo(signatureScript, scriptPublicKey.Script, true)
+	if err != nil {
+		t.Fatalf("CalcScriptInfo: %s", err)
+	}
+	if scriptInfo.ScriptPubKeyClass != ScriptHashTy {
+		t.Errorf("unexpected script public key class. Want: %s, got: %s",
+			ScriptHashTy, scriptInfo.ScriptPubKeyClass)
+	}
+	if scriptInfo.RedeemScriptClass != expectedClass {
+		t.Errorf("unexpected redeem script class. Want: %s, got: %s",
+			expectedClass, scriptInfo.RedeemScriptClass)
+	}
+	if scriptInfo.ExpectedInputs != expectedInputs {
+		t.Errorf("unexpected expected inputs. Want: %d, got: %d", expectedInputs, scriptInfo.ExpectedInputs)
+	}
+	if scriptInfo.NumInputs != expectedInputs {
+		t.Errorf("unexpected number of inputs. Want: %d, got: %d", expectedInputs, scriptInfo.NumInputs)
+	}
+}
+
+func TestHTLC(t *testing.T) {
+	recipient := newTemplateKey(t)
+	refund := newTemplateKey(t)
+	secret := make([]byte, HTLCSecretSize)
+	for i := range secret {
+		secret[i] = byte(i)
+	}
+	const lockTime = 1000
+
+	contract := &HTLC{
+		SecretHash:      sha256.Sum256(secret),
+		RecipientPubKey: recipient.pubKey,
+		RefundPubKey:    refund.pubKey,
+		LockTime:        lockTime,
+	}
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		t.Fatalf("RedeemScript: %s", err)
+	}
+	parsedContract, err := ParseHTLC(redeemScript)
+	if err != nil {
+		t.Fatalf("ParseHTLC: %s", err)
+	}
+	if !reflect.DeepEqual(parsedContract, contract) {
+		t.Fatalf("unexpected parsed contract. Want: %+v, got: %+v", contract, parsedContract)
+	}
+	if class := GetRedeemScriptClass(redeemScript); class != HTLCTy {
+		t.Fatalf("unexpected redeem script class. Want: %s, got: %s", HTLCTy, class)
+	}
+
+	claimWithSecret := func(secret []byte) func([]byte, [][]byte) ([]byte, error) {
+		return func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+			return HTLCClaimSignatureScript(redeemScript, signatures[0], secret)
+		}
+	}
+	refundSignatureScript := func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+		return HTLCRefundSignatureScript(redeemScript, signatures[0])
+	}
+
+	signatureScript, err := spendContract(t, contract, 0, constants.MaxTxInSequenceNum, claimWithSecret(secret), recipient)
+	if err != nil {
+		t.Fatalf("claiming with the secret failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, HTLCTy, 4)
+
+	wrongSecret := make([]byte, HTLCSecretSize)
+	_, err = spendContract(t, contract, 0, constants.MaxTxInSequenceNum, claimWithSecret(wrongSecret), recipient)
+	if !IsErrorCode(err, ErrEqualVerify) {
+		t.Fatalf("unexpected error claiming with a wrong secret. Want: %s, got: %v", ErrEqualVerify, err)
+	}
+
+	_, err = spendContract(t, contract, 0, constants.MaxTxInSequenceNum, claimWithSecret(secret), refund)
+	if !IsErrorCode(err, ErrNullFail) {
+		t.Fatalf("unexpected error claiming with the refund key. Want: %s, got: %v", ErrNullFail, err)
+	}
+
+	_, err = spendContract(t, contract, lockTime-1, 0, refundSignatureScript, refund)
+	if !IsErrorCode(err, ErrUnsatisfiedLockTime) {
+		t.Fatalf("unexpected error refunding before the lock time. Want: %s, got: %v", ErrUnsatisfiedLockTime, err)
+	}
+
+	signatureScript, err = spendContract(t, contract, lockTime, 0, refundSignatureScript, refund)
+	if err != nil {
+		t.Fatalf("refunding after the lock time failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, HTLCTy, 3)
+}
+
+func TestTimelockVault(t *testing.T) {
+	owner := newTemplateKey(t)
+	recovery := newTemplateKey(t)
+	const relativeLockTime = 500
+
+	contract := &TimelockVault{
+		OwnerPubKey:      owner.pubKey,
+		RecoveryPubKey:   recovery.pubKey,
+		RelativeLockTime: relativeLockTime,
+	}
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		t.Fatalf("RedeemScript: %s", err)
+	}
+	parsedContract, err := ParseTimelockVault(redeemScript)
+	if err != nil {
+		t.Fatalf("ParseTimelockVault: %s", err)
+	}
+	if !reflect.DeepEqual(parsedContract, contract) {
+		t.Fatalf("unexpected parsed contract. Want: %+v, got: %+v", contract, parsedContract)
+	}
+
+	spendSignatureScript := func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+		return TimelockVaultSpendSignatureScript(redeemScript, signatures[0])
+	}
+	recoverySignatureScript := func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+		return TimelockVaultRecoverySignatureScript(redeemScript, signatures[0])
+	}
+
+	_, err = spendContract(t, contract, 0, relativeLockTime-1, spendSignatureScript, owner)
+	if !IsErrorCode(err, ErrUnsatisfiedLockTime) {
+		t.Fatalf("unexpected error spending before the relative lock time. Want: %s, got: %v",
+			ErrUnsatisfiedLockTime, err)
+	}
+
+	signatureScript, err := spendContract(t, contract, 0, relativeLockTime, spendSignatureScript, owner)
+	if err != nil {
+		t.Fatalf("spending after the relative lock time failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, TimelockVaultTy, 3)
+
+	_, err = spendContract(t, contract, 0, 0, recoverySignatureScript, owner)
+	if !IsErrorCode(err, ErrNullFail) {
+		t.Fatalf("unexpected error recovering with the owner key. Want: %s, got: %v", ErrNullFail, err)
+	}
+
+	signatureScript, err = spendContract(t, contract, 0, 0, recoverySignatureScript, recovery)
+	if err != nil {
+		t.Fatalf("recovering failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, TimelockVaultTy, 3)
+}
+
+func TestMultiSigWithTimeout(t *testing.T) {
+	signers := []*templateKey{newTemplateKey(t), newTemplateKey(t), newTemplateKey(t)}
+	recovery := newTemplateKey(t)
+	const lockTime = 2000
+
+	contract := &MultiSigWithTimeout{
+		MinimumSignatures: 2,
+		PubKeys:           [][]byte{signers[0].pubKey, signers[1].pubKey, signers[2].pubKey},
+		RecoveryPubKey:    recovery.pubKey,
+		LockTime:          lockTime,
+	}
+	redeemScript, err := contract.RedeemScript()
+	if err != nil {
+		t.Fatalf("RedeemScript: %s", err)
+	}
+	parsedContract, err := ParseMultiSigWithTimeout(redeemScript)
+	if err != nil {
+		t.Fatalf("ParseMultiSigWithTimeout: %s", err)
+	}
+	if !reflect.DeepEqual(parsedContract, contract) {
+		t.Fatalf("unexpected parsed contract. Want: %+v, got: %+v", contract, parsedContract)
+	}
+
+	multiSigSignatureScript := func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+		return MultiSigWithTimeoutSignatureScript(redeemScript, signatures)
+	}
+	recoverySignatureScript := func(redeemScript []byte, signatures [][]byte) ([]byte, error) {
+		return MultiSigWithTimeoutRecoverySignatureScript(redeemScript, signatures[0])
+	}
+
+	signatureScript, err := spendContract(t, contract, 0, constants.MaxTxInSequenceNum, multiSigSignatureScript,
+		signers[0], signers[2])
+	if err != nil {
+		t.Fatalf("spending with 2 of 3 signatures failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, MultiSigWithTimeoutTy, 4)
+
+	_, err = spendContract(t, contract, 0, constants.MaxTxInSequenceNum, multiSigSignatureScript, signers[1])
+	if err == nil {
+		t.Fatalf("spending with 1 of 3 signatures unexpectedly succeeded")
+	}
+
+	_, err = spendContract(t, contract, lockTime-1, 0, recoverySignatureScript, recovery)
+	if !IsErrorCode(err, ErrUnsatisfiedLockTime) {
+		t.Fatalf("unexpected error recovering before the lock time. Want: %s, got: %v", ErrUnsatisfiedLockTime, err)
+	}
+
+	signatureScript, err = spendContract(t, contract, lockTime, 0, recoverySignatureScript, recovery)
+	if err != nil {
+		t.Fatalf("recovering after the lock time failed: %s", err)
+	}
+	checkContractScriptInfo(t, contract, signatureScript, MultiSigWithTimeoutTy, 3)
+}
+
+func TestContractTemplateAddress(t *testing.T) {
+	contract := &TimelockVault{
+		OwnerPubKey:      newTemplateKey(t).pubKey,
+		RecoveryPubKey:   newTemplateKey(t).pubKey,
+		RelativeLockTime: 10,
+	}
+	address, err := ContractAddress(contract, util.Bech32PrefixKaspaTest)
+	if err != nil {
+		t.Fatalf("ContractAddress: %s", err)
+	}
+	addressScript, err := PayToAddrScript(address)
+	if err != nil {
+		t.Fatalf("PayToAddrScript: %s", err)
+	}
+	contractScript, err := PayToContractScript(contract)
+	if err != nil {
+		t.Fatalf("PayToContractScript: %s", err)
+	}
+	if !addressScript.Equal(contractScript) {
+		t.Fatalf("the address script %x is different from the contract script %x",
+			addressScript.Script, contractScript.Script)
+	}
+}
+
+func TestInvalidContractTemplates(t *testing.T) {
+	pubKey := make([]byte, 32)
+	tests := []struct {
+		name     string
+		contract ContractTemplate
+	}{
+		{
+			name:     "HTLC with a short public key",
+			contract: &HTLC{RecipientPubKey: pubKey[:31], RefundPubKey: pubKey, LockTime: 1},
+		},
+		{
+			name:     "HTLC without a lock time",
+			contract: &HTLC{RecipientPubKey: pubKey, RefundPubKey: pubKey},
+		},
+		{
+			name:     "vault without a relative lock time",
+			contract: &TimelockVault{OwnerPubKey: pubKey, RecoveryPubKey: pubKey},
+		},
+		{
+			name: "vault with a disabled relative lock time",
+			contract: &TimelockVault{OwnerPubKey: pubKey, RecoveryPubKey: pubKey,
+				RelativeLockTime: constants.SequenceLockTimeDisabled | 1},
+		},
+		{
+			name: "multisig with more required signatures than keys",
+			contract: &MultiSigWithTimeout{MinimumSignatures: 3, PubKeys: [][]byte{pubKey, pubKey},
+				RecoveryPubKey: pubKey, LockTime: 1},
+		},
+		{
+			name: "multisig with a redeem script that is too big",
+			contract: &MultiSigWithTimeout{MinimumSignatures: 1, PubKeys: [][]byte{
+				pubKey, pubKey, pubKey, pubKey, pubKey, pubKey, pubKey, pubKey,
+				pubKey, pubKey, pubKey, pubKey, pubKey, pubKey, pubKey, pubKey,
+			}, RecoveryPubKey: pubKey, LockTime: 1},
+		},
+	}
+
+	for _, test := range tests {
+		_, err := test.contract.RedeemScript()
+		if err == nil {
+			t.Errorf("%s: RedeemScript unexpectedly succeeded", test.name)
+		}
+	}
+}
+
+func TestParseNonContractScripts(t *testing.T) {
+	scripts := []string{
+		"DATA_32 0x0000000000000000000000000000000000000000000000000000000000000000 CHECKSIG",
+		"IF OP_1 ELSE OP_2 ENDIF",
+	}
+	for _, script := range scripts {
+		redeemScript := mustParseShortForm(script, 0)
+		htlc, err := ParseHTLC(redeemScript)
+		if err != nil || htlc != nil {
+			t.Errorf("ParseHTLC(%s): unexpected result %+v, %v", script, htlc, err)
+		}
+		vault, err := ParseTimelockVault(redeemScript)
+		if err != nil || vault != nil {
+			t.Errorf("ParseTimelockVault(%s): unexpected result %+v, %v", script, vault, err)
+		}
+		multiSig, err := ParseMultiSigWithTimeout(redeemScript)
+		if err != nil || multiSig != nil {
+			t.Errorf("ParseMultiSigWithTimeout(%s): unexpected result %+v, %v", script, multiSig, err)
+		}
+	}
+	if class := GetRedeemScriptClass(mustParseShortForm(scripts[0], 0)); class != PubKeyTy {
+		t.Errorf("unexpected redeem script class. Want: %s, got: %s", PubKeyTy, class)
+	}
+}
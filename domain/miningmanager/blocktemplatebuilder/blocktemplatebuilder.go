@@ -8,6 +8,7 @@ import (
 	"github.com/kaspanet/kaspad/util/mstime"
 	"math"
 	"sort"
+	"sync"
 
 	"github.com/kaspanet/kaspad/util/difficulty"
 
@@ -37,6 +38,9 @@ type blockTemplateBuilder struct {
 	policy             policy
 
 	coinbasePayloadScriptPublicKeyMaxLength uint8
+
+	recentFeeStats     []*miningmanagerapi.BlockTemplateFeeStats
+	recentFeeStatsLock sync.Mutex
 }
 
 // New creates a new blockTemplateBuilder
@@ -120,7 +124,8 @@ func (btb *blockTemplateBuilder) BuildBlockTemplate(
 
 	mempoolTransactions := btb.mempool.BlockCandidateTransactions()
 	candidateTxs := make([]*candidateTx, 0, len(mempoolTransactions))
-	for _, tx := range mempoolTransactions {
+	for _, mempoolTransaction := range mempoolTransactions {
+		tx := mempoolTransaction.Transaction
 		// Calculate the tx value
 		gasLimit := uint64(0)
 		if !subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
@@ -128,7 +133,7 @@ func (btb *blockTemplateBuilder) BuildBlockTemplate(
 		}
 		candidateTxs = append(candidateTxs, &candidateTx{
 			DomainTransaction: tx,
-			txValue:           btb.calcTxValue(tx),
+			txValue:           btb.calcTxValue(mempoolTransaction),
 			gasLimit:          gasLimit,
 		})
 	}
@@ -166,6 +171,8 @@ func (btb *blockTemplateBuilder) BuildBlockTemplate(
 		return nil, err
 	}
 
+	btb.recordFeeStats(blockTxs)
+
 	log.Debugf("Created new block template (%d transactions, %d in fees, %d mass, target difficulty %064x)",
 		len(blk.Transactions), blockTxs.totalFees, blockTxs.totalMass, difficulty.CompactToBig(blk.Header.Bits()))
 
@@ -212,11 +219,15 @@ func (btb *blockTemplateBuilder) ModifyBlockTemplate(newCoinbaseData *consensuse
 // calcTxValue calculates a value to be used in transaction selection.
 // The higher the number the more likely it is that the transaction will be
 // included in the block.
-func (btb *blockTemplateBuilder) calcTxValue(tx *consensusexternalapi.DomainTransaction) float64 {
+// The value is calculated from the fee and mass of the best package the
+// transaction belongs to rather than from its own, so that a high fee child
+// transaction may pull its low fee parent into the block (child-pays-for-parent).
+func (btb *blockTemplateBuilder) calcTxValue(candidate *miningmanagerapi.BlockCandidateTransaction) float64 {
 	massLimit := btb.policy.BlockMaxMass
 
-	mass := tx.Mass
-	fee := tx.Fee
+	tx := candidate.Transaction
+	mass := candidate.PackageMass
+	fee := candidate.PackageFee
 	if subnetworks.IsBuiltInOrNative(tx.SubnetworkID) {
 		return float64(fee) / (float64(mass) / float64(massLimit))
 	}
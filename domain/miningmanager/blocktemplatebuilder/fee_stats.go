@@ -0,0 +1,45 @@
+package blocktemplatebuilder
+
+import (
+	miningmanagerapi "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+// recentFeeStatsCount is the amount of most recently built block templates
+// whose fee stats are kept for fee estimation
+const recentFeeStatsCount = 10
+
+// recordFeeStats records the fee stats of a newly built block template,
+// discarding the stats of the oldest one if there are too many
+func (btb *blockTemplateBuilder) recordFeeStats(blockTxs selectedTransactions) {
+	feeStats := &miningmanagerapi.BlockTemplateFeeStats{
+		IsFull: blockTxs.isFull,
+	}
+	for i, mass := range blockTxs.txMasses {
+		if mass == 0 {
+			continue
+		}
+		feeRate := float64(blockTxs.txFees[i]) / float64(mass)
+		if feeStats.MinimumFeeRate == 0 || feeRate < feeStats.MinimumFeeRate {
+			feeStats.MinimumFeeRate = feeRate
+		}
+	}
+
+	btb.recentFeeStatsLock.Lock()
+	defer btb.recentFeeStatsLock.Unlock()
+
+	btb.recentFeeStats = append(btb.recentFeeStats, feeStats)
+	if len(btb.recentFeeStats) > recentFeeStatsCount {
+		btb.recentFeeStats = btb.recentFeeStats[len(btb.recentFeeStats)-recentFeeStatsCount:]
+	}
+}
+
+// RecentBlockTemplatesFeeStats returns the fee stats of the most recently
+// built block templates, from oldest to newest
+func (btb *blockTemplateBuilder) RecentBlockTemplatesFeeStats() []*miningmanagerapi.BlockTemplateFeeStats {
+	btb.recentFeeStatsLock.Lock()
+	defer btb.recentFeeStatsLock.Unlock()
+
+	recentFeeStats := make([]*miningmanagerapi.BlockTemplateFeeStats, len(btb.recentFeeStats))
+	copy(recentFeeStats, btb.recentFeeStats)
+	return recentFeeStats
+}
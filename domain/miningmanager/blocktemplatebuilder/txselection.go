@@ -33,6 +33,10 @@ type selectedTransactions struct {
 	txFees      []uint64
 	totalMass   uint64
 	totalFees   uint64
+
+	// isFull is set if the selection stopped because the next
+	// transaction would have exceeded the maximum block mass
+	isFull bool
 }
 
 // selectTransactions implements a probabilistic transaction selection algorithm.
@@ -105,6 +109,7 @@ func (btb *blockTemplateBuilder) selectTransactions(candidateTxs []*candidateTx)
 			txsForBlockTemplate.totalMass+selectedTx.Mass > btb.policy.BlockMaxMass {
 			log.Tracef("Tx %s would exceed the max block mass. "+
 				"As such, stopping.", consensushashing.TransactionID(tx))
+			txsForBlockTemplate.isFull = true
 			break
 		}
 
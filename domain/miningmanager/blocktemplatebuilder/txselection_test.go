@@ -0,0 +1,50 @@
+package blocktemplatebuilder
+
+import (
+	"testing"
+
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
+)
+
+func TestSelectTransactionsIsFull(t *testing.T) {
+	const transactionCount = 5
+	const transactionMass = 10
+
+	tests := []struct {
+		name           string
+		blockMaxMass   uint64
+		expectedIsFull bool
+		expectedCount  int
+	}{
+		{name: "all transactions fit", blockMaxMass: transactionCount * transactionMass, expectedIsFull: false,
+			expectedCount: transactionCount},
+		{name: "the mass limit is reached", blockMaxMass: 2*transactionMass + transactionMass/2, expectedIsFull: true,
+			expectedCount: 2},
+	}
+
+	for _, test := range tests {
+		candidateTxs := make([]*candidateTx, transactionCount)
+		for i := range candidateTxs {
+			candidateTxs[i] = &candidateTx{
+				DomainTransaction: &consensusexternalapi.DomainTransaction{
+					SubnetworkID: subnetworks.SubnetworkIDNative,
+					LockTime:     uint64(i),
+					Mass:         transactionMass,
+					Fee:          1,
+				},
+				txValue: 1,
+			}
+		}
+
+		btb := &blockTemplateBuilder{policy: policy{BlockMaxMass: test.blockMaxMass}}
+		selected := btb.selectTransactions(candidateTxs)
+		if selected.isFull != test.expectedIsFull {
+			t.Errorf("%s: unexpected isFull. Want: %t, got: %t", test.name, test.expectedIsFull, selected.isFull)
+		}
+		if len(selected.selectedTxs) != test.expectedCount {
+			t.Errorf("%s: unexpected amount of selected transactions. Want: %d, got: %d",
+				test.name, test.expectedCount, len(selected.selectedTxs))
+		}
+	}
+}
@@ -38,6 +38,10 @@ func (f *factory) NewMiningManager(consensusReference consensusreference.Consens
 		blockTemplateBuilder: blockTemplateBuilder,
 		cachingTime:          time.Now(),
 		cacheLock:            &sync.Mutex{},
+
+		blockMaxMass: maxBlockMass,
+		// MinimumRelayTransactionFee is specified in sompi per 1000 grams of mass
+		minimumFeeRate: float64(mempoolConfig.MinimumRelayTransactionFee) / 1000,
 	}
 }
 
@@ -0,0 +1,148 @@
+package miningmanager
+
+import (
+	"sort"
+
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+const (
+	// priorityTargetBlocks, normalTargetBlocks and lowTargetBlocks are the
+	// amounts of blocks within which a transaction paying the fee rate of
+	// the respective bucket is expected to be included
+	priorityTargetBlocks = 1
+	normalTargetBlocks   = 10
+	lowTargetBlocks      = 60
+)
+
+// FeeRateBucket is a fee rate, in sompi per gram of transaction mass, together
+// with the amount of blocks a transaction paying it is expected to wait until
+// it's included in a block
+type FeeRateBucket struct {
+	FeeRate         float64
+	EstimatedBlocks uint64
+}
+
+// FeeEstimate contains the fee rates suggested for transactions of
+// different urgencies
+type FeeEstimate struct {
+	PriorityBucket *FeeRateBucket
+	NormalBucket   *FeeRateBucket
+	LowBucket      *FeeRateBucket
+}
+
+type transactionFeeRate struct {
+	feeRate float64
+	mass    uint64
+}
+
+// GetFeeEstimate estimates the fee rates required for a new transaction to be
+// included in a block, based on the fee rates of the transactions currently in
+// the mempool and of the ones included in recently built block templates
+func (mm *miningManager) GetFeeEstimate() *FeeEstimate {
+	mempoolTransactions := mm.mempool.AllTransactions()
+	feeRates := make([]*transactionFeeRate, 0, len(mempoolTransactions))
+	for _, transaction := range mempoolTransactions {
+		if transaction.Mass == 0 {
+			continue
+		}
+		feeRates = append(feeRates, &transactionFeeRate{
+			feeRate: float64(transaction.Fee) / float64(transaction.Mass),
+			mass:    transaction.Mass,
+		})
+	}
+
+	return estimateFees(feeRates, mm.blockTemplateBuilder.RecentBlockTemplatesFeeStats(),
+		mm.blockMaxMass, mm.minimumFeeRate)
+}
+
+func estimateFees(feeRates []*transactionFeeRate, recentBlockTemplatesFeeStats []*miningmanagermodel.BlockTemplateFeeStats,
+	blockMaxMass uint64, minimumFeeRate float64) *FeeEstimate {
+
+	sort.Slice(feeRates, func(i, j int) bool {
+		return feeRates[i].feeRate > feeRates[j].feeRate
+	})
+
+	priorityFeeRate := feeRateForTargetBlocks(feeRates, priorityTargetBlocks, blockMaxMass, minimumFeeRate)
+
+	// Full block templates show which fee rates were recently needed to get
+	// into a block, even if the mempool has since been drained
+	recentFeeRate := recentFullBlockTemplatesFeeRate(recentBlockTemplatesFeeStats)
+	if recentFeeRate > priorityFeeRate {
+		priorityFeeRate = recentFeeRate
+	}
+
+	normalFeeRate := feeRateForTargetBlocks(feeRates, normalTargetBlocks, blockMaxMass, minimumFeeRate)
+	if normalFeeRate > priorityFeeRate {
+		normalFeeRate = priorityFeeRate
+	}
+
+	lowFeeRate := feeRateForTargetBlocks(feeRates, lowTargetBlocks, blockMaxMass, minimumFeeRate)
+	if lowFeeRate > normalFeeRate {
+		lowFeeRate = normalFeeRate
+	}
+
+	return &FeeEstimate{
+		PriorityBucket: newFeeRateBucket(feeRates, priorityFeeRate, blockMaxMass),
+		NormalBucket:   newFeeRateBucket(feeRates, normalFeeRate, blockMaxMass),
+		LowBucket:      newFeeRateBucket(feeRates, lowFeeRate, blockMaxMass),
+	}
+}
+
+// feeRateForTargetBlocks returns the fee rate of the first transaction that
+// doesn't fit into the given amount of blocks, when the transactions are
+// ordered by fee rate. If they all fit, the minimum fee rate is returned.
+//
+// Note: feeRates is expected to be sorted by fee rate in descending order
+func feeRateForTargetBlocks(feeRates []*transactionFeeRate, targetBlocks uint64, blockMaxMass uint64,
+	minimumFeeRate float64) float64 {
+
+	capacity := targetBlocks * blockMaxMass
+	massAhead := uint64(0)
+	for _, feeRate := range feeRates {
+		massAhead += feeRate.mass
+		if massAhead > capacity {
+			if feeRate.feeRate < minimumFeeRate {
+				return minimumFeeRate
+			}
+			return feeRate.feeRate
+		}
+	}
+	return minimumFeeRate
+}
+
+// recentFullBlockTemplatesFeeRate returns the average of the minimum fee rates
+// included in the recent block templates that were full, or 0 if none were
+func recentFullBlockTemplatesFeeRate(recentBlockTemplatesFeeStats []*miningmanagermodel.BlockTemplateFeeStats) float64 {
+	sum := 0.0
+	count := 0
+	for _, feeStats := range recentBlockTemplatesFeeStats {
+		if !feeStats.IsFull {
+			continue
+		}
+		sum += feeStats.MinimumFeeRate
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// newFeeRateBucket creates a bucket for the given fee rate, estimating the amount
+// of blocks it would take to include all the transactions paying more than it
+//
+// Note: feeRates is expected to be sorted by fee rate in descending order
+func newFeeRateBucket(feeRates []*transactionFeeRate, feeRate float64, blockMaxMass uint64) *FeeRateBucket {
+	massAhead := uint64(0)
+	for _, transactionFeeRate := range feeRates {
+		if transactionFeeRate.feeRate <= feeRate {
+			break
+		}
+		massAhead += transactionFeeRate.mass
+	}
+	return &FeeRateBucket{
+		FeeRate:         feeRate,
+		EstimatedBlocks: massAhead/blockMaxMass + 1,
+	}
+}
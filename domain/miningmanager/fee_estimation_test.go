@@ -0,0 +1,80 @@
+package miningmanager
+
+import (
+	"testing"
+
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+func TestEstimateFees(t *testing.T) {
+	const blockMaxMass = 1000
+	const minimumFeeRate = 1.0
+
+	tests := []struct {
+		name                         string
+		feeRates                     []*transactionFeeRate
+		recentBlockTemplatesFeeStats []*miningmanagermodel.BlockTemplateFeeStats
+		expectedPriorityBucket       FeeRateBucket
+		expectedNormalBucket         FeeRateBucket
+		expectedLowBucket            FeeRateBucket
+	}{
+		{
+			name:                   "empty mempool",
+			expectedPriorityBucket: FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+			expectedNormalBucket:   FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+			expectedLowBucket:      FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+		},
+		{
+			name: "mempool fits into a single block",
+			feeRates: []*transactionFeeRate{
+				{feeRate: 5, mass: 400},
+				{feeRate: 2, mass: 400},
+			},
+			expectedPriorityBucket: FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+			expectedNormalBucket:   FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+			expectedLowBucket:      FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+		},
+		{
+			name: "congested mempool",
+			feeRates: []*transactionFeeRate{
+				{feeRate: 3, mass: 600},
+				{feeRate: 10, mass: 600},
+				{feeRate: 2, mass: 9000},
+				{feeRate: 1.5, mass: 60000},
+			},
+			expectedPriorityBucket: FeeRateBucket{FeeRate: 3, EstimatedBlocks: 1},
+			expectedNormalBucket:   FeeRateBucket{FeeRate: 2, EstimatedBlocks: 2},
+			expectedLowBucket:      FeeRateBucket{FeeRate: 1.5, EstimatedBlocks: 11},
+		},
+		{
+			name: "recent full block templates raise the priority fee rate",
+			feeRates: []*transactionFeeRate{
+				{feeRate: 2, mass: 400},
+			},
+			recentBlockTemplatesFeeStats: []*miningmanagermodel.BlockTemplateFeeStats{
+				{MinimumFeeRate: 4, IsFull: true},
+				{MinimumFeeRate: 6, IsFull: true},
+				{MinimumFeeRate: 100, IsFull: false},
+			},
+			expectedPriorityBucket: FeeRateBucket{FeeRate: 5, EstimatedBlocks: 1},
+			expectedNormalBucket:   FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+			expectedLowBucket:      FeeRateBucket{FeeRate: minimumFeeRate, EstimatedBlocks: 1},
+		},
+	}
+
+	for _, test := range tests {
+		feeEstimate := estimateFees(test.feeRates, test.recentBlockTemplatesFeeStats, blockMaxMass, minimumFeeRate)
+		if *feeEstimate.PriorityBucket != test.expectedPriorityBucket {
+			t.Errorf("%s: unexpected priority bucket. Want: %+v, got: %+v",
+				test.name, test.expectedPriorityBucket, *feeEstimate.PriorityBucket)
+		}
+		if *feeEstimate.NormalBucket != test.expectedNormalBucket {
+			t.Errorf("%s: unexpected normal bucket. Want: %+v, got: %+v",
+				test.name, test.expectedNormalBucket, *feeEstimate.NormalBucket)
+		}
+		if *feeEstimate.LowBucket != test.expectedLowBucket {
+			t.Errorf("%s: unexpected low bucket. Want: %+v, got: %+v",
+				test.name, test.expectedLowBucket, *feeEstimate.LowBucket)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package mempool
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+// The changes to the mempool are recorded while mp.mtx is held, and are passed to the
+// onMempoolChangedHandler once the operation that made them releases it. This way the
+// handler can't block the mempool, and may read from it. The handler must not modify
+// the mempool, since the next modification waits for the handler to return.
+//
+// mp.dispatchMtx is taken before mp.mtx is released, and is held until the handler
+// returns. Operations that modify the mempool take mp.mtx one after the other, so they
+// take mp.dispatchMtx in the same order, and their changes reach the handler in the
+// order they were made.
+
+func (mp *mempool) recordAdded(transaction *externalapi.DomainTransaction, isOrphan bool) {
+	mp.changes = append(mp.changes, &miningmanagermodel.MempoolChange{
+		Type:        miningmanagermodel.MempoolChangeAdded,
+		Transaction: transaction,
+		IsOrphan:    isOrphan,
+	})
+}
+
+func (mp *mempool) recordRemoved(transaction *externalapi.DomainTransaction, isOrphan bool,
+	reason miningmanagermodel.MempoolRemovalReason) {
+
+	mp.changes = append(mp.changes, &miningmanagermodel.MempoolChange{
+		Type:          miningmanagermodel.MempoolChangeRemoved,
+		Transaction:   transaction,
+		IsOrphan:      isOrphan,
+		RemovalReason: reason,
+	})
+}
+
+func (mp *mempool) recordUnorphaned(transaction *externalapi.DomainTransaction) {
+	mp.changes = append(mp.changes, &miningmanagermodel.MempoolChange{
+		Type:        miningmanagermodel.MempoolChangeUnorphaned,
+		Transaction: transaction,
+	})
+}
+
+// unlockAndDispatchChanges releases mp.mtx, and passes the changes recorded
+// while it was held to the onMempoolChangedHandler
+func (mp *mempool) unlockAndDispatchChanges() {
+	changes := mp.changes
+	mp.changes = nil
+	onMempoolChangedHandler := mp.onMempoolChangedHandler
+	mp.dispatchMtx.Lock()
+	defer mp.dispatchMtx.Unlock()
+	mp.mtx.Unlock()
+
+	if len(changes) > 0 && onMempoolChangedHandler != nil {
+		onMempoolChangedHandler(changes)
+	}
+}
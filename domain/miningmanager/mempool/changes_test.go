@@ -0,0 +1,54 @@
+package mempool
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+func TestUnlockAndDispatchChangesOrder(t *testing.T) {
+	const goroutineCount = 8
+	const operationsPerGoroutine = 1000
+
+	mp := &mempool{}
+
+	// Every operation records a change whose LockTime is the sequence number of the
+	// operation, so the handler can tell whether it got the changes in order
+	nextSequenceNumber := uint64(0)
+	lastSequenceNumber := uint64(0)
+	isDispatchedOutOfOrder := false
+	mp.SetOnMempoolChangedHandler(func(changes []*miningmanagermodel.MempoolChange) {
+		runtime.Gosched()
+		sequenceNumber := changes[0].Transaction.LockTime
+		if sequenceNumber != lastSequenceNumber+1 {
+			isDispatchedOutOfOrder = true
+		}
+		lastSequenceNumber = sequenceNumber
+	})
+
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < operationsPerGoroutine; j++ {
+				mp.mtx.Lock()
+				nextSequenceNumber++
+				mp.recordAdded(&externalapi.DomainTransaction{LockTime: nextSequenceNumber}, false)
+				mp.unlockAndDispatchChanges()
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if isDispatchedOutOfOrder {
+		t.Fatalf("TestUnlockAndDispatchChangesOrder: changes were dispatched out of order")
+	}
+	if lastSequenceNumber != goroutineCount*operationsPerGoroutine {
+		t.Fatalf("TestUnlockAndDispatchChangesOrder: expected %d dispatched operations, got %d",
+			goroutineCount*operationsPerGoroutine, lastSequenceNumber)
+	}
+}
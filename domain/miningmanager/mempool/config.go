@@ -23,6 +23,11 @@ const (
 	// removeOrphans when removeRedeemers = true
 	defaultMaximumOrphanTransactionCount = 50
 
+	// defaultMaximumReplacedTransactionCount is the maximum amount of transactions a single
+	// replacement may evict from the mempool, counting both the transactions it double spends
+	// and all of their descendants. It matches the limit of BIP125.
+	defaultMaximumReplacedTransactionCount = 100
+
 	// defaultMinimumRelayTransactionFee specifies the minimum transaction fee for a transaction to be accepted to
 	// the mempool and relayed. It is specified in sompi per 1kg (or 1000 grams) of transaction mass.
 	defaultMinimumRelayTransactionFee = util.Amount(1000)
@@ -45,6 +50,7 @@ type Config struct {
 	OrphanExpireScanIntervalDAAScore      uint64
 	MaximumOrphanTransactionMass          uint64
 	MaximumOrphanTransactionCount         uint64
+	MaximumReplacedTransactionCount       uint64
 	AcceptNonStandard                     bool
 	MaximumMassPerBlock                   uint64
 	MinimumRelayTransactionFee            util.Amount
@@ -65,6 +71,7 @@ func DefaultConfig(dagParams *dagconfig.Params) *Config {
 		OrphanExpireScanIntervalDAAScore:      uint64(float64(defaultOrphanExpireScanIntervalSeconds) / targetBlocksPerSecond),
 		MaximumOrphanTransactionMass:          defaultMaximumOrphanTransactionMass,
 		MaximumOrphanTransactionCount:         defaultMaximumOrphanTransactionCount,
+		MaximumReplacedTransactionCount:       defaultMaximumReplacedTransactionCount,
 		AcceptNonStandard:                     dagParams.RelayNonStdTxs,
 		MaximumMassPerBlock:                   dagParams.MaxBlockMass,
 		MinimumRelayTransactionFee:            defaultMinimumRelayTransactionFee,
@@ -86,6 +86,8 @@ func (code RejectCode) String() string {
 type TxRuleError struct {
 	RejectCode  RejectCode // The code to send with reject messages
 	Description string     // Human readable description of the issue
+
+	isDoubleSpend bool
 }
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -93,6 +95,14 @@ func (e TxRuleError) Error() string {
 	return e.Description
 }
 
+// IsDoubleSpendError returns whether the given error is the rejection of a transaction
+// that spends an output that's already spent by a transaction in the mempool. Such a
+// transaction may still replace the mempool transaction with ValidateAndReplaceTransaction.
+func IsDoubleSpendError(err error) bool {
+	var txRuleErr TxRuleError
+	return errors.As(err, &txRuleErr) && txRuleErr.RejectCode == RejectDuplicate && txRuleErr.isDoubleSpend
+}
+
 // transactionRuleError creates an underlying TxRuleError with the given a set of
 // arguments and returns a RuleError that encapsulates it.
 func transactionRuleError(c RejectCode, desc string) RuleError {
@@ -17,6 +17,10 @@ type mempool struct {
 	mempoolUTXOSet   *mempoolUTXOSet
 	transactionsPool *transactionsPool
 	orphansPool      *orphansPool
+
+	changes                 []*miningmanagermodel.MempoolChange
+	onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler
+	dispatchMtx             sync.Mutex
 }
 
 // New constructs a new mempool
@@ -37,11 +41,20 @@ func (mp *mempool) ValidateAndInsertTransaction(transaction *externalapi.DomainT
 	acceptedTransactions []*externalapi.DomainTransaction, err error) {
 
 	mp.mtx.Lock()
-	defer mp.mtx.Unlock()
+	defer mp.unlockAndDispatchChanges()
 
 	return mp.validateAndInsertTransaction(transaction, isHighPriority, allowOrphan)
 }
 
+func (mp *mempool) ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+	acceptedTransactions []*externalapi.DomainTransaction, replacedTransactions []*externalapi.DomainTransaction, err error) {
+
+	mp.mtx.Lock()
+	defer mp.unlockAndDispatchChanges()
+
+	return mp.validateAndReplaceTransaction(transaction, isHighPriority)
+}
+
 func (mp *mempool) GetTransaction(transactionID *externalapi.DomainTransactionID) (*externalapi.DomainTransaction, bool) {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
@@ -56,6 +69,22 @@ func (mp *mempool) AllTransactions() []*externalapi.DomainTransaction {
 	return mp.transactionsPool.getAllTransactions()
 }
 
+func (mp *mempool) Snapshot() []*miningmanagermodel.SnapshotTransaction {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.snapshot()
+}
+
+func (mp *mempool) UnconfirmedUTXOs(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	confirmedUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry) *miningmanagermodel.UnconfirmedUTXOs {
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.mempoolUTXOSet.unconfirmedUTXOs(scriptPublicKeys, confirmedUTXOs)
+}
+
 func (mp *mempool) TransactionCount() int {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
@@ -63,16 +92,31 @@ func (mp *mempool) TransactionCount() int {
 	return mp.transactionsPool.transactionCount()
 }
 
+func (mp *mempool) Stats() *miningmanagermodel.MempoolStats {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	totalMass := uint64(0)
+	for _, transaction := range mp.transactionsPool.allTransactions {
+		totalMass += transaction.Transaction().Mass
+	}
+	return &miningmanagermodel.MempoolStats{
+		TransactionCount: mp.transactionsPool.transactionCount(),
+		TotalMass:        totalMass,
+		OrphanCount:      len(mp.orphansPool.allOrphans),
+	}
+}
+
 func (mp *mempool) HandleNewBlockTransactions(transactions []*externalapi.DomainTransaction) (
 	acceptedOrphans []*externalapi.DomainTransaction, err error) {
 
 	mp.mtx.Lock()
-	defer mp.mtx.Unlock()
+	defer mp.unlockAndDispatchChanges()
 
 	return mp.handleNewBlockTransactions(transactions)
 }
 
-func (mp *mempool) BlockCandidateTransactions() []*externalapi.DomainTransaction {
+func (mp *mempool) BlockCandidateTransactions() []*miningmanagermodel.BlockCandidateTransaction {
 	mp.mtx.RLock()
 	defer mp.mtx.RUnlock()
 
@@ -81,21 +125,28 @@ func (mp *mempool) BlockCandidateTransactions() []*externalapi.DomainTransaction
 
 func (mp *mempool) RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error) {
 	mp.mtx.Lock()
-	defer mp.mtx.Unlock()
+	defer mp.unlockAndDispatchChanges()
 
 	return mp.revalidateHighPriorityTransactions()
 }
 
 func (mp *mempool) RemoveTransactions(transactions []*externalapi.DomainTransaction, removeRedeemers bool) error {
 	mp.mtx.Lock()
-	defer mp.mtx.Unlock()
+	defer mp.unlockAndDispatchChanges()
 
-	return mp.removeTransactions(transactions, removeRedeemers)
+	return mp.removeTransactions(transactions, removeRedeemers, miningmanagermodel.MempoolRemovalReasonInvalid)
 }
 
 func (mp *mempool) RemoveTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool) error {
+	mp.mtx.Lock()
+	defer mp.unlockAndDispatchChanges()
+
+	return mp.removeTransaction(transactionID, removeRedeemers, miningmanagermodel.MempoolRemovalReasonInvalid)
+}
+
+func (mp *mempool) SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler) {
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
-	return mp.removeTransaction(transactionID, removeRedeemers)
+	mp.onMempoolChangedHandler = onMempoolChangedHandler
 }
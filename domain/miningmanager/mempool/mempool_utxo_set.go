@@ -10,19 +10,32 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 )
 
 type mempoolUTXOSet struct {
-	mempool                       *mempool
-	poolUnspentOutputs            model.OutpointToUTXOEntryMap
-	transactionByPreviousOutpoint model.OutpointToTransactionMap
+	mempool                               *mempool
+	poolUnspentOutputs                    model.OutpointToUTXOEntryMap
+	poolUnspentOutpointsByScriptPublicKey map[scriptPublicKeyKey]map[externalapi.DomainOutpoint]struct{}
+	transactionByPreviousOutpoint         model.OutpointToTransactionMap
+}
+
+// scriptPublicKeyKey is a ScriptPublicKey in a form that can be used as a map key
+type scriptPublicKeyKey struct {
+	version uint16
+	script  string
+}
+
+func newScriptPublicKeyKey(scriptPublicKey *externalapi.ScriptPublicKey) scriptPublicKeyKey {
+	return scriptPublicKeyKey{version: scriptPublicKey.Version, script: string(scriptPublicKey.Script)}
 }
 
 func newMempoolUTXOSet(mp *mempool) *mempoolUTXOSet {
 	return &mempoolUTXOSet{
-		mempool:                       mp,
-		poolUnspentOutputs:            model.OutpointToUTXOEntryMap{},
-		transactionByPreviousOutpoint: model.OutpointToTransactionMap{},
+		mempool:                               mp,
+		poolUnspentOutputs:                    model.OutpointToUTXOEntryMap{},
+		poolUnspentOutpointsByScriptPublicKey: map[scriptPublicKeyKey]map[externalapi.DomainOutpoint]struct{}{},
+		transactionByPreviousOutpoint:         model.OutpointToTransactionMap{},
 	}
 }
 
@@ -35,7 +48,7 @@ func (mpus *mempoolUTXOSet) addTransaction(transaction *model.MempoolTransaction
 		// Delete the output this input spends, in case it was created by mempool.
 		// If the outpoint doesn't exist in mpus.poolUnspentOutputs - this means
 		// it was created in the DAG (a.k.a. in consensus).
-		delete(mpus.poolUnspentOutputs, *outpoint)
+		mpus.removePoolUnspentOutput(*outpoint)
 
 		mpus.transactionByPreviousOutpoint[input.PreviousOutpoint] = transaction
 	}
@@ -43,8 +56,8 @@ func (mpus *mempoolUTXOSet) addTransaction(transaction *model.MempoolTransaction
 	for i, output := range transaction.Transaction().Outputs {
 		outpoint := externalapi.DomainOutpoint{TransactionID: *transaction.TransactionID(), Index: uint32(i)}
 
-		mpus.poolUnspentOutputs[outpoint] =
-			utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, false, constants.UnacceptedDAAScore)
+		mpus.addPoolUnspentOutput(outpoint,
+			utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, false, constants.UnacceptedDAAScore))
 	}
 }
 
@@ -52,7 +65,7 @@ func (mpus *mempoolUTXOSet) removeTransaction(transaction *model.MempoolTransact
 	for _, input := range transaction.Transaction().Inputs {
 		// If the transaction creating the output spent by this input is in the mempool - restore it's UTXO
 		if _, ok := mpus.mempool.transactionsPool.getTransaction(&input.PreviousOutpoint.TransactionID); ok {
-			mpus.poolUnspentOutputs[input.PreviousOutpoint] = input.UTXOEntry
+			mpus.addPoolUnspentOutput(input.PreviousOutpoint, input.UTXOEntry)
 		}
 		delete(mpus.transactionByPreviousOutpoint, input.PreviousOutpoint)
 	}
@@ -61,7 +74,36 @@ func (mpus *mempoolUTXOSet) removeTransaction(transaction *model.MempoolTransact
 	for i := range transaction.Transaction().Outputs {
 		outpoint.Index = uint32(i)
 
-		delete(mpus.poolUnspentOutputs, outpoint)
+		mpus.removePoolUnspentOutput(outpoint)
+	}
+}
+
+func (mpus *mempoolUTXOSet) addPoolUnspentOutput(outpoint externalapi.DomainOutpoint, utxoEntry externalapi.UTXOEntry) {
+	mpus.removePoolUnspentOutput(outpoint)
+
+	mpus.poolUnspentOutputs[outpoint] = utxoEntry
+
+	key := newScriptPublicKeyKey(utxoEntry.ScriptPublicKey())
+	outpoints, ok := mpus.poolUnspentOutpointsByScriptPublicKey[key]
+	if !ok {
+		outpoints = make(map[externalapi.DomainOutpoint]struct{})
+		mpus.poolUnspentOutpointsByScriptPublicKey[key] = outpoints
+	}
+	outpoints[outpoint] = struct{}{}
+}
+
+func (mpus *mempoolUTXOSet) removePoolUnspentOutput(outpoint externalapi.DomainOutpoint) {
+	utxoEntry, ok := mpus.poolUnspentOutputs[outpoint]
+	if !ok {
+		return
+	}
+	delete(mpus.poolUnspentOutputs, outpoint)
+
+	key := newScriptPublicKeyKey(utxoEntry.ScriptPublicKey())
+	outpoints := mpus.poolUnspentOutpointsByScriptPublicKey[key]
+	delete(outpoints, outpoint)
+	if len(outpoints) == 0 {
+		delete(mpus.poolUnspentOutpointsByScriptPublicKey, key)
 	}
 }
 
@@ -74,9 +116,50 @@ func (mpus *mempoolUTXOSet) checkDoubleSpends(transaction *externalapi.DomainTra
 		if existingTransaction, exists := mpus.transactionByPreviousOutpoint[input.PreviousOutpoint]; exists {
 			str := fmt.Sprintf("output %s already spent by transaction %s in the memory pool",
 				input.PreviousOutpoint, existingTransaction.TransactionID())
-			return transactionRuleError(RejectDuplicate, str)
+			return newRuleError(TxRuleError{RejectCode: RejectDuplicate, Description: str, isDoubleSpend: true})
 		}
 	}
 
 	return nil
 }
+
+// getConflictingTransactions returns the mempool transactions that spend
+// any of the outpoints spent by the given transaction
+func (mpus *mempoolUTXOSet) getConflictingTransactions(transaction *externalapi.DomainTransaction) []*model.MempoolTransaction {
+	conflictingTransactions := []*model.MempoolTransaction{}
+	seen := make(map[externalapi.DomainTransactionID]struct{})
+	for _, input := range transaction.Inputs {
+		existingTransaction, exists := mpus.transactionByPreviousOutpoint[input.PreviousOutpoint]
+		if !exists {
+			continue
+		}
+		if _, ok := seen[*existingTransaction.TransactionID()]; ok {
+			continue
+		}
+		seen[*existingTransaction.TransactionID()] = struct{}{}
+		conflictingTransactions = append(conflictingTransactions, existingTransaction)
+	}
+	return conflictingTransactions
+}
+
+// unconfirmedUTXOs returns the outputs of mempool transactions that pay to any of the given
+// script public keys, and the given confirmed UTXOs that mempool transactions spend
+func (mpus *mempoolUTXOSet) unconfirmedUTXOs(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	confirmedUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry) *miningmanagermodel.UnconfirmedUTXOs {
+
+	unconfirmedUTXOs := &miningmanagermodel.UnconfirmedUTXOs{
+		Added: make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry),
+		Spent: make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry),
+	}
+	for _, scriptPublicKey := range scriptPublicKeys {
+		for outpoint := range mpus.poolUnspentOutpointsByScriptPublicKey[newScriptPublicKeyKey(scriptPublicKey)] {
+			unconfirmedUTXOs.Added[outpoint] = mpus.poolUnspentOutputs[outpoint]
+		}
+	}
+	for outpoint, utxoEntry := range confirmedUTXOs {
+		if _, ok := mpus.transactionByPreviousOutpoint[outpoint]; ok {
+			unconfirmedUTXOs.Spent[outpoint] = utxoEntry
+		}
+	}
+	return unconfirmedUTXOs
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/pkg/errors"
 )
 
@@ -80,7 +81,7 @@ func (op *orphansPool) limitOrphanPoolSize() error {
 
 		// Don't remove redeemers in the case of a random eviction since the evicted transaction is
 		// not invalid, therefore it's redeemers are as good as any orphan that just arrived.
-		err := op.removeOrphan(orphanToRemove.TransactionID(), false)
+		err := op.removeOrphan(orphanToRemove.TransactionID(), false, miningmanagermodel.MempoolRemovalReasonLowFee)
 		if err != nil {
 			return err
 		}
@@ -130,6 +131,7 @@ func (op *orphansPool) addOrphan(transaction *externalapi.DomainTransaction, isH
 	for _, input := range transaction.Inputs {
 		op.orphansByPreviousOutpoint[input.PreviousOutpoint] = orphanTransaction
 	}
+	op.mempool.recordAdded(transaction, true)
 
 	return nil
 }
@@ -165,6 +167,7 @@ func (op *orphansPool) processOrphansAfterAcceptedTransaction(acceptedTransactio
 					if errors.As(err, &RuleError{}) {
 						log.Infof("Failed to unorphan transaction %s due to rule error: %s",
 							currentTransactionID, err)
+						op.mempool.recordRemoved(orphan.Transaction(), true, miningmanagermodel.MempoolRemovalReasonInvalid)
 						continue
 					}
 					return nil, err
@@ -188,7 +191,7 @@ func countUnfilledInputs(orphan *model.OrphanTransaction) int {
 }
 
 func (op *orphansPool) unorphanTransaction(transaction *model.OrphanTransaction) error {
-	err := op.removeOrphan(transaction.TransactionID(), false)
+	err := op.deleteOrphan(transaction)
 	if err != nil {
 		return err
 	}
@@ -223,28 +226,27 @@ func (op *orphansPool) unorphanTransaction(transaction *model.OrphanTransaction)
 	if err != nil {
 		return err
 	}
+	op.mempool.recordUnorphaned(transaction.Transaction())
 
 	return nil
 }
 
-func (op *orphansPool) removeOrphan(orphanTransactionID *externalapi.DomainTransactionID, removeRedeemers bool) error {
+func (op *orphansPool) removeOrphan(orphanTransactionID *externalapi.DomainTransactionID, removeRedeemers bool,
+	reason miningmanagermodel.MempoolRemovalReason) error {
+
 	orphanTransaction, ok := op.allOrphans[*orphanTransactionID]
 	if !ok {
 		return nil
 	}
 
-	delete(op.allOrphans, *orphanTransactionID)
-
-	for i, input := range orphanTransaction.Transaction().Inputs {
-		if _, ok := op.orphansByPreviousOutpoint[input.PreviousOutpoint]; !ok {
-			return errors.Errorf("Input No. %d of %s (%s) doesn't exist in orphansByPreviousOutpoint",
-				i, orphanTransactionID, input.PreviousOutpoint)
-		}
-		delete(op.orphansByPreviousOutpoint, input.PreviousOutpoint)
+	err := op.deleteOrphan(orphanTransaction)
+	if err != nil {
+		return err
 	}
+	op.mempool.recordRemoved(orphanTransaction.Transaction(), true, reason)
 
 	if removeRedeemers {
-		err := op.removeRedeemersOf(orphanTransaction)
+		err := op.removeRedeemersOf(orphanTransaction, reason)
 		if err != nil {
 			return err
 		}
@@ -253,13 +255,28 @@ func (op *orphansPool) removeOrphan(orphanTransactionID *externalapi.DomainTrans
 	return nil
 }
 
-func (op *orphansPool) removeRedeemersOf(transaction model.Transaction) error {
+// deleteOrphan removes the given orphan from the orphan pool's sets
+func (op *orphansPool) deleteOrphan(orphanTransaction *model.OrphanTransaction) error {
+	delete(op.allOrphans, *orphanTransaction.TransactionID())
+
+	for i, input := range orphanTransaction.Transaction().Inputs {
+		if _, ok := op.orphansByPreviousOutpoint[input.PreviousOutpoint]; !ok {
+			return errors.Errorf("Input No. %d of %s (%s) doesn't exist in orphansByPreviousOutpoint",
+				i, orphanTransaction.TransactionID(), input.PreviousOutpoint)
+		}
+		delete(op.orphansByPreviousOutpoint, input.PreviousOutpoint)
+	}
+
+	return nil
+}
+
+func (op *orphansPool) removeRedeemersOf(transaction model.Transaction, reason miningmanagermodel.MempoolRemovalReason) error {
 	outpoint := externalapi.DomainOutpoint{TransactionID: *transaction.TransactionID()}
 	for i := range transaction.Transaction().Outputs {
 		outpoint.Index = uint32(i)
 		if orphan, ok := op.orphansByPreviousOutpoint[outpoint]; ok {
 			// Recursive call is bound by size of orphan pool (which is very small)
-			err := op.removeOrphan(orphan.TransactionID(), true)
+			err := op.removeOrphan(orphan.TransactionID(), true, reason)
 			if err != nil {
 				return err
 			}
@@ -286,7 +303,7 @@ func (op *orphansPool) expireOrphanTransactions() error {
 
 		// Remove all transactions whose addedAtDAAScore is older then TransactionExpireIntervalDAAScore
 		if virtualDAAScore-orphanTransaction.AddedAtDAAScore() > op.mempool.config.OrphanExpireIntervalDAAScore {
-			err = op.removeOrphan(orphanTransaction.TransactionID(), false)
+			err = op.removeOrphan(orphanTransaction.TransactionID(), false, miningmanagermodel.MempoolRemovalReasonExpired)
 			if err != nil {
 				return err
 			}
@@ -298,10 +315,10 @@ func (op *orphansPool) expireOrphanTransactions() error {
 }
 
 func (op *orphansPool) updateOrphansAfterTransactionRemoved(
-	removedTransaction *model.MempoolTransaction, removeRedeemers bool) error {
+	removedTransaction *model.MempoolTransaction, removeRedeemers bool, reason miningmanagermodel.MempoolRemovalReason) error {
 
 	if removeRedeemers {
-		return op.removeRedeemersOf(removedTransaction)
+		return op.removeRedeemersOf(removedTransaction, reason)
 	}
 
 	outpoint := externalapi.DomainOutpoint{TransactionID: *removedTransaction.TransactionID()}
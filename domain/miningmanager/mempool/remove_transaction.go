@@ -4,11 +4,14 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 )
 
-func (mp *mempool) removeTransactions(transactions []*externalapi.DomainTransaction, removeRedeemers bool) error {
+func (mp *mempool) removeTransactions(transactions []*externalapi.DomainTransaction, removeRedeemers bool,
+	reason miningmanagermodel.MempoolRemovalReason) error {
+
 	for _, transaction := range transactions {
-		err := mp.removeTransaction(consensushashing.TransactionID(transaction), removeRedeemers)
+		err := mp.removeTransaction(consensushashing.TransactionID(transaction), removeRedeemers, reason)
 		if err != nil {
 			return err
 		}
@@ -16,9 +19,13 @@ func (mp *mempool) removeTransactions(transactions []*externalapi.DomainTransact
 	return nil
 }
 
-func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool) error {
+// removeTransaction removes the given transaction from the mempool, along with its redeemers
+// if removeRedeemers is true. All the removed transactions are recorded with the given reason.
+func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactionID, removeRedeemers bool,
+	reason miningmanagermodel.MempoolRemovalReason) error {
+
 	if _, ok := mp.orphansPool.allOrphans[*transactionID]; ok {
-		return mp.orphansPool.removeOrphan(transactionID, true)
+		return mp.orphansPool.removeOrphan(transactionID, true, reason)
 	}
 
 	mempoolTransaction, ok := mp.transactionsPool.allTransactions[*transactionID]
@@ -37,14 +44,15 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 	}
 
 	for _, transactionToRemove := range transactionsToRemove {
-		err := mp.removeTransactionFromSets(transactionToRemove, removeRedeemers)
+		err := mp.removeTransactionFromSets(transactionToRemove, removeRedeemers, reason)
 		if err != nil {
 			return err
 		}
+		mp.recordRemoved(transactionToRemove.Transaction(), false, reason)
 	}
 
 	if removeRedeemers {
-		err := mp.orphansPool.removeRedeemersOf(mempoolTransaction)
+		err := mp.orphansPool.removeRedeemersOf(mempoolTransaction, reason)
 		if err != nil {
 			return err
 		}
@@ -53,7 +61,9 @@ func (mp *mempool) removeTransaction(transactionID *externalapi.DomainTransactio
 	return nil
 }
 
-func (mp *mempool) removeTransactionFromSets(mempoolTransaction *model.MempoolTransaction, removeRedeemers bool) error {
+func (mp *mempool) removeTransactionFromSets(mempoolTransaction *model.MempoolTransaction, removeRedeemers bool,
+	reason miningmanagermodel.MempoolRemovalReason) error {
+
 	mp.mempoolUTXOSet.removeTransaction(mempoolTransaction)
 
 	err := mp.transactionsPool.removeTransaction(mempoolTransaction)
@@ -61,7 +71,7 @@ func (mp *mempool) removeTransactionFromSets(mempoolTransaction *model.MempoolTr
 		return err
 	}
 
-	err = mp.orphansPool.updateOrphansAfterTransactionRemoved(mempoolTransaction, removeRedeemers)
+	err = mp.orphansPool.updateOrphansAfterTransactionRemoved(mempoolTransaction, removeRedeemers, reason)
 	if err != nil {
 		return err
 	}
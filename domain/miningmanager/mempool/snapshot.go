@@ -0,0 +1,29 @@
+package mempool
+
+import (
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+)
+
+// snapshot returns all the transactions in the mempool, including orphans.
+// The transactions are ordered so that every non-orphan transaction appears
+// after all of its parents in the mempool. Orphans come last.
+func (mp *mempool) snapshot() []*miningmanagermodel.SnapshotTransaction {
+	transactions := mp.transactionsPool.allTransactionsInTopologicalOrder()
+	result := make([]*miningmanagermodel.SnapshotTransaction, 0, len(transactions)+len(mp.orphansPool.allOrphans))
+
+	for _, mempoolTransaction := range transactions {
+		result = append(result, &miningmanagermodel.SnapshotTransaction{
+			Transaction:    mempoolTransaction.Transaction(),
+			IsHighPriority: mempoolTransaction.IsHighPriority(),
+		})
+	}
+	for _, orphanTransaction := range mp.orphansPool.allOrphans {
+		result = append(result, &miningmanagermodel.SnapshotTransaction{
+			Transaction:    orphanTransaction.Transaction(),
+			IsHighPriority: orphanTransaction.IsHighPriority(),
+			IsOrphan:       true,
+		})
+	}
+
+	return result
+}
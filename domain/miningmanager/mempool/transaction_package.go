@@ -0,0 +1,116 @@
+package mempool
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+)
+
+const (
+	// maximumPackageAncestorCount is the maximum amount of transactions, including the transaction
+	// itself, in an ancestor package that is considered for block template candidate prioritization.
+	// Larger packages are ignored. This matches the default ancestor limit of Bitcoin Core.
+	maximumPackageAncestorCount = 25
+
+	// maximumPackageDescendantCount is the maximum amount of descendants of a transaction whose
+	// packages are considered when looking for its best descendant package. This matches the default
+	// descendant limit of Bitcoin Core.
+	maximumPackageDescendantCount = 25
+)
+
+// transactionPackage is a set of mempool transactions that can only be mined together,
+// that is - a transaction and all of its ancestors in the mempool
+type transactionPackage struct {
+	fee  uint64
+	mass uint64
+}
+
+// hasHigherFeeRateThan returns whether the fee rate of tp is strictly higher than the fee rate of other
+func (tp *transactionPackage) hasHigherFeeRateThan(other *transactionPackage) bool {
+	// Compare tp.fee/tp.mass to other.fee/other.mass without dividing
+	return float64(tp.fee)*float64(other.mass) > float64(other.fee)*float64(tp.mass)
+}
+
+// bestDescendantPackage returns the package with the highest fee rate that requires the given
+// transaction to be mined. The candidates are the transaction itself and the ancestor packages
+// of up to maximumPackageDescendantCount of its descendants in the mempool. Packages with more
+// than maximumPackageAncestorCount transactions are not candidates, so that the work done under
+// the mempool lock stays linear in the amount of transactions in the mempool.
+// ancestorPackages is used to cache ancestor packages between calls.
+func (tp *transactionsPool) bestDescendantPackage(transaction *model.MempoolTransaction,
+	ancestorPackages map[externalapi.DomainTransactionID]*transactionPackage) *transactionPackage {
+
+	bestPackage := &transactionPackage{
+		fee:  transaction.Transaction().Fee,
+		mass: transaction.Transaction().Mass,
+	}
+	for _, descendant := range tp.limitedDescendants(transaction, maximumPackageDescendantCount) {
+		descendantPackage, ok := ancestorPackages[*descendant.TransactionID()]
+		if !ok {
+			descendantPackage = ancestorPackage(descendant, maximumPackageAncestorCount)
+			ancestorPackages[*descendant.TransactionID()] = descendantPackage
+		}
+		if descendantPackage != nil && descendantPackage.hasHigherFeeRateThan(bestPackage) {
+			bestPackage = descendantPackage
+		}
+	}
+	return bestPackage
+}
+
+// limitedDescendants returns up to limit descendants of the given transaction in the mempool,
+// closest descendants first
+func (tp *transactionsPool) limitedDescendants(transaction *model.MempoolTransaction,
+	limit int) []*model.MempoolTransaction {
+
+	visited := map[externalapi.DomainTransactionID]struct{}{
+		*transaction.TransactionID(): {},
+	}
+	descendants := []*model.MempoolTransaction{}
+	queue := []*model.MempoolTransaction{transaction}
+	for len(queue) > 0 {
+		var current *model.MempoolTransaction
+		current, queue = queue[0], queue[1:]
+
+		for _, redeemer := range tp.chainedTransactionsByParentID[*current.TransactionID()] {
+			if _, ok := visited[*redeemer.TransactionID()]; ok {
+				continue
+			}
+			if len(descendants) == limit {
+				return descendants
+			}
+			visited[*redeemer.TransactionID()] = struct{}{}
+			descendants = append(descendants, redeemer)
+			queue = append(queue, redeemer)
+		}
+	}
+	return descendants
+}
+
+// ancestorPackage returns the package made of the given transaction and all of its ancestors in the mempool.
+// It returns nil if the package has more than limit transactions.
+func ancestorPackage(transaction *model.MempoolTransaction, limit int) *transactionPackage {
+	visited := map[externalapi.DomainTransactionID]struct{}{
+		*transaction.TransactionID(): {},
+	}
+	result := &transactionPackage{}
+	stack := []*model.MempoolTransaction{transaction}
+	for len(stack) > 0 {
+		var current *model.MempoolTransaction
+		last := len(stack) - 1
+		current, stack = stack[last], stack[:last]
+
+		result.fee += current.Transaction().Fee
+		result.mass += current.Transaction().Mass
+
+		for parentID, parent := range current.ParentTransactionsInPool() {
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			if len(visited) == limit {
+				return nil
+			}
+			visited[parentID] = struct{}{}
+			stack = append(stack, parent)
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+)
+
+// createTransactionChain creates a chain of the given length, where every transaction spends its predecessor,
+// and adds it to a new transactions pool. Every transaction pays a fee of 1 besides the last one, which pays
+// lastFee.
+func createTransactionChain(length int, lastFee uint64) (*transactionsPool, []*model.MempoolTransaction) {
+	pool := &transactionsPool{chainedTransactionsByParentID: model.IDToTransactionsSliceMap{}}
+	chain := make([]*model.MempoolTransaction, length)
+	for i := range chain {
+		fee := uint64(1)
+		if i == length-1 {
+			fee = lastFee
+		}
+		transaction := &externalapi.DomainTransaction{LockTime: uint64(i), Fee: fee, Mass: 1000}
+		parents := model.IDToTransactionMap{}
+		if i > 0 {
+			parents[*chain[i-1].TransactionID()] = chain[i-1]
+		}
+		chain[i] = model.NewMempoolTransaction(transaction, parents, false, 0)
+		if i > 0 {
+			parentID := *chain[i-1].TransactionID()
+			pool.chainedTransactionsByParentID[parentID] = []*model.MempoolTransaction{chain[i]}
+		}
+	}
+	return pool, chain
+}
+
+func TestBestDescendantPackage(t *testing.T) {
+	tests := []struct {
+		name         string
+		length       int
+		expectedFee  uint64
+		expectedMass uint64
+	}{
+		{name: "a short chain is a single package", length: 5, expectedFee: 4 + 1_000_000, expectedMass: 5 * 1000},
+		{name: "a chain longer than the limits is ignored", length: maximumPackageAncestorCount + 5, expectedFee: 1,
+			expectedMass: 1000},
+	}
+
+	for _, test := range tests {
+		pool, chain := createTransactionChain(test.length, 1_000_000)
+		bestPackage := pool.bestDescendantPackage(chain[0], map[externalapi.DomainTransactionID]*transactionPackage{})
+		if bestPackage.fee != test.expectedFee || bestPackage.mass != test.expectedMass {
+			t.Errorf("%s: unexpected package. Want: fee %d and mass %d, got: fee %d and mass %d", test.name,
+				test.expectedFee, test.expectedMass, bestPackage.fee, bestPackage.mass)
+		}
+	}
+}
+
+func TestLimitedDescendants(t *testing.T) {
+	pool, chain := createTransactionChain(maximumPackageDescendantCount+5, 1)
+	descendants := pool.limitedDescendants(chain[0], maximumPackageDescendantCount)
+	if len(descendants) != maximumPackageDescendantCount {
+		t.Fatalf("Unexpected amount of descendants. Want: %d, got: %d", maximumPackageDescendantCount, len(descendants))
+	}
+	for i, descendant := range descendants {
+		if descendant != chain[i+1] {
+			t.Fatalf("Descendant %d is not the expected transaction", i)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 )
 
 type transactionsPool struct {
@@ -46,6 +47,7 @@ func (tp *transactionsPool) addTransaction(transaction *externalapi.DomainTransa
 	if err != nil {
 		return nil, err
 	}
+	tp.mempool.recordAdded(transaction, false)
 
 	return mempoolTransaction, nil
 }
@@ -118,7 +120,8 @@ func (tp *transactionsPool) expireOldTransactions() error {
 		if daaScoreSinceAdded > tp.mempool.config.TransactionExpireIntervalDAAScore {
 			log.Debugf("Removing transaction %s, because it expired. DAAScore moved by %d, expire interval: %d",
 				mempoolTransaction.TransactionID(), daaScoreSinceAdded, tp.mempool.config.TransactionExpireIntervalDAAScore)
-			err = tp.mempool.removeTransaction(mempoolTransaction.TransactionID(), true)
+			err = tp.mempool.removeTransaction(mempoolTransaction.TransactionID(), true,
+				miningmanagermodel.MempoolRemovalReasonExpired)
 			if err != nil {
 				return err
 			}
@@ -130,18 +133,50 @@ func (tp *transactionsPool) expireOldTransactions() error {
 	return nil
 }
 
-func (tp *transactionsPool) allReadyTransactions() []*externalapi.DomainTransaction {
-	result := []*externalapi.DomainTransaction{}
+func (tp *transactionsPool) allReadyTransactions() []*miningmanagermodel.BlockCandidateTransaction {
+	result := []*miningmanagermodel.BlockCandidateTransaction{}
+	ancestorPackages := map[externalapi.DomainTransactionID]*transactionPackage{}
 
 	for _, mempoolTransaction := range tp.allTransactions {
 		if len(mempoolTransaction.ParentTransactionsInPool()) == 0 {
-			result = append(result, mempoolTransaction.Transaction())
+			bestPackage := tp.bestDescendantPackage(mempoolTransaction, ancestorPackages)
+			result = append(result, &miningmanagermodel.BlockCandidateTransaction{
+				Transaction: mempoolTransaction.Transaction(),
+				PackageFee:  bestPackage.fee,
+				PackageMass: bestPackage.mass,
+			})
 		}
 	}
 
 	return result
 }
 
+// allTransactionsInTopologicalOrder returns all the transactions in the pool, ordered
+// so that every transaction appears after all of its parents in the pool
+func (tp *transactionsPool) allTransactionsInTopologicalOrder() []*model.MempoolTransaction {
+	result := make([]*model.MempoolTransaction, 0, len(tp.allTransactions))
+	visited := make(map[externalapi.DomainTransactionID]struct{}, len(tp.allTransactions))
+
+	var visit func(transaction *model.MempoolTransaction)
+	visit = func(transaction *model.MempoolTransaction) {
+		if _, ok := visited[*transaction.TransactionID()]; ok {
+			return
+		}
+		visited[*transaction.TransactionID()] = struct{}{}
+
+		for _, parentTransactionInPool := range transaction.ParentTransactionsInPool() {
+			visit(parentTransactionInPool)
+		}
+		result = append(result, transaction)
+	}
+
+	for _, mempoolTransaction := range tp.allTransactions {
+		visit(mempoolTransaction)
+	}
+
+	return result
+}
+
 func (tp *transactionsPool) getParentTransactionsInPool(
 	transaction *externalapi.DomainTransaction) model.IDToTransactionMap {
 
@@ -193,7 +228,8 @@ func (tp *transactionsPool) limitTransactionCount() error {
 
 		log.Debugf("Removing transaction %s, because mempoolTransaction count (%d) exceeded the limit (%d)",
 			transactionToRemove.TransactionID(), len(tp.allTransactions), tp.mempool.config.MaximumTransactionCount)
-		err := tp.mempool.removeTransaction(transactionToRemove.TransactionID(), true)
+		err := tp.mempool.removeTransaction(transactionToRemove.TransactionID(), true,
+			miningmanagermodel.MempoolRemovalReasonLowFee)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,170 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+// validateAndReplaceTransaction validates the given transaction and inserts it into the
+// mempool instead of the mempool transactions that double spend its inputs, together with
+// all of their redeemers. The replacement has to pay a strictly higher fee rate than each of
+// the transactions it double spends, and a strictly higher fee than all the transactions it
+// evicts combined. A single replacement may evict at most MaximumReplacedTransactionCount
+// transactions.
+func (mp *mempool) validateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+	acceptedTransactions []*externalapi.DomainTransaction, replacedTransactions []*externalapi.DomainTransaction, err error) {
+
+	transactionID := consensushashing.TransactionID(transaction)
+	onEnd := logger.LogAndMeasureExecutionTime(log,
+		fmt.Sprintf("validateAndReplaceTransaction %s", transactionID))
+	defer onEnd()
+
+	// Populate mass in the beginning, it will be used in multiple places throughout the validation and insertion.
+	mp.consensusReference.Consensus().PopulateMass(transaction)
+
+	err = mp.validateTransactionInIsolation(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	conflictingTransactions := mp.mempoolUTXOSet.getConflictingTransactions(transaction)
+	if len(conflictingTransactions) == 0 {
+		str := fmt.Sprintf("transaction %s does not double spend any transaction in the memory pool", transactionID)
+		return nil, nil, transactionRuleError(RejectInvalid, str)
+	}
+
+	parentsInPool, missingOutpoints, err := mp.fillInputsAndGetMissingParents(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(missingOutpoints) > 0 {
+		str := fmt.Sprintf("replacement transaction %s is an orphan", transactionID)
+		return nil, nil, transactionRuleError(RejectBadOrphan, str)
+	}
+
+	err = mp.validateTransactionInContext(transaction)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transactionsToReplace, ok := mp.transactionsToReplace(conflictingTransactions, mp.config.MaximumReplacedTransactionCount)
+	if !ok {
+		str := fmt.Sprintf("replacement transaction %s would evict more than the maximum of %d transactions",
+			transactionID, mp.config.MaximumReplacedTransactionCount)
+		return nil, nil, transactionRuleError(RejectInvalid, str)
+	}
+	for parentTransactionID := range parentsInPool {
+		if _, ok := transactionsToReplace[parentTransactionID]; ok {
+			str := fmt.Sprintf("replacement transaction %s spends an output of transaction %s, "+
+				"which it replaces", transactionID, parentTransactionID)
+			return nil, nil, transactionRuleError(RejectInvalid, str)
+		}
+	}
+
+	err = checkReplacementFees(transaction, conflictingTransactions, transactionsToReplace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replacedTransactions = make([]*externalapi.DomainTransaction, 0, len(transactionsToReplace))
+	for _, transactionToReplace := range transactionsToReplace {
+		replacedTransactions = append(replacedTransactions, transactionToReplace.Transaction())
+	}
+	for _, conflictingTransaction := range conflictingTransactions {
+		err = mp.removeTransaction(conflictingTransaction.TransactionID(), true, miningmanagermodel.MempoolRemovalReasonReplaced)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	log.Debugf("Transaction %s replaced %d transactions in the mempool", transactionID, len(replacedTransactions))
+
+	mempoolTransaction, err := mp.transactionsPool.addTransaction(transaction, parentsInPool, isHighPriority)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acceptedOrphans, err := mp.orphansPool.processOrphansAfterAcceptedTransaction(mempoolTransaction.Transaction())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acceptedTransactions = append([]*externalapi.DomainTransaction{transaction}, acceptedOrphans...)
+
+	err = mp.transactionsPool.limitTransactionCount()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return acceptedTransactions, replacedTransactions, nil
+}
+
+// transactionsToReplace returns the given conflicting transactions together with all of their redeemers.
+// It visits every transaction once, and gives up and returns false as soon as there are more than
+// maxCount of them, so that replacing the root of a large tree of chained transactions is cheap to reject.
+func (mp *mempool) transactionsToReplace(conflictingTransactions []*model.MempoolTransaction, maxCount uint64) (
+	transactionsToReplace model.IDToTransactionMap, ok bool) {
+
+	transactionsToReplace = model.IDToTransactionMap{}
+	stack := make([]*model.MempoolTransaction, 0, len(conflictingTransactions))
+	add := func(transaction *model.MempoolTransaction) bool {
+		if _, ok := transactionsToReplace[*transaction.TransactionID()]; ok {
+			return true
+		}
+		transactionsToReplace[*transaction.TransactionID()] = transaction
+		stack = append(stack, transaction)
+		return uint64(len(transactionsToReplace)) <= maxCount
+	}
+
+	for _, conflictingTransaction := range conflictingTransactions {
+		if !add(conflictingTransaction) {
+			return nil, false
+		}
+	}
+	for len(stack) > 0 {
+		var current *model.MempoolTransaction
+		last := len(stack) - 1
+		current, stack = stack[last], stack[:last]
+
+		for _, redeemer := range mp.transactionsPool.chainedTransactionsByParentID[*current.TransactionID()] {
+			if !add(redeemer) {
+				return nil, false
+			}
+		}
+	}
+	return transactionsToReplace, true
+}
+
+func checkReplacementFees(transaction *externalapi.DomainTransaction,
+	conflictingTransactions []*model.MempoolTransaction, transactionsToReplace model.IDToTransactionMap) error {
+
+	transactionID := consensushashing.TransactionID(transaction)
+	feeRate := float64(transaction.Fee) / float64(transaction.Mass)
+	for _, conflictingTransaction := range conflictingTransactions {
+		conflictingFeeRate := float64(conflictingTransaction.Transaction().Fee) /
+			float64(conflictingTransaction.Transaction().Mass)
+		if feeRate <= conflictingFeeRate {
+			str := fmt.Sprintf("replacement transaction %s has a fee rate of %f sompi per gram, which is not "+
+				"higher than the fee rate of %f of transaction %s", transactionID, feeRate, conflictingFeeRate,
+				conflictingTransaction.TransactionID())
+			return transactionRuleError(RejectInsufficientFee, str)
+		}
+	}
+
+	replacedFee := uint64(0)
+	for _, transactionToReplace := range transactionsToReplace {
+		replacedFee += transactionToReplace.Transaction().Fee
+	}
+	if transaction.Fee <= replacedFee {
+		str := fmt.Sprintf("replacement transaction %s has a fee of %d sompi, which is not higher than "+
+			"the total fee of %d of the %d transactions it replaces", transactionID, transaction.Fee, replacedFee,
+			len(transactionsToReplace))
+		return transactionRuleError(RejectInsufficientFee, str)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool/model"
+)
+
+func TestTransactionsToReplace(t *testing.T) {
+	mp := &mempool{}
+	mp.transactionsPool = newTransactionsPool(mp)
+
+	newTransaction := func(lockTime uint64) *model.MempoolTransaction {
+		return model.NewMempoolTransaction(&externalapi.DomainTransaction{LockTime: lockTime}, nil, false, 0)
+	}
+
+	// root is redeemed by left and right, which are both redeemed by bottom
+	root, left, right, bottom := newTransaction(0), newTransaction(1), newTransaction(2), newTransaction(3)
+	chainedTransactions := mp.transactionsPool.chainedTransactionsByParentID
+	chainedTransactions[*root.TransactionID()] = []*model.MempoolTransaction{left, right}
+	chainedTransactions[*left.TransactionID()] = []*model.MempoolTransaction{bottom}
+	chainedTransactions[*right.TransactionID()] = []*model.MempoolTransaction{bottom}
+
+	transactionsToReplace, ok := mp.transactionsToReplace([]*model.MempoolTransaction{root, left}, 4)
+	if !ok {
+		t.Fatalf("transactionsToReplace unexpectedly exceeded the maximum of 4 transactions")
+	}
+	if len(transactionsToReplace) != 4 {
+		t.Fatalf("Expected 4 transactions to replace, got %d", len(transactionsToReplace))
+	}
+
+	_, ok = mp.transactionsToReplace([]*model.MempoolTransaction{root}, 3)
+	if ok {
+		t.Fatalf("transactionsToReplace unexpectedly didn't exceed the maximum of 3 transactions")
+	}
+}
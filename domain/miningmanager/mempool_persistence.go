@@ -0,0 +1,209 @@
+package miningmanager
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// mempoolFileVersion is the version of the format used by SaveMempool.
+// It is written at the start of the file, and LoadMempool refuses to load
+// files of any other version.
+const mempoolFileVersion uint32 = 1
+
+// Flags written before every transaction in the mempool file
+const (
+	mempoolFileFlagHighPriority byte = 1 << iota
+	mempoolFileFlagOrphan
+)
+
+// SaveMempool writes all the transactions in the mempool, including orphans,
+// to the file at the given path, overwriting it if it already exists
+func (mm *miningManager) SaveMempool(filePath string) (savedTransactionCount int, err error) {
+	snapshot := mm.mempool.Snapshot()
+
+	// Write into a temporary file first, so that a crash during the write
+	// never leaves a partially written mempool file behind
+	temporaryFilePath := filePath + ".tmp"
+	file, err := os.Create(temporaryFilePath)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(temporaryFilePath)
+		}
+	}()
+	writer := bufio.NewWriter(file)
+
+	err = writeMempoolFile(writer, snapshot)
+	if err != nil {
+		_ = file.Close()
+		return 0, err
+	}
+	err = writer.Flush()
+	if err != nil {
+		_ = file.Close()
+		return 0, errors.WithStack(err)
+	}
+	err = file.Close()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	err = os.Rename(temporaryFilePath, filePath)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return len(snapshot), nil
+}
+
+func writeMempoolFile(writer io.Writer, snapshot []*miningmanagermodel.SnapshotTransaction) error {
+	err := binary.Write(writer, binary.LittleEndian, mempoolFileVersion)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, transaction := range snapshot {
+		serializedTransaction, err := proto.Marshal(serialization.DomainTransactionToDbTransaction(transaction.Transaction))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		flags := byte(0)
+		if transaction.IsHighPriority {
+			flags |= mempoolFileFlagHighPriority
+		}
+		if transaction.IsOrphan {
+			flags |= mempoolFileFlagOrphan
+		}
+		_, err = writer.Write([]byte{flags})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = binary.Write(writer, binary.LittleEndian, uint32(len(serializedTransaction)))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		_, err = writer.Write(serializedTransaction)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// LoadMempool reads the transactions written by SaveMempool from the file at the given
+// path, and inserts them into the mempool through ValidateAndInsertTransaction.
+// Transactions that are no longer valid - for example, because they had been mined
+// or double spent in the meantime - are dropped and counted in rejectedTransactionCount.
+// Transactions that were orphans when saved are returned to the orphan pool, and are
+// counted in neither acceptedTransactions nor rejectedTransactionCount unless their
+// missing parents are found.
+// If the file does not exist, LoadMempool does nothing.
+func (mm *miningManager) LoadMempool(filePath string) (
+	acceptedTransactions []*externalapi.DomainTransaction, rejectedTransactionCount int, err error) {
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	// A transaction's mass is never below its serialized size, and no transaction heavier
+	// than a block may enter the mempool, so a longer transaction means the file is corrupt
+	snapshot, err := readMempoolFile(bufio.NewReader(file), mm.blockMaxMass)
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "failed to read mempool file %s", filePath)
+	}
+
+	acceptedTransactions = []*externalapi.DomainTransaction{}
+	for _, transaction := range snapshot {
+		// Transactions that weren't orphans when saved may only be missing inputs if these
+		// inputs had been spent since, so they're not allowed to become orphans
+		accepted, err := mm.mempool.ValidateAndInsertTransaction(
+			transaction.Transaction, transaction.IsHighPriority, transaction.IsOrphan)
+		if err != nil {
+			if !errors.As(err, &mempool.RuleError{}) {
+				return nil, 0, err
+			}
+			rejectedTransactionCount++
+			continue
+		}
+		acceptedTransactions = append(acceptedTransactions, accepted...)
+	}
+
+	return acceptedTransactions, rejectedTransactionCount, nil
+}
+
+// readMempoolFile reads the transactions written by writeMempoolFile. Serialized transactions
+// longer than maximumTransactionLength are rejected before being allocated, so that a corrupt
+// file can't make the node allocate an arbitrary amount of memory.
+func readMempoolFile(reader io.Reader, maximumTransactionLength uint64) (
+	[]*miningmanagermodel.SnapshotTransaction, error) {
+
+	var version uint32
+	err := binary.Read(reader, binary.LittleEndian, &version)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if version != mempoolFileVersion {
+		return nil, errors.Errorf("unsupported mempool file version %d", version)
+	}
+
+	snapshot := []*miningmanagermodel.SnapshotTransaction{}
+	for {
+		flags := make([]byte, 1)
+		_, err := io.ReadFull(reader, flags)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return snapshot, nil
+			}
+			return nil, errors.WithStack(err)
+		}
+
+		var serializedTransactionLength uint32
+		err = binary.Read(reader, binary.LittleEndian, &serializedTransactionLength)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if uint64(serializedTransactionLength) > maximumTransactionLength {
+			return nil, errors.Errorf("serialized transaction length %d is above the maximum of %d",
+				serializedTransactionLength, maximumTransactionLength)
+		}
+		serializedTransaction := make([]byte, serializedTransactionLength)
+		_, err = io.ReadFull(reader, serializedTransaction)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		dbTransaction := &serialization.DbTransaction{}
+		err = proto.Unmarshal(serializedTransaction, dbTransaction)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		transaction, err := serialization.DbTransactionToDomainTransaction(dbTransaction)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot = append(snapshot, &miningmanagermodel.SnapshotTransaction{
+			Transaction:    transaction,
+			IsHighPriority: flags[0]&mempoolFileFlagHighPriority != 0,
+			IsOrphan:       flags[0]&mempoolFileFlagOrphan != 0,
+		})
+	}
+}
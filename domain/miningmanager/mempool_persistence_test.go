@@ -0,0 +1,28 @@
+package miningmanager
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestReadMempoolFileRejectsLongTransactions(t *testing.T) {
+	const maximumTransactionLength = 1000
+
+	buffer := &bytes.Buffer{}
+	err := binary.Write(buffer, binary.LittleEndian, mempoolFileVersion)
+	if err != nil {
+		t.Fatalf("binary.Write: %s", err)
+	}
+	buffer.WriteByte(0)
+	err = binary.Write(buffer, binary.LittleEndian, uint32(maximumTransactionLength+1))
+	if err != nil {
+		t.Fatalf("binary.Write: %s", err)
+	}
+
+	_, err = readMempoolFile(buffer, maximumTransactionLength)
+	if err == nil || !strings.Contains(err.Error(), "above the maximum") {
+		t.Fatalf("Expected an error for a too long transaction, got: %v", err)
+	}
+}
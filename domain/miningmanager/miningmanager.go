@@ -17,10 +17,19 @@ type MiningManager interface {
 	GetTransaction(transactionID *externalapi.DomainTransactionID) (*externalapi.DomainTransaction, bool)
 	AllTransactions() []*externalapi.DomainTransaction
 	TransactionCount() int
+	MempoolStats() *miningmanagermodel.MempoolStats
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
+	ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, replacedTransactions []*externalapi.DomainTransaction, err error)
 	RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error)
+	GetFeeEstimate() *FeeEstimate
+	SaveMempool(filePath string) (savedTransactionCount int, err error)
+	LoadMempool(filePath string) (acceptedTransactions []*externalapi.DomainTransaction, rejectedTransactionCount int, err error)
+	SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler)
+	UnconfirmedUTXOs(scriptPublicKeys []*externalapi.ScriptPublicKey,
+		confirmedUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry) *miningmanagermodel.UnconfirmedUTXOs
 }
 
 type miningManager struct {
@@ -30,6 +39,9 @@ type miningManager struct {
 	cachedBlockTemplate  *externalapi.DomainBlockTemplate
 	cachingTime          time.Time
 	cacheLock            *sync.Mutex
+
+	blockMaxMass   uint64
+	minimumFeeRate float64
 }
 
 // GetBlockTemplate obtains a block template for a miner to consume
@@ -119,6 +131,16 @@ func (mm *miningManager) ValidateAndInsertTransaction(transaction *externalapi.D
 	return mm.mempool.ValidateAndInsertTransaction(transaction, isHighPriority, allowOrphan)
 }
 
+// ValidateAndReplaceTransaction validates the given transaction, and
+// adds it to the set of known transactions that have not yet been
+// added to any block instead of the transactions that double spend it
+func (mm *miningManager) ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction,
+	isHighPriority bool) (acceptedTransactions []*externalapi.DomainTransaction,
+	replacedTransactions []*externalapi.DomainTransaction, err error) {
+
+	return mm.mempool.ValidateAndReplaceTransaction(transaction, isHighPriority)
+}
+
 func (mm *miningManager) GetTransaction(
 	transactionID *externalapi.DomainTransactionID) (*externalapi.DomainTransaction, bool) {
 
@@ -133,8 +155,24 @@ func (mm *miningManager) TransactionCount() int {
 	return mm.mempool.TransactionCount()
 }
 
+func (mm *miningManager) MempoolStats() *miningmanagermodel.MempoolStats {
+	return mm.mempool.Stats()
+}
+
 func (mm *miningManager) RevalidateHighPriorityTransactions() (
 	validTransactions []*externalapi.DomainTransaction, err error) {
 
 	return mm.mempool.RevalidateHighPriorityTransactions()
 }
+
+func (mm *miningManager) SetOnMempoolChangedHandler(onMempoolChangedHandler miningmanagermodel.OnMempoolChangedHandler) {
+	mm.mempool.SetOnMempoolChangedHandler(onMempoolChangedHandler)
+}
+
+// UnconfirmedUTXOs returns the changes that the transactions in the mempool make to
+// the UTXOs of the given script public keys, whose confirmed UTXOs are confirmedUTXOs
+func (mm *miningManager) UnconfirmedUTXOs(scriptPublicKeys []*externalapi.ScriptPublicKey,
+	confirmedUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry) *miningmanagermodel.UnconfirmedUTXOs {
+
+	return mm.mempool.UnconfirmedUTXOs(scriptPublicKeys, confirmedUTXOs)
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/kaspanet/kaspad/version"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -172,6 +174,276 @@ func TestDoubleSpendInMempool(t *testing.T) {
 	})
 }
 
+// TestReplaceByFee verifies that a transaction double spending a mempool transaction replaces it, together with its
+// redeemers, only if it pays a higher fee
+func TestReplaceByFee(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestReplaceByFee")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		parentTransaction, childTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error creating transactions: %+v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(parentTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(childTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+
+		// A transaction that doesn't double spend anything is not a replacement
+		unrelatedTransaction := createTransactionWithUTXOEntry(t, 0, 0)
+		_, _, err = miningManager.ValidateAndReplaceTransaction(unrelatedTransaction, false)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectInvalid {
+			t.Fatalf("Unexpected error %+v", err)
+		}
+
+		// A replacement that pays a higher fee than the parent, but not higher than the parent and child
+		// combined, should be rejected
+		cheapReplacement := createReplacementTransaction(parentTransaction, 500)
+		_, _, err = miningManager.ValidateAndReplaceTransaction(cheapReplacement, false)
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectInsufficientFee {
+			t.Fatalf("Unexpected error %+v", err)
+		}
+		if !contains(parentTransaction, miningManager.AllTransactions()) {
+			t.Fatalf("Rejected replacement evicted the transaction it double spends")
+		}
+
+		replacement := createReplacementTransaction(parentTransaction, 2000)
+		acceptedTransactions, replacedTransactions, err := miningManager.ValidateAndReplaceTransaction(replacement, false)
+		if err != nil {
+			t.Fatalf("ValidateAndReplaceTransaction: %v", err)
+		}
+		if len(acceptedTransactions) != 1 || !contains(replacement, acceptedTransactions) {
+			t.Fatalf("Expected only the replacement to be accepted")
+		}
+		if len(replacedTransactions) != 2 || !contains(parentTransaction, replacedTransactions) ||
+			!contains(childTransaction, replacedTransactions) {
+			t.Fatalf("Expected the parent and child transactions to be replaced")
+		}
+
+		transactionsFromMempool := miningManager.AllTransactions()
+		if len(transactionsFromMempool) != 1 || !contains(replacement, transactionsFromMempool) {
+			t.Fatalf("Expected the mempool to contain only the replacement")
+		}
+	})
+}
+
+// TestReplaceByFeeEvictionLimit verifies that a replacement is rejected if it would evict more transactions than
+// the mempool allows, even if it pays enough
+func TestReplaceByFeeEvictionLimit(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestReplaceByFeeEvictionLimit")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		mempoolConfig := mempool.DefaultConfig(&consensusConfig.Params)
+		mempoolConfig.MaximumReplacedTransactionCount = 1
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempoolConfig)
+		parentTransaction, childTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error creating transactions: %+v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(parentTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		_, err = miningManager.ValidateAndInsertTransaction(childTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+
+		// Replacing the parent would evict the child as well, which is more than allowed
+		replacement := createReplacementTransaction(parentTransaction, 2000)
+		_, _, err = miningManager.ValidateAndReplaceTransaction(replacement, false)
+		txRuleError := &mempool.TxRuleError{}
+		if !errors.As(err, txRuleError) || txRuleError.RejectCode != mempool.RejectInvalid {
+			t.Fatalf("Unexpected error %+v", err)
+		}
+		transactionsFromMempool := miningManager.AllTransactions()
+		if len(transactionsFromMempool) != 2 || !contains(parentTransaction, transactionsFromMempool) ||
+			!contains(childTransaction, transactionsFromMempool) {
+			t.Fatalf("Rejected replacement evicted transactions from the mempool")
+		}
+	})
+}
+
+// TestBlockCandidateTransactionsPackageFeeRate verifies that a block candidate transaction carries the fee and mass
+// of the highest fee rate package that requires it to be mined, so that its descendants may pay for it.
+func TestBlockCandidateTransactionsPackageFeeRate(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestBlockCandidateTransactionsPackageFeeRate")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		mempoolInstance := mempool.New(mempool.DefaultConfig(&consensusConfig.Params), consensusReference)
+
+		parentTransaction, childTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error creating transactions: %+v", err)
+		}
+		grandchildTransaction, err := testutils.CreateTransaction(childTransaction, 100000)
+		if err != nil {
+			t.Fatalf("Error creating transaction: %+v", err)
+		}
+
+		checkCandidate := func(expectedFee uint64, expectedMass uint64) {
+			candidates := mempoolInstance.BlockCandidateTransactions()
+			if len(candidates) != 1 {
+				t.Fatalf("Expected exactly one block candidate transaction, got %d", len(candidates))
+			}
+			candidate := candidates[0]
+			if !candidate.Transaction.Equal(parentTransaction) {
+				t.Fatalf("Expected the block candidate to be the parent transaction")
+			}
+			if candidate.PackageFee != expectedFee || candidate.PackageMass != expectedMass {
+				t.Fatalf("Unexpected package. Want fee %d and mass %d, got fee %d and mass %d",
+					expectedFee, expectedMass, candidate.PackageFee, candidate.PackageMass)
+			}
+		}
+
+		for _, transaction := range []*externalapi.DomainTransaction{parentTransaction, childTransaction} {
+			_, err = mempoolInstance.ValidateAndInsertTransaction(transaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+			}
+		}
+
+		// The child doesn't pay a higher fee rate than the parent, so the parent is a package by itself
+		checkCandidate(parentTransaction.Fee, parentTransaction.Mass)
+
+		_, err = mempoolInstance.ValidateAndInsertTransaction(grandchildTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+
+		// The grandchild pays a high fee rate, so the parent should be prioritized by the package
+		// of the grandchild and all of its ancestors
+		checkCandidate(parentTransaction.Fee+childTransaction.Fee+grandchildTransaction.Fee,
+			parentTransaction.Mass+childTransaction.Mass+grandchildTransaction.Mass)
+	})
+}
+
+// TestSaveAndLoadMempool verifies that transactions saved by SaveMempool, including chained transactions
+// and orphans, are restored by LoadMempool into a fresh mempool.
+func TestSaveAndLoadMempool(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestSaveAndLoadMempool")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+
+		parentTransaction, childTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error creating transactions: %+v", err)
+		}
+		orphanParentTransaction, orphanTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error creating transactions: %+v", err)
+		}
+		for _, transaction := range []*externalapi.DomainTransaction{parentTransaction, childTransaction, orphanTransaction} {
+			_, err = miningManager.ValidateAndInsertTransaction(transaction, false, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %v", err)
+			}
+		}
+
+		mempoolFilePath := filepath.Join(t.TempDir(), "mempool.dat")
+		savedTransactionCount, err := miningManager.SaveMempool(mempoolFilePath)
+		if err != nil {
+			t.Fatalf("SaveMempool: %+v", err)
+		}
+		if savedTransactionCount != 3 {
+			t.Fatalf("Unexpected saved transaction count. Want: 3, got: %d", savedTransactionCount)
+		}
+
+		newMiningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+		acceptedTransactions, rejectedTransactionCount, err := newMiningManager.LoadMempool(mempoolFilePath)
+		if err != nil {
+			t.Fatalf("LoadMempool: %+v", err)
+		}
+		if rejectedTransactionCount != 0 {
+			t.Fatalf("Unexpected rejected transaction count. Want: 0, got: %d", rejectedTransactionCount)
+		}
+		if len(acceptedTransactions) != 2 || !contains(parentTransaction, acceptedTransactions) ||
+			!contains(childTransaction, acceptedTransactions) {
+			t.Fatalf("Expected the parent and child transactions to be accepted")
+		}
+
+		// The orphan should have been restored to the orphan pool, and get accepted once its parent is
+		acceptedTransactions, err = newMiningManager.ValidateAndInsertTransaction(orphanParentTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		if len(acceptedTransactions) != 2 || !contains(orphanTransaction, acceptedTransactions) {
+			t.Fatalf("Expected the restored orphan to be accepted with its parent")
+		}
+
+		// Loading the file again should drop everything, since all of its transactions are already in the mempool
+		acceptedTransactions, rejectedTransactionCount, err = newMiningManager.LoadMempool(mempoolFilePath)
+		if err != nil {
+			t.Fatalf("LoadMempool: %+v", err)
+		}
+		if len(acceptedTransactions) != 0 || rejectedTransactionCount != 3 {
+			t.Fatalf("Unexpected load result. Want: 0 accepted and 3 rejected, got: %d accepted and %d rejected",
+				len(acceptedTransactions), rejectedTransactionCount)
+		}
+
+		// A failed save should leave neither the mempool file nor the temporary file behind.
+		// Renaming the temporary file over a non-empty directory fails.
+		failingFilePath := filepath.Join(t.TempDir(), "mempool.dat")
+		err = os.MkdirAll(filepath.Join(failingFilePath, "dir"), 0700)
+		if err != nil {
+			t.Fatalf("MkdirAll: %+v", err)
+		}
+		_, err = miningManager.SaveMempool(failingFilePath)
+		if err == nil {
+			t.Fatalf("SaveMempool unexpectedly succeeded")
+		}
+		if _, err := os.Stat(failingFilePath + ".tmp"); !os.IsNotExist(err) {
+			t.Fatalf("Expected the temporary file to be removed, got: %v", err)
+		}
+	})
+}
+
 // TestHandleNewBlockTransactions verifies that all the transactions in the block were successfully removed from the mempool.
 func TestHandleNewBlockTransactions(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
@@ -377,6 +649,87 @@ func TestOrphanTransactions(t *testing.T) {
 	})
 }
 
+// TestMempoolChangedHandler verifies that the changes in the mempool are passed to the
+// mempool changed handler after each operation.
+func TestMempoolChangedHandler(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
+		consensusConfig.BlockCoinbaseMaturity = 0
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(consensusConfig, "TestMempoolChangedHandler")
+		if err != nil {
+			t.Fatalf("Error setting up TestConsensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningFactory := miningmanager.NewFactory()
+		tcAsConsensus := tc.(externalapi.Consensus)
+		tcAsConsensusPointer := &tcAsConsensus
+		consensusReference := consensusreference.NewConsensusReference(&tcAsConsensusPointer)
+		miningManager := miningFactory.NewMiningManager(consensusReference, &consensusConfig.Params, mempool.DefaultConfig(&consensusConfig.Params))
+
+		var changes []*model.MempoolChange
+		miningManager.SetOnMempoolChangedHandler(func(newChanges []*model.MempoolChange) {
+			changes = newChanges
+		})
+		expectChanges := func(expectedChanges ...*model.MempoolChange) {
+			if len(changes) != len(expectedChanges) {
+				t.Fatalf("Expected %d changes, got %d", len(expectedChanges), len(changes))
+			}
+			for i, change := range changes {
+				expectedChange := expectedChanges[i]
+				if change.Type != expectedChange.Type || change.IsOrphan != expectedChange.IsOrphan ||
+					change.RemovalReason != expectedChange.RemovalReason ||
+					!change.Transaction.Equal(expectedChange.Transaction) {
+
+					t.Fatalf("Unexpected change %d. Want: %s of %s (orphan: %t, reason: %s), "+
+						"got: %s of %s (orphan: %t, reason: %s)", i,
+						expectedChange.Type, consensushashing.TransactionID(expectedChange.Transaction),
+						expectedChange.IsOrphan, expectedChange.RemovalReason,
+						change.Type, consensushashing.TransactionID(change.Transaction),
+						change.IsOrphan, change.RemovalReason)
+				}
+			}
+			changes = nil
+		}
+
+		parentTransaction, childTransaction, err := createParentAndChildrenTransactions(tc)
+		if err != nil {
+			t.Fatalf("Error in createParentAndChildrenTransactions: %v", err)
+		}
+
+		_, err = miningManager.ValidateAndInsertTransaction(childTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		expectChanges(&model.MempoolChange{Type: model.MempoolChangeAdded, Transaction: childTransaction, IsOrphan: true})
+
+		_, err = miningManager.ValidateAndInsertTransaction(parentTransaction, false, true)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %v", err)
+		}
+		expectChanges(
+			&model.MempoolChange{Type: model.MempoolChangeAdded, Transaction: parentTransaction},
+			&model.MempoolChange{Type: model.MempoolChangeUnorphaned, Transaction: childTransaction},
+		)
+
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, parentTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %v", err)
+		}
+		expectChanges(&model.MempoolChange{Type: model.MempoolChangeRemoved, Transaction: parentTransaction,
+			RemovalReason: model.MempoolRemovalReasonIncludedInBlock})
+
+		doubleSpendTransaction := createTransactionWithUTXOEntry(t, 0, 0)
+		doubleSpendTransaction.Inputs[0].PreviousOutpoint = childTransaction.Inputs[0].PreviousOutpoint
+		_, err = miningManager.HandleNewBlockTransactions([]*externalapi.DomainTransaction{nil, doubleSpendTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %v", err)
+		}
+		expectChanges(&model.MempoolChange{Type: model.MempoolChangeRemoved, Transaction: childTransaction,
+			RemovalReason: model.MempoolRemovalReasonDoubleSpend})
+	})
+}
+
 func TestHighPriorityTransactions(t *testing.T) {
 	testutils.ForAllNets(t, true, func(t *testing.T, consensusConfig *consensus.Config) {
 		consensusConfig.BlockCoinbaseMaturity = 0
@@ -883,6 +1236,22 @@ func createTransactionWithUTXOEntry(t *testing.T, i int, daaScore uint64) *exter
 	return &tx
 }
 
+// createReplacementTransaction creates a transaction that double spends the given one,
+// paying additionalFee more than it
+func createReplacementTransaction(transaction *externalapi.DomainTransaction,
+	additionalFee uint64) *externalapi.DomainTransaction {
+
+	replacement := transaction.Clone()
+	replacement.ID = nil
+	replacement.Fee = 0
+	replacement.Mass = 0
+	for _, input := range replacement.Inputs {
+		input.UTXOEntry = nil
+	}
+	replacement.Outputs[0].Value -= additionalFee
+	return replacement
+}
+
 func createArraysOfParentAndChildrenTransactions(tc testapi.TestConsensus) ([]*externalapi.DomainTransaction,
 	[]*externalapi.DomainTransaction, error) {
 
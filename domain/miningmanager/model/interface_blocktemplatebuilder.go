@@ -9,4 +9,18 @@ type BlockTemplateBuilder interface {
 	BuildBlockTemplate(coinbaseData *consensusexternalapi.DomainCoinbaseData) (*consensusexternalapi.DomainBlockTemplate, error)
 	ModifyBlockTemplate(newCoinbaseData *consensusexternalapi.DomainCoinbaseData,
 		blockTemplateToModify *consensusexternalapi.DomainBlockTemplate) (*consensusexternalapi.DomainBlockTemplate, error)
+	RecentBlockTemplatesFeeStats() []*BlockTemplateFeeStats
+}
+
+// BlockTemplateFeeStats summarizes the fees paid by the transactions
+// selected into a single block template
+type BlockTemplateFeeStats struct {
+	// MinimumFeeRate is the lowest fee rate, in sompi per gram of mass,
+	// among the selected transactions. It is 0 if none were selected.
+	MinimumFeeRate float64
+
+	// IsFull is true if some of the candidate transactions did not make
+	// it into the template because the maximum block mass was reached.
+	// Candidates that were left out for other reasons don't count.
+	IsFull bool
 }
@@ -8,13 +8,59 @@ import (
 // are intended to be mined into new blocks
 type Mempool interface {
 	HandleNewBlockTransactions(txs []*externalapi.DomainTransaction) ([]*externalapi.DomainTransaction, error)
-	BlockCandidateTransactions() []*externalapi.DomainTransaction
+	BlockCandidateTransactions() []*BlockCandidateTransaction
 	ValidateAndInsertTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool, allowOrphan bool) (
 		acceptedTransactions []*externalapi.DomainTransaction, err error)
+	ValidateAndReplaceTransaction(transaction *externalapi.DomainTransaction, isHighPriority bool) (
+		acceptedTransactions []*externalapi.DomainTransaction, replacedTransactions []*externalapi.DomainTransaction, err error)
 	RemoveTransactions(txs []*externalapi.DomainTransaction, removeRedeemers bool) error
 	GetTransaction(transactionID *externalapi.DomainTransactionID) (*externalapi.DomainTransaction, bool)
 	AllTransactions() []*externalapi.DomainTransaction
+	Snapshot() []*SnapshotTransaction
 	TransactionCount() int
+	Stats() *MempoolStats
 	RevalidateHighPriorityTransactions() (validTransactions []*externalapi.DomainTransaction, err error)
 	IsTransactionOutputDust(output *externalapi.DomainTransactionOutput) bool
+	SetOnMempoolChangedHandler(onMempoolChangedHandler OnMempoolChangedHandler)
+	UnconfirmedUTXOs(scriptPublicKeys []*externalapi.ScriptPublicKey,
+		confirmedUTXOs map[externalapi.DomainOutpoint]externalapi.UTXOEntry) *UnconfirmedUTXOs
+}
+
+// BlockCandidateTransaction is a mempool transaction that may be included in a block template,
+// along with the total fee and mass of the highest fee rate package of mempool transactions
+// that requires it to be mined. A package is made of a transaction and all of its ancestors
+// in the mempool, so a high fee transaction may raise the priority of its low fee ancestors.
+// Only small packages with few descendants are considered, to bound the cost of computing them.
+type BlockCandidateTransaction struct {
+	Transaction *externalapi.DomainTransaction
+	PackageFee  uint64
+	PackageMass uint64
+}
+
+// SnapshotTransaction is a transaction in a snapshot of the mempool, along with
+// whether it had been inserted into the mempool as a high priority transaction
+// and whether it is an orphan
+type SnapshotTransaction struct {
+	Transaction    *externalapi.DomainTransaction
+	IsHighPriority bool
+	IsOrphan       bool
+}
+
+// MempoolStats are statistics about the contents of the mempool
+type MempoolStats struct {
+	TransactionCount int
+	TotalMass        uint64
+	OrphanCount      int
+}
+
+// UnconfirmedUTXOs are the changes that the transactions in the mempool make to the
+// UTXOs of some script public keys
+type UnconfirmedUTXOs struct {
+	// Added are the outputs of mempool transactions that pay to the script public
+	// keys and that aren't spent by other mempool transactions
+	Added map[externalapi.DomainOutpoint]externalapi.UTXOEntry
+
+	// Spent are the confirmed UTXOs of the script public keys that are spent by
+	// mempool transactions
+	Spent map[externalapi.DomainOutpoint]externalapi.UTXOEntry
 }
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+// MempoolChangeType is the type of a change in the mempool
+type MempoolChangeType int
+
+const (
+	// MempoolChangeAdded means that the transaction was inserted into the mempool,
+	// either as a regular transaction or as an orphan
+	MempoolChangeAdded MempoolChangeType = iota
+
+	// MempoolChangeRemoved means that the transaction was removed from the mempool
+	MempoolChangeRemoved
+
+	// MempoolChangeUnorphaned means that the transaction was an orphan and had its
+	// missing parents arrive, so it was promoted into the regular mempool
+	MempoolChangeUnorphaned
+)
+
+var mempoolChangeTypeStrings = map[MempoolChangeType]string{
+	MempoolChangeAdded:      "added",
+	MempoolChangeRemoved:    "removed",
+	MempoolChangeUnorphaned: "unorphaned",
+}
+
+func (changeType MempoolChangeType) String() string {
+	return mempoolChangeTypeStrings[changeType]
+}
+
+// MempoolRemovalReason is the reason a transaction was removed from the mempool
+type MempoolRemovalReason int
+
+const (
+	// MempoolRemovalReasonIncludedInBlock means that the transaction was included in a block
+	MempoolRemovalReasonIncludedInBlock MempoolRemovalReason = iota
+
+	// MempoolRemovalReasonDoubleSpend means that a block included a transaction that spends
+	// one of the outputs this transaction spends, or that one of its mempool ancestors was
+	// removed for that reason
+	MempoolRemovalReasonDoubleSpend
+
+	// MempoolRemovalReasonReplaced means that the transaction, or one of its mempool
+	// ancestors, was replaced by a transaction that pays a higher fee
+	MempoolRemovalReasonReplaced
+
+	// MempoolRemovalReasonExpired means that the transaction, or one of its mempool
+	// ancestors, stayed in the mempool for too long
+	MempoolRemovalReasonExpired
+
+	// MempoolRemovalReasonLowFee means that the mempool was full and the transaction, or
+	// one of its mempool ancestors, had the lowest fee rate in it. Orphans evicted when
+	// the orphan pool is full are removed for this reason as well.
+	MempoolRemovalReasonLowFee
+
+	// MempoolRemovalReasonInvalid means that the transaction became invalid, e.g. since
+	// one of its inputs no longer exists
+	MempoolRemovalReasonInvalid
+)
+
+var mempoolRemovalReasonStrings = map[MempoolRemovalReason]string{
+	MempoolRemovalReasonIncludedInBlock: "includedInBlock",
+	MempoolRemovalReasonDoubleSpend:     "doubleSpend",
+	MempoolRemovalReasonReplaced:        "replaced",
+	MempoolRemovalReasonExpired:         "expired",
+	MempoolRemovalReasonLowFee:          "lowFee",
+	MempoolRemovalReasonInvalid:         "invalid",
+}
+
+func (reason MempoolRemovalReason) String() string {
+	return mempoolRemovalReasonStrings[reason]
+}
+
+// MempoolChange is a single change in the contents of the mempool
+type MempoolChange struct {
+	Type        MempoolChangeType
+	Transaction *externalapi.DomainTransaction
+
+	// IsOrphan is whether the transaction was added to or removed from the orphan pool
+	IsOrphan bool
+
+	// RemovalReason is set only for changes of type MempoolChangeRemoved
+	RemovalReason MempoolRemovalReason
+}
+
+// OnMempoolChangedHandler is a handler function that's triggered with the changes in the
+// mempool after every operation that changed it. It's called with the changes of one
+// operation at a time, in the order the operations were made, and must not modify the mempool
+type OnMempoolChangedHandler func(changes []*MempoolChange)
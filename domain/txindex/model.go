@@ -0,0 +1,23 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+// TxAcceptance describes where a transaction was accepted into the
+// virtual selected parent chain
+type TxAcceptance struct {
+	// AcceptingBlockHash is the hash of the chain block whose merge set
+	// accepted the transaction
+	AcceptingBlockHash *externalapi.DomainHash
+
+	// IncludingBlockHash is the hash of the block whose transaction was
+	// the one that got accepted
+	IncludingBlockHash *externalapi.DomainHash
+}
+
+// TxAcceptances is a map between transaction IDs to their acceptance data
+type TxAcceptances map[externalapi.DomainTransactionID]*TxAcceptance
+
+// TxInclusions is a map between transaction IDs to the set of block hashes that include them
+type TxInclusions map[externalapi.DomainTransactionID]map[externalapi.DomainHash]struct{}
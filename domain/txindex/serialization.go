@@ -0,0 +1,37 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+)
+
+func serializeTxAcceptance(txAcceptance *TxAcceptance) []byte {
+	serializedTxAcceptance := make([]byte, 2*externalapi.DomainHashSize)
+	copy(serializedTxAcceptance[:externalapi.DomainHashSize], txAcceptance.AcceptingBlockHash.ByteSlice())
+	copy(serializedTxAcceptance[externalapi.DomainHashSize:], txAcceptance.IncludingBlockHash.ByteSlice())
+	return serializedTxAcceptance
+}
+
+func deserializeTxAcceptance(serializedTxAcceptance []byte) (*TxAcceptance, error) {
+	if len(serializedTxAcceptance) != 2*externalapi.DomainHashSize {
+		return nil, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing "+
+			"transaction acceptance", len(serializedTxAcceptance))
+	}
+
+	acceptingBlockHash, err := externalapi.NewDomainHashFromByteSlice(
+		serializedTxAcceptance[:externalapi.DomainHashSize])
+	if err != nil {
+		return nil, err
+	}
+	includingBlockHash, err := externalapi.NewDomainHashFromByteSlice(
+		serializedTxAcceptance[externalapi.DomainHashSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &TxAcceptance{
+		AcceptingBlockHash: acceptingBlockHash,
+		IncludingBlockHash: includingBlockHash,
+	}, nil
+}
@@ -0,0 +1,43 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/pkg/errors"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func Test_serializeTxAcceptance(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 32; i++ {
+		var acceptingBlockHashBytes, includingBlockHashBytes [externalapi.DomainHashSize]byte
+		r.Read(acceptingBlockHashBytes[:])
+		r.Read(includingBlockHashBytes[:])
+		txAcceptance := &TxAcceptance{
+			AcceptingBlockHash: externalapi.NewDomainHashFromByteArray(&acceptingBlockHashBytes),
+			IncludingBlockHash: externalapi.NewDomainHashFromByteArray(&includingBlockHashBytes),
+		}
+		result, err := deserializeTxAcceptance(serializeTxAcceptance(txAcceptance))
+		if err != nil {
+			t.Fatalf("Failed deserializing transaction acceptance: %v", err)
+		}
+		if !result.AcceptingBlockHash.Equal(txAcceptance.AcceptingBlockHash) ||
+			!result.IncludingBlockHash.Equal(txAcceptance.IncludingBlockHash) {
+			t.Fatalf("Expected \n %+v \n==\n %+v\n", txAcceptance, result)
+		}
+	}
+}
+
+func Test_deserializeTxAcceptanceFailure(t *testing.T) {
+	txAcceptance := &TxAcceptance{
+		AcceptingBlockHash: externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{1}),
+		IncludingBlockHash: externalapi.NewDomainHashFromByteArray(&[externalapi.DomainHashSize]byte{2}),
+	}
+	serialized := serializeTxAcceptance(txAcceptance)
+	_, err := deserializeTxAcceptance(serialized[:len(serialized)-1])
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("Expected error to be EOF, instead got: %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/database/binaryserialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+var txIndexBucket = database.MakeBucket([]byte("tx-index"))
+var txAcceptancesBucket = txIndexBucket.Bucket([]byte("acceptances"))
+var txInclusionsBucket = txIndexBucket.Bucket([]byte("inclusions"))
+var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("tx-index-virtual-parents"))
+
+type txIndexStore struct {
+	database            database.Database
+	toAddAcceptances    TxAcceptances
+	toRemoveAcceptances map[externalapi.DomainTransactionID]struct{}
+	toAddInclusions     TxInclusions
+	virtualParents      []*externalapi.DomainHash
+}
+
+func newTXIndexStore(database database.Database) *txIndexStore {
+	return &txIndexStore{
+		database:            database,
+		toAddAcceptances:    make(TxAcceptances),
+		toRemoveAcceptances: make(map[externalapi.DomainTransactionID]struct{}),
+		toAddInclusions:     make(TxInclusions),
+	}
+}
+
+func (tis *txIndexStore) addAcceptance(transactionID *externalapi.DomainTransactionID, txAcceptance *TxAcceptance) {
+	log.Tracef("Adding acceptance of transaction %s by block %s", transactionID, txAcceptance.AcceptingBlockHash)
+
+	// Acceptances are always removed before they're added, so an acceptance
+	// that's staged both for removal and for addition is simply overwritten
+	// on commit
+	tis.toAddAcceptances[*transactionID] = txAcceptance
+}
+
+func (tis *txIndexStore) removeAcceptance(transactionID *externalapi.DomainTransactionID) {
+	log.Tracef("Removing acceptance of transaction %s", transactionID)
+
+	delete(tis.toAddAcceptances, *transactionID)
+	tis.toRemoveAcceptances[*transactionID] = struct{}{}
+}
+
+func (tis *txIndexStore) addInclusion(transactionID *externalapi.DomainTransactionID, blockHash *externalapi.DomainHash) {
+	log.Tracef("Adding inclusion of transaction %s in block %s", transactionID, blockHash)
+
+	if _, ok := tis.toAddInclusions[*transactionID]; !ok {
+		tis.toAddInclusions[*transactionID] = make(map[externalapi.DomainHash]struct{})
+	}
+	tis.toAddInclusions[*transactionID][*blockHash] = struct{}{}
+}
+
+func (tis *txIndexStore) updateVirtualParents(virtualParents []*externalapi.DomainHash) {
+	tis.virtualParents = virtualParents
+}
+
+func (tis *txIndexStore) discard() {
+	tis.toAddAcceptances = make(TxAcceptances)
+	tis.toRemoveAcceptances = make(map[externalapi.DomainTransactionID]struct{})
+	tis.toAddInclusions = make(TxInclusions)
+	tis.virtualParents = nil
+}
+
+func (tis *txIndexStore) commit() error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "txIndexStore.commit")
+	defer onEnd()
+
+	dbTransaction, err := tis.database.Begin()
+	if err != nil {
+		return err
+	}
+	defer dbTransaction.RollbackUnlessClosed()
+
+	err = tis.commitStagedData(dbTransaction)
+	if err != nil {
+		return err
+	}
+
+	if tis.virtualParents != nil {
+		err = dbTransaction.Put(virtualParentsKey, binaryserialization.SerializeLengthPrefixedHashes(tis.virtualParents))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = dbTransaction.Commit()
+	if err != nil {
+		return err
+	}
+
+	tis.discard()
+	return nil
+}
+
+func (tis *txIndexStore) commitStagedData(dataAccessor database.DataAccessor) error {
+	for transactionID := range tis.toRemoveAcceptances {
+		err := dataAccessor.Delete(tis.acceptanceKey(&transactionID))
+		if err != nil {
+			return err
+		}
+	}
+
+	for transactionID, txAcceptance := range tis.toAddAcceptances {
+		err := dataAccessor.Put(tis.acceptanceKey(&transactionID), serializeTxAcceptance(txAcceptance))
+		if err != nil {
+			return err
+		}
+	}
+
+	for transactionID, blockHashes := range tis.toAddInclusions {
+		bucket := tis.inclusionsBucket(&transactionID)
+		for blockHash := range blockHashes {
+			err := dataAccessor.Put(bucket.Key(blockHash.ByteSlice()), []byte{})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tis *txIndexStore) commitWithoutTransaction() error {
+	err := tis.commitStagedData(tis.database)
+	if err != nil {
+		return err
+	}
+
+	tis.discard()
+	return nil
+}
+
+func (tis *txIndexStore) updateAndCommitVirtualParentsWithoutTransaction(virtualParents []*externalapi.DomainHash) error {
+	serializeParentHashes := binaryserialization.SerializeLengthPrefixedHashes(virtualParents)
+	return tis.database.Put(virtualParentsKey, serializeParentHashes)
+}
+
+func (tis *txIndexStore) acceptanceKey(transactionID *externalapi.DomainTransactionID) *database.Key {
+	return txAcceptancesBucket.Key(transactionID.ByteSlice())
+}
+
+func (tis *txIndexStore) inclusionsBucket(transactionID *externalapi.DomainTransactionID) *database.Bucket {
+	return txInclusionsBucket.Bucket(transactionID.ByteSlice())
+}
+
+func (tis *txIndexStore) isAnythingStaged() bool {
+	return len(tis.toAddAcceptances) > 0 || len(tis.toRemoveAcceptances) > 0 || len(tis.toAddInclusions) > 0
+}
+
+func (tis *txIndexStore) getTxAcceptance(transactionID *externalapi.DomainTransactionID) (*TxAcceptance, bool, error) {
+	if tis.isAnythingStaged() {
+		return nil, false, errors.Errorf("cannot get transaction acceptance while staging isn't empty")
+	}
+
+	serializedTxAcceptance, err := tis.database.Get(tis.acceptanceKey(transactionID))
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	txAcceptance, err := deserializeTxAcceptance(serializedTxAcceptance)
+	if err != nil {
+		return nil, false, err
+	}
+	return txAcceptance, true, nil
+}
+
+func (tis *txIndexStore) getTxInclusions(transactionID *externalapi.DomainTransactionID) ([]*externalapi.DomainHash, error) {
+	if tis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get transaction inclusions while staging isn't empty")
+	}
+
+	cursor, err := tis.database.Cursor(tis.inclusionsBucket(transactionID))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var blockHashes []*externalapi.DomainHash
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		blockHash, err := externalapi.NewDomainHashFromByteSlice(key.Suffix())
+		if err != nil {
+			return nil, err
+		}
+		blockHashes = append(blockHashes, blockHash)
+	}
+	return blockHashes, nil
+}
+
+func (tis *txIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
+	if tis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get the virtual parents while staging isn't empty")
+	}
+
+	serializedHashes, err := tis.database.Get(virtualParentsKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return binaryserialization.DeserializeLengthPrefixedHashes(serializedHashes)
+}
+
+func (tis *txIndexStore) deleteAll() error {
+	// First we delete the virtual parents, so if anything goes wrong, the TX index will be marked as "not synced"
+	// and will be reset.
+	err := tis.database.Delete(virtualParentsKey)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := tis.database.Cursor(txIndexBucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	for cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+
+		err = tis.database.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
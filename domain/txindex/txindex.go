@@ -0,0 +1,228 @@
+package txindex
+
+import (
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"sync"
+)
+
+// TXIndex maintains an index between transaction IDs and the
+// blocks that include and accept them
+type TXIndex struct {
+	domain domain.Domain
+	store  *txIndexStore
+
+	mutex sync.Mutex
+}
+
+// New creates a new TX index.
+//
+// NOTE: While this is called no new blocks can be added to the consensus.
+func New(domain domain.Domain, database database.Database) (*TXIndex, error) {
+	txIndex := &TXIndex{
+		domain: domain,
+		store:  newTXIndexStore(database),
+	}
+
+	isSynced, err := txIndex.isSynced()
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSynced {
+		err = txIndex.Reset()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return txIndex, nil
+}
+
+// Reset deletes the whole TX index and resyncs it from consensus,
+// starting from the current pruning point.
+func (ti *TXIndex) Reset() error {
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	err := ti.reset()
+	if err != nil {
+		// Staged data is left behind when an error occurs mid-way. Discard it,
+		// since the store refuses to be queried while anything is staged
+		ti.store.discard()
+		return err
+	}
+	return nil
+}
+
+func (ti *TXIndex) reset() error {
+	err := ti.store.deleteAll()
+	if err != nil {
+		return err
+	}
+
+	virtualInfo, err := ti.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+
+	pruningPoint, err := ti.domain.Consensus().PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	chainPath, err := ti.domain.Consensus().GetVirtualSelectedParentChainFromBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+
+	const step = 1000
+	for i, chainBlockHash := range chainPath.Added {
+		err = ti.addChainBlock(chainBlockHash)
+		if err != nil {
+			return err
+		}
+
+		if (i+1)%step == 0 {
+			err = ti.store.commitWithoutTransaction()
+			if err != nil {
+				return err
+			}
+			log.Infof("Indexed transactions of %d out of %d chain blocks", i+1, len(chainPath.Added))
+		}
+	}
+
+	err = ti.store.commitWithoutTransaction()
+	if err != nil {
+		return err
+	}
+
+	// This has to be done last to mark that the reset went smoothly and no reset has to be called next time.
+	return ti.store.updateAndCommitVirtualParentsWithoutTransaction(virtualInfo.ParentHashes)
+}
+
+func (ti *TXIndex) isSynced() (bool, error) {
+	txIndexVirtualParents, err := ti.store.getVirtualParents()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	virtualInfo, err := ti.domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return false, err
+	}
+
+	return externalapi.HashesEqual(virtualInfo.ParentHashes, txIndexVirtualParents), nil
+}
+
+// Update updates the TX index with the given DAG selected parent chain changes
+func (ti *TXIndex) Update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.Update")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	err := ti.update(virtualChangeSet)
+	if err != nil {
+		ti.store.discard()
+		return err
+	}
+	return nil
+}
+
+func (ti *TXIndex) update(virtualChangeSet *externalapi.VirtualChangeSet) error {
+	log.Tracef("Updating TX index with VirtualSelectedParentChainChanges: %+v",
+		virtualChangeSet.VirtualSelectedParentChainChanges)
+
+	for _, removedChainBlockHash := range virtualChangeSet.VirtualSelectedParentChainChanges.Removed {
+		err := ti.removeChainBlock(removedChainBlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, addedChainBlockHash := range virtualChangeSet.VirtualSelectedParentChainChanges.Added {
+		err := ti.addChainBlock(addedChainBlockHash)
+		if err != nil {
+			return err
+		}
+	}
+
+	ti.store.updateVirtualParents(virtualChangeSet.VirtualParents)
+
+	return ti.store.commit()
+}
+
+func (ti *TXIndex) addChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	acceptanceData, err := ti.domain.Consensus().GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			transactionID := consensushashing.TransactionID(transactionAcceptanceData.Transaction)
+			ti.store.addInclusion(transactionID, blockAcceptanceData.BlockHash)
+
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			ti.store.addAcceptance(transactionID, &TxAcceptance{
+				AcceptingBlockHash: chainBlockHash,
+				IncludingBlockHash: blockAcceptanceData.BlockHash,
+			})
+		}
+	}
+	return nil
+}
+
+func (ti *TXIndex) removeChainBlock(chainBlockHash *externalapi.DomainHash) error {
+	acceptanceData, err := ti.domain.Consensus().GetBlockAcceptanceData(chainBlockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, blockAcceptanceData := range acceptanceData {
+		for _, transactionAcceptanceData := range blockAcceptanceData.TransactionAcceptanceData {
+			if !transactionAcceptanceData.IsAccepted {
+				continue
+			}
+			transactionID := consensushashing.TransactionID(transactionAcceptanceData.Transaction)
+			ti.store.removeAcceptance(transactionID)
+		}
+	}
+	return nil
+}
+
+// TXAcceptance returns where the transaction with the given ID was accepted into
+// the virtual selected parent chain. The returned boolean is false if the
+// transaction is not accepted by any chain block.
+func (ti *TXIndex) TXAcceptance(transactionID *externalapi.DomainTransactionID) (*TxAcceptance, bool, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.TXAcceptance")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	return ti.store.getTxAcceptance(transactionID)
+}
+
+// TXInclusions returns the hashes of all the blocks known to include the
+// transaction with the given ID. Only blocks that were merged by the virtual
+// selected parent chain are known to the index.
+func (ti *TXIndex) TXInclusions(transactionID *externalapi.DomainTransactionID) ([]*externalapi.DomainHash, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "TXIndex.TXInclusions")
+	defer onEnd()
+
+	ti.mutex.Lock()
+	defer ti.mutex.Unlock()
+
+	return ti.store.getTxInclusions(transactionID)
+}
@@ -2,6 +2,7 @@ package utxoindex
 
 import (
 	"encoding/binary"
+	"github.com/kaspanet/kaspad/domain/consensus/database/binaryserialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
@@ -149,7 +150,7 @@ func (uis *utxoIndexStore) commit() error {
 		}
 	}
 
-	serializeParentHashes := serializeHashes(uis.virtualParents)
+	serializeParentHashes := binaryserialization.SerializeLengthPrefixedHashes(uis.virtualParents)
 	err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
 	if err != nil {
 		return err
@@ -185,7 +186,7 @@ func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*
 }
 
 func (uis *utxoIndexStore) updateAndCommitVirtualParentsWithoutTransaction(virtualParents []*externalapi.DomainHash) error {
-	serializeParentHashes := serializeHashes(virtualParents)
+	serializeParentHashes := binaryserialization.SerializeLengthPrefixedHashes(virtualParents)
 	return uis.database.Put(virtualParentsKey, serializeParentHashes)
 }
 
@@ -283,7 +284,7 @@ func (uis *utxoIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error
 		return nil, err
 	}
 
-	return deserializeHashes(serializedHashes)
+	return binaryserialization.DeserializeLengthPrefixedHashes(serializedHashes)
 }
 
 func (uis *utxoIndexStore) deleteAll() error {
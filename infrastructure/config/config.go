@@ -20,6 +20,7 @@ import (
 	"github.com/jessevdk/go-flags"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/backend"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
 	"github.com/kaspanet/kaspad/util"
 	"github.com/kaspanet/kaspad/util/network"
@@ -30,6 +31,7 @@ import (
 const (
 	defaultConfigFilename      = "kaspad.conf"
 	defaultLogLevel            = "info"
+	defaultLogFormat           = "text"
 	defaultLogDirname          = "logs"
 	defaultLogFilename         = "kaspad.log"
 	defaultErrLogFilename      = "kaspad_err.log"
@@ -39,7 +41,7 @@ const (
 	defaultBanThreshold        = 100
 	//DefaultConnectTimeout is the default connection timeout when dialing
 	DefaultConnectTimeout        = time.Second * 30
-	defaultMaxRPCClients         = 10
+	defaultMaxRPCClients         = 128 // The limit the RPC server had hardcoded before --rpcmaxclients was enforced
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
 	defaultBlockMaxMass          = 10_000_000
@@ -51,6 +53,7 @@ const (
 	DefaultMaxOrphanTxSize  = 100_000
 	defaultSigCacheMaxSize  = 100_000
 	sampleConfigFilename    = "sample-kaspad.conf"
+	mempoolFilename         = "mempool.dat"
 	defaultMaxUTXOCacheSize = 5_000_000_000
 	defaultProtocolVersion  = 5
 )
@@ -91,11 +94,26 @@ type Flags struct {
 	BanThreshold                    uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
 	Whitelists                      []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
 	RPCListeners                    []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 16110, testnet: 16210)"`
+	RPCJSONListeners                []string      `long:"rpcjsonlisten" description:"Add an interface/port to serve JSON-RPC over HTTP and WebSocket on. The JSON-RPC server is disabled unless one is specified"`
+	RPCJSONAllowedOrigins           []string      `long:"rpcjsonallowedorigin" description:"Add an origin of web pages that may call the JSON-RPC server (eg. https://explorer.example.com) -- Use * to allow any origin"`
 	RPCCert                         string        `long:"rpccert" description:"File containing the certificate file"`
 	RPCKey                          string        `long:"rpckey" description:"File containing the certificate key"`
+	RPCTLS                          bool          `long:"rpctls" description:"Serve RPC over TLS using --rpccert and --rpckey. A self-signed certificate is generated if they don't exist"`
+	RPCUser                         string        `long:"rpcuser" description:"Username for RPC connections with admin permissions"`
+	RPCPass                         string        `long:"rpcpass" default-mask:"-" description:"Password for RPC connections with admin permissions"`
+	RPCAuthToken                    string        `long:"rpcauthtoken" default-mask:"-" description:"Token for RPC connections with admin permissions"`
+	RPCSubmitUser                   string        `long:"rpcsubmituser" description:"Username for RPC connections with read-only permissions that may also submit blocks and transactions"`
+	RPCSubmitPass                   string        `long:"rpcsubmitpass" default-mask:"-" description:"Password for RPC connections with read-only permissions that may also submit blocks and transactions"`
+	RPCSubmitAuthToken              string        `long:"rpcsubmitauthtoken" default-mask:"-" description:"Token for RPC connections with read-only permissions that may also submit blocks and transactions"`
+	RPCReadOnlyUser                 string        `long:"rpcreadonlyuser" description:"Username for RPC connections with read-only permissions"`
+	RPCReadOnlyPass                 string        `long:"rpcreadonlypass" default-mask:"-" description:"Password for RPC connections with read-only permissions"`
+	RPCReadOnlyAuthToken            string        `long:"rpcreadonlyauthtoken" default-mask:"-" description:"Token for RPC connections with read-only permissions"`
 	RPCMaxClients                   int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWebsockets                int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
-	RPCMaxConcurrentReqs            int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCMaxConcurrentReqs            int           `long:"rpcmaxconcurrentreqs" description:"Max number of RPC requests that may be processed concurrently, not counting GetBlockTemplate and SubmitBlock -- 0 disables the limit"`
+	RPCRateLimit                    float64       `long:"rpcratelimit" description:"Max total cost of the RPC requests a single client IP may make per second, where most commands cost 1 -- 0 disables rate limiting"`
+	RPCRateBurst                    float64       `long:"rpcrateburst" description:"Max total cost of the RPC requests a single client IP may make in a burst (default: 20 seconds' worth of rpcratelimit)"`
+	RPCCommandCosts                 []string      `long:"rpccommandcost" description:"Set the rate limiting cost of an RPC command, eg. GetBlocks=20 -- May be specified multiple times"`
 	DisableRPC                      bool          `long:"norpc" description:"Disable built-in RPC server"`
 	DisableDNSSeed                  bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
 	DNSSeed                         string        `long:"dnsseed" description:"Override DNS seeds with specified hostname (Only 1 hostname allowed)"`
@@ -104,12 +122,20 @@ type Flags struct {
 	Proxy                           string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
 	ProxyUser                       string        `long:"proxyuser" description:"Username for proxy server"`
 	ProxyPass                       string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
-	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
+	OnionProxy                      string        `long:"onion" description:"Connect to tor hidden services via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
+	OnionProxyUser                  string        `long:"onionuser" description:"Username for onion proxy server"`
+	OnionProxyPass                  string        `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
+	NoOnion                         bool          `long:"noonion" description:"Disable connecting to tor hidden services"`
+	DbType                          string        `long:"dbtype" description:"Database backend to use for the Block DAG {leveldb, bbolt} -- Defaults to the backend of the existing database, or leveldb for a new one"`
+	BoltNoSync                      bool          `long:"bboltnosync" description:"Don't fsync the commits of a bbolt database. Speeds up writing, but a crash of kaspad or of the machine may corrupt the database"`
 	Profile                         string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	Metrics                         string        `long:"metrics" description:"Serve Prometheus metrics over HTTP on the given port or host:port. Binds to localhost if no host is given. The endpoint is unauthenticated (eg. 9464)"`
 	LogLevel                        string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	Upnp                            bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	LogFormat                       string        `long:"logformat" description:"Format of the log lines {text, json} -- json writes every line as a JSON object with the time, level, subsystem, message and fields"`
+	Upnp                            bool          `long:"upnp" description:"Use UPnP or NAT-PMP to map our listening port outside of NAT"`
 	MinRelayTxFee                   float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
 	MaxOrphanTxs                    uint64        `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	NoPersistMempool                bool          `long:"nopersistmempool" description:"Don't save the mempool to disk on shutdown and don't load it back on startup"`
 	BlockMaxMass                    uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
 	UserAgentComments               []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
 	NoPeerBloomFilters              bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
@@ -118,8 +144,12 @@ type Flags struct {
 	RelayNonStd                     bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
 	RejectNonStd                    bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	ResetDatabase                   bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
+	ExportSnapshot                  string        `long:"export-snapshot" description:"Write a snapshot of the pruning point, its anticone and its future to the given file, then exit"`
+	ImportSnapshot                  string        `long:"import-snapshot" description:"Bootstrap the node from a snapshot file written by --export-snapshot instead of syncing the pruning point from peers"`
 	MaxUTXOCacheSize                uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
 	UTXOIndex                       bool          `long:"utxoindex" description:"Enable the UTXO index"`
+	TXIndex                         bool          `long:"txindex" description:"Enable the transaction index"`
+	AddressHistoryIndex             bool          `long:"addresshistoryindex" description:"Enable the address history index"`
 	IsArchivalNode                  bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
 	AllowSubmitBlockWhenNotSynced   bool          `long:"allow-submit-block-when-not-synced" hidden:"true" description:"Allow the node to accept blocks from RPC while not synced (this flag is mainly used for testing)"`
 	EnableSanityCheckPruningUTXOSet bool          `long:"enable-sanity-check-pruning-utxo" hidden:"true" description:"When moving the pruning point - check that the utxo set matches the utxo commitment"`
@@ -135,10 +165,85 @@ type Config struct {
 	*Flags
 	Lookup        func(string) ([]net.IP, error)
 	Dial          func(string, string, time.Duration) (net.Conn, error)
+	OnionDial     func(string, string, time.Duration) (net.Conn, error) // nil if onion services are unreachable
 	MiningAddrs   []util.Address
 	MinRelayTxFee util.Amount
 	Whitelists    []*net.IPNet
 	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+
+	// RPCCommandCostOverrides are the rate limiting costs set with --rpccommandcost, by command name
+	RPCCommandCostOverrides map[string]float64
+}
+
+// MempoolFilePath returns the path of the file the mempool is saved to on shutdown
+// and loaded from on startup
+func (cfg *Config) MempoolFilePath() string {
+	return filepath.Join(cfg.AppDir, mempoolFilename)
+}
+
+// IsRPCAuthenticationEnabled returns whether RPC clients are required to authenticate.
+// This is the case if any RPC credentials are configured
+func (cfg *Config) IsRPCAuthenticationEnabled() bool {
+	return cfg.RPCUser != "" || cfg.RPCAuthToken != "" ||
+		cfg.RPCSubmitUser != "" || cfg.RPCSubmitAuthToken != "" ||
+		cfg.RPCReadOnlyUser != "" || cfg.RPCReadOnlyAuthToken != ""
+}
+
+// validateRPCRateLimit makes sure that the RPC rate limiting options are valid, and
+// parses the command costs of --rpccommandcost into cfg.RPCCommandCostOverrides
+func validateRPCRateLimit(cfg *Config) error {
+	if cfg.RPCRateLimit < 0 {
+		return errors.Errorf("the rpcratelimit option may not be less than 0 -- parsed [%f]", cfg.RPCRateLimit)
+	}
+	if cfg.RPCRateBurst < 0 {
+		return errors.Errorf("the rpcrateburst option may not be less than 0 -- parsed [%f]", cfg.RPCRateBurst)
+	}
+
+	cfg.RPCCommandCostOverrides = make(map[string]float64, len(cfg.RPCCommandCosts))
+	for _, commandCost := range cfg.RPCCommandCosts {
+		command, costString, ok := strings.Cut(commandCost, "=")
+		if !ok || command == "" {
+			return errors.Errorf("the rpccommandcost option must be in the form <command>=<cost> -- parsed [%s]",
+				commandCost)
+		}
+		cost, err := strconv.ParseFloat(costString, 64)
+		if err != nil || cost <= 0 {
+			return errors.Errorf("the cost in the rpccommandcost option must be a positive number -- parsed [%s]",
+				commandCost)
+		}
+		cfg.RPCCommandCostOverrides[command] = cost
+	}
+	return nil
+}
+
+// validateRPCAuthentication makes sure that the configured RPC credentials are
+// complete, and that every credential identifies a single role
+func validateRPCAuthentication(cfg *Config) error {
+	if (cfg.RPCUser == "") != (cfg.RPCPass == "") {
+		return errors.New("--rpcuser and --rpcpass must be specified together")
+	}
+	if (cfg.RPCSubmitUser == "") != (cfg.RPCSubmitPass == "") {
+		return errors.New("--rpcsubmituser and --rpcsubmitpass must be specified together")
+	}
+	if (cfg.RPCReadOnlyUser == "") != (cfg.RPCReadOnlyPass == "") {
+		return errors.New("--rpcreadonlyuser and --rpcreadonlypass must be specified together")
+	}
+
+	users := []struct{ option, value string }{
+		{"--rpcuser", cfg.RPCUser}, {"--rpcsubmituser", cfg.RPCSubmitUser}, {"--rpcreadonlyuser", cfg.RPCReadOnlyUser}}
+	tokens := []struct{ option, value string }{
+		{"--rpcauthtoken", cfg.RPCAuthToken}, {"--rpcsubmitauthtoken", cfg.RPCSubmitAuthToken},
+		{"--rpcreadonlyauthtoken", cfg.RPCReadOnlyAuthToken}}
+	for _, credentials := range [][]struct{ option, value string }{users, tokens} {
+		for i := range credentials {
+			for j := i + 1; j < len(credentials); j++ {
+				if credentials[i].value != "" && credentials[i].value == credentials[j].value {
+					return errors.Errorf("%s and %s must be different", credentials[i].option, credentials[j].option)
+				}
+			}
+		}
+	}
+	return nil
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -174,6 +279,7 @@ func defaultFlags() *Flags {
 	return &Flags{
 		ConfigFile:           defaultConfigFile,
 		LogLevel:             defaultLogLevel,
+		LogFormat:            defaultLogFormat,
 		TargetOutboundPeers:  defaultTargetOutboundPeers,
 		MaxInboundPeers:      defaultMaxInboundPeers,
 		BanDuration:          defaultBanDuration,
@@ -338,6 +444,19 @@ func LoadConfig() (*Config, error) {
 		os.Exit(0)
 	}
 
+	logFormat, ok := logger.FormatFromString(cfg.LogFormat)
+	if !ok {
+		str := "%s: Invalid log format: %s"
+		err := errors.Errorf(str, funcName, cfg.LogFormat)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	err = logger.BackendLog.SetFormat(logFormat)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize log rotation. After log rotation has been initialized, the
 	// logger variables may be used.
 	logger.InitLog(filepath.Join(cfg.LogDir, defaultLogFilename), filepath.Join(cfg.LogDir, defaultErrLogFilename))
@@ -362,6 +481,51 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Validate the database type
+	if cfg.DbType != "" && !backend.IsValidType(cfg.DbType) {
+		str := "%s: The dbtype option must be one of {%s} -- parsed [%s]"
+		err := errors.Errorf(str, funcName, strings.Join(backend.Types, ", "), cfg.DbType)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// --export-snapshot and --import-snapshot contradict each other.
+	if cfg.ExportSnapshot != "" && cfg.ImportSnapshot != "" {
+		str := "%s: the --export-snapshot and --import-snapshot options may not be activated at the same time"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.ExportSnapshot != "" {
+		cfg.ExportSnapshot = cleanAndExpandPath(cfg.ExportSnapshot)
+	}
+	if cfg.ImportSnapshot != "" {
+		cfg.ImportSnapshot = cleanAndExpandPath(cfg.ImportSnapshot)
+	}
+
+	// Validate the metrics listen address. The metrics endpoint is unauthenticated,
+	// so it only listens on localhost unless a host is explicitly given
+	if cfg.Metrics != "" {
+		host, port, err := net.SplitHostPort(cfg.Metrics)
+		if err != nil {
+			host, port = "", cfg.Metrics
+		}
+		_, err = strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			str := "%s: The metrics option must be a port or a host:port address -- parsed [%s]"
+			err := errors.Errorf(str, funcName, cfg.Metrics)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		if host == "" {
+			host = "127.0.0.1"
+		}
+		cfg.Metrics = net.JoinHostPort(host, port)
+	}
+
 	// Don't allow ban durations that are too short.
 	if cfg.BanDuration < time.Second {
 		str := "%s: The banduration option may not be less than 1s -- parsed [%s]"
@@ -436,6 +600,7 @@ func LoadConfig() (*Config, error) {
 
 	if cfg.DisableRPC {
 		log.Infof("RPC service is disabled")
+		cfg.RPCJSONListeners = nil
 	}
 
 	// Add the default RPC listener if none were specified. The default
@@ -447,6 +612,30 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	if cfg.RPCMaxWebsockets < 0 {
+		str := "%s: The rpcmaxwebsockets option may not be less than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.RPCMaxWebsockets)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.RPCMaxClients < 0 {
+		str := "%s: The rpcmaxclients option may not be less than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.RPCMaxClients)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	err = validateRPCRateLimit(cfg)
+	if err != nil {
+		err := errors.Errorf("%s: %s", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	if cfg.RPCMaxConcurrentReqs < 0 {
 		str := "%s: The rpcmaxwebsocketconcurrentrequests option may " +
 			"not be less than 0 -- parsed [%d]"
@@ -456,6 +645,18 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	err = validateRPCAuthentication(cfg)
+	if err != nil {
+		err := errors.Errorf("%s: %s", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	if cfg.RPCTLS {
+		cfg.RPCCert = cleanAndExpandPath(cfg.RPCCert)
+		cfg.RPCKey = cleanAndExpandPath(cfg.RPCKey)
+	}
+
 	// Validate the the minrelaytxfee.
 	cfg.MinRelayTxFee, err = util.NewAmount(cfg.Flags.MinRelayTxFee)
 	if err != nil {
@@ -539,6 +740,15 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// --onion and --noonion contradict each other.
+	if cfg.OnionProxy != "" && cfg.NoOnion {
+		str := "%s: the --onion and --noonion options may not be activated at the same time"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Setup dial and DNS resolution (lookup) functions depending on the
 	// specified options. The default is to use the standard
 	// net.DialTimeout function as well as the system DNS resolver. When a
@@ -562,6 +772,43 @@ func LoadConfig() (*Config, error) {
 			Password: cfg.ProxyPass,
 		}
 		cfg.Dial = proxy.DialTimeout
+
+		// Treat the proxy as tor and perform DNS resolution through it
+		// unless the --noonion flag is set or there is an onion-specific
+		// proxy configured.
+		if !cfg.NoOnion && cfg.OnionProxy == "" {
+			cfg.Lookup = func(host string) ([]net.IP, error) {
+				return torLookupIP(host, cfg.Proxy, cfg.ProxyUser, cfg.ProxyPass)
+			}
+			cfg.OnionDial = proxy.DialTimeout
+		}
+	}
+
+	// Setup onion address dial function depending on the specified options.
+	// The default is to use the same dial function selected above. However,
+	// when an onion-specific proxy is specified, the onion address dial
+	// function is set to use the onion-specific proxy.
+	if cfg.OnionProxy != "" {
+		_, _, err := net.SplitHostPort(cfg.OnionProxy)
+		if err != nil {
+			str := "%s: Onion proxy address '%s' is invalid: %s"
+			err := errors.Errorf(str, funcName, cfg.OnionProxy, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+
+		onionProxy := &socks.Proxy{
+			Addr:     cfg.OnionProxy,
+			Username: cfg.OnionProxyUser,
+			Password: cfg.OnionProxyPass,
+		}
+		cfg.OnionDial = onionProxy.DialTimeout
+	}
+
+	if cfg.IsRPCAuthenticationEnabled() && !cfg.RPCTLS {
+		log.Warnf("RPC authentication is enabled without --rpctls. " +
+			"RPC credentials will be sent unencrypted")
 	}
 
 	// Warn about missing config file only after all other configuration is
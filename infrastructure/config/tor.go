@@ -0,0 +1,160 @@
+package config
+
+import (
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	torSucceeded         = 0x00
+	torGeneralError      = 0x01
+	torNotAllowed        = 0x02
+	torNetUnreachable    = 0x03
+	torHostUnreachable   = 0x04
+	torConnectionRefused = 0x05
+	torTTLExpired        = 0x06
+	torCmdNotSupported   = 0x07
+	torAddrNotSupported  = 0x08
+
+	torATypeIPv4       = 1
+	torATypeDomainName = 3
+	torATypeIPv6       = 4
+
+	torCmdResolve = 240
+
+	torAuthNone             = 0x00
+	torAuthUsernamePassword = 0x02
+	torAuthNoAcceptable     = 0xff
+)
+
+var (
+	errTorInvalidAddressResponse = errors.New("invalid address response")
+	errTorInvalidProxyResponse   = errors.New("invalid proxy response")
+	errTorUnrecognizedAuthMethod = errors.New("invalid proxy authentication method")
+	errTorAuthenticationFailed   = errors.New("proxy authentication failed")
+
+	torStatusErrors = map[byte]error{
+		torSucceeded:         errors.New("tor succeeded"),
+		torGeneralError:      errors.New("tor general error"),
+		torNotAllowed:        errors.New("tor not allowed"),
+		torNetUnreachable:    errors.New("tor network is unreachable"),
+		torHostUnreachable:   errors.New("tor host is unreachable"),
+		torConnectionRefused: errors.New("tor connection refused"),
+		torTTLExpired:        errors.New("tor TTL expired"),
+		torCmdNotSupported:   errors.New("tor command not supported"),
+		torAddrNotSupported:  errors.New("tor address type not supported"),
+	}
+)
+
+// torLookupIP uses Tor's RESOLVE SOCKS extension to resolve the given host
+// through the given proxy, so that DNS requests don't leak outside of Tor
+func torLookupIP(host, proxy, username, password string) ([]net.IP, error) {
+	if len(host) > 255 {
+		return nil, errors.Errorf("host %s is too long to be resolved through tor", host)
+	}
+
+	conn, err := net.Dial("tcp", proxy)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	err = torAuthenticate(conn, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	request := []byte{'\x05', torCmdResolve, '\x00', torATypeDomainName, byte(len(host))}
+	request = append(request, host...)
+	request = append(request, 0, 0) // The port is ignored by RESOLVE
+	_, err = conn.Write(request)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	response := make([]byte, 4)
+	_, err = io.ReadFull(conn, response)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if response[0] != 5 {
+		return nil, errTorInvalidProxyResponse
+	}
+	if response[1] != torSucceeded {
+		statusErr, ok := torStatusErrors[response[1]]
+		if !ok {
+			return nil, errTorInvalidProxyResponse
+		}
+		return nil, statusErr
+	}
+
+	var addressLength int
+	switch response[3] {
+	case torATypeIPv4:
+		addressLength = net.IPv4len
+	case torATypeIPv6:
+		addressLength = net.IPv6len
+	default:
+		return nil, errTorInvalidAddressResponse
+	}
+
+	// The address is followed by a port, which is ignored
+	addressAndPort := make([]byte, addressLength+2)
+	_, err = io.ReadFull(conn, addressAndPort)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return []net.IP{addressAndPort[:addressLength]}, nil
+}
+
+// torAuthenticate performs the SOCKS5 method negotiation, and the
+// username/password sub-negotiation if credentials were given
+func torAuthenticate(conn net.Conn, username, password string) error {
+	method := byte(torAuthNone)
+	if username != "" || password != "" {
+		method = torAuthUsernamePassword
+	}
+	_, err := conn.Write([]byte{'\x05', '\x01', method})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	response := make([]byte, 2)
+	_, err = io.ReadFull(conn, response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if response[0] != 5 {
+		return errTorInvalidProxyResponse
+	}
+	if response[1] == torAuthNoAcceptable || response[1] != method {
+		return errTorUnrecognizedAuthMethod
+	}
+	if method == torAuthNone {
+		return nil
+	}
+
+	if len(username) > 255 || len(password) > 255 {
+		return errors.New("proxy username and password must be at most 255 bytes long")
+	}
+	request := []byte{'\x01', byte(len(username))}
+	request = append(request, username...)
+	request = append(request, byte(len(password)))
+	request = append(request, password...)
+	_, err = conn.Write(request)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = io.ReadFull(conn, response)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if response[1] != torSucceeded {
+		return errTorAuthenticationFailed
+	}
+	return nil
+}
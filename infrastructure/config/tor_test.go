@@ -0,0 +1,133 @@
+package config
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+// serveFakeTorResolve accepts a single connection and answers its RESOLVE request
+// with the given IPv4 address, after checking the given username and password
+func serveFakeTorResolve(t *testing.T, listener net.Listener, username, password string, resolved net.IP) {
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Errorf("Accept: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	methodRequest := make([]byte, 3)
+	_, err = io.ReadFull(conn, methodRequest)
+	if err != nil {
+		t.Errorf("error reading the method request: %s", err)
+		return
+	}
+	_, err = conn.Write([]byte{5, methodRequest[2]})
+	if err != nil {
+		t.Errorf("error writing the method response: %s", err)
+		return
+	}
+	if methodRequest[2] == torAuthUsernamePassword {
+		header := make([]byte, 2)
+		_, err = io.ReadFull(conn, header)
+		if err != nil {
+			t.Errorf("error reading the username length: %s", err)
+			return
+		}
+		gotUsername := make([]byte, header[1])
+		_, err = io.ReadFull(conn, gotUsername)
+		if err != nil {
+			t.Errorf("error reading the username: %s", err)
+			return
+		}
+		passwordLength := make([]byte, 1)
+		_, err = io.ReadFull(conn, passwordLength)
+		if err != nil {
+			t.Errorf("error reading the password length: %s", err)
+			return
+		}
+		gotPassword := make([]byte, passwordLength[0])
+		_, err = io.ReadFull(conn, gotPassword)
+		if err != nil {
+			t.Errorf("error reading the password: %s", err)
+			return
+		}
+		status := byte(torSucceeded)
+		if string(gotUsername) != username || string(gotPassword) != password {
+			status = torGeneralError
+		}
+		_, err = conn.Write([]byte{1, status})
+		if err != nil || status != torSucceeded {
+			return
+		}
+	}
+
+	requestHeader := make([]byte, 5)
+	_, err = io.ReadFull(conn, requestHeader)
+	if err != nil {
+		t.Errorf("error reading the resolve request: %s", err)
+		return
+	}
+	if requestHeader[1] != torCmdResolve {
+		t.Errorf("unexpected command %d", requestHeader[1])
+		return
+	}
+	hostAndPort := make([]byte, int(requestHeader[4])+2)
+	_, err = io.ReadFull(conn, hostAndPort)
+	if err != nil {
+		t.Errorf("error reading the resolved host: %s", err)
+		return
+	}
+
+	response := []byte{5, torSucceeded, 0, torATypeIPv4}
+	response = append(response, resolved.To4()...)
+	response = append(response, 0, 0)
+	_, err = conn.Write(response)
+	if err != nil {
+		t.Errorf("error writing the resolve response: %s", err)
+	}
+}
+
+func TestTorLookupIP(t *testing.T) {
+	resolved := net.ParseIP("1.2.3.4")
+	tests := []struct {
+		name          string
+		username      string
+		password      string
+		expectSuccess bool
+	}{
+		{name: "no authentication", expectSuccess: true},
+		{name: "valid credentials", username: "user", password: "pass", expectSuccess: true},
+		{name: "invalid credentials", username: "user", password: "wrong", expectSuccess: false},
+	}
+
+	for _, test := range tests {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Listen: %s", err)
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			serveFakeTorResolve(t, listener, "user", "pass", resolved)
+		}()
+
+		ips, err := torLookupIP("seeder.example.com", listener.Addr().String(), test.username, test.password)
+		<-done
+		listener.Close()
+
+		if !test.expectSuccess {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: torLookupIP: %s", test.name, err)
+			continue
+		}
+		if len(ips) != 1 || !ips[0].Equal(resolved) {
+			t.Errorf("%s: expected %s, got %v", test.name, resolved, ips)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Package backend opens the database backends that kaspad supports, and
+// detects which backend an existing database directory was created with.
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/boltdb"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LevelDB is the type of the leveldb backend, implemented by the ldb package
+	LevelDB = "leveldb"
+
+	// BoltDB is the type of the bbolt backend, implemented by the boltdb package
+	BoltDB = "bbolt"
+
+	// Default is the type of new databases when no type is requested
+	Default = LevelDB
+)
+
+// Types are the types of all the supported backends
+var Types = []string{LevelDB, BoltDB}
+
+// levelDBCurrentFileName is the name of the file that leveldb keeps in every database directory
+const levelDBCurrentFileName = "CURRENT"
+
+// IsValidType returns whether the given database type is supported
+func IsValidType(dbType string) bool {
+	for _, validType := range Types {
+		if dbType == validType {
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the type of the database in the given directory, or an
+// empty string if the directory doesn't contain a database.
+func Detect(path string) (string, error) {
+	var detectedTypes []string
+	for dbType, fileName := range map[string]string{
+		LevelDB: levelDBCurrentFileName,
+		BoltDB:  boltdb.FileName,
+	} {
+		_, err := os.Stat(filepath.Join(path, fileName))
+		if err == nil {
+			detectedTypes = append(detectedTypes, dbType)
+			continue
+		}
+		if !os.IsNotExist(err) {
+			return "", errors.WithStack(err)
+		}
+	}
+
+	switch len(detectedTypes) {
+	case 0:
+		return "", nil
+	case 1:
+		return detectedTypes[0], nil
+	default:
+		return "", errors.Errorf("the directory %s contains databases of more than one type", path)
+	}
+}
+
+// Open opens the database of the given type in the given directory. If the
+// directory already contains a database, its type must match dbType. An
+// empty dbType opens the existing database whatever its type, or creates a
+// database of the Default type. boltNoSync disables fsyncing the commits of
+// a bbolt database, and is ignored by the other backends.
+func Open(dbType string, path string, cacheSizeMiB int, boltNoSync bool) (database.Database, error) {
+	if dbType != "" && !IsValidType(dbType) {
+		return nil, errors.Errorf("unknown database type %s. Supported types: %s",
+			dbType, strings.Join(Types, ", "))
+	}
+
+	existingType, err := Detect(path)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case dbType == "" && existingType == "":
+		dbType = Default
+	case dbType == "":
+		dbType = existingType
+	case existingType != "" && existingType != dbType:
+		return nil, errors.Errorf("the database in %s is a %s database, but the database type is %s. "+
+			"Use the kaspadb tool to migrate it", path, existingType, dbType)
+	}
+
+	switch dbType {
+	case LevelDB:
+		db, err := ldb.NewLevelDB(path, cacheSizeMiB)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case BoltDB:
+		db, err := boltdb.NewBoltDB(path, boltNoSync)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	return nil, errors.Errorf("unknown database type %s", dbType)
+}
+
+// OpenReadOnly opens the existing database in the given directory in read-only
+// mode, whatever its type.
+func OpenReadOnly(path string, cacheSizeMiB int) (database.Database, error) {
+	dbType, err := Detect(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch dbType {
+	case LevelDB:
+		db, err := ldb.NewLevelDBReadOnly(path, cacheSizeMiB)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case BoltDB:
+		db, err := boltdb.NewBoltDBReadOnly(path)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	return nil, errors.Errorf("there's no database in %s", path)
+}
@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name          string
+		createType    string
+		openType      string
+		expectedType  string
+		expectedError string
+	}{
+		{name: "new default", openType: "", expectedType: Default},
+		{name: "new bbolt", openType: BoltDB, expectedType: BoltDB},
+		{name: "existing leveldb", createType: LevelDB, openType: "", expectedType: LevelDB},
+		{name: "existing bbolt", createType: BoltDB, openType: "", expectedType: BoltDB},
+		{name: "matching type", createType: BoltDB, openType: BoltDB, expectedType: BoltDB},
+		{name: "mismatching type", createType: LevelDB, openType: BoltDB, expectedError: "is a leveldb database"},
+		{name: "unknown type", openType: "sqlite", expectedError: "unknown database type"},
+	}
+
+	for _, test := range tests {
+		func() {
+			path, err := ioutil.TempDir("", "TestOpen")
+			if err != nil {
+				t.Fatalf("%s: TempDir unexpectedly failed: %s", test.name, err)
+			}
+			defer os.RemoveAll(path)
+
+			if test.createType != "" {
+				db, err := Open(test.createType, path, 8, false)
+				if err != nil {
+					t.Fatalf("%s: Open unexpectedly failed: %s", test.name, err)
+				}
+				err = db.Close()
+				if err != nil {
+					t.Fatalf("%s: Close unexpectedly failed: %s", test.name, err)
+				}
+			}
+
+			db, err := Open(test.openType, path, 8, false)
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Fatalf("%s: expected an error containing %q, got: %v", test.name, test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%s: Open unexpectedly failed: %s", test.name, err)
+			}
+			defer db.Close()
+
+			detectedType, err := Detect(path)
+			if err != nil {
+				t.Fatalf("%s: Detect unexpectedly failed: %s", test.name, err)
+			}
+			if detectedType != test.expectedType {
+				t.Fatalf("%s: got database type %s, want %s", test.name, detectedType, test.expectedType)
+			}
+		}()
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	for _, dbType := range Types {
+		func() {
+			path, err := ioutil.TempDir("", "TestOpenReadOnly")
+			if err != nil {
+				t.Fatalf("%s: TempDir unexpectedly failed: %s", dbType, err)
+			}
+			defer os.RemoveAll(path)
+
+			_, err = OpenReadOnly(path, 8)
+			if err == nil {
+				t.Fatalf("%s: OpenReadOnly unexpectedly succeeded without a database", dbType)
+			}
+
+			key := database.MakeBucket(nil).Key([]byte("key"))
+			db, err := Open(dbType, path, 8, false)
+			if err != nil {
+				t.Fatalf("%s: Open unexpectedly failed: %s", dbType, err)
+			}
+			err = db.Put(key, []byte("value"))
+			if err != nil {
+				t.Fatalf("%s: Put unexpectedly failed: %s", dbType, err)
+			}
+			err = db.Close()
+			if err != nil {
+				t.Fatalf("%s: Close unexpectedly failed: %s", dbType, err)
+			}
+
+			db, err = OpenReadOnly(path, 8)
+			if err != nil {
+				t.Fatalf("%s: OpenReadOnly unexpectedly failed: %s", dbType, err)
+			}
+			defer db.Close()
+			value, err := db.Get(key)
+			if err != nil {
+				t.Fatalf("%s: Get unexpectedly failed: %s", dbType, err)
+			}
+			if string(value) != "value" {
+				t.Fatalf("%s: Get returned %s", dbType, value)
+			}
+			err = db.Put(key, []byte("other value"))
+			if err == nil {
+				t.Fatalf("%s: Put unexpectedly succeeded in read-only mode", dbType)
+			}
+		}()
+	}
+}
@@ -0,0 +1,154 @@
+package boltdb
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// FileName is the name of the bbolt file inside the database directory
+const FileName = "kaspad.bolt"
+
+// openTimeout is how long to wait for the lock of the database file before
+// giving up, for example when another kaspad instance uses the same directory
+const openTimeout = 5 * time.Second
+
+// bucketName is the name of the single bbolt bucket all keys are stored in.
+// The hierarchy of database.Bucket is encoded in the keys themselves, the same
+// way it is in leveldb.
+var bucketName = []byte("kaspad")
+
+// BoltDB defines a thin wrapper around bbolt.
+type BoltDB struct {
+	db *bolt.DB
+}
+
+// NewBoltDB opens a bbolt instance inside the directory defined by the given path.
+// Every commit is fsynced unless noSync is set. With noSync, a crash of the
+// process or the machine may leave the database file corrupted.
+func NewBoltDB(path string, noSync bool) (*BoltDB, error) {
+	// Open bbolt. If it doesn't exist, create it.
+	err := os.MkdirAll(path, 0700)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	options := Options()
+	options.NoSync = noSync
+	db, err := bolt.Open(filepath.Join(path, FileName), 0600, options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening bbolt database in %s", path)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		closeErr := db.Close()
+		if closeErr != nil {
+			log.Warnf("Error closing bbolt database: %s", closeErr)
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	return &BoltDB{db: db}, nil
+}
+
+// NewBoltDBReadOnly opens an existing bbolt instance inside the directory defined
+// by the given path in read-only mode. All writes to it fail.
+func NewBoltDBReadOnly(path string) (*BoltDB, error) {
+	filePath := filepath.Join(path, FileName)
+	_, err := os.Stat(filePath)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	options := Options()
+	options.ReadOnly = true
+	db, err := bolt.Open(filePath, 0600, options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed opening bbolt database in %s", path)
+	}
+	return &BoltDB{db: db}, nil
+}
+
+// Options returns the bolt.Options used for opening a database.
+// Commits are fsynced with these options.
+func Options() *bolt.Options {
+	return &bolt.Options{
+		Timeout:      openTimeout,
+		FreelistType: bolt.FreelistMapType,
+	}
+}
+
+// Compact does nothing. bbolt reuses the pages of deleted data for new
+// data, so there's nothing to compact while the database is open. The file
+// itself never shrinks; copy it with the kaspadb tool in order to reclaim the
+// free space.
+func (db *BoltDB) Compact() error {
+	return nil
+}
+
+// Close closes the bbolt instance.
+func (db *BoltDB) Close() error {
+	err := db.db.Close()
+	return errors.WithStack(err)
+}
+
+// Put sets the value for the given key. It overwrites
+// any previous value for that key.
+func (db *BoltDB) Put(key *database.Key, value []byte) error {
+	err := db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put(key.Bytes(), value)
+	})
+	return errors.WithStack(err)
+}
+
+// Get gets the value for the given key. It returns
+// ErrNotFound if the given key does not exist.
+func (db *BoltDB) Get(key *database.Key) ([]byte, error) {
+	var data []byte
+	err := db.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(bucketName).Get(key.Bytes())
+		if value != nil {
+			// The value is only valid during the transaction, so it's copied out
+			data = append([]byte{}, value...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if data == nil {
+		return nil, errors.Wrapf(database.ErrNotFound,
+			"key %s not found", key)
+	}
+	return data, nil
+}
+
+// Has returns true if the database does contains the
+// given key.
+func (db *BoltDB) Has(key *database.Key) (bool, error) {
+	exists := false
+	err := db.db.View(func(tx *bolt.Tx) error {
+		exists = tx.Bucket(bucketName).Get(key.Bytes()) != nil
+		return nil
+	})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return exists, nil
+}
+
+// Delete deletes the value for the given key. Will not
+// return an error if the key doesn't exist.
+func (db *BoltDB) Delete(key *database.Key) error {
+	err := db.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete(key.Bytes())
+	})
+	return errors.WithStack(err)
+}
@@ -0,0 +1,155 @@
+package boltdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+func prepareDatabaseForTest(t *testing.T, testName string) (db *BoltDB, teardownFunc func()) {
+	// Create a temp db to run tests against
+	path, err := ioutil.TempDir("", testName)
+	if err != nil {
+		t.Fatalf("%s: TempDir unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	db, err = NewBoltDB(path, false)
+	if err != nil {
+		t.Fatalf("%s: NewBoltDB unexpectedly "+
+			"failed: %s", testName, err)
+	}
+	teardownFunc = func() {
+		err = db.Close()
+		if err != nil {
+			t.Fatalf("%s: Close unexpectedly "+
+				"failed: %s", testName, err)
+		}
+		os.RemoveAll(path)
+	}
+	return db, teardownFunc
+}
+
+func TestBoltDBEmptyValue(t *testing.T) {
+	db, teardownFunc := prepareDatabaseForTest(t, "TestBoltDBEmptyValue")
+	defer teardownFunc()
+
+	// An empty value must still count as an existing key
+	key := database.MakeBucket(nil).Key([]byte("key"))
+	err := db.Put(key, []byte{})
+	if err != nil {
+		t.Fatalf("TestBoltDBEmptyValue: Put returned "+
+			"unexpected error: %s", err)
+	}
+	value, err := db.Get(key)
+	if err != nil {
+		t.Fatalf("TestBoltDBEmptyValue: Get returned "+
+			"unexpected error: %s", err)
+	}
+	if len(value) != 0 {
+		t.Fatalf("TestBoltDBEmptyValue: Get returned %x", value)
+	}
+	exists, err := db.Has(key)
+	if err != nil {
+		t.Fatalf("TestBoltDBEmptyValue: Has returned "+
+			"unexpected error: %s", err)
+	}
+	if !exists {
+		t.Fatalf("TestBoltDBEmptyValue: Has unexpectedly returned false")
+	}
+}
+
+func TestBoltDBTransactionCopiesData(t *testing.T) {
+	db, teardownFunc := prepareDatabaseForTest(t, "TestBoltDBTransactionCopiesData")
+	defer teardownFunc()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("TestBoltDBTransactionCopiesData: Begin "+
+			"unexpectedly failed: %s", err)
+	}
+
+	// Reuse the value slice after putting it, like callers may with leveldb batches
+	key := database.MakeBucket(nil).Key([]byte("key"))
+	value := []byte("value")
+	err = tx.Put(key, value)
+	if err != nil {
+		t.Fatalf("TestBoltDBTransactionCopiesData: Put "+
+			"returned unexpected error: %s", err)
+	}
+	copy(value, "xxxxx")
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("TestBoltDBTransactionCopiesData: Commit "+
+			"returned unexpected error: %s", err)
+	}
+	getData, err := db.Get(key)
+	if err != nil {
+		t.Fatalf("TestBoltDBTransactionCopiesData: Get "+
+			"returned unexpected error: %s", err)
+	}
+	if !bytes.Equal(getData, []byte("value")) {
+		t.Fatalf("TestBoltDBTransactionCopiesData: got %s", getData)
+	}
+}
+
+func TestBoltDBReopen(t *testing.T) {
+	path, err := ioutil.TempDir("", "TestBoltDBReopen")
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: TempDir unexpectedly failed: %s", err)
+	}
+	defer os.RemoveAll(path)
+
+	db, err := NewBoltDB(path, false)
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: NewBoltDB unexpectedly failed: %s", err)
+	}
+	key := database.MakeBucket([]byte("bucket")).Key([]byte("key"))
+	err = db.Put(key, []byte("value"))
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: Put unexpectedly failed: %s", err)
+	}
+	err = db.Close()
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: Close unexpectedly failed: %s", err)
+	}
+
+	db, err = NewBoltDB(path, false)
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: NewBoltDB unexpectedly failed: %s", err)
+	}
+	defer db.Close()
+	value, err := db.Get(key)
+	if err != nil {
+		t.Fatalf("TestBoltDBReopen: Get unexpectedly failed: %s", err)
+	}
+	if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("TestBoltDBReopen: got %s", value)
+	}
+}
+
+func TestBoltDBSyncsByDefault(t *testing.T) {
+	db, teardownFunc := prepareDatabaseForTest(t, "TestBoltDBSyncsByDefault")
+	defer teardownFunc()
+
+	if db.db.NoSync {
+		t.Fatalf("TestBoltDBSyncsByDefault: commits are not fsynced")
+	}
+
+	path, err := ioutil.TempDir("", "TestBoltDBSyncsByDefault")
+	if err != nil {
+		t.Fatalf("TestBoltDBSyncsByDefault: TempDir unexpectedly failed: %s", err)
+	}
+	defer os.RemoveAll(path)
+	noSyncDB, err := NewBoltDB(path, true)
+	if err != nil {
+		t.Fatalf("TestBoltDBSyncsByDefault: NewBoltDB unexpectedly failed: %s", err)
+	}
+	defer noSyncDB.Close()
+	if !noSyncDB.db.NoSync {
+		t.Fatalf("TestBoltDBSyncsByDefault: commits are fsynced even though noSync was set")
+	}
+}
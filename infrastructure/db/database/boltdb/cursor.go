@@ -0,0 +1,192 @@
+package boltdb
+
+import (
+	"bytes"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+// cursorBatchSize is the number of key/value pairs a cursor reads in every
+// bbolt read transaction
+const cursorBatchSize = 256
+
+// BoltDBCursor iterates over the keys of a bucket.
+//
+// A bbolt cursor is only valid while its read transaction is open, and an
+// open read transaction blocks read-write transactions that need to grow the
+// memory map of the database. Since cursors are often kept open while the
+// same goroutine writes to the database, BoltDBCursor doesn't keep a read
+// transaction open. Instead, it reads the pairs in batches of cursorBatchSize,
+// each in its own short read transaction, and continues every batch from the
+// last key it read.
+type BoltDBCursor struct {
+	db     *BoltDB
+	bucket *database.Bucket
+
+	// current is the pair the cursor is at, or nil if the cursor is before
+	// the first pair or exhausted
+	current *keyValuePair
+	// pending are the pairs that were read and come after current
+	pending []*keyValuePair
+	// isDone is set when there are no pairs after the ones in pending
+	isDone      bool
+	isExhausted bool
+	err         error
+
+	isClosed bool
+}
+
+type keyValuePair struct {
+	key   []byte
+	value []byte
+}
+
+// Cursor begins a new cursor over the given prefix.
+func (db *BoltDB) Cursor(bucket *database.Bucket) (database.Cursor, error) {
+	return &BoltDBCursor{
+		db:       db,
+		bucket:   bucket,
+		isClosed: false,
+	}, nil
+}
+
+// Next moves the iterator to the next key/value pair. It returns whether the
+// iterator is exhausted. Panics if the cursor is closed.
+func (c *BoltDBCursor) Next() bool {
+	if c.isClosed {
+		panic("cannot call next on a closed cursor")
+	}
+	if c.isExhausted {
+		return false
+	}
+	if len(c.pending) == 0 && !c.isDone {
+		if c.current == nil {
+			c.readBatch(c.bucket.Path(), true)
+		} else {
+			c.readBatch(c.current.key, false)
+		}
+	}
+	return c.moveToNextPending()
+}
+
+// First moves the iterator to the first key/value pair. It returns false if
+// such a pair does not exist. Panics if the cursor is closed.
+func (c *BoltDBCursor) First() bool {
+	if c.isClosed {
+		panic("cannot call first on a closed cursor")
+	}
+	c.readBatch(c.bucket.Path(), true)
+	return c.moveToNextPending()
+}
+
+// Seek moves the iterator to the first key/value pair whose key is greater
+// than or equal to the given key. It returns ErrNotFound if such pair does not
+// exist.
+func (c *BoltDBCursor) Seek(key *database.Key) error {
+	if c.isClosed {
+		return errors.New("cannot seek a closed cursor")
+	}
+
+	c.readBatch(key.Bytes(), true)
+	found := c.moveToNextPending()
+	if !found {
+		return errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+
+	if !bytes.Equal(c.current.key, key.Bytes()) {
+		return errors.Wrapf(database.ErrNotFound, "key %s not found", key)
+	}
+
+	return nil
+}
+
+// Key returns the key of the current key/value pair, or ErrNotFound if done.
+// Note that the key is trimmed to not include the prefix the cursor was opened
+// with.
+func (c *BoltDBCursor) Key() (*database.Key, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the key of a closed cursor")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.current == nil {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"key of an exhausted cursor")
+	}
+	suffix := bytes.TrimPrefix(c.current.key, c.bucket.Path())
+	return c.bucket.Key(suffix), nil
+}
+
+// Value returns the value of the current key/value pair, or ErrNotFound if done.
+// The caller should not modify the contents of the returned slice.
+func (c *BoltDBCursor) Value() ([]byte, error) {
+	if c.isClosed {
+		return nil, errors.New("cannot get the value of a closed cursor")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.current == nil {
+		return nil, errors.Wrapf(database.ErrNotFound, "cannot get the "+
+			"value of an exhausted cursor")
+	}
+	return c.current.value, nil
+}
+
+// Close releases associated resources.
+func (c *BoltDBCursor) Close() error {
+	if c.isClosed {
+		return errors.New("cannot close an already closed cursor")
+	}
+	c.isClosed = true
+	c.current = nil
+	c.pending = nil
+	c.bucket = nil
+	return nil
+}
+
+// readBatch replaces the pending pairs with up to cursorBatchSize pairs of
+// the cursor's bucket, starting at the given key. If inclusive is false, the
+// pair of the given key itself is skipped.
+func (c *BoltDBCursor) readBatch(from []byte, inclusive bool) {
+	prefix := c.bucket.Path()
+	pending := make([]*keyValuePair, 0, cursorBatchSize)
+	err := c.db.db.View(func(tx *bolt.Tx) error {
+		boltCursor := tx.Bucket(bucketName).Cursor()
+		key, value := boltCursor.Seek(from)
+		if key != nil && !inclusive && bytes.Equal(key, from) {
+			key, value = boltCursor.Next()
+		}
+		for ; key != nil && bytes.HasPrefix(key, prefix) && len(pending) < cursorBatchSize; key, value = boltCursor.Next() {
+			// The key and value are only valid during the transaction, so they're copied out
+			pending = append(pending, &keyValuePair{
+				key:   append([]byte{}, key...),
+				value: append([]byte{}, value...),
+			})
+		}
+		return nil
+	})
+
+	c.pending = pending
+	c.isDone = len(pending) < cursorBatchSize
+	c.isExhausted = false
+	c.err = errors.WithStack(err)
+	if err != nil {
+		c.pending = nil
+		c.isDone = true
+	}
+}
+
+func (c *BoltDBCursor) moveToNextPending() bool {
+	if len(c.pending) == 0 {
+		c.current = nil
+		c.isExhausted = true
+		return false
+	}
+	c.current = c.pending[0]
+	c.pending = c.pending[1:]
+	return true
+}
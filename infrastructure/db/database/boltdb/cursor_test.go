@@ -0,0 +1,83 @@
+package boltdb
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+)
+
+// TestCursorAcrossBatches makes sure that a cursor iterates over buckets that
+// are larger than cursorBatchSize, ignores the keys of other buckets, and
+// doesn't block writes while it's open.
+func TestCursorAcrossBatches(t *testing.T) {
+	db, teardownFunc := prepareDatabaseForTest(t, "TestCursorAcrossBatches")
+	defer teardownFunc()
+
+	bucket := database.MakeBucket([]byte("bucket"))
+	otherBucket := database.MakeBucket([]byte("bucket2"))
+	const entryCount = cursorBatchSize*2 + 10
+	for i := 0; i < entryCount; i++ {
+		key := []byte(fmt.Sprintf("key%05d", i))
+		err := db.Put(bucket.Key(key), key)
+		if err != nil {
+			t.Fatalf("TestCursorAcrossBatches: Put unexpectedly failed: %s", err)
+		}
+		err = db.Put(otherBucket.Key(key), key)
+		if err != nil {
+			t.Fatalf("TestCursorAcrossBatches: Put unexpectedly failed: %s", err)
+		}
+	}
+
+	cursor, err := db.Cursor(bucket)
+	if err != nil {
+		t.Fatalf("TestCursorAcrossBatches: Cursor unexpectedly failed: %s", err)
+	}
+	defer cursor.Close()
+
+	count := 0
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		expectedKey := []byte(fmt.Sprintf("key%05d", count))
+		key, err := cursor.Key()
+		if err != nil {
+			t.Fatalf("TestCursorAcrossBatches: Key unexpectedly failed: %s", err)
+		}
+		if string(key.Suffix()) != string(expectedKey) {
+			t.Fatalf("TestCursorAcrossBatches: got key %s, want %s", key.Suffix(), expectedKey)
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			t.Fatalf("TestCursorAcrossBatches: Value unexpectedly failed: %s", err)
+		}
+		if string(value) != string(expectedKey) {
+			t.Fatalf("TestCursorAcrossBatches: got value %s, want %s", value, expectedKey)
+		}
+
+		// Writing while the cursor is open must not block
+		err = db.Delete(otherBucket.Key(expectedKey))
+		if err != nil {
+			t.Fatalf("TestCursorAcrossBatches: Delete unexpectedly failed: %s", err)
+		}
+		count++
+	}
+	if count != entryCount {
+		t.Fatalf("TestCursorAcrossBatches: iterated over %d keys, want %d", count, entryCount)
+	}
+	if cursor.Next() {
+		t.Fatalf("TestCursorAcrossBatches: Next unexpectedly succeeded on an exhausted cursor")
+	}
+
+	// Seeking back into the middle of the bucket continues from there
+	err = cursor.Seek(bucket.Key([]byte(fmt.Sprintf("key%05d", cursorBatchSize))))
+	if err != nil {
+		t.Fatalf("TestCursorAcrossBatches: Seek unexpectedly failed: %s", err)
+	}
+	count = 1
+	for cursor.Next() {
+		count++
+	}
+	if count != entryCount-cursorBatchSize {
+		t.Fatalf("TestCursorAcrossBatches: iterated over %d keys after seeking, want %d",
+			count, entryCount-cursorBatchSize)
+	}
+}
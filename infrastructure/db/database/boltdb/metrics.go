@@ -0,0 +1,59 @@
+package boltdb
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Metrics returns metrics that export the internal statistics of the bbolt instance.
+// They aren't registered anywhere, it's up to the caller to register them
+func (db *BoltDB) Metrics() []prometheus.Collector {
+	return []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_bbolt_file_size_bytes",
+			Help: "Size of the bbolt database file",
+		}, func() float64 {
+			var size int64
+			err := db.db.View(func(tx *bolt.Tx) error {
+				size = tx.Size()
+				return nil
+			})
+			if err != nil {
+				log.Debugf("Error getting the bbolt file size: %s", err)
+			}
+			return float64(size)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_bbolt_free_pages",
+			Help: "Number of free pages in the bbolt file",
+		}, func() float64 { return float64(db.db.Stats().FreePageN) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_bbolt_pending_pages",
+			Help: "Number of pages that will be freed once no transaction uses them",
+		}, func() float64 { return float64(db.db.Stats().PendingPageN) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_bbolt_free_bytes",
+			Help: "Number of bytes allocated in free pages",
+		}, func() float64 { return float64(db.db.Stats().FreeAlloc) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_bbolt_open_read_transactions",
+			Help: "Number of open bbolt read transactions",
+		}, func() float64 { return float64(db.db.Stats().OpenTxN) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_bbolt_read_transactions_total",
+			Help: "Number of bbolt read transactions started",
+		}, func() float64 { return float64(db.db.Stats().TxN) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_bbolt_page_splits_total",
+			Help: "Number of bbolt page splits",
+		}, func() float64 { return float64(db.db.Stats().TxStats.Split) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_bbolt_writes_total",
+			Help: "Number of writes bbolt performed",
+		}, func() float64 { return float64(db.db.Stats().TxStats.Write) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_bbolt_write_seconds_total",
+			Help: "Total time bbolt spent writing to disk",
+		}, func() float64 { return db.db.Stats().TxStats.WriteTime.Seconds() }),
+	}
+}
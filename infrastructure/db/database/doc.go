@@ -6,8 +6,8 @@ Overview
 This package provides a database layer to store and retrieve data in a simple
 and efficient manner.
 
-The current backend is ffldb, which makes use of leveldb, flat files, and strict
-checksums in key areas to ensure data integrity.
+The available backends are ldb, which makes use of leveldb, and boltdb, which
+makes use of bbolt. The backend package opens the backend selected by --dbtype.
 
 Implementors of additional backends are required to implement the following interfaces:
 
@@ -20,10 +20,25 @@ func NewLevelDB(path string, cacheSizeMiB int) (*LevelDB, error) {
 	options := Options()
 	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
 	options.WriteBuffer = (cacheSizeMiB * opt.MiB) / 2
-	ldb, err := leveldb.OpenFile(path, &options)
+	return open(path, &options)
+}
+
+// NewLevelDBReadOnly opens an existing leveldb instance defined by the given path
+// in read-only mode. All writes to it fail.
+func NewLevelDBReadOnly(path string, cacheSizeMiB int) (*LevelDB, error) {
+	options := Options()
+	options.BlockCacheCapacity = cacheSizeMiB * opt.MiB
+	options.ReadOnly = true
+	options.ErrorIfMissing = true
+	return open(path, &options)
+}
+
+func open(path string, options *opt.Options) (*LevelDB, error) {
+	ldb, err := leveldb.OpenFile(path, options)
 
-	// If the database is corrupted, attempt to recover.
-	if _, corrupted := err.(*ldbErrors.ErrCorrupted); corrupted {
+	// If the database is corrupted, attempt to recover. Recovering
+	// rewrites the database, so it's not attempted in read-only mode.
+	if _, corrupted := err.(*ldbErrors.ErrCorrupted); corrupted && !options.ReadOnly {
 		log.Warnf("LevelDB corruption detected for path %s: %s",
 			path, err)
 		var recoverErr error
@@ -0,0 +1,77 @@
+package ldb
+
+import (
+	"strconv"
+
+	"github.com/kaspanet/kaspad/infrastructure/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Metrics returns metrics that export the internal statistics of the leveldb instance.
+// They aren't registered anywhere, it's up to the caller to register them
+func (db *LevelDB) Metrics() []prometheus.Collector {
+	stats := func() *leveldb.DBStats {
+		stats := &leveldb.DBStats{}
+		err := db.ldb.Stats(stats)
+		if err != nil {
+			log.Debugf("Error getting leveldb stats: %s", err)
+		}
+		return stats
+	}
+
+	return []prometheus.Collector{
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_leveldb_io_read_bytes_total",
+			Help: "Number of bytes leveldb read from storage",
+		}, func() float64 { return float64(stats().IORead) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_leveldb_io_write_bytes_total",
+			Help: "Number of bytes leveldb wrote to storage",
+		}, func() float64 { return float64(stats().IOWrite) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_leveldb_write_delays_total",
+			Help: "Number of times writes were delayed to let compaction catch up",
+		}, func() float64 { return float64(stats().WriteDelayCount) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "kaspad_leveldb_write_delay_seconds_total",
+			Help: "Total time writes were delayed to let compaction catch up",
+		}, func() float64 { return stats().WriteDelayDuration.Seconds() }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_leveldb_block_cache_size_bytes",
+			Help: "Size of the leveldb block cache",
+		}, func() float64 { return float64(stats().BlockCacheSize) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_leveldb_open_tables",
+			Help: "Number of tables leveldb has open",
+		}, func() float64 { return float64(stats().OpenedTablesCount) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "kaspad_leveldb_alive_iterators",
+			Help: "Number of leveldb iterators that weren't released",
+		}, func() float64 { return float64(stats().AliveIterators) }),
+		metrics.NewGaugeVecFunc("kaspad_leveldb_level_size_bytes", "Size of the tables of each leveldb level",
+			func() []metrics.LabeledValue {
+				levelSizes := stats().LevelSizes
+				labeledValues := make([]metrics.LabeledValue, len(levelSizes))
+				for level, size := range levelSizes {
+					labeledValues[level] = metrics.LabeledValue{
+						LabelValues: []string{strconv.Itoa(level)},
+						Value:       float64(size),
+					}
+				}
+				return labeledValues
+			}, "level"),
+		metrics.NewGaugeVecFunc("kaspad_leveldb_level_tables", "Number of tables in each leveldb level",
+			func() []metrics.LabeledValue {
+				levelTablesCounts := stats().LevelTablesCounts
+				labeledValues := make([]metrics.LabeledValue, len(levelTablesCounts))
+				for level, count := range levelTablesCounts {
+					labeledValues[level] = metrics.LabeledValue{
+						LabelValues: []string{strconv.Itoa(level)},
+						Value:       float64(count),
+					}
+				}
+				return labeledValues
+			}, "level"),
+	}
+}
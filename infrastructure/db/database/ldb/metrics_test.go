@@ -0,0 +1,39 @@
+package ldb
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestLevelDBMetrics(t *testing.T) {
+	ldb, teardownFunc := prepareDatabaseForTest(t, "TestLevelDBMetrics")
+	defer teardownFunc()
+
+	err := ldb.Put(database.MakeBucket(nil).Key([]byte("key")), []byte("value"))
+	if err != nil {
+		t.Fatalf("TestLevelDBMetrics: Put unexpectedly failed: %s", err)
+	}
+
+	registry := prometheus.NewRegistry()
+	for _, collector := range ldb.Metrics() {
+		err = registry.Register(collector)
+		if err != nil {
+			t.Fatalf("TestLevelDBMetrics: Register unexpectedly failed: %s", err)
+		}
+	}
+	metricFamilies, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("TestLevelDBMetrics: Gather unexpectedly failed: %s", err)
+	}
+	names := make(map[string]struct{}, len(metricFamilies))
+	for _, metricFamily := range metricFamilies {
+		names[metricFamily.GetName()] = struct{}{}
+	}
+	for _, expectedName := range []string{"kaspad_leveldb_io_write_bytes_total", "kaspad_leveldb_open_tables"} {
+		if _, ok := names[expectedName]; !ok {
+			t.Fatalf("TestLevelDBMetrics: the metrics don't contain %s", expectedName)
+		}
+	}
+}
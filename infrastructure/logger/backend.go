@@ -54,6 +54,7 @@ const logsBuffer = 0
 // subsystems.
 type Backend struct {
 	flag      uint32
+	format    uint32 // atomic
 	isRunning uint32
 	writers   []logWriter
 	writeChan chan logEntry
@@ -167,6 +168,21 @@ func (b *Backend) runBlocking() {
 	}
 }
 
+// SetFormat sets the format of the log lines written by all the subsystem
+// loggers of this backend. It can only be called before the backend runs.
+func (b *Backend) SetFormat(format Format) error {
+	if b.IsRunning() {
+		return errors.New("The logger is already running")
+	}
+	atomic.StoreUint32(&b.format, uint32(format))
+	return nil
+}
+
+// Format returns the format of the log lines written by this backend
+func (b *Backend) Format() Format {
+	return Format(atomic.LoadUint32(&b.format))
+}
+
 // IsRunning returns true if backend.Run() has been called and false if it hasn't.
 func (b *Backend) IsRunning() bool {
 	return atomic.LoadUint32(&b.isRunning) != 0
@@ -187,5 +203,6 @@ func (b *Backend) Close() {
 // Backend b. A tag describes the subsystem and is included in all log
 // messages. The logger uses the info verbosity level by default.
 func (b *Backend) Logger(subsystemTag string) *Logger {
-	return &Logger{LevelOff, subsystemTag, b, b.writeChan}
+	lvl := LevelOff
+	return &Logger{lvl: &lvl, tag: subsystemTag, b: b, writeChan: b.writeChan}
 }
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kaspanet/kaspad/util/mstime"
+)
+
+// Format is the format of the log lines written by a Backend
+type Format uint32
+
+// Format constants.
+const (
+	// FormatText writes every log line as
+	// 'YYYY-MM-DD hh:mm:ss.sss [LVL] TAG: message key=value ...'
+	FormatText Format = iota
+
+	// FormatJSON writes every log line as a JSON object with the time,
+	// level, subsystem, message and fields of the log
+	FormatJSON
+)
+
+// FormatFromString returns a format based on the input string s. If the input
+// can't be interpreted as a valid log format, FormatText and false is returned.
+func FormatFromString(s string) (format Format, ok bool) {
+	switch strings.ToLower(s) {
+	case "text":
+		return FormatText, true
+	case "json":
+		return FormatJSON, true
+	default:
+		return FormatText, false
+	}
+}
+
+// String returns the name of the format
+func (f Format) String() string {
+	if f == FormatJSON {
+		return "json"
+	}
+	return "text"
+}
+
+// Fields are key/value pairs that are attached to a log line. In the text
+// format they are appended to the message as key=value, and in the JSON
+// format they are written as a JSON object.
+type Fields map[string]interface{}
+
+// WithFields returns a logger for the same subsystem that attaches the given
+// fields, in addition to the fields of l, to every line it writes.
+// The returned logger shares its level with l.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	mergedFields := make(Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		mergedFields[key] = value
+	}
+	for key, value := range fields {
+		mergedFields[key] = value
+	}
+	return &Logger{
+		lvl:       l.lvl,
+		tag:       l.tag,
+		b:         l.b,
+		writeChan: l.writeChan,
+		fields:    mergedFields,
+	}
+}
+
+// sortedKeys returns the keys of the fields in a stable order
+func (fields Fields) sortedKeys() []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldValue converts errors and fmt.Stringers to strings, so that they're
+// written the same way in both formats
+func fieldValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case error:
+		return value.Error()
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return value
+	}
+}
+
+// formatFields appends the fields as ' key=value' pairs. Values that contain
+// spaces or quotes are quoted.
+func formatFields(buf *[]byte, fields Fields) {
+	for _, key := range fields.sortedKeys() {
+		value := fmt.Sprint(fieldValue(fields[key]))
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		*buf = append(*buf, ' ')
+		*buf = append(*buf, key...)
+		*buf = append(*buf, '=')
+		*buf = append(*buf, value...)
+	}
+}
+
+type jsonLogLine struct {
+	Time      string                 `json:"time"`
+	Level     string                 `json:"level"`
+	Subsystem string                 `json:"subsystem"`
+	File      string                 `json:"file,omitempty"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// formatJSON appends the log line as a single-line JSON object
+func formatJSON(buf *[]byte, t mstime.Time, lvl Level, tag string, file string, line int,
+	message string, fields Fields) {
+
+	logLine := jsonLogLine{
+		Time:      t.ToNativeTime().Format(time.RFC3339Nano),
+		Level:     lvl.Name(),
+		Subsystem: tag,
+		Message:   message,
+	}
+	if file != "" {
+		logLine.File = file + ":" + strconv.Itoa(line)
+	}
+	if len(fields) > 0 {
+		logLine.Fields = make(map[string]interface{}, len(fields))
+		for key, value := range fields {
+			logLine.Fields[key] = fieldValue(value)
+		}
+	}
+
+	serializedLogLine, err := json.Marshal(logLine)
+	if err != nil {
+		// A field couldn't be serialized, so it's written using its default format instead
+		for key, value := range logLine.Fields {
+			logLine.Fields[key] = fmt.Sprint(value)
+		}
+		serializedLogLine, _ = json.Marshal(logLine)
+	}
+	*buf = append(*buf, serializedLogLine...)
+}
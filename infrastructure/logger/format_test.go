@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type bufferWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*bufferWriteCloser) Close() error {
+	return nil
+}
+
+func logLines(t *testing.T, format Format, logFunc func(log *Logger)) []string {
+	backend := NewBackendWithFlags(LogFlagShortFile)
+	buffer := &bufferWriteCloser{}
+	err := backend.AddLogWriter(buffer, LevelTrace)
+	if err != nil {
+		t.Fatalf("AddLogWriter: %s", err)
+	}
+	err = backend.SetFormat(format)
+	if err != nil {
+		t.Fatalf("SetFormat: %s", err)
+	}
+	err = backend.Run()
+	if err != nil {
+		t.Fatalf("Run: %s", err)
+	}
+
+	log := backend.Logger("TEST")
+	log.SetLevel(LevelInfo)
+	logFunc(log)
+	backend.Close()
+
+	return strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+}
+
+func TestTextFormat(t *testing.T) {
+	lines := logLines(t, FormatText, func(log *Logger) {
+		log.Infof("block %d", 1)
+		log.Debugf("filtered")
+		log.WithFields(Fields{"peer": "1.2.3.4:16111", "reason": "bad block"}).Warn("banned", "peer")
+	})
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), lines)
+	}
+	if !strings.HasSuffix(lines[0], " [INF] TEST format_test.go:46: block 1") {
+		t.Fatalf("unexpected line: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], ` [WRN] TEST format_test.go:48: banned peer peer=1.2.3.4:16111 reason="bad block"`) {
+		t.Fatalf("unexpected line: %s", lines[1])
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	lines := logLines(t, FormatJSON, func(log *Logger) {
+		fieldsLog := log.WithFields(Fields{"height": 5})
+		fieldsLog.WithFields(Fields{"err": errors.New("oops")}).Errorf("failed %s", "badly")
+
+		// The logger returned by WithFields shares its level with its parent
+		log.SetLevel(LevelCritical)
+		fieldsLog.Errorf("filtered")
+	})
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line, got %d: %q", len(lines), lines)
+	}
+
+	var logLine struct {
+		Time      string
+		Level     string
+		Subsystem string
+		Message   string
+		Fields    map[string]interface{}
+	}
+	err := json.Unmarshal([]byte(lines[0]), &logLine)
+	if err != nil {
+		t.Fatalf("the line isn't valid JSON: %s: %s", err, lines[0])
+	}
+	if logLine.Time == "" || logLine.Level != "error" || logLine.Subsystem != "TEST" || logLine.Message != "failed badly" {
+		t.Fatalf("unexpected line: %s", lines[0])
+	}
+	if logLine.Fields["height"] != float64(5) || logLine.Fields["err"] != "oops" {
+		t.Fatalf("unexpected fields: %v", logLine.Fields)
+	}
+}
@@ -135,6 +135,17 @@ func SupportedSubsystems() []string {
 	return subsystems
 }
 
+// LogLevels returns the logging level of every subsystem
+func LogLevels() map[string]Level {
+	subsystemLoggersMutex.Lock()
+	defer subsystemLoggersMutex.Unlock()
+	logLevels := make(map[string]Level, len(subsystemLoggers))
+	for subsystemID, logger := range subsystemLoggers {
+		logLevels[subsystemID] = logger.Level()
+	}
+	return logLevels
+}
+
 func getSubsystem(tag string) (logger *Logger, ok bool) {
 	subsystemLoggersMutex.Lock()
 	defer subsystemLoggersMutex.Unlock()
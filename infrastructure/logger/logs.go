@@ -33,7 +33,6 @@
 package logger
 
 import (
-	"bytes"
 	"fmt"
 	"github.com/kaspanet/kaspad/util/mstime"
 	"os"
@@ -43,10 +42,11 @@ import (
 
 // Logger is a subsystem logger for a Backend.
 type Logger struct {
-	lvl       Level // atomic
+	lvl       *Level // atomic, shared with the loggers returned by WithFields
 	tag       string
 	b         *Backend
 	writeChan chan<- logEntry
+	fields    Fields
 }
 
 type logEntry struct {
@@ -146,12 +146,12 @@ func (l *Logger) Writef(logLevel Level, format string, args ...interface{}) {
 
 // Level returns the current logging level
 func (l *Logger) Level() Level {
-	return Level(atomic.LoadUint32((*uint32)(&l.lvl)))
+	return Level(atomic.LoadUint32((*uint32)(l.lvl)))
 }
 
 // SetLevel changes the logging level to the passed level.
 func (l *Logger) SetLevel(level Level) {
-	atomic.StoreUint32((*uint32)(&l.lvl), uint32(level))
+	atomic.StoreUint32((*uint32)(l.lvl), uint32(level))
 }
 
 // Backend returns the log backend
@@ -160,40 +160,21 @@ func (l *Logger) Backend() *Backend {
 }
 
 // printf outputs a log message to the writer associated with the backend after
-// creating a prefix for the given level and tag according to the formatHeader
-// function and formatting the provided arguments according to the given format
-// specifier.
+// formatting the provided arguments according to the given format specifier.
 func (l *Logger) printf(lvl Level, tag string, format string, args ...interface{}) {
-	t := mstime.Now() // get as early as possible
-
-	var file string
-	var line int
-	if l.b.flag&(LogFlagShortFile|LogFlagLongFile) != 0 {
-		file, line = callsite(l.b.flag)
-	}
-
-	buf := make([]byte, 0, normalLogSize)
-
-	formatHeader(&buf, t, lvl.String(), tag, file, line)
-	bytesBuf := bytes.NewBuffer(buf)
-	_, _ = fmt.Fprintf(bytesBuf, format, args...)
-	bytesBuf.WriteByte('\n')
-
-	if !l.b.IsRunning() {
-		_, _ = fmt.Fprintf(os.Stderr, bytesBuf.String())
-		panic("Writing to the logger when it's not running")
-	}
-	l.writeChan <- logEntry{bytesBuf.Bytes(), lvl}
+	l.output(lvl, tag, fmt.Sprintf(format, args...))
 }
 
 // print outputs a log message to the writer associated with the backend after
-// creating a prefix for the given level and tag according to the formatHeader
-// function and formatting the provided arguments using the default formatting
-// rules.
+// formatting the provided arguments using the default formatting rules.
 func (l *Logger) print(lvl Level, tag string, args ...interface{}) {
-	if atomic.LoadUint32(&l.b.isRunning) == 0 {
-		panic("printing log without initializing")
-	}
+	message := fmt.Sprintln(args...)
+	l.output(lvl, tag, message[:len(message)-1])
+}
+
+// output formats the given message along with the logger's fields according
+// to the backend's format, and sends it to the backend.
+func (l *Logger) output(lvl Level, tag string, message string) {
 	t := mstime.Now() // get as early as possible
 
 	var file string
@@ -203,14 +184,20 @@ func (l *Logger) print(lvl Level, tag string, args ...interface{}) {
 	}
 
 	buf := make([]byte, 0, normalLogSize)
-	formatHeader(&buf, t, lvl.String(), tag, file, line)
-	bytesBuf := bytes.NewBuffer(buf)
-	_, _ = fmt.Fprintln(bytesBuf, args...)
+	if l.b.Format() == FormatJSON {
+		formatJSON(&buf, t, lvl, tag, file, line, message, l.fields)
+	} else {
+		formatHeader(&buf, t, lvl.String(), tag, file, line)
+		buf = append(buf, message...)
+		formatFields(&buf, l.fields)
+	}
+	buf = append(buf, '\n')
 
 	if !l.b.IsRunning() {
+		_, _ = fmt.Fprint(os.Stderr, string(buf))
 		panic("Writing to the logger when it's not running")
 	}
-	l.writeChan <- logEntry{bytesBuf.Bytes(), lvl}
+	l.writeChan <- logEntry{buf, lvl}
 }
 
 // From stdlib log package.
@@ -269,7 +256,7 @@ func formatHeader(buf *[]byte, t mstime.Time, lvl, tag string, file string, line
 // caller of the subsystem logger. It is used to recover the filename and line
 // number of the logging call if either the short or long file flags are
 // specified.
-const calldepth = 4
+const calldepth = 5
 
 // callsite returns the file name and line number of the callsite to the
 // subsystem logger.
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// DefaultRegistry is the registry the metrics of all the node's packages are registered in.
+// It also exports the metrics of the Go runtime and of the process
+var DefaultRegistry = prometheus.NewRegistry()
+
+func init() {
+	DefaultRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// MustRegister adds the given collectors to the DefaultRegistry, and panics if it fails.
+// It's meant to be used for metrics that are defined as package variables
+func MustRegister(collectors ...prometheus.Collector) {
+	DefaultRegistry.MustRegister(collectors...)
+}
+
+// Register adds the given collectors to the DefaultRegistry. If any of them fails
+// to register, the ones that were already registered are unregistered
+func Register(collectors ...prometheus.Collector) error {
+	for i, collector := range collectors {
+		err := DefaultRegistry.Register(collector)
+		if err != nil {
+			Unregister(collectors[:i]...)
+			return err
+		}
+	}
+	return nil
+}
+
+// Unregister removes the given collectors from the DefaultRegistry
+func Unregister(collectors ...prometheus.Collector) {
+	for _, collector := range collectors {
+		DefaultRegistry.Unregister(collector)
+	}
+}
+
+// DefaultDurationBuckets are histogram buckets, in seconds, that fit
+// the durations of most operations of the node
+var DefaultDurationBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// LabeledValue is a value of a metric family with labels, along with its label values
+type LabeledValue struct {
+	LabelValues []string
+	Value       float64
+}
+
+// funcVec is a metric family whose label values and values are computed by a function
+// whenever the metrics are collected. It fills the gap of prometheus.NewGaugeFunc,
+// which doesn't support variable labels
+type funcVec struct {
+	descriptor *prometheus.Desc
+	valueType  prometheus.ValueType
+	function   func() []LabeledValue
+}
+
+// NewGaugeVecFunc creates a family of gauges with the given label names, whose label
+// values and values are the result of the given function. The function must only
+// return a small and bounded set of label values
+func NewGaugeVecFunc(name, help string, function func() []LabeledValue, labelNames ...string) prometheus.Collector {
+	return &funcVec{
+		descriptor: prometheus.NewDesc(name, help, labelNames, nil),
+		valueType:  prometheus.GaugeValue,
+		function:   function,
+	}
+}
+
+func (fv *funcVec) Describe(descriptors chan<- *prometheus.Desc) {
+	descriptors <- fv.descriptor
+}
+
+func (fv *funcVec) Collect(metrics chan<- prometheus.Metric) {
+	for _, labeledValue := range fv.function() {
+		metric, err := prometheus.NewConstMetric(fv.descriptor, fv.valueType, labeledValue.Value,
+			labeledValue.LabelValues...)
+		if err != nil {
+			log.Debugf("Error collecting a metric: %s", err)
+			continue
+		}
+		metrics <- metric
+	}
+}
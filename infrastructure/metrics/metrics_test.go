@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestServer(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "test_server_requests_total",
+		Help: "A test counter",
+	})
+	counter.Inc()
+	gaugeVecFunc := NewGaugeVecFunc("test_server_func", "A test function gauge", func() []LabeledValue {
+		return []LabeledValue{
+			{LabelValues: []string{"a"}, Value: 1.5},
+			{LabelValues: []string{"b", "extra"}, Value: 2},
+		}
+	}, "kind")
+	err := Register(counter, gaugeVecFunc)
+	if err != nil {
+		t.Fatalf("Register: %+v", err)
+	}
+	defer Unregister(counter, gaugeVecFunc)
+
+	server, err := NewServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("NewServer: %+v", err)
+	}
+	server.Start()
+	defer server.Stop()
+
+	response, err := http.Get("http://" + server.Address().String() + Path)
+	if err != nil {
+		t.Fatalf("Get: %s", err)
+	}
+	defer response.Body.Close()
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected content type %s", response.Header.Get("Content-Type"))
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %s", err)
+	}
+	for _, expectedLine := range []string{
+		"\ntest_server_requests_total 1\n",
+		"\ntest_server_func{kind=\"a\"} 1.5\n",
+		"\ngo_goroutines ",
+	} {
+		if !strings.Contains(string(body), expectedLine) {
+			t.Fatalf("the response doesn't contain %q:\n%s", expectedLine, body)
+		}
+	}
+	// A labeled value with the wrong amount of label values is skipped
+	if strings.Contains(string(body), "extra") {
+		t.Fatalf("the response contains a malformed labeled value:\n%s", body)
+	}
+}
+
+func TestRegisterIsAtomic(t *testing.T) {
+	first := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_register_first", Help: "A test gauge"})
+	duplicate := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_register_first", Help: "A test gauge"})
+	err := Register(first, duplicate)
+	if err == nil {
+		t.Fatalf("Registering a duplicate metric unexpectedly succeeded")
+	}
+
+	// The first gauge should have been unregistered when the duplicate failed
+	err = Register(first)
+	if err != nil {
+		t.Fatalf("Register: %+v", err)
+	}
+	Unregister(first)
+}
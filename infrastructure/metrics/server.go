@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the HTTP path metrics are served at
+const Path = "/metrics"
+
+// Server serves the metrics of the DefaultRegistry over HTTP.
+//
+// NOTE: The server doesn't authenticate its clients. Anyone who can reach its
+// address can read the metrics, so it should only listen on interfaces that
+// are reachable by trusted hosts.
+type Server struct {
+	httpServer *http.Server
+	listener   net.Listener
+}
+
+// NewServer creates a Server that listens on the given address
+func NewServer(listenAddress string) (*Server, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error listening for metrics requests on %s", listenAddress)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(Path, promhttp.HandlerFor(DefaultRegistry, promhttp.HandlerOpts{}))
+	return &Server{
+		httpServer: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		listener: listener,
+	}, nil
+}
+
+// Address returns the address the server listens on
+func (s *Server) Address() net.Addr {
+	return s.listener.Addr()
+}
+
+// Start begins serving metrics requests
+func (s *Server) Start() {
+	log.Infof("Metrics server listening on %s", s.listener.Addr())
+	if tcpAddress, ok := s.listener.Addr().(*net.TCPAddr); ok && !tcpAddress.IP.IsLoopback() {
+		log.Warnf("The metrics server listens on %s, which isn't a loopback address, "+
+			"and doesn't authenticate its clients", tcpAddress)
+	}
+	spawn("metrics.Server.Serve", func() {
+		err := s.httpServer.Serve(s.listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Metrics server stopped unexpectedly: %s", err)
+		}
+	})
+}
+
+// Stop stops the server
+func (s *Server) Stop() error {
+	const shutdownTimeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return errors.WithStack(s.httpServer.Shutdown(ctx))
+}
@@ -25,10 +25,17 @@ type addressRandomizer interface {
 	RandomAddresses(addresses []*address, count int) []*appmessage.NetAddress
 }
 
-// addressKey represents a pair of IP and port, the IP is always in V6 representation
+// addressKey represents a pair of IP and port, the IP is always in V6 representation.
+// For onion addresses, the IP is zero and onionHost is set instead
 type addressKey struct {
-	port    uint16
-	address ipv6
+	port      uint16
+	address   ipv6
+	onionHost string
+}
+
+// bannedKey returns the key that addresses are banned by, which ignores the port
+func (key addressKey) bannedKey() addressKey {
+	return addressKey{address: key.address, onionHost: key.onionHost}
 }
 
 type address struct {
@@ -38,10 +45,6 @@ type address struct {
 
 type ipv6 [net.IPv6len]byte
 
-func (i ipv6) equal(other ipv6) bool {
-	return i == other
-}
-
 // ErrAddressNotFound is an error returned from some functions when a
 // given address is not found in the address manager
 var ErrAddressNotFound = errors.New("address not found")
@@ -49,6 +52,10 @@ var ErrAddressNotFound = errors.New("address not found")
 // NetAddressKey returns a key of the ip address to use it in maps.
 func netAddressKey(netAddress *appmessage.NetAddress) addressKey {
 	key := addressKey{port: netAddress.Port}
+	if netAddress.IsOnion() {
+		key.onionHost = netAddress.OnionHost
+		return key
+	}
 	// all IPv4 can be represented as IPv6.
 	copy(key.address[:], netAddress.IP.To16())
 	return key
@@ -160,7 +167,7 @@ func (am *AddressManager) MarkConnectionFailure(address *appmessage.NetAddress)
 	key := netAddressKey(address)
 	entry, ok := am.store.getNotBanned(key)
 	if !ok {
-		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
+		return errors.Errorf("address %s is not registered with the address manager", address)
 	}
 	entry.connectionFailedCount = entry.connectionFailedCount + 1
 
@@ -181,7 +188,7 @@ func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress)
 	key := netAddressKey(address)
 	entry, ok := am.store.getNotBanned(key)
 	if !ok {
-		return errors.Errorf("address %s is not registered with the address manager", address.TCPAddress())
+		return errors.Errorf("address %s is not registered with the address manager", address)
 	}
 	entry.connectionFailedCount = 0
 	return am.store.updateNotBanned(key, entry)
@@ -211,9 +218,19 @@ func (am *AddressManager) notBannedAddressesWithException(exceptions []*appmessa
 	return am.store.getAllNotBannedNetAddressesWithout(exceptions)
 }
 
-// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions
+// RandomAddresses returns count addresses at random that aren't banned and aren't in exceptions.
+// Onion addresses are returned only if they're reachable
 func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.NetAddress) []*appmessage.NetAddress {
 	validAddresses := am.notBannedAddressesWithException(exceptions)
+	if !am.cfg.OnionReachable {
+		reachableAddresses := make([]*address, 0, len(validAddresses))
+		for _, address := range validAddresses {
+			if !address.netAddress.IsOnion() {
+				reachableAddresses = append(reachableAddresses, address)
+			}
+		}
+		validAddresses = reachableAddresses
+	}
 	return am.random.RandomAddresses(validAddresses, count)
 }
 
@@ -223,6 +240,18 @@ func (am *AddressManager) BestLocalAddress(remoteAddress *appmessage.NetAddress)
 	return am.localAddresses.bestLocalAddress(remoteAddress)
 }
 
+// AddLocalAddress adds the given address to the local addresses that may be
+// advertised to peers, with the given priority
+func (am *AddressManager) AddLocalAddress(netAddress *appmessage.NetAddress, priority AddressPriority) error {
+	return am.localAddresses.addLocalNetAddress(netAddress, priority)
+}
+
+// RemoveLocalAddress removes the given address from the local addresses that may
+// be advertised to peers, for example once a port mapping that made it reachable expires
+func (am *AddressManager) RemoveLocalAddress(netAddress *appmessage.NetAddress) {
+	am.localAddresses.removeLocalNetAddress(netAddress)
+}
+
 // Ban marks the given address as banned
 func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress) error {
 	am.mutex.Lock()
@@ -232,7 +261,7 @@ func (am *AddressManager) Ban(addressToBan *appmessage.NetAddress) error {
 	keysToDelete := make([]addressKey, 0)
 	for _, address := range am.store.getAllNotBannedNetAddresses() {
 		key := netAddressKey(address)
-		if key.address.equal(keyToBan.address) {
+		if key.bannedKey() == keyToBan.bannedKey() {
 			keysToDelete = append(keysToDelete, key)
 		}
 	}
@@ -255,7 +284,7 @@ func (am *AddressManager) Unban(address *appmessage.NetAddress) error {
 	key := netAddressKey(address)
 	if !am.store.isBanned(key) {
 		return errors.Wrapf(ErrAddressNotFound, "address %s "+
-			"is not registered with the address manager as banned", address.TCPAddress())
+			"is not registered with the address manager as banned", address)
 	}
 
 	return am.store.removeBanned(key)
@@ -274,7 +303,7 @@ func (am *AddressManager) IsBanned(address *appmessage.NetAddress) (bool, error)
 	if !am.store.isBanned(key) {
 		if !am.store.isNotBanned(key) {
 			return false, errors.Wrapf(ErrAddressNotFound, "address %s "+
-				"is not registered with the address manager", address.TCPAddress())
+				"is not registered with the address manager", address)
 		}
 		return false, nil
 	}
@@ -372,3 +372,57 @@ func TestOverfillAddressManager(t *testing.T) {
 		}
 	}
 }
+
+func TestOnionAddresses(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestOnionAddresses")
+	defer teardown()
+
+	ipAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Timestamp: mstime.Now()}
+	onionAddress := appmessage.NewNetAddressOnionPort(
+		"duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion", 16111)
+	err := addressManager.AddAddresses(ipAddress, onionAddress)
+	if err != nil {
+		t.Fatalf("AddAddresses() failed: %s", err)
+	}
+
+	// Onion addresses are stored and gossiped regardless of whether they're reachable
+	addresses := addressManager.Addresses()
+	if len(addresses) != 2 {
+		t.Fatalf("Unexpected amount of addresses returned from Addresses(). "+
+			"Want: %d, got: %d", 2, len(addresses))
+	}
+
+	// But they aren't selected for outbound connections unless they're reachable
+	randomAddresses := addressManager.RandomAddresses(2, nil)
+	if len(randomAddresses) != 1 || randomAddresses[0].IsOnion() {
+		t.Fatalf("RandomAddresses() unexpectedly returned %v while onion "+
+			"addresses are unreachable", randomAddresses)
+	}
+	addressManager.cfg.OnionReachable = true
+	randomAddresses = addressManager.RandomAddresses(2, nil)
+	if len(randomAddresses) != 2 {
+		t.Fatalf("Unexpected amount of addresses returned from RandomAddresses(). "+
+			"Want: %d, got: %d", 2, len(randomAddresses))
+	}
+
+	// Banning an onion address bans its host on every port
+	err = addressManager.Ban(onionAddress)
+	if err != nil {
+		t.Fatalf("Ban() failed: %s", err)
+	}
+	otherPortOnionAddress := appmessage.NewNetAddressOnionPort(onionAddress.OnionHost, 16112)
+	isBanned, err := addressManager.IsBanned(otherPortOnionAddress)
+	if err != nil {
+		t.Fatalf("IsBanned() failed: %s", err)
+	}
+	if !isBanned {
+		t.Fatalf("Address %s is unexpectedly not banned", otherPortOnionAddress)
+	}
+	isBanned, err = addressManager.IsBanned(ipAddress)
+	if err != nil {
+		t.Fatalf("IsBanned() failed: %s", err)
+	}
+	if isBanned {
+		t.Fatalf("Address %s is unexpectedly banned", ipAddress)
+	}
+}
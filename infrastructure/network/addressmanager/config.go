@@ -13,6 +13,9 @@ type Config struct {
 	ExternalIPs      []string
 	Listeners        []string
 	Lookup           func(string) ([]net.IP, error)
+
+	// OnionReachable is whether onion addresses can be connected to
+	OnionReachable bool
 }
 
 // NewConfig returns a new address manager Config.
@@ -23,5 +26,6 @@ func NewConfig(cfg *config.Config) *Config {
 		ExternalIPs:      cfg.ExternalIPs,
 		Listeners:        cfg.Listeners,
 		Lookup:           cfg.Lookup,
+		OnionReachable:   cfg.OnionDial != nil,
 	}
 }
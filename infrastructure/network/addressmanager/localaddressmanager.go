@@ -63,7 +63,7 @@ func newLocalAddressManager(cfg *Config) (*localAddressManager, error) {
 // with the given priority.
 func (lam *localAddressManager) addLocalNetAddress(netAddress *appmessage.NetAddress, priority AddressPriority) error {
 	if !IsRoutable(netAddress, lam.cfg.AcceptUnroutable) {
-		return errors.Errorf("address %s is not routable", netAddress.IP)
+		return errors.Errorf("address %s is not routable", netAddress)
 	}
 
 	lam.mutex.Lock()
@@ -84,6 +84,14 @@ func (lam *localAddressManager) addLocalNetAddress(netAddress *appmessage.NetAdd
 	return nil
 }
 
+// removeLocalNetAddress removes netAddress from the list of known local addresses
+func (lam *localAddressManager) removeLocalNetAddress(netAddress *appmessage.NetAddress) {
+	lam.mutex.Lock()
+	defer lam.mutex.Unlock()
+
+	delete(lam.localAddresses, netAddressKey(netAddress))
+}
+
 // bestLocalAddress returns the most appropriate local address to use
 // for the given remote address.
 func (lam *localAddressManager) bestLocalAddress(remoteAddress *appmessage.NetAddress) *appmessage.NetAddress {
@@ -230,8 +238,12 @@ func (lam *localAddressManager) initListeners() error {
 }
 
 // hostToNetAddress returns a netaddress given a host address. If
-// the host is not an IP address it will be resolved.
+// the host is not an IP address or an onion host it will be resolved.
 func (lam *localAddressManager) hostToNetAddress(host string, port uint16) (*appmessage.NetAddress, error) {
+	if appmessage.IsOnionHost(host) {
+		return appmessage.NewNetAddressOnionPort(host, port), nil
+	}
+
 	ip := net.ParseIP(host)
 	if ip == nil {
 		ips, err := lam.lookupFunc(host)
@@ -322,6 +334,21 @@ func reachabilityFrom(localAddress, remoteAddress *appmessage.NetAddress, accept
 			IsLocal(na) || (IsRFC4193(na)))
 	}
 
+	// Onion services are reachable from anywhere, and can reach
+	// addresses that are reachable from the IPv4 internet
+	if localAddress.IsOnion() {
+		if remoteAddress.IsOnion() {
+			return Private
+		}
+		return Default
+	}
+	if remoteAddress.IsOnion() {
+		if IsRoutable(localAddress) && IsIPv4(localAddress) {
+			return Ipv4
+		}
+		return Default
+	}
+
 	if !IsRoutable(remoteAddress) {
 		return Unreachable
 	}
@@ -193,11 +193,16 @@ func IsRFC6598(na *appmessage.NetAddress) bool {
 	return rfc6598Net.Contains(na.IP)
 }
 
-// IsValid returns whether or not the passed address is valid. The address is
+// IsValid returns whether or not the passed address is valid. Onion addresses
+// are valid if they're well-formed. Other addresses are
 // considered invalid under the following circumstances:
 // IPv4: It is either a zero or all bits set address.
 // IPv6: It is either a zero or RFC3849 documentation address.
 func IsValid(na *appmessage.NetAddress) bool {
+	if na.IsOnion() {
+		return appmessage.IsValidOnionHost(na.OnionHost)
+	}
+
 	// IsUnspecified returns if address is 0, so only all bits set, and
 	// RFC3849 need to be explicitly checked.
 	return na.IP != nil && !(na.IP.IsUnspecified() ||
@@ -208,6 +213,9 @@ func IsValid(na *appmessage.NetAddress) bool {
 // the public internet. This is true as long as the address is valid and is not
 // in any reserved ranges.
 func IsRoutable(na *appmessage.NetAddress, acceptUnroutable bool) bool {
+	if na.IsOnion() {
+		return IsValid(na)
+	}
 	if acceptUnroutable {
 		return !IsLocal(na)
 	}
@@ -220,9 +228,14 @@ func IsRoutable(na *appmessage.NetAddress, acceptUnroutable bool) bool {
 
 // GroupKey returns a string representing the network group an address is part
 // of. This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
-// "local" for a local address, and the string "unroutable" for an unroutable
-// address.
+// "local" for a local address, the string "onion" for an onion address, and the
+// string "unroutable" for an unroutable address.
 func (am *AddressManager) GroupKey(na *appmessage.NetAddress) string {
+	if na.IsOnion() {
+		// Onion addresses can't be grouped by their location,
+		// so they're all considered to be in the same group
+		return "onion"
+	}
 	if IsLocal(na) {
 		return "local"
 	}
@@ -15,14 +15,14 @@ var bannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
 type addressStore struct {
 	database           database.Database
 	notBannedAddresses map[addressKey]*address
-	bannedAddresses    map[ipv6]*address
+	bannedAddresses    map[addressKey]*address
 }
 
 func newAddressStore(database database.Database) (*addressStore, error) {
 	addressStore := &addressStore{
 		database:           database,
 		notBannedAddresses: map[addressKey]*address{},
-		bannedAddresses:    map[ipv6]*address{},
+		bannedAddresses:    map[addressKey]*address{},
 	}
 	err := addressStore.restoreNotBannedAddresses()
 	if err != nil {
@@ -74,15 +74,14 @@ func (as *addressStore) restoreBannedAddresses() error {
 		if err != nil {
 			return err
 		}
-		var ipv6 ipv6
-		copy(ipv6[:], databaseKey.Suffix())
+		key := as.deserializeBannedAddressKey(databaseKey.Suffix())
 
 		serializedNetAddress, err := cursor.Value()
 		if err != nil {
 			return err
 		}
 		netAddress := as.deserializeAddress(serializedNetAddress)
-		as.bannedAddresses[ipv6] = netAddress
+		as.bannedAddresses[key] = netAddress
 	}
 	return nil
 }
@@ -106,7 +105,7 @@ func (as *addressStore) add(key addressKey, address *address) error {
 // updateNotBanned updates the not-banned address collection
 func (as *addressStore) updateNotBanned(key addressKey, address *address) error {
 	if _, ok := as.notBannedAddresses[key]; !ok {
-		return errors.Errorf("address %s is not in the store", address.netAddress.TCPAddress())
+		return errors.Errorf("address %s is not in the store", address.netAddress)
 	}
 
 	as.notBannedAddresses[key] = address
@@ -162,11 +161,11 @@ func (as *addressStore) isNotBanned(key addressKey) bool {
 }
 
 func (as *addressStore) addBanned(key addressKey, address *address) error {
-	if _, ok := as.bannedAddresses[key.address]; ok {
+	if _, ok := as.bannedAddresses[key.bannedKey()]; ok {
 		return nil
 	}
 
-	as.bannedAddresses[key.address] = address
+	as.bannedAddresses[key.bannedKey()] = address
 
 	databaseKey := as.bannedDatabaseKey(key)
 	serializedAddress := as.serializeAddress(address)
@@ -174,7 +173,7 @@ func (as *addressStore) addBanned(key addressKey, address *address) error {
 }
 
 func (as *addressStore) removeBanned(key addressKey) error {
-	delete(as.bannedAddresses, key.address)
+	delete(as.bannedAddresses, key.bannedKey())
 
 	databaseKey := as.bannedDatabaseKey(key)
 	return as.database.Delete(databaseKey)
@@ -189,12 +188,12 @@ func (as *addressStore) getAllBannedNetAddresses() []*appmessage.NetAddress {
 }
 
 func (as *addressStore) isBanned(key addressKey) bool {
-	_, ok := as.bannedAddresses[key.address]
+	_, ok := as.bannedAddresses[key.bannedKey()]
 	return ok
 }
 
 func (as *addressStore) getBanned(key addressKey) (*address, bool) {
-	bannedAddress, ok := as.bannedAddresses[key.address]
+	bannedAddress, ok := as.bannedAddresses[key.bannedKey()]
 	return bannedAddress, ok
 }
 
@@ -213,16 +212,34 @@ func (as *addressStore) notBannedDatabaseKey(key addressKey) *database.Key {
 	return notBannedAddressBucket.Key(serializedKey)
 }
 
+// The onion hosts of onion addresses are appended to their serialized keys and
+// addresses, so that addresses serialized before onion addresses were supported
+// remain readable
+
 func (as *addressStore) bannedDatabaseKey(key addressKey) *database.Key {
-	return bannedAddressBucket.Key(key.address[:])
+	serializedKey := make([]byte, 16+len(key.onionHost)) // ipv6 + onion host
+	copy(serializedKey[:], key.address[:])
+	copy(serializedKey[16:], key.onionHost)
+	return bannedAddressBucket.Key(serializedKey)
+}
+
+func (as *addressStore) deserializeBannedAddressKey(serializedKey []byte) addressKey {
+	var ip ipv6
+	copy(ip[:], serializedKey[:])
+
+	return addressKey{
+		address:   ip,
+		onionHost: string(serializedKey[16:]),
+	}
 }
 
 func (as *addressStore) serializeAddressKey(key addressKey) []byte {
-	serializedSize := 16 + 2 // ipv6 + port
+	serializedSize := 16 + 2 + len(key.onionHost) // ipv6 + port + onion host
 	serializedKey := make([]byte, serializedSize)
 
 	copy(serializedKey[:], key.address[:])
 	binary.LittleEndian.PutUint16(serializedKey[16:], key.port)
+	copy(serializedKey[18:], key.onionHost)
 
 	return serializedKey
 }
@@ -234,37 +251,44 @@ func (as *addressStore) deserializeAddressKey(serializedKey []byte) addressKey {
 	port := binary.LittleEndian.Uint16(serializedKey[16:])
 
 	return addressKey{
-		port:    port,
-		address: ip,
+		port:      port,
+		address:   ip,
+		onionHost: string(serializedKey[18:]),
 	}
 }
 
 func (as *addressStore) serializeAddress(address *address) []byte {
-	serializedSize := 16 + 2 + 8 + 8 // ipv6 + port + timestamp + connectionFailedCount
+	// ipv6 + port + timestamp + connectionFailedCount + onion host
+	serializedSize := 16 + 2 + 8 + 8 + len(address.netAddress.OnionHost)
 	serializedNetAddress := make([]byte, serializedSize)
 
 	copy(serializedNetAddress[:], address.netAddress.IP.To16()[:])
 	binary.LittleEndian.PutUint16(serializedNetAddress[16:], address.netAddress.Port)
 	binary.LittleEndian.PutUint64(serializedNetAddress[18:], uint64(address.netAddress.Timestamp.UnixMilliseconds()))
 	binary.LittleEndian.PutUint64(serializedNetAddress[26:], uint64(address.connectionFailedCount))
+	copy(serializedNetAddress[34:], address.netAddress.OnionHost)
 
 	return serializedNetAddress
 }
 
 func (as *addressStore) deserializeAddress(serializedAddress []byte) *address {
-	ip := make(net.IP, 16)
-	copy(ip[:], serializedAddress[:])
-
 	port := binary.LittleEndian.Uint16(serializedAddress[16:])
 	timestamp := mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedAddress[18:])))
 	connectionFailedCount := binary.LittleEndian.Uint64(serializedAddress[26:])
 
+	netAddress := &appmessage.NetAddress{
+		Port:      port,
+		Timestamp: timestamp,
+	}
+	if len(serializedAddress) > 34 {
+		netAddress.OnionHost = string(serializedAddress[34:])
+	} else {
+		netAddress.IP = make(net.IP, 16)
+		copy(netAddress.IP[:], serializedAddress[:])
+	}
+
 	return &address{
-		netAddress: &appmessage.NetAddress{
-			IP:        ip,
-			Port:      port,
-			Timestamp: timestamp,
-		},
+		netAddress:            netAddress,
 		connectionFailedCount: connectionFailedCount,
 	}
 }
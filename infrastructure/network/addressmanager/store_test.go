@@ -45,3 +45,34 @@ func TestAddressSerialization(t *testing.T) {
 			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
 	}
 }
+
+func TestOnionAddressSerialization(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestOnionAddressSerialization")
+	defer teardown()
+	addressStore := addressManager.store
+
+	testNetAddress := &appmessage.NetAddress{
+		OnionHost: "duckduckgogg42xjoc72x3sjasowoarfbgcmvfimaftt6twagswzczad.onion",
+		Port:      16111,
+		Timestamp: mstime.Now(),
+	}
+
+	testAddressKey := netAddressKey(testNetAddress)
+	serializedTestAddressKey := addressStore.serializeAddressKey(testAddressKey)
+	deserializedTestAddressKey := addressStore.deserializeAddressKey(serializedTestAddressKey)
+	if !reflect.DeepEqual(testAddressKey, deserializedTestAddressKey) {
+		t.Fatalf("testAddressKey and deserializedTestAddressKey are not equal\n"+
+			"testAddressKey:%+v\ndeserializedTestAddressKey:%+v", testAddressKey, deserializedTestAddressKey)
+	}
+
+	testAddress := &address{
+		netAddress:            testNetAddress,
+		connectionFailedCount: 3,
+	}
+	serializedTestAddress := addressStore.serializeAddress(testAddress)
+	deserializedTestAddress := addressStore.deserializeAddress(serializedTestAddress)
+	if !reflect.DeepEqual(testAddress, deserializedTestAddress) {
+		t.Fatalf("testAddress and deserializedTestAddress are not equal\n"+
+			"testAddress:%+v\ndeserializedTestAddress:%+v", testAddress, deserializedTestAddress)
+	}
+}
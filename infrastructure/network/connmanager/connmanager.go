@@ -264,7 +264,7 @@ func (c *ConnectionManager) seedFromDNS() {
 				_ = c.addressManager.AddAddresses(addresses...)
 			})
 
-		dnsseed.SeedFromGRPC(cfg.NetParams(), cfg.GRPCSeed, false, nil,
+		dnsseed.SeedFromGRPC(cfg.NetParams(), cfg.GRPCSeed, false, nil, netadapter.ProxyDialFunc(cfg),
 			func(addresses []*appmessage.NetAddress) {
 				_ = c.addressManager.AddAddresses(addresses...)
 			})
@@ -34,7 +34,7 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses)
 
 	for _, netAddress := range netAddresses {
-		addressString := netAddress.TCPAddress().String()
+		addressString := netAddress.String()
 
 		log.Debugf("Connecting to %s because we have %d outgoing connections and the target is "+
 			"%d", addressString, len(c.activeOutgoing), c.targetOutgoing)
@@ -94,9 +94,11 @@ func SeedFromDNS(dagParams *dagconfig.Params, customSeed string, includeAllSubne
 	}
 }
 
-// SeedFromGRPC send gRPC request to get list of peers for a given host
+// SeedFromGRPC send gRPC request to get list of peers for a given host.
+// If dial is not nil, the connection to the seeder is established with it
 func SeedFromGRPC(dagParams *dagconfig.Params, customSeed string, includeAllSubnetworks bool,
-	subnetworkID *externalapi.DomainSubnetworkID, seedFn OnSeed) {
+	subnetworkID *externalapi.DomainSubnetworkID, dial func(context.Context, string) (net.Conn, error),
+	seedFn OnSeed) {
 
 	var grpcSeeds []string
 	if customSeed != "" {
@@ -109,7 +111,11 @@ func SeedFromGRPC(dagParams *dagconfig.Params, customSeed string, includeAllSubn
 		spawn("SeedFromGRPC", func() {
 			randSource := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-			conn, err := grpc.Dial(host, grpc.WithInsecure())
+			dialOptions := []grpc.DialOption{grpc.WithInsecure()}
+			if dial != nil {
+				dialOptions = append(dialOptions, grpc.WithContextDialer(dial))
+			}
+			conn, err := grpc.Dial(host, dialOptions...)
 			client := pb2.NewPeerServiceClient(conn)
 			if err != nil {
 				log.Warnf("Failed to connect to gRPC server: %s", host)
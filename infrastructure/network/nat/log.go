@@ -0,0 +1,9 @@
+package nat
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/kaspanet/kaspad/util/panics"
+)
+
+var log = logger.RegisterSubSystem("NATT")
+var spawn = panics.GoroutineWrapperFunc(log)
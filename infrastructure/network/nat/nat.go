@@ -0,0 +1,52 @@
+package nat
+
+import (
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NAT is a gateway that may map ports of this host to its external address
+type NAT interface {
+	// Type returns the name of the protocol used to talk to the gateway
+	Type() string
+
+	// ExternalAddress returns the external IP address of the gateway
+	ExternalAddress() (net.IP, error)
+
+	// AddPortMapping maps requestedExternalPort of the gateway to internalPort of this
+	// host for the given lifetime, and returns the external port that was actually mapped.
+	// protocol is either "TCP" or "UDP"
+	AddPortMapping(protocol string, internalPort, requestedExternalPort uint16, description string,
+		lifetime time.Duration) (externalPort uint16, err error)
+
+	// DeletePortMapping removes a mapping that was previously added with AddPortMapping
+	DeletePortMapping(protocol string, internalPort, externalPort uint16) error
+}
+
+const upnpDiscoveryTimeout = 3 * time.Second
+
+// Discover looks for a NAT gateway on the local network. UPnP is attempted
+// first, and then NAT-PMP on the default gateway
+func Discover() (NAT, error) {
+	upnpNAT, upnpErr := discoverUPnP(ssdpMulticastAddress, upnpDiscoveryTimeout)
+	if upnpErr == nil {
+		return upnpNAT, nil
+	}
+	log.Debugf("UPnP discovery failed: %s", upnpErr)
+
+	gateways, err := defaultGateways()
+	if err != nil {
+		return nil, errors.Wrapf(err, "no UPnP gateway found (%s), and no default gateway to "+
+			"try NAT-PMP with", upnpErr)
+	}
+	for _, gateway := range gateways {
+		natPMPNAT, err := discoverNATPMP(&net.UDPAddr{IP: gateway, Port: natPMPPort})
+		if err == nil {
+			return natPMPNAT, nil
+		}
+		log.Debugf("NAT-PMP discovery on %s failed: %s", gateway, err)
+	}
+	return nil, errors.Errorf("no UPnP or NAT-PMP gateway found")
+}
@@ -0,0 +1,374 @@
+package nat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeMapping struct {
+	internalPort   uint16
+	internalClient string
+	lifetime       time.Duration
+}
+
+// fakeGateway is a gateway that speaks both UPnP and NAT-PMP on the loopback interface
+type fakeGateway struct {
+	t *testing.T
+
+	mutex               sync.Mutex
+	externalIP          net.IP
+	mappings            map[uint16]fakeMapping
+	mappingRequestCount int
+	onlyPermanentLeases bool
+
+	ssdpConn   *net.UDPConn
+	httpServer *httptest.Server
+	natPMPConn *net.UDPConn
+}
+
+func newFakeGateway(t *testing.T) *fakeGateway {
+	gateway := &fakeGateway{
+		t:          t,
+		externalIP: net.ParseIP("203.0.113.7"),
+		mappings:   map[uint16]fakeMapping{},
+	}
+
+	var err error
+	gateway.ssdpConn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	gateway.natPMPConn, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rootDesc.xml", gateway.serveDeviceDescription)
+	mux.HandleFunc("/ctl/IPConn", gateway.serveControl)
+	gateway.httpServer = httptest.NewServer(mux)
+
+	go gateway.serveSSDP()
+	go gateway.serveNATPMP()
+	return gateway
+}
+
+func (g *fakeGateway) close() {
+	g.ssdpConn.Close()
+	g.natPMPConn.Close()
+	g.httpServer.Close()
+}
+
+func (g *fakeGateway) ssdpAddress() *net.UDPAddr {
+	return g.ssdpConn.LocalAddr().(*net.UDPAddr)
+}
+
+func (g *fakeGateway) natPMPAddress() *net.UDPAddr {
+	return g.natPMPConn.LocalAddr().(*net.UDPAddr)
+}
+
+func (g *fakeGateway) setExternalIP(externalIP net.IP) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	g.externalIP = externalIP
+}
+
+func (g *fakeGateway) mapping(externalPort uint16) (fakeMapping, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	mapping, ok := g.mappings[externalPort]
+	return mapping, ok
+}
+
+func (g *fakeGateway) requestCount() int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.mappingRequestCount
+}
+
+func (g *fakeGateway) serveSSDP() {
+	buffer := make([]byte, 2048)
+	for {
+		n, address, err := g.ssdpConn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		if !strings.HasPrefix(string(buffer[:n]), "M-SEARCH") {
+			continue
+		}
+		response := "HTTP/1.1 200 OK\r\n" +
+			"CACHE-CONTROL: max-age=120\r\n" +
+			"ST: " + internetGatewayDeviceType + "\r\n" +
+			"LOCATION: " + g.httpServer.URL + "/rootDesc.xml\r\n\r\n"
+		_, _ = g.ssdpConn.WriteToUDP([]byte(response), address)
+	}
+}
+
+func (g *fakeGateway) serveDeviceDescription(writer http.ResponseWriter, _ *http.Request) {
+	_, _ = io.WriteString(writer, `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <serviceList>
+      <service>
+        <serviceType>urn:schemas-upnp-org:service:Layer3Forwarding:1</serviceType>
+        <controlURL>/ctl/L3F</controlURL>
+      </service>
+    </serviceList>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/ctl/IPConn</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`)
+}
+
+func (g *fakeGateway) serveControl(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		g.t.Errorf("error reading the SOAP request: %s", err)
+		return
+	}
+	argument := func(name string) string {
+		value, err := soapResponseValue(body, name)
+		if err != nil {
+			g.t.Errorf("%s", err)
+		}
+		return value
+	}
+	soapAction := strings.Trim(request.Header.Get("SOAPAction"), `"`)
+	if !strings.HasPrefix(soapAction, "urn:schemas-upnp-org:service:WANIPConnection:1#") {
+		g.t.Errorf("unexpected SOAPAction %s", soapAction)
+	}
+	action := soapAction[strings.Index(soapAction, "#")+1:]
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	responseArguments := ""
+	switch action {
+	case "GetExternalIPAddress":
+		responseArguments = "<NewExternalIPAddress>" + g.externalIP.String() + "</NewExternalIPAddress>"
+	case "AddPortMapping":
+		g.mappingRequestCount++
+		leaseDuration, _ := strconv.Atoi(argument("NewLeaseDuration"))
+		if g.onlyPermanentLeases && leaseDuration != 0 {
+			writer.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(writer, `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body><s:Fault>
+<faultcode>s:Client</faultcode><faultstring>UPnPError</faultstring><detail>
+<UPnPError xmlns="urn:schemas-upnp-org:control-1-0"><errorCode>725</errorCode>
+<errorDescription>OnlyPermanentLeasesSupported</errorDescription></UPnPError>
+</detail></s:Fault></s:Body></s:Envelope>`)
+			return
+		}
+		externalPort, _ := strconv.Atoi(argument("NewExternalPort"))
+		internalPort, _ := strconv.Atoi(argument("NewInternalPort"))
+		g.mappings[uint16(externalPort)] = fakeMapping{
+			internalPort:   uint16(internalPort),
+			internalClient: argument("NewInternalClient"),
+			lifetime:       time.Duration(leaseDuration) * time.Second,
+		}
+	case "DeletePortMapping":
+		externalPort, _ := strconv.Atoi(argument("NewExternalPort"))
+		delete(g.mappings, uint16(externalPort))
+	default:
+		g.t.Errorf("unexpected action %s", action)
+	}
+
+	_, _ = fmt.Fprintf(writer, `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<u:%sResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">%s</u:%sResponse>
+</s:Body></s:Envelope>`, action, responseArguments, action)
+}
+
+func (g *fakeGateway) serveNATPMP() {
+	buffer := make([]byte, 64)
+	for {
+		n, address, err := g.natPMPConn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+		if n < 2 || buffer[0] != natPMPVersion {
+			continue
+		}
+
+		g.mutex.Lock()
+		var response []byte
+		switch buffer[1] {
+		case natPMPOpExternalAddress:
+			response = make([]byte, 12)
+			copy(response[8:12], g.externalIP.To4())
+		case natPMPOpMapTCP:
+			g.mappingRequestCount++
+			internalPort := binary.BigEndian.Uint16(buffer[4:6])
+			requestedExternalPort := binary.BigEndian.Uint16(buffer[6:8])
+			lifetime := time.Duration(binary.BigEndian.Uint32(buffer[8:12])) * time.Second
+			externalPort := requestedExternalPort
+			if lifetime == 0 {
+				for mappedPort, mapping := range g.mappings {
+					if mapping.internalPort == internalPort {
+						delete(g.mappings, mappedPort)
+					}
+				}
+			} else {
+				// Like real gateways, hand out a different external port than requested
+				if externalPort == internalPort {
+					externalPort++
+				}
+				g.mappings[externalPort] = fakeMapping{
+					internalPort:   internalPort,
+					internalClient: address.IP.String(),
+					lifetime:       lifetime,
+				}
+			}
+			response = make([]byte, 16)
+			binary.BigEndian.PutUint16(response[8:10], internalPort)
+			binary.BigEndian.PutUint16(response[10:12], externalPort)
+			binary.BigEndian.PutUint32(response[12:16], uint32(lifetime/time.Second))
+		default:
+			response = make([]byte, 8)
+			binary.BigEndian.PutUint16(response[2:4], 5) // unsupported opcode
+		}
+		g.mutex.Unlock()
+
+		response[0] = natPMPVersion
+		response[1] = buffer[1] | natPMPOpResponseFlag
+		_, _ = g.natPMPConn.WriteToUDP(response, address)
+	}
+}
+
+func TestUPnP(t *testing.T) {
+	for _, onlyPermanentLeases := range []bool{false, true} {
+		gateway := newFakeGateway(t)
+		gateway.onlyPermanentLeases = onlyPermanentLeases
+
+		nat, err := discoverUPnP(gateway.ssdpAddress(), time.Second)
+		if err != nil {
+			t.Fatalf("discoverUPnP: %s", err)
+		}
+		if nat.Type() != "UPnP" {
+			t.Fatalf("unexpected NAT type %s", nat.Type())
+		}
+
+		externalAddress, err := nat.ExternalAddress()
+		if err != nil {
+			t.Fatalf("ExternalAddress: %s", err)
+		}
+		if !externalAddress.Equal(gateway.externalIP) {
+			t.Fatalf("expected external address %s, got %s", gateway.externalIP, externalAddress)
+		}
+
+		externalPort, err := nat.AddPortMapping("TCP", 16111, 16111, "kaspad test", 20*time.Minute)
+		if err != nil {
+			t.Fatalf("AddPortMapping: %s", err)
+		}
+		if externalPort != 16111 {
+			t.Fatalf("expected external port 16111, got %d", externalPort)
+		}
+		mapping, ok := gateway.mapping(externalPort)
+		if !ok {
+			t.Fatalf("the port was not mapped on the gateway")
+		}
+		expectedLifetime := 20 * time.Minute
+		if onlyPermanentLeases {
+			expectedLifetime = 0
+		}
+		if mapping.internalPort != 16111 || mapping.internalClient != "127.0.0.1" ||
+			mapping.lifetime != expectedLifetime {
+			t.Fatalf("unexpected mapping %+v", mapping)
+		}
+
+		err = nat.DeletePortMapping("TCP", 16111, externalPort)
+		if err != nil {
+			t.Fatalf("DeletePortMapping: %s", err)
+		}
+		if _, ok := gateway.mapping(externalPort); ok {
+			t.Fatalf("the port mapping was not deleted")
+		}
+
+		gateway.close()
+	}
+}
+
+func TestUPnPNoGateway(t *testing.T) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %s", err)
+	}
+	defer conn.Close()
+
+	_, err = discoverUPnP(conn.LocalAddr().(*net.UDPAddr), 100*time.Millisecond)
+	if err == nil {
+		t.Fatalf("expected discovery to fail when nothing responds")
+	}
+}
+
+func TestNATPMP(t *testing.T) {
+	gateway := newFakeGateway(t)
+	defer gateway.close()
+
+	nat, err := discoverNATPMP(gateway.natPMPAddress())
+	if err != nil {
+		t.Fatalf("discoverNATPMP: %s", err)
+	}
+	if nat.Type() != "NAT-PMP" {
+		t.Fatalf("unexpected NAT type %s", nat.Type())
+	}
+
+	externalAddress, err := nat.ExternalAddress()
+	if err != nil {
+		t.Fatalf("ExternalAddress: %s", err)
+	}
+	if !externalAddress.Equal(gateway.externalIP) {
+		t.Fatalf("expected external address %s, got %s", gateway.externalIP, externalAddress)
+	}
+
+	externalPort, err := nat.AddPortMapping("TCP", 16111, 16111, "kaspad test", time.Hour)
+	if err != nil {
+		t.Fatalf("AddPortMapping: %s", err)
+	}
+	if externalPort != 16112 {
+		t.Fatalf("expected the external port the gateway chose, 16112, got %d", externalPort)
+	}
+	mapping, ok := gateway.mapping(externalPort)
+	if !ok {
+		t.Fatalf("the port was not mapped on the gateway")
+	}
+	if mapping.internalPort != 16111 || mapping.lifetime != time.Hour {
+		t.Fatalf("unexpected mapping %+v", mapping)
+	}
+
+	err = nat.DeletePortMapping("TCP", 16111, externalPort)
+	if err != nil {
+		t.Fatalf("DeletePortMapping: %s", err)
+	}
+	if _, ok := gateway.mapping(externalPort); ok {
+		t.Fatalf("the port mapping was not deleted")
+	}
+
+	_, err = nat.AddPortMapping("SCTP", 16111, 16111, "kaspad test", time.Hour)
+	if err == nil {
+		t.Fatalf("expected mapping an unsupported protocol to fail")
+	}
+}
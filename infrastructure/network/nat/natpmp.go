@@ -0,0 +1,222 @@
+package nat
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NAT-PMP is specified in RFC 6886
+
+const natPMPPort = 5351
+
+const (
+	natPMPVersion = 0
+
+	natPMPOpExternalAddress = 0
+	natPMPOpMapUDP          = 1
+	natPMPOpMapTCP          = 2
+	natPMPOpResponseFlag    = 128
+
+	natPMPResultSuccess = 0
+)
+
+var natPMPResultDescriptions = map[uint16]string{
+	1: "unsupported version",
+	2: "not authorized/refused",
+	3: "network failure",
+	4: "out of resources",
+	5: "unsupported opcode",
+}
+
+const (
+	natPMPInitialTimeout = 250 * time.Millisecond
+	natPMPMaxAttempts    = 4
+)
+
+type natPMPNAT struct {
+	gatewayAddress *net.UDPAddr
+}
+
+// discoverNATPMP checks whether the gateway at gatewayAddress speaks NAT-PMP
+// by requesting its external address
+func discoverNATPMP(gatewayAddress *net.UDPAddr) (NAT, error) {
+	nat := &natPMPNAT{gatewayAddress: gatewayAddress}
+	_, err := nat.ExternalAddress()
+	if err != nil {
+		return nil, err
+	}
+	return nat, nil
+}
+
+func (n *natPMPNAT) Type() string {
+	return "NAT-PMP"
+}
+
+func (n *natPMPNAT) ExternalAddress() (net.IP, error) {
+	response, err := n.request([]byte{natPMPVersion, natPMPOpExternalAddress}, 12)
+	if err != nil {
+		return nil, err
+	}
+	return net.IPv4(response[8], response[9], response[10], response[11]), nil
+}
+
+func (n *natPMPNAT) AddPortMapping(protocol string, internalPort, requestedExternalPort uint16,
+	_ string, lifetime time.Duration) (uint16, error) {
+
+	response, err := n.mapPort(protocol, internalPort, requestedExternalPort, lifetime)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint16(response[10:12]), nil
+}
+
+func (n *natPMPNAT) DeletePortMapping(protocol string, internalPort, _ uint16) error {
+	// A mapping is deleted by requesting it with a lifetime and an external port of zero
+	_, err := n.mapPort(protocol, internalPort, 0, 0)
+	return err
+}
+
+func (n *natPMPNAT) mapPort(protocol string, internalPort, requestedExternalPort uint16,
+	lifetime time.Duration) ([]byte, error) {
+
+	var op byte
+	switch protocol {
+	case "TCP":
+		op = natPMPOpMapTCP
+	case "UDP":
+		op = natPMPOpMapUDP
+	default:
+		return nil, errors.Errorf("unsupported protocol %s", protocol)
+	}
+
+	request := make([]byte, 12)
+	request[0] = natPMPVersion
+	request[1] = op
+	binary.BigEndian.PutUint16(request[4:6], internalPort)
+	binary.BigEndian.PutUint16(request[6:8], requestedExternalPort)
+	binary.BigEndian.PutUint32(request[8:12], uint32(lifetime/time.Second))
+	return n.request(request, 16)
+}
+
+// request sends the given request to the gateway, retransmitting it with an exponentially
+// increasing timeout as the RFC requires, and returns the response once it arrives
+func (n *natPMPNAT) request(request []byte, responseLength int) ([]byte, error) {
+	conn, err := net.DialUDP("udp4", nil, n.gatewayAddress)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	response := make([]byte, 16)
+	timeout := natPMPInitialTimeout
+	for attempt := 0; attempt < natPMPMaxAttempts; attempt++ {
+		_, err = conn.Write(request)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		err = conn.SetReadDeadline(time.Now().Add(timeout))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		timeout *= 2
+
+		for {
+			readCount, err := conn.Read(response)
+			if err != nil {
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					break
+				}
+				return nil, errors.WithStack(err)
+			}
+			// Ignore anything that isn't a response to this request, such as
+			// the address change announcements gateways multicast
+			if readCount < responseLength || response[0] != natPMPVersion ||
+				response[1] != request[1]|natPMPOpResponseFlag {
+				continue
+			}
+
+			resultCode := binary.BigEndian.Uint16(response[2:4])
+			if resultCode != natPMPResultSuccess {
+				description, ok := natPMPResultDescriptions[resultCode]
+				if !ok {
+					description = "unknown error"
+				}
+				return nil, errors.Errorf("NAT-PMP request failed with result code %d: %s",
+					resultCode, description)
+			}
+			return response[:responseLength], nil
+		}
+	}
+	return nil, errors.Errorf("NAT-PMP gateway %s did not respond", n.gatewayAddress)
+}
+
+// defaultGateways returns the addresses of the likely default gateways of this host.
+// On Linux they're read from the routing table. Elsewhere, the first address of the
+// network of each private IPv4 interface address is guessed, since that's how most
+// home routers are set up
+func defaultGateways() ([]net.IP, error) {
+	gateways, err := routingTableDefaultGateways()
+	if err == nil && len(gateways) > 0 {
+		return gateways, nil
+	}
+
+	interfaceAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, interfaceAddress := range interfaceAddresses {
+		ipNet, ok := interfaceAddress.(*net.IPNet)
+		if !ok || !ipNet.IP.IsPrivate() {
+			continue
+		}
+		ip := ipNet.IP.Mask(ipNet.Mask).To4()
+		if ip == nil {
+			continue
+		}
+		ip[3]++
+		gateways = append(gateways, ip)
+	}
+	if len(gateways) == 0 {
+		return nil, errors.New("no private IPv4 interface addresses were found")
+	}
+	return gateways, nil
+}
+
+// routingTableDefaultGateways reads the gateways of the default routes in
+// the Linux routing table
+func routingTableDefaultGateways() ([]net.IP, error) {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer file.Close()
+
+	var gateways []net.IP
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// The columns are Iface, Destination, Gateway, and then some more.
+		// Addresses are hex encoded in host byte order, which is little-endian
+		// on all architectures that matter here
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		gatewayBytes, err := hex.DecodeString(fields[2])
+		if err != nil || len(gatewayBytes) != net.IPv4len {
+			continue
+		}
+		gateway := net.IPv4(gatewayBytes[3], gatewayBytes[2], gatewayBytes[1], gatewayBytes[0])
+		if gateway.IsUnspecified() {
+			continue
+		}
+		gateways = append(gateways, gateway)
+	}
+	return gateways, errors.WithStack(scanner.Err())
+}
@@ -0,0 +1,151 @@
+package nat
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	portMappingProtocol    = "TCP"
+	portMappingDescription = "kaspad listen port"
+
+	// portMappingLifetime is the lease duration requested for port mappings.
+	// Mappings are renewed halfway through their lease
+	portMappingLifetime = 20 * time.Minute
+
+	// portMappingRetryInterval is how long to wait before looking for
+	// a gateway again after discovery or mapping had failed
+	portMappingRetryInterval = 5 * time.Minute
+)
+
+// ExternalAddressHandler is called whenever the external address under which the
+// mapped port is reachable changes. previous is nil once the port gets mapped
+// for the first time, and current is nil once the mapping is lost or removed
+type ExternalAddressHandler func(previous, current *net.TCPAddr)
+
+// PortMapper maps a local port on the NAT gateway of the local network,
+// keeps renewing the mapping, and removes it when stopped
+type PortMapper struct {
+	port                   uint16
+	externalAddressHandler ExternalAddressHandler
+
+	discover      func() (NAT, error)
+	lifetime      time.Duration
+	retryInterval time.Duration
+
+	nat             NAT
+	externalAddress *net.TCPAddr
+
+	stopChan  chan struct{}
+	stoppedWG sync.WaitGroup
+}
+
+// NewPortMapper creates a PortMapper that maps the given local TCP port
+func NewPortMapper(port uint16, externalAddressHandler ExternalAddressHandler) *PortMapper {
+	return &PortMapper{
+		port:                   port,
+		externalAddressHandler: externalAddressHandler,
+		discover:               Discover,
+		lifetime:               portMappingLifetime,
+		retryInterval:          portMappingRetryInterval,
+		stopChan:               make(chan struct{}),
+	}
+}
+
+// Start begins mapping the port in the background
+func (pm *PortMapper) Start() {
+	pm.stoppedWG.Add(1)
+	spawn("PortMapper.mappingLoop", pm.mappingLoop)
+}
+
+// Stop stops renewing the port mapping and removes it from the gateway
+func (pm *PortMapper) Stop() {
+	close(pm.stopChan)
+	pm.stoppedWG.Wait()
+
+	if pm.nat == nil || pm.externalAddress == nil {
+		return
+	}
+	err := pm.nat.DeletePortMapping(portMappingProtocol, pm.port, uint16(pm.externalAddress.Port))
+	if err != nil {
+		log.Warnf("Error removing the %s mapping of port %d: %s", pm.nat.Type(), pm.port, err)
+	} else {
+		log.Infof("Removed the %s mapping of port %d", pm.nat.Type(), pm.port)
+	}
+	pm.setExternalAddress(nil)
+}
+
+func (pm *PortMapper) mappingLoop() {
+	defer pm.stoppedWG.Done()
+
+	for {
+		waitDuration := pm.lifetime / 2
+		err := pm.refreshMapping()
+		if err != nil {
+			log.Warnf("Error mapping port %d on the NAT gateway: %s", pm.port, err)
+			waitDuration = pm.retryInterval
+		}
+
+		select {
+		case <-pm.stopChan:
+			return
+		case <-time.After(waitDuration):
+		}
+	}
+}
+
+// refreshMapping finds a gateway if there isn't one yet, (re)maps the port
+// on it and updates the external address accordingly
+func (pm *PortMapper) refreshMapping() error {
+	if pm.nat == nil {
+		nat, err := pm.discover()
+		if err != nil {
+			return err
+		}
+		log.Infof("Found a %s gateway", nat.Type())
+		pm.nat = nat
+	}
+
+	requestedExternalPort := pm.port
+	if pm.externalAddress != nil {
+		requestedExternalPort = uint16(pm.externalAddress.Port)
+	}
+	externalPort, err := pm.nat.AddPortMapping(portMappingProtocol, pm.port, requestedExternalPort,
+		portMappingDescription, pm.lifetime)
+	if err != nil {
+		pm.forgetGateway()
+		return err
+	}
+	externalIP, err := pm.nat.ExternalAddress()
+	if err != nil {
+		pm.forgetGateway()
+		return err
+	}
+
+	externalAddress := &net.TCPAddr{IP: externalIP, Port: int(externalPort)}
+	if pm.externalAddress == nil || !pm.externalAddress.IP.Equal(externalAddress.IP) ||
+		pm.externalAddress.Port != externalAddress.Port {
+		log.Infof("Mapped port %d to external address %s using %s", pm.port, externalAddress, pm.nat.Type())
+		pm.setExternalAddress(externalAddress)
+	}
+	return nil
+}
+
+// forgetGateway drops the current gateway, so that it is discovered again
+// on the next attempt, as the gateway or the network might have changed
+func (pm *PortMapper) forgetGateway() {
+	pm.nat = nil
+	pm.setExternalAddress(nil)
+}
+
+func (pm *PortMapper) setExternalAddress(externalAddress *net.TCPAddr) {
+	previous := pm.externalAddress
+	pm.externalAddress = externalAddress
+	if previous == nil && externalAddress == nil {
+		return
+	}
+	if pm.externalAddressHandler != nil {
+		pm.externalAddressHandler(previous, externalAddress)
+	}
+}
@@ -0,0 +1,90 @@
+package nat
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type externalAddressChange struct {
+	previous *net.TCPAddr
+	current  *net.TCPAddr
+}
+
+func TestPortMapper(t *testing.T) {
+	gateway := newFakeGateway(t)
+	defer gateway.close()
+
+	var changesLock sync.Mutex
+	var changes []externalAddressChange
+	lastChange := func() (externalAddressChange, int) {
+		changesLock.Lock()
+		defer changesLock.Unlock()
+		if len(changes) == 0 {
+			return externalAddressChange{}, 0
+		}
+		return changes[len(changes)-1], len(changes)
+	}
+	waitFor := func(description string, condition func() bool) {
+		deadline := time.Now().Add(5 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", description)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	portMapper := NewPortMapper(16111, func(previous, current *net.TCPAddr) {
+		changesLock.Lock()
+		defer changesLock.Unlock()
+		changes = append(changes, externalAddressChange{previous: previous, current: current})
+	})
+	portMapper.discover = func() (NAT, error) {
+		return discoverNATPMP(gateway.natPMPAddress())
+	}
+	// NAT-PMP lifetimes are in seconds, so this is the shortest
+	// lifetime that the mapping could be renewed with
+	portMapper.lifetime = 2 * time.Second
+	portMapper.Start()
+
+	waitFor("the port to be mapped", func() bool {
+		_, count := lastChange()
+		return count == 1
+	})
+	change, _ := lastChange()
+	expectedAddress := &net.TCPAddr{IP: gateway.externalIP, Port: 16112}
+	if change.previous != nil || change.current.String() != expectedAddress.String() {
+		t.Fatalf("unexpected external address change %+v", change)
+	}
+
+	// The external address changes and is picked up once the mapping is renewed
+	newExternalIP := net.ParseIP("198.51.100.3")
+	gateway.setExternalIP(newExternalIP)
+	waitFor("the mapping to be renewed", func() bool {
+		_, count := lastChange()
+		return count == 2
+	})
+	change, _ = lastChange()
+	newExpectedAddress := &net.TCPAddr{IP: newExternalIP, Port: 16112}
+	if change.previous.String() != expectedAddress.String() ||
+		change.current.String() != newExpectedAddress.String() {
+		t.Fatalf("unexpected external address change %+v", change)
+	}
+	if gateway.requestCount() < 2 {
+		t.Fatalf("expected the mapping to have been renewed")
+	}
+	if _, ok := gateway.mapping(16112); !ok {
+		t.Fatalf("expected the renewed mapping to keep its external port")
+	}
+
+	portMapper.Stop()
+	change, count := lastChange()
+	if count != 3 || change.previous.String() != newExpectedAddress.String() || change.current != nil {
+		t.Fatalf("unexpected external address change %+v after stopping", change)
+	}
+	if _, ok := gateway.mapping(16112); ok {
+		t.Fatalf("the mapping was not removed on stop")
+	}
+}
@@ -0,0 +1,342 @@
+package nat
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ssdpMulticastAddress = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+const internetGatewayDeviceType = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+
+// wanConnectionServiceTypes are the service types that can map ports, by order of preference
+var wanConnectionServiceTypes = []string{
+	"urn:schemas-upnp-org:service:WANIPConnection:2",
+	"urn:schemas-upnp-org:service:WANIPConnection:1",
+	"urn:schemas-upnp-org:service:WANPPPConnection:1",
+}
+
+// upnpErrorOnlyPermanentLeasesSupported is returned by gateways that don't support
+// port mappings with a lease duration
+const upnpErrorOnlyPermanentLeasesSupported = 725
+
+const upnpRequestTimeout = 5 * time.Second
+
+type upnpNAT struct {
+	httpClient  *http.Client
+	controlURL  string
+	serviceType string
+	localIP     net.IP
+}
+
+// discoverUPnP searches for an internet gateway device by sending an SSDP
+// M-SEARCH request to ssdpAddress, and returns the first one that can map ports
+func discoverUPnP(ssdpAddress *net.UDPAddr, timeout time.Duration) (NAT, error) {
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	searchRequest := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: 239.255.255.250:1900\r\n" +
+		"ST: " + internetGatewayDeviceType + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	_, err = conn.WriteToUDP([]byte(searchRequest), ssdpAddress)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = conn.SetReadDeadline(time.Now().Add(timeout))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	buffer := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "no UPnP gateway responded")
+		}
+		response, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buffer[:n])), nil)
+		if err != nil {
+			log.Debugf("Ignoring malformed SSDP response: %s", err)
+			continue
+		}
+		location := response.Header.Get("Location")
+		if response.StatusCode != http.StatusOK || location == "" ||
+			!strings.Contains(response.Header.Get("St"), "InternetGatewayDevice") {
+			continue
+		}
+
+		nat, err := newUPnPNAT(location)
+		if err != nil {
+			log.Debugf("Ignoring UPnP gateway at %s: %s", location, err)
+			continue
+		}
+		return nat, nil
+	}
+}
+
+// upnpDevice is a device in a UPnP device description. Devices
+// may contain both services and further embedded devices
+type upnpDevice struct {
+	DeviceType string        `xml:"deviceType"`
+	Services   []upnpService `xml:"serviceList>service"`
+	Devices    []upnpDevice  `xml:"deviceList>device"`
+}
+
+type upnpService struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpDeviceDescription struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+func (d *upnpDevice) findService(serviceType string) (*upnpService, bool) {
+	for i := range d.Services {
+		if d.Services[i].ServiceType == serviceType {
+			return &d.Services[i], true
+		}
+	}
+	for i := range d.Devices {
+		service, ok := d.Devices[i].findService(serviceType)
+		if ok {
+			return service, true
+		}
+	}
+	return nil, false
+}
+
+// newUPnPNAT fetches the device description at location, and finds
+// the service of the gateway that can map ports in it
+func newUPnPNAT(location string) (*upnpNAT, error) {
+	httpClient := &http.Client{Timeout: upnpRequestTimeout}
+	response, err := httpClient.Get(location)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected status %s fetching the device description", response.Status)
+	}
+
+	var description upnpDeviceDescription
+	err = xml.NewDecoder(response.Body).Decode(&description)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding the device description")
+	}
+
+	var service *upnpService
+	for _, serviceType := range wanConnectionServiceTypes {
+		var ok bool
+		service, ok = description.Device.findService(serviceType)
+		if ok {
+			break
+		}
+	}
+	if service == nil {
+		return nil, errors.New("the device has no WAN connection service")
+	}
+
+	baseURL, err := url.Parse(location)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if description.URLBase != "" {
+		baseURL, err = url.Parse(description.URLBase)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	controlURL, err := baseURL.Parse(service.ControlURL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	localIP, err := localIPTowards(controlURL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upnpNAT{
+		httpClient:  httpClient,
+		controlURL:  controlURL.String(),
+		serviceType: service.ServiceType,
+		localIP:     localIP,
+	}, nil
+}
+
+// localIPTowards returns the IP address of this host on the interface
+// it would use to reach the given host. No packets are sent
+func localIPTowards(hostPort string) (net.IP, error) {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host, port = hostPort, "80"
+	}
+	conn, err := net.Dial("udp4", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+func (n *upnpNAT) Type() string {
+	return "UPnP"
+}
+
+func (n *upnpNAT) ExternalAddress() (net.IP, error) {
+	response, err := n.soapRequest("GetExternalIPAddress", nil)
+	if err != nil {
+		return nil, err
+	}
+	externalAddressString, err := soapResponseValue(response, "NewExternalIPAddress")
+	if err != nil {
+		return nil, err
+	}
+	externalAddress := net.ParseIP(strings.TrimSpace(externalAddressString))
+	if externalAddress == nil {
+		return nil, errors.Errorf("the gateway returned a malformed external address %s",
+			externalAddressString)
+	}
+	return externalAddress, nil
+}
+
+func (n *upnpNAT) AddPortMapping(protocol string, internalPort, requestedExternalPort uint16,
+	description string, lifetime time.Duration) (uint16, error) {
+
+	arguments := func(lifetime time.Duration) []soapArgument {
+		return []soapArgument{
+			{"NewRemoteHost", ""},
+			{"NewExternalPort", strconv.Itoa(int(requestedExternalPort))},
+			{"NewProtocol", protocol},
+			{"NewInternalPort", strconv.Itoa(int(internalPort))},
+			{"NewInternalClient", n.localIP.String()},
+			{"NewEnabled", "1"},
+			{"NewPortMappingDescription", description},
+			{"NewLeaseDuration", strconv.Itoa(int(lifetime / time.Second))},
+		}
+	}
+
+	_, err := n.soapRequest("AddPortMapping", arguments(lifetime))
+	var upnpErr *upnpError
+	if errors.As(err, &upnpErr) && upnpErr.code == upnpErrorOnlyPermanentLeasesSupported {
+		// The mapping is removed on shutdown, so a permanent mapping is acceptable
+		_, err = n.soapRequest("AddPortMapping", arguments(0))
+	}
+	if err != nil {
+		return 0, err
+	}
+	return requestedExternalPort, nil
+}
+
+func (n *upnpNAT) DeletePortMapping(protocol string, _, externalPort uint16) error {
+	_, err := n.soapRequest("DeletePortMapping", []soapArgument{
+		{"NewRemoteHost", ""},
+		{"NewExternalPort", strconv.Itoa(int(externalPort))},
+		{"NewProtocol", protocol},
+	})
+	return err
+}
+
+type soapArgument struct {
+	name  string
+	value string
+}
+
+// upnpError is an error returned by a UPnP gateway in a SOAP fault
+type upnpError struct {
+	code        int
+	description string
+}
+
+func (e *upnpError) Error() string {
+	return fmt.Sprintf("UPnP error %d: %s", e.code, e.description)
+}
+
+// soapRequest invokes the given action of the gateway's WAN connection service,
+// and returns the body of the response
+func (n *upnpNAT) soapRequest(action string, arguments []soapArgument) ([]byte, error) {
+	body := &bytes.Buffer{}
+	body.WriteString(`<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" ` +
+		`s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/"><s:Body>`)
+	fmt.Fprintf(body, `<u:%s xmlns:u="%s">`, action, n.serviceType)
+	for _, argument := range arguments {
+		fmt.Fprintf(body, "<%s>", argument.name)
+		err := xml.EscapeText(body, []byte(argument.value))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		fmt.Fprintf(body, "</%s>", argument.name)
+	}
+	fmt.Fprintf(body, `</u:%s></s:Body></s:Envelope>`, action)
+
+	request, err := http.NewRequest(http.MethodPost, n.controlURL, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	request.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	request.Header.Set("SOAPAction", fmt.Sprintf(`"%s#%s"`, n.serviceType, action))
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		codeString, err := soapResponseValue(responseBody, "errorCode")
+		if err != nil {
+			return nil, errors.Errorf("%s failed with status %s", action, response.Status)
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(codeString))
+		if err != nil {
+			return nil, errors.Errorf("%s failed with status %s", action, response.Status)
+		}
+		description, _ := soapResponseValue(responseBody, "errorDescription")
+		return nil, errors.Wrapf(&upnpError{code: code, description: description}, "%s failed", action)
+	}
+	return responseBody, nil
+}
+
+// soapResponseValue returns the text of the first element with the given
+// local name in the given SOAP response
+func soapResponseValue(response []byte, name string) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(response))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", errors.Errorf("%s is missing from the gateway's response", name)
+		}
+		startElement, ok := token.(xml.StartElement)
+		if !ok || startElement.Name.Local != name {
+			continue
+		}
+		var value string
+		err = decoder.DecodeElement(&value, &startElement)
+		if err != nil {
+			return "", errors.Wrapf(err, "error decoding %s", name)
+		}
+		return value, nil
+	}
+}
@@ -1,8 +1,12 @@
 package netadapter
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/config"
@@ -10,6 +14,7 @@ import (
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/jsonrpcserver"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +33,7 @@ type NetAdapter struct {
 	p2pServer            server.P2PServer
 	p2pRouterInitializer RouterInitializer
 	rpcServer            server.Server
+	jsonRPCServer        server.Server
 	rpcRouterInitializer RouterInitializer
 	stop                 uint32
 
@@ -42,25 +48,57 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
-	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners)
+	p2pServer, err := grpcserver.NewP2PServer(cfg.Listeners, ProxyDialFunc(cfg))
 	if err != nil {
 		return nil, err
 	}
-	rpcServer, err := grpcserver.NewRPCServer(cfg.RPCListeners)
+	var rpcTLSConfig *tls.Config
+	if cfg.RPCTLS {
+		rpcTLSConfig, err = grpcserver.LoadRPCTLSConfig(cfg.RPCCert, cfg.RPCKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	rpcCredentials := &grpcserver.RPCCredentials{
+		AdminUsername:    cfg.RPCUser,
+		AdminPassword:    cfg.RPCPass,
+		AdminToken:       cfg.RPCAuthToken,
+		SubmitUsername:   cfg.RPCSubmitUser,
+		SubmitPassword:   cfg.RPCSubmitPass,
+		SubmitToken:      cfg.RPCSubmitAuthToken,
+		ReadOnlyUsername: cfg.RPCReadOnlyUser,
+		ReadOnlyPassword: cfg.RPCReadOnlyPass,
+		ReadOnlyToken:    cfg.RPCReadOnlyAuthToken,
+	}
+	rpcServer, err := grpcserver.NewRPCServer(cfg.RPCListeners, cfg.RPCMaxClients, rpcTLSConfig, rpcCredentials)
 	if err != nil {
 		return nil, err
 	}
+	var jsonRPCServer server.Server
+	if len(cfg.RPCJSONListeners) > 0 {
+		jsonRPCServer = jsonrpcserver.NewServer(&jsonrpcserver.Config{
+			ListeningAddresses: cfg.RPCJSONListeners,
+			TLSConfig:          rpcTLSConfig,
+			Credentials:        rpcCredentials,
+			AllowedOrigins:     cfg.RPCJSONAllowedOrigins,
+			MaxWebsockets:      cfg.RPCMaxWebsockets,
+		})
+	}
 	adapter := NetAdapter{
-		cfg:       cfg,
-		id:        netAdapterID,
-		p2pServer: p2pServer,
-		rpcServer: rpcServer,
+		cfg:           cfg,
+		id:            netAdapterID,
+		p2pServer:     p2pServer,
+		rpcServer:     rpcServer,
+		jsonRPCServer: jsonRPCServer,
 
 		p2pConnections: make(map[*NetConnection]struct{}),
 	}
 
 	adapter.p2pServer.SetOnConnectedHandler(adapter.onP2PConnectedHandler)
 	adapter.rpcServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
+	if adapter.jsonRPCServer != nil {
+		adapter.jsonRPCServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
+	}
 
 	return &adapter, nil
 }
@@ -82,6 +120,12 @@ func (na *NetAdapter) Start() error {
 	if err != nil {
 		return err
 	}
+	if na.jsonRPCServer != nil {
+		err = na.jsonRPCServer.Start()
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -95,16 +139,89 @@ func (na *NetAdapter) Stop() error {
 	if err != nil {
 		return err
 	}
+	if na.jsonRPCServer != nil {
+		err = na.jsonRPCServer.Stop()
+		if err != nil {
+			return err
+		}
+	}
 	return na.rpcServer.Stop()
 }
 
 // P2PConnect tells the NetAdapter's underlying p2p server to initiate a connection
 // to the given address
 func (na *NetAdapter) P2PConnect(address string) error {
-	_, err := na.p2pServer.Connect(address)
+	address, err := na.resolveProxiedP2PAddress(address)
+	if err != nil {
+		return err
+	}
+	_, err = na.p2pServer.Connect(address)
 	return err
 }
 
+// isP2PProxied returns whether outbound P2P connections are made through a proxy
+func isP2PProxied(cfg *config.Config) bool {
+	return cfg.Proxy != "" || cfg.OnionDial != nil
+}
+
+// ProxyDialFunc returns the function outbound connections to peers and seeders are
+// dialed with, or nil if they are to be dialed directly. Onion addresses are dialed
+// through the onion proxy, if one is reachable, and all other addresses through the
+// regular proxy
+func ProxyDialFunc(cfg *config.Config) grpcserver.DialFunc {
+	if !isP2PProxied(cfg) {
+		return nil
+	}
+
+	return func(ctx context.Context, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		dial := cfg.Dial
+		if appmessage.IsOnionHost(host) {
+			if cfg.OnionDial == nil {
+				return nil, errors.Errorf("cannot connect to %s: onion services are unreachable", address)
+			}
+			dial = cfg.OnionDial
+		}
+		if dial == nil {
+			dial = net.DialTimeout
+		}
+
+		var timeout time.Duration
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		return dial("tcp", address, timeout)
+	}
+}
+
+// resolveProxiedP2PAddress resolves the host of the given address if it's a
+// host name and P2P connections are made through a proxy. That way host names
+// are resolved with cfg.Lookup, which doesn't leak DNS requests outside the proxy
+func (na *NetAdapter) resolveProxiedP2PAddress(address string) (string, error) {
+	if !isP2PProxied(na.cfg) || na.cfg.Lookup == nil {
+		return address, nil
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if net.ParseIP(host) != nil || appmessage.IsOnionHost(host) {
+		return address, nil
+	}
+	ips, err := na.cfg.Lookup(host)
+	if err != nil {
+		return "", errors.Wrapf(err, "error resolving %s", host)
+	}
+	if len(ips) == 0 {
+		return "", errors.Errorf("no addresses found for %s", host)
+	}
+	return net.JoinHostPort(ips[0].String(), port), nil
+}
+
 // P2PConnections returns a list of p2p connections currently connected and active
 func (na *NetAdapter) P2PConnections() []*NetConnection {
 	na.p2pConnectionsLock.RLock()
@@ -5,6 +5,7 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/pkg/errors"
+	"net"
 	"sync/atomic"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/id"
@@ -75,9 +76,27 @@ func (c *NetConnection) IsOutbound() bool {
 	return c.connection.IsOutbound()
 }
 
+// RPCRole returns the role of the RPC client of this connection
+func (c *NetConnection) RPCRole() server.RPCRole {
+	return c.connection.RPCRole()
+}
+
+// BytesSent returns the total size of the messages sent through this connection
+func (c *NetConnection) BytesSent() uint64 {
+	return c.connection.BytesSent()
+}
+
+// BytesReceived returns the total size of the messages received through this connection
+func (c *NetConnection) BytesReceived() uint64 {
+	return c.connection.BytesReceived()
+}
+
 // NetAddress returns the NetAddress associated with this connection
 func (c *NetConnection) NetAddress() *appmessage.NetAddress {
-	return appmessage.NewNetAddress(c.connection.Address())
+	if onionAddress, ok := c.connection.Address().(*server.OnionAddress); ok {
+		return appmessage.NewNetAddressOnionPort(onionAddress.Host, onionAddress.Port)
+	}
+	return appmessage.NewNetAddress(c.connection.Address().(*net.TCPAddr))
 }
 
 func (c *NetConnection) setOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
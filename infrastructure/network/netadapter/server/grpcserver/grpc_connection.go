@@ -10,14 +10,21 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"google.golang.org/grpc"
 )
 
 type gRPCConnection struct {
+	// bytesSent and bytesReceived are the encoded sizes of the messages sent and received
+	// through this connection. They're first in the struct so that they're 64-bit aligned
+	// for atomic access on 32-bit platforms
+	bytesSent     uint64
+	bytesReceived uint64
+
 	server                   *gRPCServer
-	address                  *net.TCPAddr
+	address                  net.Addr
 	stream                   grpcStream
 	router                   *router.Router
 	lowLevelClientConnection *grpc.ClientConn
@@ -34,6 +41,9 @@ type gRPCConnection struct {
 	onInvalidMessageHandler server.OnInvalidMessageHandler
 
 	isConnected uint32
+
+	// rpcRole is the role of the client of an inbound RPC connection
+	rpcRole server.RPCRole
 }
 
 type grpcStream interface {
@@ -41,7 +51,7 @@ type grpcStream interface {
 	Recv() (*protowire.KaspadMessage, error)
 }
 
-func newConnection(server *gRPCServer, address *net.TCPAddr, stream grpcStream,
+func newConnection(server *gRPCServer, address net.Addr, stream grpcStream,
 	lowLevelClientConnection *grpc.ClientConn) *gRPCConnection {
 	connection := &gRPCConnection{
 		server:                   server,
@@ -123,10 +133,26 @@ func (c *gRPCConnection) Disconnect() {
 	}
 }
 
-func (c *gRPCConnection) Address() *net.TCPAddr {
+func (c *gRPCConnection) Address() net.Addr {
 	return c.address
 }
 
+// BytesSent returns the total size of the messages sent through this connection
+func (c *gRPCConnection) BytesSent() uint64 {
+	return atomic.LoadUint64(&c.bytesSent)
+}
+
+// BytesReceived returns the total size of the messages received through this connection
+func (c *gRPCConnection) BytesReceived() uint64 {
+	return atomic.LoadUint64(&c.bytesReceived)
+}
+
+// RPCRole returns the role the client of this connection authenticated with.
+// It's only meaningful for inbound RPC connections
+func (c *gRPCConnection) RPCRole() server.RPCRole {
+	return c.rpcRole
+}
+
 func (c *gRPCConnection) receive() (*protowire.KaspadMessage, error) {
 	// We use RLock here and in send() because they can work
 	// in parallel. closeSend(), however, must not have either
@@ -134,7 +160,12 @@ func (c *gRPCConnection) receive() (*protowire.KaspadMessage, error) {
 	c.streamLock.RLock()
 	defer c.streamLock.RUnlock()
 
-	return c.stream.Recv()
+	message, err := c.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	atomic.AddUint64(&c.bytesReceived, uint64(proto.Size(message)))
+	return message, nil
 }
 
 func (c *gRPCConnection) send(message *protowire.KaspadMessage) error {
@@ -144,7 +175,12 @@ func (c *gRPCConnection) send(message *protowire.KaspadMessage) error {
 	c.streamLock.RLock()
 	defer c.streamLock.RUnlock()
 
-	return c.stream.Send(message)
+	err := c.stream.Send(message)
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&c.bytesSent, uint64(proto.Size(message)))
+	return nil
 }
 
 func (c *gRPCConnection) closeSend() {
@@ -2,6 +2,7 @@ package grpcserver
 
 import (
 	"context"
+	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/kaspanet/kaspad/util/panics"
@@ -10,12 +11,19 @@ import (
 	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/peer"
 	"net"
+	"strconv"
 	"time"
 )
 
+// DialFunc establishes a network connection to the given address.
+// It's used to route outbound P2P connections through proxies
+type DialFunc func(ctx context.Context, address string) (net.Conn, error)
+
 type p2pServer struct {
 	protowire.UnimplementedP2PServer
 	gRPCServer
+
+	dial DialFunc
 }
 
 const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
@@ -26,10 +34,20 @@ const p2pMaxMessageSize = 1024 * 1024 * 1024 // 1GB
 // is handled in the ConnectionManager instead.
 const p2pMaxInboundConnections = 0
 
-// NewP2PServer creates a new P2PServer
-func NewP2PServer(listeningAddresses []string) (server.P2PServer, error) {
+const (
+	directDialTimeout = 1 * time.Second
+
+	// Connections through a proxy, and tor circuits in particular,
+	// take considerably longer to establish
+	proxiedDialTimeout = 10 * time.Second
+)
+
+// NewP2PServer creates a new P2PServer.
+// If dial is not nil, outbound connections are established with it instead
+// of directly. In that case, it is given either IP or onion addresses
+func NewP2PServer(listeningAddresses []string, dial DialFunc) (server.P2PServer, error) {
 	gRPCServer := newGRPCServer(listeningAddresses, p2pMaxMessageSize, p2pMaxInboundConnections, "P2P")
-	p2pServer := &p2pServer{gRPCServer: *gRPCServer}
+	p2pServer := &p2pServer{gRPCServer: *gRPCServer, dial: dial}
 	protowire.RegisterP2PServer(gRPCServer.server, p2pServer)
 	return p2pServer, nil
 }
@@ -45,11 +63,16 @@ func (p *p2pServer) MessageStream(stream protowire.P2P_MessageStreamServer) erro
 func (p *p2pServer) Connect(address string) (server.Connection, error) {
 	log.Debugf("%s Dialing to %s", p.name, address)
 
-	const dialTimeout = 1 * time.Second
+	dialTimeout := directDialTimeout
+	dialOptions := []grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}
+	if p.dial != nil {
+		dialTimeout = proxiedDialTimeout
+		dialOptions = append(dialOptions, grpc.WithContextDialer(p.dial))
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
-	gRPCClientConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	gRPCClientConnection, err := grpc.DialContext(ctx, address, dialOptions...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "%s error connecting to %s", p.name, address)
 	}
@@ -61,16 +84,12 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 		return nil, errors.Wrapf(err, "%s error getting client stream for %s", p.name, address)
 	}
 
-	peerInfo, ok := peer.FromContext(stream.Context())
-	if !ok {
-		return nil, errors.Errorf("%s error getting stream peer info from context for %s", p.name, address)
-	}
-	tcpAddress, ok := peerInfo.Addr.(*net.TCPAddr)
-	if !ok {
-		return nil, errors.Errorf("non-tcp addresses are not supported")
+	connectionAddress, err := p.outboundConnectionAddress(stream, address)
+	if err != nil {
+		return nil, err
 	}
 
-	connection := newConnection(&p.gRPCServer, tcpAddress, stream, gRPCClientConnection)
+	connection := newConnection(&p.gRPCServer, connectionAddress, stream, gRPCClientConnection)
 
 	err = p.onConnectedHandler(connection)
 	if err != nil {
@@ -81,3 +100,36 @@ func (p *p2pServer) Connect(address string) (server.Connection, error) {
 
 	return connection, nil
 }
+
+// outboundConnectionAddress returns the address of the peer of the given outbound stream.
+// When connecting through a proxy, the stream's peer is the proxy, so the dialed
+// address is used instead
+func (p *p2pServer) outboundConnectionAddress(stream grpc.ClientStream, address string) (net.Addr, error) {
+	if p.dial == nil {
+		peerInfo, ok := peer.FromContext(stream.Context())
+		if !ok {
+			return nil, errors.Errorf("%s error getting stream peer info from context for %s", p.name, address)
+		}
+		tcpAddress, ok := peerInfo.Addr.(*net.TCPAddr)
+		if !ok {
+			return nil, errors.Errorf("non-tcp addresses are not supported")
+		}
+		return tcpAddress, nil
+	}
+
+	host, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s error parsing address %s", p.name, address)
+	}
+	port, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s error parsing the port of %s", p.name, address)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+	}
+	if appmessage.IsOnionHost(host) {
+		return &server.OnionAddress{Host: host, Port: uint16(port)}, nil
+	}
+	return nil, errors.Errorf("%s address %s is neither an IP nor an onion address", p.name, address)
+}
@@ -103,6 +103,16 @@ func (x *NetAddress) toAppMessage() (*appmessage.NetAddress, error) {
 	if x.Port > math.MaxUint16 {
 		return nil, errors.Errorf("port number is larger than %d", math.MaxUint16)
 	}
+	if x.OnionHost != "" {
+		if !appmessage.IsOnionHost(x.OnionHost) {
+			return nil, errors.Errorf("onion host %s is not in the .onion domain", x.OnionHost)
+		}
+		return &appmessage.NetAddress{
+			Timestamp: mstime.UnixMilliseconds(x.Timestamp),
+			OnionHost: x.OnionHost,
+			Port:      uint16(x.Port),
+		}, nil
+	}
 	return &appmessage.NetAddress{
 		Timestamp: mstime.UnixMilliseconds(x.Timestamp),
 		IP:        x.Ip,
@@ -115,6 +125,7 @@ func appMessageNetAddressToProto(address *appmessage.NetAddress) *NetAddress {
 		Timestamp: address.Timestamp.UnixMilliseconds(),
 		Ip:        address.IP,
 		Port:      uint32(address.Port),
+		OnionHost: address.OnionHost,
 	}
 }
 
@@ -157,6 +157,31 @@ type KaspadMessage struct {
 	//	*KaspadMessage_NotifyNewBlockTemplateRequest
 	//	*KaspadMessage_NotifyNewBlockTemplateResponse
 	//	*KaspadMessage_NewBlockTemplateNotification
+	//	*KaspadMessage_GetTransactionRequest
+	//	*KaspadMessage_GetTransactionResponse
+	//	*KaspadMessage_GetTransactionAcceptanceRequest
+	//	*KaspadMessage_GetTransactionAcceptanceResponse
+	//	*KaspadMessage_GetAddressHistoryRequest
+	//	*KaspadMessage_GetAddressHistoryResponse
+	//	*KaspadMessage_GetFeeEstimateRequest
+	//	*KaspadMessage_GetFeeEstimateResponse
+	//	*KaspadMessage_SubmitTransactionReplacementRequest
+	//	*KaspadMessage_SubmitTransactionReplacementResponse
+	//	*KaspadMessage_SaveMempoolRequest
+	//	*KaspadMessage_SaveMempoolResponse
+	//	*KaspadMessage_LoadMempoolRequest
+	//	*KaspadMessage_LoadMempoolResponse
+	//	*KaspadMessage_SetLogLevelRequest
+	//	*KaspadMessage_SetLogLevelResponse
+	//	*KaspadMessage_GetLogLevelsRequest
+	//	*KaspadMessage_GetLogLevelsResponse
+	//	*KaspadMessage_NotifyMempoolChangedRequest
+	//	*KaspadMessage_NotifyMempoolChangedResponse
+	//	*KaspadMessage_MempoolChangedNotification
+	//	*KaspadMessage_StopNotifyingMempoolChangedRequest
+	//	*KaspadMessage_StopNotifyingMempoolChangedResponse
+	//	*KaspadMessage_DebugTransactionScriptsRequest
+	//	*KaspadMessage_DebugTransactionScriptsResponse
 	Payload isKaspadMessage_Payload `protobuf_oneof:"payload"`
 }
 
@@ -1081,6 +1106,181 @@ func (x *KaspadMessage) GetNewBlockTemplateNotification() *NewBlockTemplateNotif
 	return nil
 }
 
+func (x *KaspadMessage) GetGetTransactionRequest() *GetTransactionRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionRequest); ok {
+		return x.GetTransactionRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionResponse() *GetTransactionResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionResponse); ok {
+		return x.GetTransactionResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionAcceptanceRequest() *GetTransactionAcceptanceRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionAcceptanceRequest); ok {
+		return x.GetTransactionAcceptanceRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetTransactionAcceptanceResponse() *GetTransactionAcceptanceResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetTransactionAcceptanceResponse); ok {
+		return x.GetTransactionAcceptanceResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetAddressHistoryRequest() *GetAddressHistoryRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetAddressHistoryRequest); ok {
+		return x.GetAddressHistoryRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetAddressHistoryResponse() *GetAddressHistoryResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetAddressHistoryResponse); ok {
+		return x.GetAddressHistoryResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetFeeEstimateRequest() *GetFeeEstimateRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetFeeEstimateRequest); ok {
+		return x.GetFeeEstimateRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetFeeEstimateResponse() *GetFeeEstimateResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetFeeEstimateResponse); ok {
+		return x.GetFeeEstimateResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSubmitTransactionReplacementRequest() *SubmitTransactionReplacementRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SubmitTransactionReplacementRequest); ok {
+		return x.SubmitTransactionReplacementRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSubmitTransactionReplacementResponse() *SubmitTransactionReplacementResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SubmitTransactionReplacementResponse); ok {
+		return x.SubmitTransactionReplacementResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSaveMempoolRequest() *SaveMempoolRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SaveMempoolRequest); ok {
+		return x.SaveMempoolRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSaveMempoolResponse() *SaveMempoolResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SaveMempoolResponse); ok {
+		return x.SaveMempoolResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetLoadMempoolRequest() *LoadMempoolRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_LoadMempoolRequest); ok {
+		return x.LoadMempoolRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetLoadMempoolResponse() *LoadMempoolResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_LoadMempoolResponse); ok {
+		return x.LoadMempoolResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSetLogLevelRequest() *SetLogLevelRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SetLogLevelRequest); ok {
+		return x.SetLogLevelRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetSetLogLevelResponse() *SetLogLevelResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_SetLogLevelResponse); ok {
+		return x.SetLogLevelResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetLogLevelsRequest() *GetLogLevelsRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetLogLevelsRequest); ok {
+		return x.GetLogLevelsRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetGetLogLevelsResponse() *GetLogLevelsResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_GetLogLevelsResponse); ok {
+		return x.GetLogLevelsResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetNotifyMempoolChangedRequest() *NotifyMempoolChangedRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_NotifyMempoolChangedRequest); ok {
+		return x.NotifyMempoolChangedRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetNotifyMempoolChangedResponse() *NotifyMempoolChangedResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_NotifyMempoolChangedResponse); ok {
+		return x.NotifyMempoolChangedResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetMempoolChangedNotification() *MempoolChangedNotificationMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_MempoolChangedNotification); ok {
+		return x.MempoolChangedNotification
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetStopNotifyingMempoolChangedRequest() *StopNotifyingMempoolChangedRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_StopNotifyingMempoolChangedRequest); ok {
+		return x.StopNotifyingMempoolChangedRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetStopNotifyingMempoolChangedResponse() *StopNotifyingMempoolChangedResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_StopNotifyingMempoolChangedResponse); ok {
+		return x.StopNotifyingMempoolChangedResponse
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetDebugTransactionScriptsRequest() *DebugTransactionScriptsRequestMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_DebugTransactionScriptsRequest); ok {
+		return x.DebugTransactionScriptsRequest
+	}
+	return nil
+}
+
+func (x *KaspadMessage) GetDebugTransactionScriptsResponse() *DebugTransactionScriptsResponseMessage {
+	if x, ok := x.GetPayload().(*KaspadMessage_DebugTransactionScriptsResponse); ok {
+		return x.DebugTransactionScriptsResponse
+	}
+	return nil
+}
+
 type isKaspadMessage_Payload interface {
 	isKaspadMessage_Payload()
 }
@@ -1589,6 +1789,106 @@ type KaspadMessage_NewBlockTemplateNotification struct {
 	NewBlockTemplateNotification *NewBlockTemplateNotificationMessage `protobuf:"bytes,1083,opt,name=newBlockTemplateNotification,proto3,oneof"`
 }
 
+type KaspadMessage_GetTransactionRequest struct {
+	GetTransactionRequest *GetTransactionRequestMessage `protobuf:"bytes,1084,opt,name=getTransactionRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionResponse struct {
+	GetTransactionResponse *GetTransactionResponseMessage `protobuf:"bytes,1085,opt,name=getTransactionResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionAcceptanceRequest struct {
+	GetTransactionAcceptanceRequest *GetTransactionAcceptanceRequestMessage `protobuf:"bytes,1086,opt,name=getTransactionAcceptanceRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetTransactionAcceptanceResponse struct {
+	GetTransactionAcceptanceResponse *GetTransactionAcceptanceResponseMessage `protobuf:"bytes,1087,opt,name=getTransactionAcceptanceResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetAddressHistoryRequest struct {
+	GetAddressHistoryRequest *GetAddressHistoryRequestMessage `protobuf:"bytes,1088,opt,name=getAddressHistoryRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetAddressHistoryResponse struct {
+	GetAddressHistoryResponse *GetAddressHistoryResponseMessage `protobuf:"bytes,1089,opt,name=getAddressHistoryResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetFeeEstimateRequest struct {
+	GetFeeEstimateRequest *GetFeeEstimateRequestMessage `protobuf:"bytes,1090,opt,name=getFeeEstimateRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetFeeEstimateResponse struct {
+	GetFeeEstimateResponse *GetFeeEstimateResponseMessage `protobuf:"bytes,1091,opt,name=getFeeEstimateResponse,proto3,oneof"`
+}
+
+type KaspadMessage_SubmitTransactionReplacementRequest struct {
+	SubmitTransactionReplacementRequest *SubmitTransactionReplacementRequestMessage `protobuf:"bytes,1092,opt,name=submitTransactionReplacementRequest,proto3,oneof"`
+}
+
+type KaspadMessage_SubmitTransactionReplacementResponse struct {
+	SubmitTransactionReplacementResponse *SubmitTransactionReplacementResponseMessage `protobuf:"bytes,1093,opt,name=submitTransactionReplacementResponse,proto3,oneof"`
+}
+
+type KaspadMessage_SaveMempoolRequest struct {
+	SaveMempoolRequest *SaveMempoolRequestMessage `protobuf:"bytes,1094,opt,name=saveMempoolRequest,proto3,oneof"`
+}
+
+type KaspadMessage_SaveMempoolResponse struct {
+	SaveMempoolResponse *SaveMempoolResponseMessage `protobuf:"bytes,1095,opt,name=saveMempoolResponse,proto3,oneof"`
+}
+
+type KaspadMessage_LoadMempoolRequest struct {
+	LoadMempoolRequest *LoadMempoolRequestMessage `protobuf:"bytes,1096,opt,name=loadMempoolRequest,proto3,oneof"`
+}
+
+type KaspadMessage_LoadMempoolResponse struct {
+	LoadMempoolResponse *LoadMempoolResponseMessage `protobuf:"bytes,1097,opt,name=loadMempoolResponse,proto3,oneof"`
+}
+
+type KaspadMessage_SetLogLevelRequest struct {
+	SetLogLevelRequest *SetLogLevelRequestMessage `protobuf:"bytes,1098,opt,name=setLogLevelRequest,proto3,oneof"`
+}
+
+type KaspadMessage_SetLogLevelResponse struct {
+	SetLogLevelResponse *SetLogLevelResponseMessage `protobuf:"bytes,1099,opt,name=setLogLevelResponse,proto3,oneof"`
+}
+
+type KaspadMessage_GetLogLevelsRequest struct {
+	GetLogLevelsRequest *GetLogLevelsRequestMessage `protobuf:"bytes,1100,opt,name=getLogLevelsRequest,proto3,oneof"`
+}
+
+type KaspadMessage_GetLogLevelsResponse struct {
+	GetLogLevelsResponse *GetLogLevelsResponseMessage `protobuf:"bytes,1101,opt,name=getLogLevelsResponse,proto3,oneof"`
+}
+
+type KaspadMessage_NotifyMempoolChangedRequest struct {
+	NotifyMempoolChangedRequest *NotifyMempoolChangedRequestMessage `protobuf:"bytes,1102,opt,name=notifyMempoolChangedRequest,proto3,oneof"`
+}
+
+type KaspadMessage_NotifyMempoolChangedResponse struct {
+	NotifyMempoolChangedResponse *NotifyMempoolChangedResponseMessage `protobuf:"bytes,1103,opt,name=notifyMempoolChangedResponse,proto3,oneof"`
+}
+
+type KaspadMessage_MempoolChangedNotification struct {
+	MempoolChangedNotification *MempoolChangedNotificationMessage `protobuf:"bytes,1104,opt,name=mempoolChangedNotification,proto3,oneof"`
+}
+
+type KaspadMessage_StopNotifyingMempoolChangedRequest struct {
+	StopNotifyingMempoolChangedRequest *StopNotifyingMempoolChangedRequestMessage `protobuf:"bytes,1105,opt,name=stopNotifyingMempoolChangedRequest,proto3,oneof"`
+}
+
+type KaspadMessage_StopNotifyingMempoolChangedResponse struct {
+	StopNotifyingMempoolChangedResponse *StopNotifyingMempoolChangedResponseMessage `protobuf:"bytes,1106,opt,name=stopNotifyingMempoolChangedResponse,proto3,oneof"`
+}
+
+type KaspadMessage_DebugTransactionScriptsRequest struct {
+	DebugTransactionScriptsRequest *DebugTransactionScriptsRequestMessage `protobuf:"bytes,1107,opt,name=debugTransactionScriptsRequest,proto3,oneof"`
+}
+
+type KaspadMessage_DebugTransactionScriptsResponse struct {
+	DebugTransactionScriptsResponse *DebugTransactionScriptsResponseMessage `protobuf:"bytes,1108,opt,name=debugTransactionScriptsResponse,proto3,oneof"`
+}
+
 func (*KaspadMessage_Addresses) isKaspadMessage_Payload() {}
 
 func (*KaspadMessage_Block) isKaspadMessage_Payload() {}
@@ -1841,13 +2141,63 @@ func (*KaspadMessage_NotifyNewBlockTemplateResponse) isKaspadMessage_Payload() {
 
 func (*KaspadMessage_NewBlockTemplateNotification) isKaspadMessage_Payload() {}
 
+func (*KaspadMessage_GetTransactionRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionAcceptanceRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetTransactionAcceptanceResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetAddressHistoryRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetAddressHistoryResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetFeeEstimateRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetFeeEstimateResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SubmitTransactionReplacementRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SubmitTransactionReplacementResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SaveMempoolRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SaveMempoolResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_LoadMempoolRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_LoadMempoolResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SetLogLevelRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_SetLogLevelResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetLogLevelsRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_GetLogLevelsResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_NotifyMempoolChangedRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_NotifyMempoolChangedResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_MempoolChangedNotification) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_StopNotifyingMempoolChangedRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_StopNotifyingMempoolChangedResponse) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_DebugTransactionScriptsRequest) isKaspadMessage_Payload() {}
+
+func (*KaspadMessage_DebugTransactionScriptsResponse) isKaspadMessage_Payload() {}
+
 var File_messages_proto protoreflect.FileDescriptor
 
 var file_messages_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x12, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x1a, 0x09, 0x70, 0x32, 0x70,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x09, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x22, 0xe1, 0x69, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
+	0x6f, 0x22, 0xb3, 0x7f, 0x0a, 0x0d, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
 	0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
 	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69,
 	0x72, 0x65, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x4d, 0x65, 0x73, 0x73,
@@ -2692,21 +3042,194 @@ var file_messages_proto_rawDesc = []byte{
 	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
 	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1c, 0x6e, 0x65,
 	0x77, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x4e, 0x6f,
-	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x70, 0x61,
-	0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x50, 0x0a, 0x03, 0x50, 0x32, 0x50, 0x12, 0x49, 0x0a, 0x0d,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64,
-	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
-	0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x50, 0x0a, 0x03, 0x52, 0x50, 0x43, 0x12, 0x49,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x60, 0x0a, 0x15, 0x67, 0x65,
+	0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0xbc, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x63, 0x0a, 0x16,
+	0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xbd, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67, 0x65, 0x74, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x7e, 0x0a, 0x1f, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0xbe, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x1f, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x81, 0x01, 0x0a, 0x20, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xbf, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x20, 0x67, 0x65, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x18, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0xc0, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x18, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x6c, 0x0a, 0x19, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xc1, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65,
+	0x2e, 0x47, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x19, 0x67, 0x65, 0x74, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60,
+	0x0a, 0x15, 0x67, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xc2, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x65,
+	0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x15, 0x67, 0x65, 0x74, 0x46, 0x65,
+	0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x63, 0x0a, 0x16, 0x67, 0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xc3, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x28, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65,
+	0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x16, 0x67,
+	0x65, 0x74, 0x46, 0x65, 0x65, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x8a, 0x01, 0x0a, 0x23, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x61,
+	0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xc4, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65,
+	0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x23, 0x73,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x8d, 0x01, 0x0a, 0x24, 0x73, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61,
+	0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xc5, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x36, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53,
+	0x75, 0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x24, 0x73, 0x75,
+	0x62, 0x6d, 0x69, 0x74, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x57, 0x0a, 0x12, 0x73, 0x61, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xc6, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x61, 0x76, 0x65,
+	0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x12, 0x73, 0x61, 0x76, 0x65, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x13, 0x73,
+	0x61, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x18, 0xc7, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x61, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x48, 0x00, 0x52, 0x13, 0x73, 0x61, 0x76, 0x65, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x12, 0x6c, 0x6f, 0x61, 0x64, 0x4d,
+	0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xc8, 0x08,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65,
+	0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x12, 0x6c, 0x6f,
+	0x61, 0x64, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x5a, 0x0a, 0x13, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xc9, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x4d,
+	0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x13, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x12,
+	0x73, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0xca, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48,
+	0x00, 0x52, 0x12, 0x73, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x13, 0x73, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xcb, 0x08, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e,
+	0x53, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x13, 0x73, 0x65,
+	0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5a, 0x0a, 0x13, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xcc, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x4c,
+	0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x13, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67,
+	0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5d, 0x0a,
+	0x14, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xcd, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c,
+	0x65, 0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x14, 0x67, 0x65, 0x74, 0x4c, 0x6f, 0x67, 0x4c, 0x65,
+	0x76, 0x65, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x1b,
+	0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61,
+	0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0xce, 0x08, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4e,
+	0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e,
+	0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x1b, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f,
+	0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x75, 0x0a, 0x1c, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f,
+	0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x18, 0xcf, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
+	0x69, 0x72, 0x65, 0x2e, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f,
+	0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1c, 0x6e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6f, 0x0a, 0x1a, 0x6d, 0x65, 0x6d, 0x70, 0x6f,
+	0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0xd0, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1a, 0x6d, 0x65,
+	0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x4e, 0x6f, 0x74, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x87, 0x01, 0x0a, 0x22, 0x73, 0x74, 0x6f,
+	0x70, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f,
+	0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18,
+	0xd1, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69,
+	0x72, 0x65, 0x2e, 0x53, 0x74, 0x6f, 0x70, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67,
+	0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x22,
+	0x73, 0x74, 0x6f, 0x70, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x6d,
+	0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x8a, 0x01, 0x0a, 0x23, 0x73, 0x74, 0x6f, 0x70, 0x4e, 0x6f, 0x74, 0x69, 0x66,
+	0x79, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0xd2, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x53, 0x74,
+	0x6f, 0x70, 0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x6d, 0x70, 0x6f,
+	0x6f, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x23, 0x73, 0x74, 0x6f, 0x70,
+	0x4e, 0x6f, 0x74, 0x69, 0x66, 0x79, 0x69, 0x6e, 0x67, 0x4d, 0x65, 0x6d, 0x70, 0x6f, 0x6f, 0x6c,
+	0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x7b, 0x0a, 0x1e, 0x64, 0x65, 0x62, 0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x18, 0xd3, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x77, 0x69, 0x72, 0x65, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1e, 0x64, 0x65,
+	0x62, 0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x7e, 0x0a, 0x1f,
+	0x64, 0x65, 0x62, 0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
+	0xd4, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69,
+	0x72, 0x65, 0x2e, 0x44, 0x65, 0x62, 0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x1f, 0x64, 0x65, 0x62,
+	0x75, 0x67, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x09, 0x0a, 0x07,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x32, 0x50, 0x0a, 0x03, 0x50, 0x32, 0x50, 0x12, 0x49,
 	0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
 	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70,
 	0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x6e, 0x65, 0x74,
-	0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72,
-	0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x50, 0x0a, 0x03, 0x52, 0x50, 0x43,
+	0x12, 0x49, 0x0a, 0x0d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61,
+	0x73, 0x70, 0x61, 0x64, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x77, 0x69, 0x72, 0x65, 0x2e, 0x4b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x26, 0x5a, 0x24, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x6e,
+	0x65, 0x74, 0x2f, 0x6b, 0x61, 0x73, 0x70, 0x61, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x77,
+	0x69, 0x72, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2849,6 +3372,31 @@ var file_messages_proto_goTypes = []interface{}{
 	(*NotifyNewBlockTemplateRequestMessage)(nil),                       // 123: protowire.NotifyNewBlockTemplateRequestMessage
 	(*NotifyNewBlockTemplateResponseMessage)(nil),                      // 124: protowire.NotifyNewBlockTemplateResponseMessage
 	(*NewBlockTemplateNotificationMessage)(nil),                        // 125: protowire.NewBlockTemplateNotificationMessage
+	(*GetTransactionRequestMessage)(nil),                               // 126: protowire.GetTransactionRequestMessage
+	(*GetTransactionResponseMessage)(nil),                              // 127: protowire.GetTransactionResponseMessage
+	(*GetTransactionAcceptanceRequestMessage)(nil),                     // 128: protowire.GetTransactionAcceptanceRequestMessage
+	(*GetTransactionAcceptanceResponseMessage)(nil),                    // 129: protowire.GetTransactionAcceptanceResponseMessage
+	(*GetAddressHistoryRequestMessage)(nil),                            // 130: protowire.GetAddressHistoryRequestMessage
+	(*GetAddressHistoryResponseMessage)(nil),                           // 131: protowire.GetAddressHistoryResponseMessage
+	(*GetFeeEstimateRequestMessage)(nil),                               // 132: protowire.GetFeeEstimateRequestMessage
+	(*GetFeeEstimateResponseMessage)(nil),                              // 133: protowire.GetFeeEstimateResponseMessage
+	(*SubmitTransactionReplacementRequestMessage)(nil),                 // 134: protowire.SubmitTransactionReplacementRequestMessage
+	(*SubmitTransactionReplacementResponseMessage)(nil),                // 135: protowire.SubmitTransactionReplacementResponseMessage
+	(*SaveMempoolRequestMessage)(nil),                                  // 136: protowire.SaveMempoolRequestMessage
+	(*SaveMempoolResponseMessage)(nil),                                 // 137: protowire.SaveMempoolResponseMessage
+	(*LoadMempoolRequestMessage)(nil),                                  // 138: protowire.LoadMempoolRequestMessage
+	(*LoadMempoolResponseMessage)(nil),                                 // 139: protowire.LoadMempoolResponseMessage
+	(*SetLogLevelRequestMessage)(nil),                                  // 140: protowire.SetLogLevelRequestMessage
+	(*SetLogLevelResponseMessage)(nil),                                 // 141: protowire.SetLogLevelResponseMessage
+	(*GetLogLevelsRequestMessage)(nil),                                 // 142: protowire.GetLogLevelsRequestMessage
+	(*GetLogLevelsResponseMessage)(nil),                                // 143: protowire.GetLogLevelsResponseMessage
+	(*NotifyMempoolChangedRequestMessage)(nil),                         // 144: protowire.NotifyMempoolChangedRequestMessage
+	(*NotifyMempoolChangedResponseMessage)(nil),                        // 145: protowire.NotifyMempoolChangedResponseMessage
+	(*MempoolChangedNotificationMessage)(nil),                          // 146: protowire.MempoolChangedNotificationMessage
+	(*StopNotifyingMempoolChangedRequestMessage)(nil),                  // 147: protowire.StopNotifyingMempoolChangedRequestMessage
+	(*StopNotifyingMempoolChangedResponseMessage)(nil),                 // 148: protowire.StopNotifyingMempoolChangedResponseMessage
+	(*DebugTransactionScriptsRequestMessage)(nil),                      // 149: protowire.DebugTransactionScriptsRequestMessage
+	(*DebugTransactionScriptsResponseMessage)(nil),                     // 150: protowire.DebugTransactionScriptsResponseMessage
 }
 var file_messages_proto_depIdxs = []int32{
 	1,   // 0: protowire.KaspadMessage.addresses:type_name -> protowire.AddressesMessage
@@ -2977,15 +3525,40 @@ var file_messages_proto_depIdxs = []int32{
 	123, // 123: protowire.KaspadMessage.notifyNewBlockTemplateRequest:type_name -> protowire.NotifyNewBlockTemplateRequestMessage
 	124, // 124: protowire.KaspadMessage.notifyNewBlockTemplateResponse:type_name -> protowire.NotifyNewBlockTemplateResponseMessage
 	125, // 125: protowire.KaspadMessage.newBlockTemplateNotification:type_name -> protowire.NewBlockTemplateNotificationMessage
-	0,   // 126: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 127: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
-	0,   // 128: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
-	0,   // 129: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
-	128, // [128:130] is the sub-list for method output_type
-	126, // [126:128] is the sub-list for method input_type
-	126, // [126:126] is the sub-list for extension type_name
-	126, // [126:126] is the sub-list for extension extendee
-	0,   // [0:126] is the sub-list for field type_name
+	126, // 126: protowire.KaspadMessage.getTransactionRequest:type_name -> protowire.GetTransactionRequestMessage
+	127, // 127: protowire.KaspadMessage.getTransactionResponse:type_name -> protowire.GetTransactionResponseMessage
+	128, // 128: protowire.KaspadMessage.getTransactionAcceptanceRequest:type_name -> protowire.GetTransactionAcceptanceRequestMessage
+	129, // 129: protowire.KaspadMessage.getTransactionAcceptanceResponse:type_name -> protowire.GetTransactionAcceptanceResponseMessage
+	130, // 130: protowire.KaspadMessage.getAddressHistoryRequest:type_name -> protowire.GetAddressHistoryRequestMessage
+	131, // 131: protowire.KaspadMessage.getAddressHistoryResponse:type_name -> protowire.GetAddressHistoryResponseMessage
+	132, // 132: protowire.KaspadMessage.getFeeEstimateRequest:type_name -> protowire.GetFeeEstimateRequestMessage
+	133, // 133: protowire.KaspadMessage.getFeeEstimateResponse:type_name -> protowire.GetFeeEstimateResponseMessage
+	134, // 134: protowire.KaspadMessage.submitTransactionReplacementRequest:type_name -> protowire.SubmitTransactionReplacementRequestMessage
+	135, // 135: protowire.KaspadMessage.submitTransactionReplacementResponse:type_name -> protowire.SubmitTransactionReplacementResponseMessage
+	136, // 136: protowire.KaspadMessage.saveMempoolRequest:type_name -> protowire.SaveMempoolRequestMessage
+	137, // 137: protowire.KaspadMessage.saveMempoolResponse:type_name -> protowire.SaveMempoolResponseMessage
+	138, // 138: protowire.KaspadMessage.loadMempoolRequest:type_name -> protowire.LoadMempoolRequestMessage
+	139, // 139: protowire.KaspadMessage.loadMempoolResponse:type_name -> protowire.LoadMempoolResponseMessage
+	140, // 140: protowire.KaspadMessage.setLogLevelRequest:type_name -> protowire.SetLogLevelRequestMessage
+	141, // 141: protowire.KaspadMessage.setLogLevelResponse:type_name -> protowire.SetLogLevelResponseMessage
+	142, // 142: protowire.KaspadMessage.getLogLevelsRequest:type_name -> protowire.GetLogLevelsRequestMessage
+	143, // 143: protowire.KaspadMessage.getLogLevelsResponse:type_name -> protowire.GetLogLevelsResponseMessage
+	144, // 144: protowire.KaspadMessage.notifyMempoolChangedRequest:type_name -> protowire.NotifyMempoolChangedRequestMessage
+	145, // 145: protowire.KaspadMessage.notifyMempoolChangedResponse:type_name -> protowire.NotifyMempoolChangedResponseMessage
+	146, // 146: protowire.KaspadMessage.mempoolChangedNotification:type_name -> protowire.MempoolChangedNotificationMessage
+	147, // 147: protowire.KaspadMessage.stopNotifyingMempoolChangedRequest:type_name -> protowire.StopNotifyingMempoolChangedRequestMessage
+	148, // 148: protowire.KaspadMessage.stopNotifyingMempoolChangedResponse:type_name -> protowire.StopNotifyingMempoolChangedResponseMessage
+	149, // 149: protowire.KaspadMessage.debugTransactionScriptsRequest:type_name -> protowire.DebugTransactionScriptsRequestMessage
+	150, // 150: protowire.KaspadMessage.debugTransactionScriptsResponse:type_name -> protowire.DebugTransactionScriptsResponseMessage
+	0,   // 151: protowire.P2P.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 152: protowire.RPC.MessageStream:input_type -> protowire.KaspadMessage
+	0,   // 153: protowire.P2P.MessageStream:output_type -> protowire.KaspadMessage
+	0,   // 154: protowire.RPC.MessageStream:output_type -> protowire.KaspadMessage
+	153, // [153:155] is the sub-list for method output_type
+	151, // [151:153] is the sub-list for method input_type
+	151, // [151:151] is the sub-list for extension type_name
+	151, // [151:151] is the sub-list for extension extendee
+	0,   // [0:151] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -3136,6 +3709,31 @@ func file_messages_proto_init() {
 		(*KaspadMessage_NotifyNewBlockTemplateRequest)(nil),
 		(*KaspadMessage_NotifyNewBlockTemplateResponse)(nil),
 		(*KaspadMessage_NewBlockTemplateNotification)(nil),
+		(*KaspadMessage_GetTransactionRequest)(nil),
+		(*KaspadMessage_GetTransactionResponse)(nil),
+		(*KaspadMessage_GetTransactionAcceptanceRequest)(nil),
+		(*KaspadMessage_GetTransactionAcceptanceResponse)(nil),
+		(*KaspadMessage_GetAddressHistoryRequest)(nil),
+		(*KaspadMessage_GetAddressHistoryResponse)(nil),
+		(*KaspadMessage_GetFeeEstimateRequest)(nil),
+		(*KaspadMessage_GetFeeEstimateResponse)(nil),
+		(*KaspadMessage_SubmitTransactionReplacementRequest)(nil),
+		(*KaspadMessage_SubmitTransactionReplacementResponse)(nil),
+		(*KaspadMessage_SaveMempoolRequest)(nil),
+		(*KaspadMessage_SaveMempoolResponse)(nil),
+		(*KaspadMessage_LoadMempoolRequest)(nil),
+		(*KaspadMessage_LoadMempoolResponse)(nil),
+		(*KaspadMessage_SetLogLevelRequest)(nil),
+		(*KaspadMessage_SetLogLevelResponse)(nil),
+		(*KaspadMessage_GetLogLevelsRequest)(nil),
+		(*KaspadMessage_GetLogLevelsResponse)(nil),
+		(*KaspadMessage_NotifyMempoolChangedRequest)(nil),
+		(*KaspadMessage_NotifyMempoolChangedResponse)(nil),
+		(*KaspadMessage_MempoolChangedNotification)(nil),
+		(*KaspadMessage_StopNotifyingMempoolChangedRequest)(nil),
+		(*KaspadMessage_StopNotifyingMempoolChangedResponse)(nil),
+		(*KaspadMessage_DebugTransactionScriptsRequest)(nil),
+		(*KaspadMessage_DebugTransactionScriptsResponse)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
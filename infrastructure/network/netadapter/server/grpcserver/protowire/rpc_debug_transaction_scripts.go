@@ -0,0 +1,159 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_DebugTransactionScriptsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_DebugTransactionScriptsRequest is nil")
+	}
+	return x.DebugTransactionScriptsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_DebugTransactionScriptsRequest) fromAppMessage(message *appmessage.DebugTransactionScriptsRequestMessage) error {
+	x.DebugTransactionScriptsRequest = &DebugTransactionScriptsRequestMessage{
+		Transaction: &RpcTransaction{},
+		UtxoEntries: make([]*RpcUtxoEntry, len(message.UTXOEntries)),
+	}
+	x.DebugTransactionScriptsRequest.Transaction.fromAppMessage(message.Transaction)
+	for i, utxoEntry := range message.UTXOEntries {
+		x.DebugTransactionScriptsRequest.UtxoEntries[i] = &RpcUtxoEntry{}
+		x.DebugTransactionScriptsRequest.UtxoEntries[i].fromAppMessage(utxoEntry)
+	}
+	return nil
+}
+
+func (x *DebugTransactionScriptsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DebugTransactionScriptsRequestMessage is nil")
+	}
+	rpcTransaction, err := x.Transaction.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	utxoEntries := make([]*appmessage.RPCUTXOEntry, len(x.UtxoEntries))
+	for i, utxoEntry := range x.UtxoEntries {
+		utxoEntries[i], err = utxoEntry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.DebugTransactionScriptsRequestMessage{
+		Transaction: rpcTransaction,
+		UTXOEntries: utxoEntries,
+	}, nil
+}
+
+func (x *KaspadMessage_DebugTransactionScriptsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_DebugTransactionScriptsResponse is nil")
+	}
+	return x.DebugTransactionScriptsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_DebugTransactionScriptsResponse) fromAppMessage(message *appmessage.DebugTransactionScriptsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	inputTraces := make([]*RpcScriptInputTrace, len(message.InputTraces))
+	for i, inputTrace := range message.InputTraces {
+		inputTraces[i] = &RpcScriptInputTrace{}
+		inputTraces[i].fromAppMessage(inputTrace)
+	}
+	x.DebugTransactionScriptsResponse = &DebugTransactionScriptsResponseMessage{
+		InputTraces: inputTraces,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *DebugTransactionScriptsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DebugTransactionScriptsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.InputTraces) != 0 {
+		return nil, errors.New("DebugTransactionScriptsResponseMessage contains both an error and a response")
+	}
+
+	inputTraces := make([]*appmessage.RPCScriptInputTrace, len(x.InputTraces))
+	for i, inputTrace := range x.InputTraces {
+		inputTraces[i], err = inputTrace.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.DebugTransactionScriptsResponseMessage{
+		InputTraces: inputTraces,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *RpcScriptInputTrace) toAppMessage() (*appmessage.RPCScriptInputTrace, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcScriptInputTrace is nil")
+	}
+	steps := make([]*appmessage.RPCScriptTraceStep, len(x.Steps))
+	for i, step := range x.Steps {
+		var err error
+		steps[i], err = step.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &appmessage.RPCScriptInputTrace{
+		InputIndex: x.InputIndex,
+		Scripts:    x.Scripts,
+		Steps:      steps,
+		IsValid:    x.IsValid,
+		Error:      x.Error,
+	}, nil
+}
+
+func (x *RpcScriptInputTrace) fromAppMessage(message *appmessage.RPCScriptInputTrace) {
+	steps := make([]*RpcScriptTraceStep, len(message.Steps))
+	for i, step := range message.Steps {
+		steps[i] = &RpcScriptTraceStep{}
+		steps[i].fromAppMessage(step)
+	}
+	*x = RpcScriptInputTrace{
+		InputIndex: message.InputIndex,
+		Scripts:    message.Scripts,
+		Steps:      steps,
+		IsValid:    message.IsValid,
+		Error:      message.Error,
+	}
+}
+
+func (x *RpcScriptTraceStep) toAppMessage() (*appmessage.RPCScriptTraceStep, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcScriptTraceStep is nil")
+	}
+	return &appmessage.RPCScriptTraceStep{
+		ScriptIndex:  x.ScriptIndex,
+		ScriptOffset: x.ScriptOffset,
+		Opcode:       x.Opcode,
+		IsExecuted:   x.IsExecuted,
+		DataStack:    x.DataStack,
+		AltStack:     x.AltStack,
+	}, nil
+}
+
+func (x *RpcScriptTraceStep) fromAppMessage(message *appmessage.RPCScriptTraceStep) {
+	*x = RpcScriptTraceStep{
+		ScriptIndex:  message.ScriptIndex,
+		ScriptOffset: message.ScriptOffset,
+		Opcode:       message.Opcode,
+		IsExecuted:   message.IsExecuted,
+		DataStack:    message.DataStack,
+		AltStack:     message.AltStack,
+	}
+}
@@ -0,0 +1,121 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetAddressHistoryRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetAddressHistoryRequest is nil")
+	}
+	return x.GetAddressHistoryRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetAddressHistoryRequest) fromAppMessage(message *appmessage.GetAddressHistoryRequestMessage) error {
+	x.GetAddressHistoryRequest = &GetAddressHistoryRequestMessage{
+		Address: message.Address,
+		Limit:   message.Limit,
+		Cursor:  message.Cursor,
+	}
+	return nil
+}
+
+func (x *GetAddressHistoryRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetAddressHistoryRequestMessage is nil")
+	}
+	return &appmessage.GetAddressHistoryRequestMessage{
+		Address: x.Address,
+		Limit:   x.Limit,
+		Cursor:  x.Cursor,
+	}, nil
+}
+
+func (x *KaspadMessage_GetAddressHistoryResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetAddressHistoryResponse is nil")
+	}
+	return x.GetAddressHistoryResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetAddressHistoryResponse) fromAppMessage(message *appmessage.GetAddressHistoryResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	entries := make([]*AddressHistoryEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = &AddressHistoryEntry{}
+		entries[i].fromAppMessage(entry)
+	}
+	x.GetAddressHistoryResponse = &GetAddressHistoryResponseMessage{
+		Address:    message.Address,
+		Entries:    entries,
+		NextCursor: message.NextCursor,
+		Error:      rpcErr,
+	}
+	return nil
+}
+
+func (x *GetAddressHistoryResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetAddressHistoryResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetAddressHistoryResponseMessage contains both an error and a response")
+	}
+
+	entries := make([]*appmessage.AddressHistoryEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entryAsAppMessage, err := entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entryAsAppMessage
+	}
+
+	return &appmessage.GetAddressHistoryResponseMessage{
+		Address:    x.Address,
+		Entries:    entries,
+		NextCursor: x.NextCursor,
+		Error:      rpcErr,
+	}, nil
+}
+
+func (x *AddressHistoryEntry) toAppMessage() (*appmessage.AddressHistoryEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "AddressHistoryEntry is nil")
+	}
+	outpoint, err := x.Outpoint.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.AddressHistoryEntry{
+		Outpoint:           outpoint,
+		TransactionID:      x.TransactionId,
+		Amount:             x.Amount,
+		IsDebit:            x.IsDebit,
+		AcceptingBlockHash: x.AcceptingBlockHash,
+		DAAScore:           x.DaaScore,
+	}, nil
+}
+
+func (x *AddressHistoryEntry) fromAppMessage(message *appmessage.AddressHistoryEntry) {
+	outpoint := &RpcOutpoint{}
+	outpoint.fromAppMessage(message.Outpoint)
+	*x = AddressHistoryEntry{
+		Outpoint:           outpoint,
+		TransactionId:      message.TransactionID,
+		Amount:             message.Amount,
+		IsDebit:            message.IsDebit,
+		AcceptingBlockHash: message.AcceptingBlockHash,
+		DaaScore:           message.DAAScore,
+	}
+}
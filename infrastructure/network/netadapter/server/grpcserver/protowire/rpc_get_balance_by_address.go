@@ -14,7 +14,8 @@ func (x *KaspadMessage_GetBalanceByAddressRequest) toAppMessage() (appmessage.Me
 
 func (x *KaspadMessage_GetBalanceByAddressRequest) fromAppMessage(message *appmessage.GetBalanceByAddressRequestMessage) error {
 	x.GetBalanceByAddressRequest = &GetBalanceByAddressRequestMessage{
-		Address: message.Address,
+		Address:            message.Address,
+		IncludeUnconfirmed: message.IncludeUnconfirmed,
 	}
 	return nil
 }
@@ -24,7 +25,8 @@ func (x *GetBalanceByAddressRequestMessage) toAppMessage() (appmessage.Message,
 		return nil, errors.Wrapf(errorNil, "GetBalanceByAddressRequest is nil")
 	}
 	return &appmessage.GetBalanceByAddressRequestMessage{
-		Address: x.Address,
+		Address:            x.Address,
+		IncludeUnconfirmed: x.IncludeUnconfirmed,
 	}, nil
 }
 
@@ -41,7 +43,9 @@ func (x *KaspadMessage_GetBalanceByAddressResponse) fromAppMessage(message *appm
 		err = &RPCError{Message: message.Error.Message}
 	}
 	x.GetBalanceByAddressResponse = &GetBalanceByAddressResponseMessage{
-		Balance: message.Balance,
+		Balance:             message.Balance,
+		UnconfirmedIncoming: message.UnconfirmedIncoming,
+		UnconfirmedOutgoing: message.UnconfirmedOutgoing,
 
 		Error: err,
 	}
@@ -63,7 +67,9 @@ func (x *GetBalanceByAddressResponseMessage) toAppMessage() (appmessage.Message,
 	}
 
 	return &appmessage.GetBalanceByAddressResponseMessage{
-		Balance: x.Balance,
-		Error:   rpcErr,
+		Balance:             x.Balance,
+		UnconfirmedIncoming: x.UnconfirmedIncoming,
+		UnconfirmedOutgoing: x.UnconfirmedOutgoing,
+		Error:               rpcErr,
 	}, nil
 }
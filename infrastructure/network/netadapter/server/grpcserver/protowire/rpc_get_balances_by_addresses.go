@@ -14,7 +14,8 @@ func (x *KaspadMessage_GetBalancesByAddressesRequest) toAppMessage() (appmessage
 
 func (x *KaspadMessage_GetBalancesByAddressesRequest) fromAppMessage(message *appmessage.GetBalancesByAddressesRequestMessage) error {
 	x.GetBalancesByAddressesRequest = &GetBalancesByAddressesRequestMessage{
-		Addresses: message.Addresses,
+		Addresses:          message.Addresses,
+		IncludeUnconfirmed: message.IncludeUnconfirmed,
 	}
 	return nil
 }
@@ -24,7 +25,8 @@ func (x *GetBalancesByAddressesRequestMessage) toAppMessage() (appmessage.Messag
 		return nil, errors.Wrapf(errorNil, "GetBalanceByAddressRequest is nil")
 	}
 	return &appmessage.GetBalancesByAddressesRequestMessage{
-		Addresses: x.Addresses,
+		Addresses:          x.Addresses,
+		IncludeUnconfirmed: x.IncludeUnconfirmed,
 	}, nil
 }
 
@@ -86,14 +88,18 @@ func (x *BalancesByAddressEntry) toAppMessage() (*appmessage.BalancesByAddresses
 		return nil, errors.Wrapf(errorNil, "BalancesByAddressesEntry is nil")
 	}
 	return &appmessage.BalancesByAddressesEntry{
-		Address: x.Address,
-		Balance: x.Balance,
+		Address:             x.Address,
+		Balance:             x.Balance,
+		UnconfirmedIncoming: x.UnconfirmedIncoming,
+		UnconfirmedOutgoing: x.UnconfirmedOutgoing,
 	}, nil
 }
 
 func (x *BalancesByAddressEntry) fromAppMessage(message *appmessage.BalancesByAddressesEntry) {
 	*x = BalancesByAddressEntry{
-		Address: message.Address,
-		Balance: message.Balance,
+		Address:             message.Address,
+		Balance:             message.Balance,
+		UnconfirmedIncoming: message.UnconfirmedIncoming,
+		UnconfirmedOutgoing: message.UnconfirmedOutgoing,
 	}
 }
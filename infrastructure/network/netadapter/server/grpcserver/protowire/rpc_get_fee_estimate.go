@@ -0,0 +1,100 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetFeeEstimateRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetFeeEstimateRequest is nil")
+	}
+	return &appmessage.GetFeeEstimateRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetFeeEstimateRequest) fromAppMessage(_ *appmessage.GetFeeEstimateRequestMessage) error {
+	x.GetFeeEstimateRequest = &GetFeeEstimateRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetFeeEstimateResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetFeeEstimateResponse is nil")
+	}
+	return x.GetFeeEstimateResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetFeeEstimateResponse) fromAppMessage(message *appmessage.GetFeeEstimateResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	x.GetFeeEstimateResponse = &GetFeeEstimateResponseMessage{
+		PriorityBucket:  feeRateBucketFromAppMessage(message.PriorityBucket),
+		NormalBucket:    feeRateBucketFromAppMessage(message.NormalBucket),
+		LowBucket:       feeRateBucketFromAppMessage(message.LowBucket),
+		VirtualDaaScore: message.VirtualDAAScore,
+		Error:           rpcErr,
+	}
+	return nil
+}
+
+func (x *GetFeeEstimateResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetFeeEstimateResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil {
+		if x.PriorityBucket != nil || x.NormalBucket != nil || x.LowBucket != nil {
+			return nil, errors.New("GetFeeEstimateResponseMessage contains both an error and a response")
+		}
+		return &appmessage.GetFeeEstimateResponseMessage{
+			Error: rpcErr,
+		}, nil
+	}
+
+	priorityBucket, err := x.PriorityBucket.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	normalBucket, err := x.NormalBucket.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	lowBucket, err := x.LowBucket.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	return &appmessage.GetFeeEstimateResponseMessage{
+		PriorityBucket:  priorityBucket,
+		NormalBucket:    normalBucket,
+		LowBucket:       lowBucket,
+		VirtualDAAScore: x.VirtualDaaScore,
+	}, nil
+}
+
+func (x *RpcFeeRateBucket) toAppMessage() (*appmessage.RPCFeeRateBucket, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "RpcFeeRateBucket is nil")
+	}
+	return &appmessage.RPCFeeRateBucket{
+		FeeRate:                    x.Feerate,
+		EstimatedInclusionDAAScore: x.EstimatedInclusionDaaScore,
+	}, nil
+}
+
+func feeRateBucketFromAppMessage(bucket *appmessage.RPCFeeRateBucket) *RpcFeeRateBucket {
+	if bucket == nil {
+		return nil
+	}
+	return &RpcFeeRateBucket{
+		Feerate:                    bucket.FeeRate,
+		EstimatedInclusionDaaScore: bucket.EstimatedInclusionDAAScore,
+	}
+}
@@ -0,0 +1,66 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetLogLevelsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetLogLevelsRequest is nil")
+	}
+	return &appmessage.GetLogLevelsRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetLogLevelsRequest) fromAppMessage(_ *appmessage.GetLogLevelsRequestMessage) error {
+	x.GetLogLevelsRequest = &GetLogLevelsRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetLogLevelsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetLogLevelsResponse is nil")
+	}
+	return x.GetLogLevelsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetLogLevelsResponse) fromAppMessage(message *appmessage.GetLogLevelsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	logLevels := make([]*SubsystemLogLevel, len(message.LogLevels))
+	for i, logLevel := range message.LogLevels {
+		logLevels[i] = &SubsystemLogLevel{
+			Subsystem: logLevel.Subsystem,
+			Level:     logLevel.Level,
+		}
+	}
+	x.GetLogLevelsResponse = &GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+		Error:     err,
+	}
+	return nil
+}
+
+func (x *GetLogLevelsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetLogLevelsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	logLevels := make([]*appmessage.SubsystemLogLevel, len(x.LogLevels))
+	for i, logLevel := range x.LogLevels {
+		logLevels[i] = &appmessage.SubsystemLogLevel{
+			Subsystem: logLevel.Subsystem,
+			Level:     logLevel.Level,
+		}
+	}
+	return &appmessage.GetLogLevelsResponseMessage{
+		LogLevels: logLevels,
+		Error:     rpcErr,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetTransactionAcceptanceRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionAcceptanceRequest is nil")
+	}
+	return x.GetTransactionAcceptanceRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionAcceptanceRequest) fromAppMessage(message *appmessage.GetTransactionAcceptanceRequestMessage) error {
+	x.GetTransactionAcceptanceRequest = &GetTransactionAcceptanceRequestMessage{
+		TransactionIds: message.TransactionIDs,
+	}
+	return nil
+}
+
+func (x *GetTransactionAcceptanceRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionAcceptanceRequestMessage is nil")
+	}
+	return &appmessage.GetTransactionAcceptanceRequestMessage{
+		TransactionIDs: x.TransactionIds,
+	}, nil
+}
+
+func (x *KaspadMessage_GetTransactionAcceptanceResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionAcceptanceResponse is nil")
+	}
+	return x.GetTransactionAcceptanceResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionAcceptanceResponse) fromAppMessage(message *appmessage.GetTransactionAcceptanceResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	entries := make([]*TransactionAcceptanceEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = &TransactionAcceptanceEntry{}
+		entries[i].fromAppMessage(entry)
+	}
+	x.GetTransactionAcceptanceResponse = &GetTransactionAcceptanceResponseMessage{
+		Entries: entries,
+		Error:   rpcErr,
+	}
+	return nil
+}
+
+func (x *GetTransactionAcceptanceResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionAcceptanceResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetTransactionAcceptanceResponseMessage contains both an error and a response")
+	}
+
+	entries := make([]*appmessage.TransactionAcceptanceEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entryAsAppMessage, err := entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entryAsAppMessage
+	}
+
+	return &appmessage.GetTransactionAcceptanceResponseMessage{
+		Entries: entries,
+		Error:   rpcErr,
+	}, nil
+}
+
+func (x *TransactionAcceptanceEntry) toAppMessage() (*appmessage.TransactionAcceptanceEntry, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TransactionAcceptanceEntry is nil")
+	}
+	return &appmessage.TransactionAcceptanceEntry{
+		TransactionID:           x.TransactionId,
+		IsAccepted:              x.IsAccepted,
+		AcceptingBlockHash:      x.AcceptingBlockHash,
+		IncludingBlockHash:      x.IncludingBlockHash,
+		AcceptingBlockBlueScore: x.AcceptingBlockBlueScore,
+		Confirmations:           x.Confirmations,
+	}, nil
+}
+
+func (x *TransactionAcceptanceEntry) fromAppMessage(message *appmessage.TransactionAcceptanceEntry) {
+	*x = TransactionAcceptanceEntry{
+		TransactionId:           message.TransactionID,
+		IsAccepted:              message.IsAccepted,
+		AcceptingBlockHash:      message.AcceptingBlockHash,
+		IncludingBlockHash:      message.IncludingBlockHash,
+		AcceptingBlockBlueScore: message.AcceptingBlockBlueScore,
+		Confirmations:           message.Confirmations,
+	}
+}
@@ -14,7 +14,8 @@ func (x *KaspadMessage_GetUtxosByAddressesRequest) toAppMessage() (appmessage.Me
 
 func (x *KaspadMessage_GetUtxosByAddressesRequest) fromAppMessage(message *appmessage.GetUTXOsByAddressesRequestMessage) error {
 	x.GetUtxosByAddressesRequest = &GetUtxosByAddressesRequestMessage{
-		Addresses: message.Addresses,
+		Addresses:          message.Addresses,
+		IncludeUnconfirmed: message.IncludeUnconfirmed,
 	}
 	return nil
 }
@@ -24,7 +25,8 @@ func (x *GetUtxosByAddressesRequestMessage) toAppMessage() (appmessage.Message,
 		return nil, errors.Wrapf(errorNil, "GetUtxosByAddressesRequestMessage is nil")
 	}
 	return &appmessage.GetUTXOsByAddressesRequestMessage{
-		Addresses: x.Addresses,
+		Addresses:          x.Addresses,
+		IncludeUnconfirmed: x.IncludeUnconfirmed,
 	}, nil
 }
 
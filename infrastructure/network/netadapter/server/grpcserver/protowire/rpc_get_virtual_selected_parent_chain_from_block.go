@@ -14,7 +14,8 @@ func (x *KaspadMessage_GetVirtualSelectedParentChainFromBlockRequest) toAppMessa
 
 func (x *KaspadMessage_GetVirtualSelectedParentChainFromBlockRequest) fromAppMessage(message *appmessage.GetVirtualSelectedParentChainFromBlockRequestMessage) error {
 	x.GetVirtualSelectedParentChainFromBlockRequest = &GetVirtualSelectedParentChainFromBlockRequestMessage{
-		StartHash: message.StartHash,
+		StartHash:                     message.StartHash,
+		IncludeAcceptedTransactionIds: message.IncludeAcceptedTransactionIDs,
 	}
 	return nil
 }
@@ -24,7 +25,8 @@ func (x *GetVirtualSelectedParentChainFromBlockRequestMessage) toAppMessage() (a
 		return nil, errors.Wrapf(errorNil, "GetVirtualSelectedParentChainFromBlockRequestMessage is nil")
 	}
 	return &appmessage.GetVirtualSelectedParentChainFromBlockRequestMessage{
-		StartHash: x.StartHash,
+		StartHash:                     x.StartHash,
+		IncludeAcceptedTransactionIDs: x.IncludeAcceptedTransactionIds,
 	}, nil
 }
 
@@ -43,6 +45,7 @@ func (x *KaspadMessage_GetVirtualSelectedParentChainFromBlockResponse) fromAppMe
 	x.GetVirtualSelectedParentChainFromBlockResponse = &GetVirtualSelectedParentChainFromBlockResponseMessage{
 		RemovedChainBlockHashes: message.RemovedChainBlockHashes,
 		AddedChainBlockHashes:   message.AddedChainBlockHashes,
+		AcceptedTransactionIds:  acceptedTransactionIDsFromAppMessage(message.AcceptedTransactionIDs),
 		Error:                   err,
 	}
 	return nil
@@ -58,13 +61,47 @@ func (x *GetVirtualSelectedParentChainFromBlockResponseMessage) toAppMessage() (
 		return nil, err
 	}
 
-	if rpcErr != nil && (len(x.AddedChainBlockHashes) != 0 || len(x.RemovedChainBlockHashes) != 0) {
+	if rpcErr != nil && (len(x.AddedChainBlockHashes) != 0 || len(x.RemovedChainBlockHashes) != 0 ||
+		len(x.AcceptedTransactionIds) != 0) {
 		return nil, errors.New("GetVirtualSelectedParentChainFromBlockResponseMessage contains both an error and a response")
 	}
 
 	return &appmessage.GetVirtualSelectedParentChainFromBlockResponseMessage{
 		RemovedChainBlockHashes: x.RemovedChainBlockHashes,
 		AddedChainBlockHashes:   x.AddedChainBlockHashes,
+		AcceptedTransactionIDs:  acceptedTransactionIDsToAppMessage(x.AcceptedTransactionIds),
 		Error:                   rpcErr,
 	}, nil
 }
+
+func acceptedTransactionIDsFromAppMessage(
+	acceptedTransactionIDs []*appmessage.AcceptedTransactionIDs) []*AcceptedTransactionIds {
+
+	if acceptedTransactionIDs == nil {
+		return nil
+	}
+	protoAcceptedTransactionIDs := make([]*AcceptedTransactionIds, len(acceptedTransactionIDs))
+	for i, acceptedTransactionIDsOfBlock := range acceptedTransactionIDs {
+		protoAcceptedTransactionIDs[i] = &AcceptedTransactionIds{
+			AcceptingBlockHash:     acceptedTransactionIDsOfBlock.AcceptingBlockHash,
+			AcceptedTransactionIds: acceptedTransactionIDsOfBlock.AcceptedTransactionIDs,
+		}
+	}
+	return protoAcceptedTransactionIDs
+}
+
+func acceptedTransactionIDsToAppMessage(
+	protoAcceptedTransactionIDs []*AcceptedTransactionIds) []*appmessage.AcceptedTransactionIDs {
+
+	if protoAcceptedTransactionIDs == nil {
+		return nil
+	}
+	acceptedTransactionIDs := make([]*appmessage.AcceptedTransactionIDs, len(protoAcceptedTransactionIDs))
+	for i, protoAcceptedTransactionIDsOfBlock := range protoAcceptedTransactionIDs {
+		acceptedTransactionIDs[i] = &appmessage.AcceptedTransactionIDs{
+			AcceptingBlockHash:     protoAcceptedTransactionIDsOfBlock.AcceptingBlockHash,
+			AcceptedTransactionIDs: protoAcceptedTransactionIDsOfBlock.AcceptedTransactionIds,
+		}
+	}
+	return acceptedTransactionIDs
+}
@@ -0,0 +1,54 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_LoadMempoolRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_LoadMempoolRequest is nil")
+	}
+	return &appmessage.LoadMempoolRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_LoadMempoolRequest) fromAppMessage(_ *appmessage.LoadMempoolRequestMessage) error {
+	x.LoadMempoolRequest = &LoadMempoolRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_LoadMempoolResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_LoadMempoolResponse is nil")
+	}
+	return x.LoadMempoolResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_LoadMempoolResponse) fromAppMessage(message *appmessage.LoadMempoolResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.LoadMempoolResponse = &LoadMempoolResponseMessage{
+		AcceptedTransactionCount: message.AcceptedTransactionCount,
+		RejectedTransactionCount: message.RejectedTransactionCount,
+		Error:                    err,
+	}
+	return nil
+}
+
+func (x *LoadMempoolResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "LoadMempoolResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.LoadMempoolResponseMessage{
+		AcceptedTransactionCount: x.AcceptedTransactionCount,
+		RejectedTransactionCount: x.RejectedTransactionCount,
+		Error:                    rpcErr,
+	}, nil
+}
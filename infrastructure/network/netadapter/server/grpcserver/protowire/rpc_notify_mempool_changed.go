@@ -0,0 +1,139 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_NotifyMempoolChangedRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolChangedRequest is nil")
+	}
+	return x.NotifyMempoolChangedRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedRequest) fromAppMessage(message *appmessage.NotifyMempoolChangedRequestMessage) error {
+	scriptPublicKeys := make([]*RpcScriptPublicKey, len(message.ScriptPublicKeys))
+	for i, scriptPublicKey := range message.ScriptPublicKeys {
+		scriptPublicKeys[i] = &RpcScriptPublicKey{}
+		scriptPublicKeys[i].fromAppMessage(scriptPublicKey)
+	}
+	x.NotifyMempoolChangedRequest = &NotifyMempoolChangedRequestMessage{
+		Addresses:        message.Addresses,
+		ScriptPublicKeys: scriptPublicKeys,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolChangedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolChangedRequestMessage is nil")
+	}
+	scriptPublicKeys := make([]*appmessage.RPCScriptPublicKey, len(x.ScriptPublicKeys))
+	for i, scriptPublicKey := range x.ScriptPublicKeys {
+		appScriptPublicKey, err := scriptPublicKey.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		scriptPublicKeys[i] = appScriptPublicKey
+	}
+	return &appmessage.NotifyMempoolChangedRequestMessage{
+		Addresses:        x.Addresses,
+		ScriptPublicKeys: scriptPublicKeys,
+	}, nil
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolChangedResponse is nil")
+	}
+	return x.NotifyMempoolChangedResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolChangedResponse) fromAppMessage(message *appmessage.NotifyMempoolChangedResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.NotifyMempoolChangedResponse = &NotifyMempoolChangedResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolChangedResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolChangedResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.NotifyMempoolChangedResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *KaspadMessage_MempoolChangedNotification) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_MempoolChangedNotification is nil")
+	}
+	return x.MempoolChangedNotification.toAppMessage()
+}
+
+func (x *KaspadMessage_MempoolChangedNotification) fromAppMessage(message *appmessage.MempoolChangedNotificationMessage) error {
+	changes := make([]*MempoolChange, len(message.Changes))
+	for i, change := range message.Changes {
+		changes[i] = &MempoolChange{}
+		changes[i].fromAppMessage(change)
+	}
+	x.MempoolChangedNotification = &MempoolChangedNotificationMessage{
+		Changes: changes,
+	}
+	return nil
+}
+
+func (x *MempoolChangedNotificationMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "MempoolChangedNotificationMessage is nil")
+	}
+	changes := make([]*appmessage.MempoolChange, len(x.Changes))
+	for i, change := range x.Changes {
+		appChange, err := change.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = appChange
+	}
+	return &appmessage.MempoolChangedNotificationMessage{
+		Changes: changes,
+	}, nil
+}
+
+func (x *MempoolChange) toAppMessage() (*appmessage.MempoolChange, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "MempoolChange is nil")
+	}
+	transaction, err := x.Transaction.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.MempoolChange{
+		Type:          x.Type,
+		Transaction:   transaction,
+		IsOrphan:      x.IsOrphan,
+		RemovalReason: x.RemovalReason,
+	}, nil
+}
+
+func (x *MempoolChange) fromAppMessage(message *appmessage.MempoolChange) {
+	transaction := &RpcTransaction{}
+	transaction.fromAppMessage(message.Transaction)
+	*x = MempoolChange{
+		Type:          message.Type,
+		Transaction:   transaction,
+		IsOrphan:      message.IsOrphan,
+		RemovalReason: message.RemovalReason,
+	}
+}
@@ -133,9 +133,11 @@ func (x *UtxosByAddressesEntry) toAppMessage() (*appmessage.UTXOsByAddressesEntr
 		return nil, err
 	}
 	return &appmessage.UTXOsByAddressesEntry{
-		Address:   x.Address,
-		Outpoint:  outpoint,
-		UTXOEntry: entry,
+		Address:              x.Address,
+		Outpoint:             outpoint,
+		UTXOEntry:            entry,
+		IsUnconfirmed:        x.IsUnconfirmed,
+		IsSpentByUnconfirmed: x.IsSpentByUnconfirmed,
 	}, nil
 }
 
@@ -148,8 +150,10 @@ func (x *UtxosByAddressesEntry) fromAppMessage(message *appmessage.UTXOsByAddres
 		utxoEntry.fromAppMessage(message.UTXOEntry)
 	}
 	*x = UtxosByAddressesEntry{
-		Address:   message.Address,
-		Outpoint:  outpoint,
-		UtxoEntry: utxoEntry,
+		Address:              message.Address,
+		Outpoint:             outpoint,
+		UtxoEntry:            utxoEntry,
+		IsUnconfirmed:        message.IsUnconfirmed,
+		IsSpentByUnconfirmed: message.IsSpentByUnconfirmed,
 	}
 }
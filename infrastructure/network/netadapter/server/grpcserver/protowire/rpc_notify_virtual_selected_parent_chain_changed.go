@@ -9,14 +9,25 @@ func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) toAppMess
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest is nil")
 	}
-	return &appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage{}, nil
+	return x.NotifyVirtualSelectedParentChainChangedRequest.toAppMessage()
 }
 
-func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) fromAppMessage(_ *appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage) error {
-	x.NotifyVirtualSelectedParentChainChangedRequest = &NotifyVirtualSelectedParentChainChangedRequestMessage{}
+func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) fromAppMessage(message *appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage) error {
+	x.NotifyVirtualSelectedParentChainChangedRequest = &NotifyVirtualSelectedParentChainChangedRequestMessage{
+		IncludeAcceptedTransactionIds: message.IncludeAcceptedTransactionIDs,
+	}
 	return nil
 }
 
+func (x *NotifyVirtualSelectedParentChainChangedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyVirtualSelectedParentChainChangedRequestMessage is nil")
+	}
+	return &appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage{
+		IncludeAcceptedTransactionIDs: x.IncludeAcceptedTransactionIds,
+	}, nil
+}
+
 func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedResponse) toAppMessage() (appmessage.Message, error) {
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyVirtualSelectedParentChainChangedResponse is nil")
@@ -60,6 +71,7 @@ func (x *KaspadMessage_VirtualSelectedParentChainChangedNotification) fromAppMes
 	x.VirtualSelectedParentChainChangedNotification = &VirtualSelectedParentChainChangedNotificationMessage{
 		RemovedChainBlockHashes: message.RemovedChainBlockHashes,
 		AddedChainBlockHashes:   message.AddedChainBlockHashes,
+		AcceptedTransactionIds:  acceptedTransactionIDsFromAppMessage(message.AcceptedTransactionIDs),
 	}
 	return nil
 }
@@ -71,5 +83,6 @@ func (x *VirtualSelectedParentChainChangedNotificationMessage) toAppMessage() (a
 	return &appmessage.VirtualSelectedParentChainChangedNotificationMessage{
 		RemovedChainBlockHashes: x.RemovedChainBlockHashes,
 		AddedChainBlockHashes:   x.AddedChainBlockHashes,
+		AcceptedTransactionIDs:  acceptedTransactionIDsToAppMessage(x.AcceptedTransactionIds),
 	}, nil
 }
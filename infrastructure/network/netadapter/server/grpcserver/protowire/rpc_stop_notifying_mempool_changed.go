@@ -0,0 +1,57 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_StopNotifyingMempoolChangedRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_StopNotifyingMempoolChangedRequest is nil")
+	}
+	return x.StopNotifyingMempoolChangedRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolChangedRequest) fromAppMessage(_ *appmessage.StopNotifyingMempoolChangedRequestMessage) error {
+	x.StopNotifyingMempoolChangedRequest = &StopNotifyingMempoolChangedRequestMessage{}
+	return nil
+}
+
+func (x *StopNotifyingMempoolChangedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "StopNotifyingMempoolChangedRequestMessage is nil")
+	}
+	return &appmessage.StopNotifyingMempoolChangedRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolChangedResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_StopNotifyingMempoolChangedResponse is nil")
+	}
+	return x.StopNotifyingMempoolChangedResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolChangedResponse) fromAppMessage(message *appmessage.StopNotifyingMempoolChangedResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.StopNotifyingMempoolChangedResponse = &StopNotifyingMempoolChangedResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *StopNotifyingMempoolChangedResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "StopNotifyingMempoolChangedResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.StopNotifyingMempoolChangedResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// AuthorizationMetadataKey is the gRPC metadata key RPC clients send their credentials in.
+// Its value is either "Basic <base64 of username:password>" or "Bearer <token>"
+const AuthorizationMetadataKey = "authorization"
+
+// RPCRoleMetadataKey is the gRPC header metadata key in which the RPC server tells
+// authenticated clients their role
+const RPCRoleMetadataKey = "kaspad-rpc-role"
+
+const (
+	basicAuthorizationPrefix  = "Basic "
+	bearerAuthorizationPrefix = "Bearer "
+)
+
+// RPCCredentials are the credentials RPC clients may authenticate with.
+// If none of them are set, authentication is disabled and all clients
+// have admin permissions
+type RPCCredentials struct {
+	AdminUsername string
+	AdminPassword string
+	AdminToken    string
+
+	SubmitUsername string
+	SubmitPassword string
+	SubmitToken    string
+
+	ReadOnlyUsername string
+	ReadOnlyPassword string
+	ReadOnlyToken    string
+}
+
+type rpcRoleContextKey struct{}
+
+func (c *RPCCredentials) isAuthenticationEnabled() bool {
+	return c != nil && (c.AdminUsername != "" || c.AdminToken != "" ||
+		c.SubmitUsername != "" || c.SubmitToken != "" ||
+		c.ReadOnlyUsername != "" || c.ReadOnlyToken != "")
+}
+
+// authenticate returns the role of the RPC client that sent the
+// authorization metadata in the given context
+func (c *RPCCredentials) authenticate(ctx context.Context) (server.RPCRole, error) {
+	if !c.isAuthenticationEnabled() {
+		return server.RPCRoleAdmin, nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, errors.New("missing authorization metadata")
+	}
+	authorizations := md.Get(AuthorizationMetadataKey)
+	if len(authorizations) != 1 {
+		return 0, errors.New("missing authorization metadata")
+	}
+	return c.AuthenticateAuthorization(authorizations[0])
+}
+
+// AuthenticateAuthorization returns the role of the RPC client that sent the given
+// authorization, which is either "Basic <base64 of username:password>" or "Bearer <token>".
+// It's used by RPC transports that receive the authorization in other ways than gRPC metadata
+func (c *RPCCredentials) AuthenticateAuthorization(authorization string) (server.RPCRole, error) {
+	if !c.isAuthenticationEnabled() {
+		return server.RPCRoleAdmin, nil
+	}
+
+	switch {
+	case strings.HasPrefix(authorization, bearerAuthorizationPrefix):
+		token := strings.TrimPrefix(authorization, bearerAuthorizationPrefix)
+		if c.AdminToken != "" && secureEquals(token, c.AdminToken) {
+			return server.RPCRoleAdmin, nil
+		}
+		if c.SubmitToken != "" && secureEquals(token, c.SubmitToken) {
+			return server.RPCRoleSubmit, nil
+		}
+		if c.ReadOnlyToken != "" && secureEquals(token, c.ReadOnlyToken) {
+			return server.RPCRoleReadOnly, nil
+		}
+
+	case strings.HasPrefix(authorization, basicAuthorizationPrefix):
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(authorization, basicAuthorizationPrefix))
+		if err != nil {
+			return 0, errors.New("malformed basic authorization")
+		}
+		username, password, ok := strings.Cut(string(decoded), ":")
+		if !ok {
+			return 0, errors.New("malformed basic authorization")
+		}
+		if c.AdminUsername != "" && secureEquals(username, c.AdminUsername) &&
+			secureEquals(password, c.AdminPassword) {
+			return server.RPCRoleAdmin, nil
+		}
+		if c.SubmitUsername != "" && secureEquals(username, c.SubmitUsername) &&
+			secureEquals(password, c.SubmitPassword) {
+			return server.RPCRoleSubmit, nil
+		}
+		if c.ReadOnlyUsername != "" && secureEquals(username, c.ReadOnlyUsername) &&
+			secureEquals(password, c.ReadOnlyPassword) {
+			return server.RPCRoleReadOnly, nil
+		}
+
+	default:
+		return 0, errors.New("unsupported authorization scheme")
+	}
+
+	return 0, errors.New("invalid credentials")
+}
+
+// secureEquals compares the given strings in constant time. The strings
+// are hashed first so that not even their lengths are leaked
+func secureEquals(a, b string) bool {
+	aHash := sha256.Sum256([]byte(a))
+	bHash := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(aHash[:], bHash[:]) == 1
+}
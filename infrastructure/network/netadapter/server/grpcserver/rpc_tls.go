@@ -0,0 +1,162 @@
+package grpcserver
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const selfSignedCertificateValidity = 10 * 365 * 24 * time.Hour
+
+// LoadRPCTLSConfig loads the certificate and key of the RPC server from the given files.
+// If neither of the files exists, a self-signed certificate is generated and written to them first
+func LoadRPCTLSConfig(certificateFile, keyFile string) (*tls.Config, error) {
+	certificateFileExists, err := fileExists(certificateFile)
+	if err != nil {
+		return nil, err
+	}
+	keyFileExists, err := fileExists(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if certificateFileExists != keyFileExists {
+		return nil, errors.Errorf("only one of the RPC certificate file %s and the RPC key file %s exists",
+			certificateFile, keyFile)
+	}
+	if !certificateFileExists {
+		log.Infof("Generating a self-signed RPC certificate into %s", certificateFile)
+		err := generateSelfSignedCertificate(certificateFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certificateFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error loading the RPC certificate")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+func fileExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// generateSelfSignedCertificate generates a self-signed certificate that is valid for
+// the local host names and addresses. The certificate may act as its own certificate
+// authority, so that clients can trust it directly.
+func generateSelfSignedCertificate(certificateFile, keyFile string) error {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
+	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	dnsNames := []string{"localhost"}
+	if hostname != "localhost" {
+		dnsNames = append(dnsNames, hostname)
+	}
+	ipAddresses, err := localIPAddresses()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"kaspad autogenerated cert"},
+			CommonName:   hostname,
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(selfSignedCertificateValidity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		DNSNames:              dnsNames,
+		IPAddresses:           ipAddresses,
+	}
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = writePEMFile(certificateFile, "CERTIFICATE", certificateDER, 0644)
+	if err != nil {
+		return err
+	}
+	err = writePEMFile(keyFile, "EC PRIVATE KEY", keyDER, 0600)
+	if err != nil {
+		_ = os.Remove(certificateFile)
+		return err
+	}
+	return nil
+}
+
+func localIPAddresses() ([]net.IP, error) {
+	ipAddresses := []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	interfaceAddresses, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for _, interfaceAddress := range interfaceAddresses {
+		ipNet, ok := interfaceAddress.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		ipAddresses = append(ipAddresses, ipNet.IP)
+	}
+	return ipAddresses, nil
+}
+
+func writePEMFile(path string, blockType string, bytes []byte, permissions os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, permissions)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = pem.Encode(file, &pem.Block{Type: blockType, Bytes: bytes})
+	if err != nil {
+		_ = file.Close()
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(file.Close())
+}
@@ -1,26 +1,43 @@
 package grpcserver
 
 import (
+	"context"
+	"crypto/tls"
+
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
 	"github.com/kaspanet/kaspad/util/panics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 type rpcServer struct {
 	protowire.UnimplementedRPCServer
 	gRPCServer
+
+	credentials *RPCCredentials
 }
 
 // RPCMaxMessageSize is the max message size for the RPC server to send and receive
 const RPCMaxMessageSize = 1024 * 1024 * 1024 // 1 GB
 
-// RPCMaxInboundConnections is the max amount of inbound connections for the RPC server
-const RPCMaxInboundConnections = 128
+// NewRPCServer creates a new RPCServer that accepts up to maxInboundConnections concurrent
+// clients, where 0 means no limit.
+// If tlsConfig is not nil, the server is served over TLS.
+// If rpcCredentials is not nil and has any credentials set, clients are required to authenticate
+func NewRPCServer(listeningAddresses []string, maxInboundConnections int, tlsConfig *tls.Config,
+	rpcCredentials *RPCCredentials) (server.Server, error) {
 
-// NewRPCServer creates a new RPCServer
-func NewRPCServer(listeningAddresses []string) (server.Server, error) {
-	gRPCServer := newGRPCServer(listeningAddresses, RPCMaxMessageSize, RPCMaxInboundConnections, "RPC")
-	rpcServer := &rpcServer{gRPCServer: *gRPCServer}
+	var serverOptions []grpc.ServerOption
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	gRPCServer := newGRPCServer(listeningAddresses, RPCMaxMessageSize, maxInboundConnections, "RPC", serverOptions...)
+	rpcServer := &rpcServer{gRPCServer: *gRPCServer, credentials: rpcCredentials}
 	protowire.RegisterRPCServer(gRPCServer.server, rpcServer)
 	return rpcServer, nil
 }
@@ -28,5 +45,23 @@ func NewRPCServer(listeningAddresses []string) (server.Server, error) {
 func (r *rpcServer) MessageStream(stream protowire.RPC_MessageStreamServer) error {
 	defer panics.HandlePanic(log, "rpcServer.MessageStream", nil)
 
-	return r.handleInboundConnection(stream.Context(), stream)
+	ctx := stream.Context()
+	rpcRole, err := r.credentials.authenticate(ctx)
+	if err != nil {
+		address := "unknown address"
+		if peerInfo, ok := peer.FromContext(ctx); ok {
+			address = peerInfo.Addr.String()
+		}
+		log.Warnf("RPC authentication failed for %s: %s", address, err)
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	// Send the headers right away, so that clients learn they had
+	// been authenticated successfully before exchanging any messages
+	err = stream.SendHeader(metadata.Pairs(RPCRoleMetadataKey, rpcRole.String()))
+	if err != nil {
+		return err
+	}
+	ctx = context.WithValue(ctx, rpcRoleContextKey{}, rpcRole)
+
+	return r.handleInboundConnection(ctx, stream)
 }
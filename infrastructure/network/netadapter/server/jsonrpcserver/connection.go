@@ -0,0 +1,236 @@
+package jsonrpcserver
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/pkg/errors"
+)
+
+// jsonRPCConnection is the connection of a JSON-RPC client. It feeds the RPC messages
+// the client calls into its router, and converts the RPC messages the router sends
+// back into JSON-RPC responses and notifications.
+//
+// RPC requests are handled one by one in the order they're received, and every request
+// gets exactly one response, so responses are matched with the JSON-RPC IDs of their
+// requests by order
+type jsonRPCConnection struct {
+	// bytesSent and bytesReceived are the sizes of the JSON-RPC messages sent and received
+	// through this connection. They're first in the struct so that they're 64-bit aligned
+	// for atomic access on 32-bit platforms
+	bytesSent     uint64
+	bytesReceived uint64
+
+	address net.Addr
+	rpcRole server.RPCRole
+	router  *router.Router
+
+	// notify sends notifications to the client. It's nil for connections of
+	// single HTTP calls, which can't receive notifications
+	notify func(notification *notification) error
+
+	pendingRequests     []*pendingRequest
+	pendingRequestsLock sync.Mutex
+	messageNumber       uint64
+
+	stopChan                chan struct{}
+	onDisconnectedHandler   server.OnDisconnectedHandler
+	onInvalidMessageHandler server.OnInvalidMessageHandler
+
+	isConnected uint32
+}
+
+// pendingRequest is a request that was passed on to the router and wasn't responded to yet
+type pendingRequest struct {
+	id      json.RawMessage
+	respond func(response *response)
+}
+
+func newConnection(address net.Addr, rpcRole server.RPCRole,
+	notify func(notification *notification) error) *jsonRPCConnection {
+
+	return &jsonRPCConnection{
+		address:     address,
+		rpcRole:     rpcRole,
+		notify:      notify,
+		stopChan:    make(chan struct{}),
+		isConnected: 1,
+	}
+}
+
+func (c *jsonRPCConnection) Start(router *router.Router) {
+	if c.onDisconnectedHandler == nil {
+		panic(errors.New("onDisconnectedHandler is nil"))
+	}
+
+	c.router = router
+
+	spawn("jsonRPCConnection.Start-sendLoop", func() {
+		err := c.sendLoop()
+		if err != nil {
+			log.Errorf("Error from the JSON-RPC send loop of %s: %s", c, err)
+		}
+		c.Disconnect()
+	})
+}
+
+func (c *jsonRPCConnection) String() string {
+	return c.address.String()
+}
+
+func (c *jsonRPCConnection) IsConnected() bool {
+	return atomic.LoadUint32(&c.isConnected) != 0
+}
+
+func (c *jsonRPCConnection) IsOutbound() bool {
+	return false
+}
+
+func (c *jsonRPCConnection) SetOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
+func (c *jsonRPCConnection) SetOnInvalidMessageHandler(onInvalidMessageHandler server.OnInvalidMessageHandler) {
+	c.onInvalidMessageHandler = onInvalidMessageHandler
+}
+
+// Disconnect disconnects the connection
+// Calling this function a second time doesn't do anything
+//
+// This is part of the Connection interface
+func (c *jsonRPCConnection) Disconnect() {
+	if !atomic.CompareAndSwapUint32(&c.isConnected, 1, 0) {
+		return
+	}
+
+	close(c.stopChan)
+
+	log.Debugf("Disconnecting from %s", c)
+	if c.onDisconnectedHandler != nil {
+		c.onDisconnectedHandler()
+	}
+}
+
+func (c *jsonRPCConnection) Address() net.Addr {
+	return c.address
+}
+
+// RPCRole returns the role the client of this connection authenticated with
+func (c *jsonRPCConnection) RPCRole() server.RPCRole {
+	return c.rpcRole
+}
+
+// BytesSent returns the total size of the JSON-RPC messages sent through this connection
+func (c *jsonRPCConnection) BytesSent() uint64 {
+	return atomic.LoadUint64(&c.bytesSent)
+}
+
+// BytesReceived returns the total size of the JSON-RPC messages received through this connection
+func (c *jsonRPCConnection) BytesReceived() uint64 {
+	return atomic.LoadUint64(&c.bytesReceived)
+}
+
+func (c *jsonRPCConnection) addBytesSent(count int) {
+	atomic.AddUint64(&c.bytesSent, uint64(count))
+}
+
+func (c *jsonRPCConnection) addBytesReceived(count int) {
+	atomic.AddUint64(&c.bytesReceived, uint64(count))
+}
+
+// call passes the RPC message that the given request calls on to the router. respond is
+// called with the response to the request once it's ready, or right away if the request
+// is malformed. respond isn't called for JSON-RPC notifications
+func (c *jsonRPCConnection) call(request *request, respond func(response *response)) error {
+	if request.isNotification() {
+		respond = func(*response) {}
+	}
+
+	message, responseError := requestToAppMessage(request)
+	if responseError != nil {
+		respond(newErrorResponse(request.ID, responseError.Code, responseError.Message))
+		return nil
+	}
+
+	c.pendingRequestsLock.Lock()
+	c.pendingRequests = append(c.pendingRequests, &pendingRequest{id: request.ID, respond: respond})
+	c.pendingRequestsLock.Unlock()
+
+	c.messageNumber++
+	message.SetMessageNumber(c.messageNumber)
+	message.SetReceivedAt(time.Now())
+
+	log.Debugf("incoming '%s' message from %s (message number %d)", message.Command(), c,
+		message.MessageNumber())
+
+	err := c.router.EnqueueIncomingMessage(message)
+	if err != nil {
+		if errors.Is(err, router.ErrRouteClosed) {
+			return nil
+		}
+		if c.onInvalidMessageHandler != nil {
+			c.onInvalidMessageHandler(err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *jsonRPCConnection) popPendingRequest() (*pendingRequest, bool) {
+	c.pendingRequestsLock.Lock()
+	defer c.pendingRequestsLock.Unlock()
+
+	if len(c.pendingRequests) == 0 {
+		return nil, false
+	}
+	pendingRequest := c.pendingRequests[0]
+	c.pendingRequests = c.pendingRequests[1:]
+	return pendingRequest, true
+}
+
+func (c *jsonRPCConnection) sendLoop() error {
+	outgoingRoute := c.router.OutgoingRoute()
+	for c.IsConnected() {
+		message, err := outgoingRoute.Dequeue()
+		if err != nil {
+			if errors.Is(err, router.ErrRouteClosed) {
+				return nil
+			}
+			return err
+		}
+
+		log.Debugf("outgoing '%s' message to %s", message.Command(), c)
+
+		outgoingMessage, err := appMessageToOutgoingMessage(message)
+		if err != nil {
+			return err
+		}
+
+		if !outgoingMessage.isResponse() {
+			if c.notify == nil {
+				continue
+			}
+			err := c.notify(outgoingMessage.toNotification())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		pendingRequest, ok := c.popPendingRequest()
+		if !ok {
+			return errors.Errorf("got a %s response without a pending request", message.Command())
+		}
+		response, err := outgoingMessage.toResponse(pendingRequest.id)
+		if err != nil {
+			return err
+		}
+		pendingRequest.respond(response)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package jsonrpcserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// jsonRPCVersion is the version of the JSON-RPC protocol the server speaks
+const jsonRPCVersion = "2.0"
+
+// Error codes defined by the JSON-RPC 2.0 specification
+const (
+	errorCodeParseError     = -32700
+	errorCodeInvalidRequest = -32600
+	errorCodeMethodNotFound = -32601
+	errorCodeInvalidParams  = -32602
+	errorCodeInternalError  = -32603
+)
+
+// errorCodeRPCError is the error code of responses whose
+// RPC message has its error field set
+const errorCodeRPCError = -32000
+
+const (
+	requestFieldSuffix      = "Request"
+	responseFieldSuffix     = "Response"
+	notificationFieldSuffix = "Notification"
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification returns whether the request is a JSON-RPC notification,
+// meaning that the client doesn't expect a response for it
+func (r *request) isNotification() bool {
+	return r.ID == nil
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *responseError  `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+var nullID = json.RawMessage("null")
+
+func newErrorResponse(id json.RawMessage, code int, message string) *response {
+	if id == nil {
+		id = nullID
+	}
+	return &response{
+		JSONRPC: jsonRPCVersion,
+		ID:      id,
+		Error:   &responseError{Code: code, Message: message},
+	}
+}
+
+var payloadOneof = (&protowire.KaspadMessage{}).ProtoReflect().Descriptor().Oneofs().ByName("payload")
+
+// methods maps JSON-RPC method names to the KaspadMessage fields of their requests.
+// The method name of a request is the JSON name of its field without the Request
+// suffix, so that for example getBlockDagInfoRequest is called with getBlockDagInfo
+var methods = buildMethods()
+
+func buildMethods() map[string]protoreflect.FieldDescriptor {
+	methods := make(map[string]protoreflect.FieldDescriptor)
+	fields := payloadOneof.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if strings.HasSuffix(field.JSONName(), requestFieldSuffix) {
+			methods[strings.TrimSuffix(field.JSONName(), requestFieldSuffix)] = field
+		}
+	}
+	return methods
+}
+
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// requestToAppMessage converts the given JSON-RPC request to the RPC message it calls.
+// The returned error is the JSON-RPC error to respond with if the conversion fails
+func requestToAppMessage(request *request) (appmessage.Message, *responseError) {
+	if request.JSONRPC != jsonRPCVersion || request.Method == "" {
+		return nil, &responseError{Code: errorCodeInvalidRequest, Message: "invalid JSON-RPC 2.0 request"}
+	}
+	field, ok := methods[request.Method]
+	if !ok {
+		return nil, &responseError{Code: errorCodeMethodNotFound, Message: "method " + request.Method + " not found"}
+	}
+
+	kaspadMessage := &protowire.KaspadMessage{}
+	reflectMessage := kaspadMessage.ProtoReflect()
+	payload := reflectMessage.NewField(field).Message()
+	params := bytes.TrimSpace(request.Params)
+	if len(params) > 0 && !bytes.Equal(params, nullID) {
+		if params[0] != '{' {
+			return nil, &responseError{Code: errorCodeInvalidParams, Message: "params must be an object"}
+		}
+		err := protojson.Unmarshal(params, payload.Interface())
+		if err != nil {
+			return nil, &responseError{Code: errorCodeInvalidParams, Message: err.Error()}
+		}
+	}
+	reflectMessage.Set(field, protoreflect.ValueOfMessage(payload))
+
+	message, err := kaspadMessage.ToAppMessage()
+	if err != nil {
+		return nil, &responseError{Code: errorCodeInvalidParams, Message: err.Error()}
+	}
+	// The payload of KaspadMessage includes P2P messages as well,
+	// which mustn't be routed to the RPC handlers
+	if _, ok := appmessage.RPCMessageCommandToString[message.Command()]; !ok {
+		return nil, &responseError{Code: errorCodeMethodNotFound, Message: "method " + request.Method + " not found"}
+	}
+	return message, nil
+}
+
+// outgoingMessage is an RPC message that is sent to a JSON-RPC client
+type outgoingMessage struct {
+	// name is the JSON name of the message's field in KaspadMessage
+	name string
+
+	// payload is the message in the JSON encoding of protobuf
+	payload json.RawMessage
+}
+
+func (m *outgoingMessage) isResponse() bool {
+	return strings.HasSuffix(m.name, responseFieldSuffix)
+}
+
+func appMessageToOutgoingMessage(message appmessage.Message) (*outgoingMessage, error) {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	reflectMessage := kaspadMessage.ProtoReflect()
+	field := reflectMessage.WhichOneof(payloadOneof)
+	if field == nil {
+		return nil, errors.Errorf("message %s has no payload", message.Command())
+	}
+	payload, err := marshalOptions.Marshal(reflectMessage.Get(field).Message().Interface())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &outgoingMessage{name: field.JSONName(), payload: payload}, nil
+}
+
+// toResponse converts a response RPC message to the JSON-RPC response to the request with the given ID.
+// If the error field of the message is set, it's converted to a JSON-RPC error
+func (m *outgoingMessage) toResponse(id json.RawMessage) (*response, error) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(m.payload, &fields)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if rawRPCError, ok := fields["error"]; ok {
+		delete(fields, "error")
+		if !bytes.Equal(rawRPCError, nullID) {
+			var rpcError struct {
+				Message string `json:"message"`
+			}
+			err := json.Unmarshal(rawRPCError, &rpcError)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			return newErrorResponse(id, errorCodeRPCError, rpcError.Message), nil
+		}
+	}
+	result, err := json.Marshal(fields)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &response{JSONRPC: jsonRPCVersion, ID: id, Result: result}, nil
+}
+
+func (m *outgoingMessage) toNotification() *notification {
+	return &notification{JSONRPC: jsonRPCVersion, Method: m.name, Params: m.payload}
+}
+
+// parseRequests parses the body of a JSON-RPC call, which is either
+// a single request or a batch of requests
+func parseRequests(body []byte) (requests []*request, isBatch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		err := json.Unmarshal(body, &requests)
+		if err != nil {
+			return nil, true, errors.WithStack(err)
+		}
+		if len(requests) == 0 {
+			return nil, true, errors.New("empty batch")
+		}
+		for _, batchRequest := range requests {
+			if batchRequest == nil {
+				return nil, true, errors.New("null request in batch")
+			}
+		}
+		return requests, true, nil
+	}
+
+	singleRequest := &request{}
+	err = json.Unmarshal(body, singleRequest)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return []*request{singleRequest}, false, nil
+}
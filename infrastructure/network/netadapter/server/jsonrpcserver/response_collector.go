@@ -0,0 +1,73 @@
+package jsonrpcserver
+
+import "sync"
+
+// responseCollector collects the responses to the requests of a single JSON-RPC call,
+// which may be a batch, so that they're all sent back together
+type responseCollector struct {
+	requests []*request
+
+	lock               sync.Mutex
+	collectedResponses []*response
+	remainingCount     int
+	done               chan struct{}
+}
+
+func newResponseCollector(requests []*request) *responseCollector {
+	collector := &responseCollector{
+		requests:           requests,
+		collectedResponses: make([]*response, len(requests)),
+		done:               make(chan struct{}),
+	}
+	for _, request := range requests {
+		if !request.isNotification() {
+			collector.remainingCount++
+		}
+	}
+	if collector.remainingCount == 0 {
+		close(collector.done)
+	}
+	return collector
+}
+
+// responder returns the function that collects the response to the request with the given index
+func (c *responseCollector) responder(index int) func(response *response) {
+	if c.requests[index].isNotification() {
+		return func(*response) {}
+	}
+	return func(response *response) {
+		c.lock.Lock()
+		defer c.lock.Unlock()
+
+		if c.collectedResponses[index] != nil {
+			return
+		}
+		c.collectedResponses[index] = response
+		c.remainingCount--
+		if c.remainingCount == 0 {
+			close(c.done)
+		}
+	}
+}
+
+// responses returns the collected responses, in the order of their requests. If newMissingResponse
+// isn't nil, it's used to create the responses to requests that weren't responded to
+func (c *responseCollector) responses(newMissingResponse func(request *request) *response) []*response {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	responses := make([]*response, 0, len(c.requests))
+	for i, request := range c.requests {
+		if request.isNotification() {
+			continue
+		}
+		response := c.collectedResponses[i]
+		if response == nil && newMissingResponse != nil {
+			response = newMissingResponse(request)
+		}
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	return responses
+}
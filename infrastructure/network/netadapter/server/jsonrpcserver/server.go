@@ -0,0 +1,464 @@
+package jsonrpcserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/util/panics"
+	"github.com/pkg/errors"
+)
+
+// MaxMessageSize is the max size of the JSON-RPC messages clients may send
+const MaxMessageSize = 32 * 1024 * 1024 // 32 MB
+
+const (
+	// websocketPingInterval is how often WebSocket clients are pinged to keep their connections alive
+	websocketPingInterval = 30 * time.Second
+
+	// websocketReadTimeout is how long a WebSocket client may be silent before it's disconnected.
+	// Clients answer pings, so this only happens to clients that stopped responding
+	websocketReadTimeout = 3 * websocketPingInterval
+)
+
+// tokenQueryParameter is the URL query parameter clients may send their token in. Tokens in
+// URLs tend to end up in logs and browser histories, so clients should rather send them in the
+// Authorization header, or over WebSocket in the Sec-WebSocket-Protocol header
+const tokenQueryParameter = "token"
+
+const bearerAuthorizationPrefix = "Bearer "
+
+// Config is the configuration of a JSON-RPC server
+type Config struct {
+	// ListeningAddresses are the addresses the server listens on
+	ListeningAddresses []string
+
+	// TLSConfig, if not nil, is used to serve over TLS
+	TLSConfig *tls.Config
+
+	// Credentials are the credentials clients must authenticate with,
+	// in the same way as clients of the gRPC RPC server
+	Credentials *grpcserver.RPCCredentials
+
+	// AllowedOrigins are the origins of the web pages that may call the server.
+	// An origin of * allows any web page. Requests that aren't sent by browsers
+	// don't have an origin, and are always allowed
+	AllowedOrigins []string
+
+	// MaxWebsockets is the max amount of concurrent WebSocket connections. 0 means no limit
+	MaxWebsockets int
+}
+
+type jsonRPCServer struct {
+	config             *Config
+	httpServer         *http.Server
+	onConnectedHandler server.OnConnectedHandler
+
+	websocketCount           int
+	websocketConnections     map[*jsonRPCConnection]struct{}
+	websocketConnectionsLock sync.Mutex
+}
+
+// NewServer creates a server that serves the RPC commands as JSON-RPC 2.0 over HTTP
+// and WebSocket. Calls are made by POSTing JSON-RPC requests, and clients that wish
+// to get notifications open a WebSocket connection, over which they may call methods
+// as well. The method names are the names of the RPC request messages without the
+// Request suffix, and the params are the request messages in their JSON encoding
+func NewServer(config *Config) server.Server {
+	s := &jsonRPCServer{
+		config:               config,
+		websocketConnections: make(map[*jsonRPCConnection]struct{}),
+	}
+	s.httpServer = &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+func (s *jsonRPCServer) Start() error {
+	if s.onConnectedHandler == nil {
+		return errors.New("onConnectedHandler is nil")
+	}
+
+	for _, listenAddress := range s.config.ListeningAddresses {
+		err := s.listenOn(listenAddress)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonRPCServer) listenOn(listenAddress string) error {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "JSON-RPC error listening on %s", listenAddress)
+	}
+	if s.config.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.config.TLSConfig)
+	}
+
+	spawn("jsonRPCServer.listenOn-Serve", func() {
+		err := s.httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panics.Exit(log, fmt.Sprintf("error serving JSON-RPC on %s: %+v", listenAddress, err))
+		}
+	})
+
+	log.Infof("JSON-RPC Server listening on %s", listener.Addr())
+	return nil
+}
+
+func (s *jsonRPCServer) Stop() error {
+	const stopTimeout = 2 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	err := s.httpServer.Shutdown(ctx)
+	if err != nil {
+		log.Warnf("Could not gracefully stop the JSON-RPC server: %s", err)
+		_ = s.httpServer.Close()
+	}
+
+	// WebSocket connections are hijacked from the HTTP server, so they must be closed separately
+	s.websocketConnectionsLock.Lock()
+	websocketConnections := make([]*jsonRPCConnection, 0, len(s.websocketConnections))
+	for connection := range s.websocketConnections {
+		websocketConnections = append(websocketConnections, connection)
+	}
+	s.websocketConnectionsLock.Unlock()
+	for _, connection := range websocketConnections {
+		connection.Disconnect()
+	}
+	return nil
+}
+
+// SetOnConnectedHandler sets the client connected handler
+// function for the server
+func (s *jsonRPCServer) SetOnConnectedHandler(onConnectedHandler server.OnConnectedHandler) {
+	s.onConnectedHandler = onConnectedHandler
+}
+
+func (s *jsonRPCServer) ServeHTTP(writer http.ResponseWriter, httpRequest *http.Request) {
+	defer panics.HandlePanic(log, "jsonRPCServer.ServeHTTP", nil)
+
+	if !s.handleCORS(writer, httpRequest) {
+		return
+	}
+
+	rpcRole, err := s.authenticate(httpRequest)
+	if err != nil {
+		log.Warnf("JSON-RPC authentication failed for %s: %s", httpRequest.RemoteAddr, err)
+		writer.Header().Set("WWW-Authenticate", `Basic realm="kaspad"`)
+		http.Error(writer, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	address, err := net.ResolveTCPAddr("tcp", httpRequest.RemoteAddr)
+	if err != nil {
+		http.Error(writer, "Unsupported remote address", http.StatusBadRequest)
+		return
+	}
+
+	if isWebsocketUpgrade(httpRequest) {
+		s.serveWebsocket(writer, httpRequest, address, rpcRole)
+		return
+	}
+	if httpRequest.Method != http.MethodPost {
+		writer.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(writer, "JSON-RPC calls must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.serveHTTPCall(writer, httpRequest, address, rpcRole)
+}
+
+// authenticate returns the role of the client that sent the given request. The client
+// authenticates with the Authorization header, which browsers can't set when opening
+// WebSocket connections. WebSocket clients may therefore send their token in the
+// Sec-WebSocket-Protocol header instead, and any client may send it in the URL query
+func (s *jsonRPCServer) authenticate(httpRequest *http.Request) (server.RPCRole, error) {
+	authorization := httpRequest.Header.Get("Authorization")
+	if authorization == "" && isWebsocketUpgrade(httpRequest) {
+		authorization = websocketTokenAuthorization(httpRequest)
+	}
+	if authorization == "" && httpRequest.URL.Query().Has(tokenQueryParameter) {
+		authorization = bearerAuthorizationPrefix + httpRequest.URL.Query().Get(tokenQueryParameter)
+	}
+	return s.config.Credentials.AuthenticateAuthorization(authorization)
+}
+
+// handleCORS sets the CORS headers of responses to web pages with an allowed origin,
+// and answers CORS preflight requests. It returns whether the request should be
+// handled further
+func (s *jsonRPCServer) handleCORS(writer http.ResponseWriter, httpRequest *http.Request) bool {
+	origin := httpRequest.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if !s.isOriginAllowed(origin) {
+		log.Debugf("Rejected a JSON-RPC request from %s with the disallowed origin %s", httpRequest.RemoteAddr, origin)
+		http.Error(writer, "Origin not allowed", http.StatusForbidden)
+		return false
+	}
+
+	writer.Header().Set("Access-Control-Allow-Origin", origin)
+	writer.Header().Set("Access-Control-Allow-Credentials", "true")
+	writer.Header().Add("Vary", "Origin")
+	if httpRequest.Method != http.MethodOptions {
+		return true
+	}
+	writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	writer.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+	writer.Header().Set("Access-Control-Max-Age", "600")
+	writer.WriteHeader(http.StatusNoContent)
+	return false
+}
+
+func (s *jsonRPCServer) isOriginAllowed(origin string) bool {
+	for _, allowedOrigin := range s.config.AllowedOrigins {
+		if allowedOrigin == "*" || strings.EqualFold(allowedOrigin, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNotificationMethod returns whether the given method manages notifications.
+// These are only meaningful over WebSocket, since notifications can't be sent
+// back over plain HTTP
+func isNotificationMethod(method string) bool {
+	return strings.HasPrefix(method, "notify") || strings.HasPrefix(method, "stopNotifying")
+}
+
+func (s *jsonRPCServer) serveHTTPCall(writer http.ResponseWriter, httpRequest *http.Request,
+	address *net.TCPAddr, rpcRole server.RPCRole) {
+
+	body, err := io.ReadAll(http.MaxBytesReader(writer, httpRequest.Body, MaxMessageSize))
+	if err != nil {
+		http.Error(writer, "Request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	requests, isBatch, err := parseRequests(body)
+	if err != nil {
+		writeJSON(writer, newErrorResponse(nil, errorCodeParseError, err.Error()))
+		return
+	}
+
+	connection := newConnection(address, rpcRole, nil)
+	connection.addBytesReceived(len(body))
+	err = s.onConnectedHandler(connection)
+	if err != nil {
+		log.Warnf("Error handling JSON-RPC call from %s: %s", address, err)
+		http.Error(writer, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	defer connection.Disconnect()
+
+	log.Debugf("JSON-RPC call from %s", address)
+
+	collector := newResponseCollector(requests)
+	for i, callRequest := range requests {
+		respond := collector.responder(i)
+		if isNotificationMethod(callRequest.Method) {
+			respond(newErrorResponse(callRequest.ID, errorCodeMethodNotFound,
+				"method "+callRequest.Method+" is only available over WebSocket"))
+			continue
+		}
+		err := connection.call(callRequest, respond)
+		if err != nil {
+			log.Warnf("Error handling JSON-RPC call from %s: %s", address, err)
+			http.Error(writer, "Internal error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	select {
+	case <-collector.done:
+	case <-connection.stopChan:
+	case <-httpRequest.Context().Done():
+		return
+	}
+
+	responses := collector.responses(func(callRequest *request) *response {
+		return newErrorResponse(callRequest.ID, errorCodeInternalError, "the request was not handled")
+	})
+	if len(responses) == 0 {
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+	var written int
+	if isBatch {
+		written = writeJSON(writer, responses)
+	} else {
+		written = writeJSON(writer, responses[0])
+	}
+	connection.addBytesSent(written)
+}
+
+func (s *jsonRPCServer) serveWebsocket(writer http.ResponseWriter, httpRequest *http.Request,
+	address *net.TCPAddr, rpcRole server.RPCRole) {
+
+	if !s.addWebsocketSlot() {
+		http.Error(writer, "Too many WebSocket connections", http.StatusServiceUnavailable)
+		return
+	}
+	websocket, err := upgradeToWebsocket(writer, httpRequest, MaxMessageSize)
+	if err != nil {
+		s.releaseWebsocketSlot(nil)
+		log.Debugf("Failed upgrading the JSON-RPC connection of %s to WebSocket: %s", address, err)
+		return
+	}
+	defer websocket.close()
+
+	var connection *jsonRPCConnection
+	writeMessage := func(message interface{}) error {
+		payload, err := json.Marshal(message)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		err = websocket.writeMessage(payload)
+		if err != nil {
+			return err
+		}
+		connection.addBytesSent(len(payload))
+		return nil
+	}
+	connection = newConnection(address, rpcRole, func(notification *notification) error {
+		return writeMessage(notification)
+	})
+	defer s.releaseWebsocketSlot(connection)
+
+	s.websocketConnectionsLock.Lock()
+	s.websocketConnections[connection] = struct{}{}
+	s.websocketConnectionsLock.Unlock()
+
+	err = s.onConnectedHandler(connection)
+	if err != nil {
+		log.Warnf("Error handling JSON-RPC WebSocket connection from %s: %s", address, err)
+		return
+	}
+	defer connection.Disconnect()
+
+	log.Infof("JSON-RPC Incoming WebSocket connection from %s", address)
+
+	spawn("jsonRPCServer.serveWebsocket-keepAlive", func() {
+		ticker := time.NewTicker(websocketPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-connection.stopChan:
+				// Unblock the read loop in case the connection was disconnected by the node
+				websocket.closeNormally()
+				_ = websocket.close()
+				return
+			case <-ticker.C:
+				err := websocket.ping()
+				if err != nil {
+					connection.Disconnect()
+					return
+				}
+			}
+		}
+	})
+
+	respond := func(response interface{}) {
+		err := writeMessage(response)
+		if err != nil {
+			log.Debugf("Error writing to the JSON-RPC WebSocket of %s: %s", address, err)
+			connection.Disconnect()
+		}
+	}
+	for connection.IsConnected() {
+		message, err := websocket.readMessage(websocketReadTimeout)
+		if err != nil {
+			if !errors.Is(err, errWebsocketClosed) && connection.IsConnected() {
+				log.Debugf("Error reading from the JSON-RPC WebSocket of %s: %s", address, err)
+			}
+			return
+		}
+		connection.addBytesReceived(len(message))
+
+		requests, isBatch, err := parseRequests(message)
+		if err != nil {
+			respond(newErrorResponse(nil, errorCodeParseError, err.Error()))
+			continue
+		}
+
+		collector := newResponseCollector(requests)
+		for i, callRequest := range requests {
+			responder := collector.responder(i)
+			if !isBatch {
+				responder = func(response *response) { respond(response) }
+			}
+			err := connection.call(callRequest, responder)
+			if err != nil {
+				log.Warnf("Error handling JSON-RPC call from %s: %s", address, err)
+				return
+			}
+		}
+		if isBatch {
+			spawn("jsonRPCServer.serveWebsocket-respondToBatch", func() {
+				select {
+				case <-collector.done:
+				case <-connection.stopChan:
+					return
+				}
+				responses := collector.responses(nil)
+				if len(responses) > 0 {
+					respond(responses)
+				}
+			})
+		}
+	}
+}
+
+// addWebsocketSlot reserves a slot for a new WebSocket connection. It
+// returns false if the max amount of WebSocket connections is reached
+func (s *jsonRPCServer) addWebsocketSlot() bool {
+	s.websocketConnectionsLock.Lock()
+	defer s.websocketConnectionsLock.Unlock()
+
+	if s.config.MaxWebsockets > 0 && s.websocketCount >= s.config.MaxWebsockets {
+		return false
+	}
+	s.websocketCount++
+	return true
+}
+
+// releaseWebsocketSlot releases the slot of a WebSocket connection. connection
+// is nil if the connection failed before it was created
+func (s *jsonRPCServer) releaseWebsocketSlot(connection *jsonRPCConnection) {
+	s.websocketConnectionsLock.Lock()
+	defer s.websocketConnectionsLock.Unlock()
+
+	s.websocketCount--
+	if connection != nil {
+		delete(s.websocketConnections, connection)
+	}
+}
+
+func writeJSON(writer http.ResponseWriter, value interface{}) int {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		log.Errorf("Error encoding a JSON-RPC response: %s", err)
+		http.Error(writer, "Internal error", http.StatusInternalServerError)
+		return 0
+	}
+	writer.Header().Set("Content-Type", "application/json")
+	written, err := io.Copy(writer, bytes.NewReader(payload))
+	if err != nil {
+		log.Debugf("Error writing a JSON-RPC response: %s", err)
+	}
+	return int(written)
+}
@@ -0,0 +1,376 @@
+package jsonrpcserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kaspanet/kaspad/app/appmessage"
+	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+)
+
+// fakeRPCHandler answers a few RPC commands on the given connection,
+// the same way the RPC manager of the node does
+func fakeRPCHandler(t *testing.T) server.OnConnectedHandler {
+	return func(connection server.Connection) error {
+		router := routerpkg.NewRouter("test")
+		incomingRoute, err := router.AddIncomingRoute("test", []appmessage.MessageCommand{
+			appmessage.CmdGetCurrentNetworkRequestMessage,
+			appmessage.CmdNotifyVirtualDaaScoreChangedRequestMessage,
+			appmessage.CmdBanRequestMessage,
+		})
+		if err != nil {
+			t.Fatalf("AddIncomingRoute: %s", err)
+		}
+		connection.SetOnDisconnectedHandler(router.Close)
+
+		go func() {
+			outgoingRoute := router.OutgoingRoute()
+			for {
+				request, err := incomingRoute.Dequeue()
+				if err != nil {
+					return
+				}
+				switch request := request.(type) {
+				case *appmessage.GetCurrentNetworkRequestMessage:
+					_ = outgoingRoute.Enqueue(appmessage.NewGetCurrentNetworkResponseMessage("kaspa-test-" + connection.RPCRole().String()))
+				case *appmessage.NotifyVirtualDaaScoreChangedRequestMessage:
+					_ = outgoingRoute.Enqueue(appmessage.NewNotifyVirtualDaaScoreChangedResponseMessage())
+					_ = outgoingRoute.Enqueue(appmessage.NewVirtualDaaScoreChangedNotificationMessage(1234))
+				case *appmessage.BanRequestMessage:
+					_ = outgoingRoute.Enqueue(&appmessage.BanResponseMessage{
+						Error: appmessage.RPCErrorf("could not ban %s", request.IP),
+					})
+				}
+			}
+		}()
+
+		connection.Start(router)
+		return nil
+	}
+}
+
+func newTestServer(t *testing.T, config *Config) *httptest.Server {
+	server := NewServer(config)
+	server.SetOnConnectedHandler(fakeRPCHandler(t))
+	return httptest.NewServer(server.(*jsonRPCServer))
+}
+
+func post(t *testing.T, url string, body string, header http.Header) (int, string) {
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %s", err)
+	}
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Do: %s", err)
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %s", err)
+	}
+	return response.StatusCode, strings.TrimSpace(string(responseBody))
+}
+
+func TestHTTPCalls(t *testing.T) {
+	testServer := newTestServer(t, &Config{})
+	defer testServer.Close()
+
+	tests := []struct {
+		name             string
+		body             string
+		expectedStatus   int
+		expectedResponse string
+	}{
+		{
+			name:             "call",
+			body:             `{"jsonrpc":"2.0","id":1,"method":"getCurrentNetwork"}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","id":1,"result":{"currentNetwork":"kaspa-test-admin"}}`,
+		},
+		{
+			name:             "RPC error",
+			body:             `{"jsonrpc":"2.0","id":"a","method":"ban","params":{"ip":"1.2.3.4"}}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","id":"a","error":{"code":-32000,"message":"could not ban 1.2.3.4"}}`,
+		},
+		{
+			name: "batch",
+			body: `[{"jsonrpc":"2.0","id":1,"method":"getCurrentNetwork"},` +
+				`{"jsonrpc":"2.0","method":"getCurrentNetwork"},` +
+				`{"jsonrpc":"2.0","id":2,"method":"noSuchMethod"},` +
+				`{"jsonrpc":"2.0","id":3,"method":"getCurrentNetwork","params":[]}]`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: `[{"jsonrpc":"2.0","id":1,"result":{"currentNetwork":"kaspa-test-admin"}},` +
+				`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"method noSuchMethod not found"}},` +
+				`{"jsonrpc":"2.0","id":3,"error":{"code":-32602,"message":"params must be an object"}}]`,
+		},
+		{
+			name:             "notification",
+			body:             `{"jsonrpc":"2.0","method":"getCurrentNetwork"}`,
+			expectedStatus:   http.StatusNoContent,
+			expectedResponse: ``,
+		},
+		{
+			name:             "notification method",
+			body:             `{"jsonrpc":"2.0","id":1,"method":"notifyVirtualDaaScoreChanged"}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method notifyVirtualDaaScoreChanged is only available over WebSocket"}}`,
+		},
+		{
+			name:             "P2P message",
+			body:             `{"jsonrpc":"2.0","id":1,"method":"ibdBlockLocator","params":{}}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method ibdBlockLocator not found"}}`,
+		},
+		{
+			name:             "invalid version",
+			body:             `{"jsonrpc":"1.0","id":1,"method":"getCurrentNetwork"}`,
+			expectedStatus:   http.StatusOK,
+			expectedResponse: `{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"invalid JSON-RPC 2.0 request"}}`,
+		},
+	}
+	for _, test := range tests {
+		status, response := post(t, testServer.URL, test.body, nil)
+		if status != test.expectedStatus {
+			t.Errorf("%s: expected status %d but got %d", test.name, test.expectedStatus, status)
+		}
+		if response != test.expectedResponse {
+			t.Errorf("%s: unexpected response.\nWant: %s\nGot:  %s", test.name, test.expectedResponse, response)
+		}
+	}
+
+	status, response := post(t, testServer.URL, `{"jsonrpc":`, nil)
+	if status != http.StatusOK || !strings.Contains(response, `"code":-32700`) {
+		t.Errorf("expected a parse error but got %d %s", status, response)
+	}
+}
+
+func TestHTTPAuthenticationAndCORS(t *testing.T) {
+	testServer := newTestServer(t, &Config{
+		Credentials: &grpcserver.RPCCredentials{
+			AdminToken:    "admin-token",
+			ReadOnlyToken: "read-only-token",
+		},
+		AllowedOrigins: []string{"https://explorer.example.com"},
+	})
+	defer testServer.Close()
+
+	const body = `{"jsonrpc":"2.0","id":1,"method":"getCurrentNetwork"}`
+
+	status, _ := post(t, testServer.URL, body, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected an unauthenticated call to fail, but got status %d", status)
+	}
+	status, _ = post(t, testServer.URL, body, http.Header{"Authorization": {"Bearer wrong-token"}})
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected a call with a wrong token to fail, but got status %d", status)
+	}
+	status, response := post(t, testServer.URL, body, http.Header{"Authorization": {"Bearer read-only-token"}})
+	if status != http.StatusOK || !strings.Contains(response, "kaspa-test-read-only") {
+		t.Fatalf("expected a read-only call to succeed, but got %d %s", status, response)
+	}
+
+	status, _ = post(t, testServer.URL, body, http.Header{
+		"Authorization": {"Bearer admin-token"},
+		"Origin":        {"https://evil.example.com"},
+	})
+	if status != http.StatusForbidden {
+		t.Fatalf("expected a call from a disallowed origin to fail, but got status %d", status)
+	}
+
+	preflight, err := http.NewRequest(http.MethodOptions, testServer.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest: %s", err)
+	}
+	preflight.Header.Set("Origin", "https://explorer.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", "POST")
+	preflightResponse, err := http.DefaultClient.Do(preflight)
+	if err != nil {
+		t.Fatalf("Do: %s", err)
+	}
+	preflightResponse.Body.Close()
+	if preflightResponse.StatusCode != http.StatusNoContent ||
+		preflightResponse.Header.Get("Access-Control-Allow-Origin") != "https://explorer.example.com" ||
+		!strings.Contains(preflightResponse.Header.Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Fatalf("unexpected preflight response %d %v", preflightResponse.StatusCode, preflightResponse.Header)
+	}
+}
+
+// testWebsocketClient is a WebSocket client of the JSON-RPC server
+type testWebsocketClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+}
+
+func dialWebsocket(t *testing.T, url string, header http.Header, subprotocols ...string) (*testWebsocketClient, int) {
+	dialer := &websocket.Dialer{Subprotocols: subprotocols}
+	conn, response, err := dialer.Dial("ws://"+strings.TrimPrefix(url, "http://"), header)
+	if err != nil {
+		if response == nil {
+			t.Fatalf("Dial: %s", err)
+		}
+		return nil, response.StatusCode
+	}
+	return &testWebsocketClient{t: t, conn: conn}, response.StatusCode
+}
+
+func (c *testWebsocketClient) write(message string) {
+	err := c.conn.WriteMessage(websocket.TextMessage, []byte(message))
+	if err != nil {
+		c.t.Fatalf("WriteMessage: %s", err)
+	}
+}
+
+func (c *testWebsocketClient) read() string {
+	err := c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err != nil {
+		c.t.Fatalf("SetReadDeadline: %s", err)
+	}
+	messageType, message, err := c.conn.ReadMessage()
+	if err != nil {
+		c.t.Fatalf("ReadMessage: %s", err)
+	}
+	if messageType != websocket.TextMessage {
+		c.t.Fatalf("expected a text message but got type %d", messageType)
+	}
+	return string(message)
+}
+
+func TestWebsocket(t *testing.T) {
+	testServer := newTestServer(t, &Config{MaxWebsockets: 1})
+	defer testServer.Close()
+
+	client, status := dialWebsocket(t, testServer.URL, nil)
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("unexpected status %d", status)
+	}
+	defer client.conn.Close()
+
+	// Only a single WebSocket connection is allowed
+	_, status = dialWebsocket(t, testServer.URL, nil)
+	if status != http.StatusServiceUnavailable {
+		t.Fatalf("expected the second WebSocket connection to be rejected, but got status %d", status)
+	}
+
+	client.write(`{"jsonrpc":"2.0","id":1,"method":"notifyVirtualDaaScoreChanged"}`)
+	expectedResponse := `{"jsonrpc":"2.0","id":1,"result":{}}`
+	if response := client.read(); response != expectedResponse {
+		t.Fatalf("unexpected response.\nWant: %s\nGot:  %s", expectedResponse, response)
+	}
+	var notification struct {
+		JSONRPC string `json:"jsonrpc"`
+		Method  string `json:"method"`
+		Params  struct {
+			VirtualDaaScore string `json:"virtualDaaScore"`
+		} `json:"params"`
+	}
+	err := json.Unmarshal([]byte(client.read()), &notification)
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if notification.JSONRPC != "2.0" || notification.Method != "virtualDaaScoreChangedNotification" ||
+		notification.Params.VirtualDaaScore != "1234" {
+		t.Fatalf("unexpected notification %+v", notification)
+	}
+
+	client.write(`[{"jsonrpc":"2.0","id":2,"method":"getCurrentNetwork"},{"jsonrpc":"2.0","id":3,"method":"getCurrentNetwork"}]`)
+	expectedResponse = `[{"jsonrpc":"2.0","id":2,"result":{"currentNetwork":"kaspa-test-admin"}},` +
+		`{"jsonrpc":"2.0","id":3,"result":{"currentNetwork":"kaspa-test-admin"}}]`
+	if response := client.read(); response != expectedResponse {
+		t.Fatalf("unexpected response.\nWant: %s\nGot:  %s", expectedResponse, response)
+	}
+
+	// Closing the connection frees its slot
+	client.conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		secondClient, status := dialWebsocket(t, testServer.URL, nil)
+		if status == http.StatusSwitchingProtocols {
+			secondClient.conn.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the WebSocket slot was not released")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebsocketAuthentication(t *testing.T) {
+	testServer := newTestServer(t, &Config{
+		Credentials: &grpcserver.RPCCredentials{
+			AdminToken:    "admin-token",
+			ReadOnlyToken: "read-only-token",
+		},
+	})
+	defer testServer.Close()
+
+	_, status := dialWebsocket(t, testServer.URL, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected an unauthenticated WebSocket connection to fail, but got status %d", status)
+	}
+
+	encodeToken := func(token string) string {
+		return websocketTokenSubprotocolPrefix + base64.RawURLEncoding.EncodeToString([]byte(token))
+	}
+	_, status = dialWebsocket(t, testServer.URL, nil, websocketSubprotocol, encodeToken("wrong-token"))
+	if status != http.StatusUnauthorized {
+		t.Fatalf("expected a WebSocket connection with a wrong token to fail, but got status %d", status)
+	}
+
+	tests := []struct {
+		name             string
+		url              string
+		header           http.Header
+		subprotocols     []string
+		expectedNetwork  string
+		expectedProtocol string
+	}{
+		{
+			name:            "Authorization header",
+			url:             testServer.URL,
+			header:          http.Header{"Authorization": {"Bearer read-only-token"}},
+			expectedNetwork: "kaspa-test-read-only",
+		},
+		{
+			name:             "Sec-WebSocket-Protocol header",
+			url:              testServer.URL,
+			subprotocols:     []string{websocketSubprotocol, encodeToken("admin-token")},
+			expectedNetwork:  "kaspa-test-admin",
+			expectedProtocol: websocketSubprotocol,
+		},
+		{
+			name:            "query parameter",
+			url:             testServer.URL + "/?" + tokenQueryParameter + "=read-only-token",
+			expectedNetwork: "kaspa-test-read-only",
+		},
+	}
+	for _, test := range tests {
+		client, status := dialWebsocket(t, test.url, test.header, test.subprotocols...)
+		if status != http.StatusSwitchingProtocols {
+			t.Fatalf("%s: unexpected status %d", test.name, status)
+		}
+		if client.conn.Subprotocol() != test.expectedProtocol {
+			t.Fatalf("%s: unexpected subprotocol %s", test.name, client.conn.Subprotocol())
+		}
+		client.write(`{"jsonrpc":"2.0","id":1,"method":"getCurrentNetwork"}`)
+		response := client.read()
+		if !strings.Contains(response, test.expectedNetwork) {
+			t.Fatalf("%s: unexpected response %s", test.name, response)
+		}
+		client.conn.Close()
+	}
+}
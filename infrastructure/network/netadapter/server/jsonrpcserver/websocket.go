@@ -0,0 +1,122 @@
+package jsonrpcserver
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// websocketSubprotocol is the subprotocol the server selects for WebSocket connections.
+// Clients that send their token in websocketTokenSubprotocolPrefix must offer it as well,
+// since browsers fail connections in which the server doesn't select any of the offered
+// subprotocols
+const websocketSubprotocol = "kaspad-jsonrpc"
+
+// websocketTokenSubprotocolPrefix prefixes the base64url encoding, without padding, of the
+// token of clients that authenticate through the Sec-WebSocket-Protocol header. This is the
+// only header browsers let web pages set when opening WebSocket connections
+const websocketTokenSubprotocolPrefix = "kaspad-token."
+
+// websocketWriteTimeout bounds the time it may take to write a message to a client
+const websocketWriteTimeout = 10 * time.Second
+
+var errWebsocketClosed = errors.New("websocket closed")
+
+var websocketUpgrader = websocket.Upgrader{
+	Subprotocols: []string{websocketSubprotocol},
+	// The origin was already checked by handleCORS
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// websocketConn is a WebSocket connection of a client
+type websocketConn struct {
+	conn *websocket.Conn
+
+	// writeLock serializes writes of data messages, since gorilla/websocket
+	// supports only one concurrent writer. Control messages don't need it
+	writeLock sync.Mutex
+}
+
+// isWebsocketUpgrade returns whether the given request asks to upgrade the connection to a WebSocket
+func isWebsocketUpgrade(request *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(request)
+}
+
+// websocketTokenAuthorization returns the bearer authorization of the token the client
+// of the given WebSocket upgrade request sent in its Sec-WebSocket-Protocol header, or
+// an empty string if it didn't send one
+func websocketTokenAuthorization(request *http.Request) string {
+	for _, subprotocol := range websocket.Subprotocols(request) {
+		if !strings.HasPrefix(subprotocol, websocketTokenSubprotocolPrefix) {
+			continue
+		}
+		token, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(subprotocol, websocketTokenSubprotocolPrefix))
+		if err != nil {
+			return ""
+		}
+		return bearerAuthorizationPrefix + string(token)
+	}
+	return ""
+}
+
+// upgradeToWebsocket completes the WebSocket opening handshake of the given request
+// and takes over its connection. If the handshake fails, an error response is
+// written and an error is returned
+func upgradeToWebsocket(writer http.ResponseWriter, request *http.Request, maxMessageSize int) (*websocketConn, error) {
+	conn, err := websocketUpgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	conn.SetReadLimit(int64(maxMessageSize))
+	return &websocketConn{conn: conn}, nil
+}
+
+// readMessage reads the next data message sent by the client. Pings are answered
+// while reading, and a close message ends the connection, in which case
+// errWebsocketClosed is returned
+func (c *websocketConn) readMessage(readTimeout time.Duration) ([]byte, error) {
+	err := c.conn.SetReadDeadline(time.Now().Add(readTimeout))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_, message, err := c.conn.ReadMessage()
+	if err != nil {
+		var closeError *websocket.CloseError
+		if errors.As(err, &closeError) {
+			return nil, errWebsocketClosed
+		}
+		return nil, errors.WithStack(err)
+	}
+	return message, nil
+}
+
+// writeMessage sends the given payload to the client as a text message
+func (c *websocketConn) writeMessage(payload []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	err := c.conn.SetWriteDeadline(time.Now().Add(websocketWriteTimeout))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(c.conn.WriteMessage(websocket.TextMessage, payload))
+}
+
+func (c *websocketConn) ping() error {
+	return errors.WithStack(c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteTimeout)))
+}
+
+// closeNormally tells the client that the connection is being closed
+func (c *websocketConn) closeNormally() {
+	_ = c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(websocketWriteTimeout))
+}
+
+func (c *websocketConn) close() error {
+	return errors.WithStack(c.conn.Close())
+}
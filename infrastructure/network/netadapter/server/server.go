@@ -3,6 +3,7 @@ package server
 import (
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 )
@@ -42,5 +43,55 @@ type Connection interface {
 	IsOutbound() bool
 	SetOnDisconnectedHandler(onDisconnectedHandler OnDisconnectedHandler)
 	SetOnInvalidMessageHandler(onInvalidMessageHandler OnInvalidMessageHandler)
-	Address() *net.TCPAddr
+	Address() net.Addr
+	RPCRole() RPCRole
+	BytesSent() uint64
+	BytesReceived() uint64
+}
+
+// OnionAddress is the address of a connection to a tor onion service.
+// Such connections are made through a proxy, so their address can't
+// be represented as a net.TCPAddr
+type OnionAddress struct {
+	Host string
+	Port uint16
+}
+
+// Network returns the name of the network of the address
+func (a *OnionAddress) Network() string {
+	return "onion"
+}
+
+// String returns the address in host:port form
+func (a *OnionAddress) String() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(int(a.Port)))
+}
+
+// RPCRole is the set of permissions an RPC client has
+type RPCRole uint8
+
+const (
+	// RPCRoleReadOnly is the role of RPC clients that may only use RPC
+	// commands that don't change the state of the node
+	RPCRoleReadOnly RPCRole = iota
+
+	// RPCRoleSubmit is the role of RPC clients that may also submit blocks
+	// and transactions, such as miners and wallets, but may not manage the node
+	RPCRoleSubmit
+
+	// RPCRoleAdmin is the role of RPC clients that may use all RPC commands
+	RPCRoleAdmin
+)
+
+func (role RPCRole) String() string {
+	switch role {
+	case RPCRoleReadOnly:
+		return "read-only"
+	case RPCRoleSubmit:
+		return "submit"
+	case RPCRoleAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("unknown RPC role %d", role)
+	}
 }
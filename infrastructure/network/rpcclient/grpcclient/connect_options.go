@@ -0,0 +1,89 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"os"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ConnectOptions are the options of the connection to the RPC server.
+// They are annotated for go-flags, so that command line tools may embed them in their configs
+type ConnectOptions struct {
+	UseTLS          bool   `long:"rpctls" description:"Connect to the RPC server over TLS"`
+	CertificateFile string `long:"rpccert" description:"File containing the certificate of the RPC server to trust, such as the self-signed certificate kaspad generates (default: the system's trusted certificates)"`
+	SkipTLSVerify   bool   `long:"rpcskipverify" description:"Don't verify the certificate of the RPC server. This is insecure"`
+	Username        string `long:"rpcuser" description:"Username to authenticate to the RPC server with"`
+	Password        string `long:"rpcpass" default-mask:"-" description:"Password to authenticate to the RPC server with"`
+	AuthToken       string `long:"rpcauthtoken" default-mask:"-" description:"Token to authenticate to the RPC server with"`
+}
+
+func (options *ConnectOptions) dialOptions() ([]grpc.DialOption, error) {
+	if options.CertificateFile != "" && !options.UseTLS {
+		return nil, errors.New("a certificate file was given without enabling TLS")
+	}
+	if options.Username != "" && options.AuthToken != "" {
+		return nil, errors.New("only one of a username and a token may be used to authenticate")
+	}
+
+	dialOptions := []grpc.DialOption{}
+	if options.UseTLS {
+		tlsConfig := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: options.SkipTLSVerify,
+		}
+		if options.CertificateFile != "" {
+			certificate, err := os.ReadFile(options.CertificateFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error reading the RPC certificate")
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(certificate) {
+				return nil, errors.Errorf("no certificates found in %s", options.CertificateFile)
+			}
+		}
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+
+	authorization := ""
+	switch {
+	case options.AuthToken != "":
+		authorization = "Bearer " + options.AuthToken
+	case options.Username != "":
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(options.Username+":"+options.Password))
+	}
+	if authorization != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(&authorizationCredentials{
+			authorization: authorization,
+		}))
+	}
+
+	return dialOptions, nil
+}
+
+func (options *ConnectOptions) hasCredentials() bool {
+	return options.Username != "" || options.AuthToken != ""
+}
+
+// authorizationCredentials attaches the authorization metadata to every request.
+// It doesn't require transport security so that authentication may be used
+// without TLS, e.g. on localhost
+type authorizationCredentials struct {
+	authorization string
+}
+
+func (c *authorizationCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{grpcserver.AuthorizationMetadataKey: c.authorization}, nil
+}
+
+func (c *authorizationCredentials) RequireTransportSecurity() bool {
+	return false
+}
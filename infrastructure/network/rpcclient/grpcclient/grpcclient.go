@@ -28,11 +28,22 @@ type GRPCClient struct {
 
 // Connect connects to the RPC server with the given address
 func Connect(address string) (*GRPCClient, error) {
+	return ConnectWithOptions(address, &ConnectOptions{})
+}
+
+// ConnectWithOptions connects to the RPC server with the given address,
+// using TLS and authentication as specified in the given options
+func ConnectWithOptions(address string, options *ConnectOptions) (*GRPCClient, error) {
+	dialOptions, err := options.dialOptions()
+	if err != nil {
+		return nil, err
+	}
+
 	const dialTimeout = 5 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
 	defer cancel()
 
-	gRPCConnection, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	gRPCConnection, err := grpc.DialContext(ctx, address, append(dialOptions, grpc.WithBlock())...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error connecting to %s", address)
 	}
@@ -43,9 +54,33 @@ func Connect(address string) (*GRPCClient, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "error getting client stream for %s", address)
 	}
+	if options.hasCredentials() {
+		err := waitForAuthentication(stream)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error authenticating to %s", address)
+		}
+	}
 	return &GRPCClient{stream: stream}, nil
 }
 
+// waitForAuthentication waits for the server to either accept the credentials of the
+// client, in which case it sends its role in the stream headers, or to reject them,
+// in which case it ends the stream with an error
+func waitForAuthentication(stream protowire.RPC_MessageStreamClient) error {
+	header, err := stream.Header()
+	if err != nil {
+		return err
+	}
+	if len(header.Get(grpcserver.RPCRoleMetadataKey)) > 0 {
+		return nil
+	}
+	err = stream.RecvMsg(&protowire.KaspadMessage{})
+	if err != nil {
+		return err
+	}
+	return errors.New("the server didn't respond to the authentication")
+}
+
 // Disconnect disconnects from the RPC server
 func (c *GRPCClient) Disconnect() error {
 	return c.stream.CloseSend()
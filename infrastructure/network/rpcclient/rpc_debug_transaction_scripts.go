@@ -0,0 +1,22 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// DebugTransactionScripts sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) DebugTransactionScripts(transaction *appmessage.RPCTransaction,
+	utxoEntries []*appmessage.RPCUTXOEntry) (*appmessage.DebugTransactionScriptsResponseMessage, error) {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewDebugTransactionScriptsRequestMessage(transaction, utxoEntries))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdDebugTransactionScriptsResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	debugTransactionScriptsResponse := response.(*appmessage.DebugTransactionScriptsResponseMessage)
+	if debugTransactionScriptsResponse.Error != nil {
+		return nil, c.convertRPCError(debugTransactionScriptsResponse.Error)
+	}
+	return debugTransactionScriptsResponse, nil
+}
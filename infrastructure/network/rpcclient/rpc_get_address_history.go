@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetAddressHistory sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetAddressHistory(address string, limit uint32, cursor string) (*appmessage.GetAddressHistoryResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetAddressHistoryRequestMessage(address, limit, cursor))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetAddressHistoryResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getAddressHistoryResponse := response.(*appmessage.GetAddressHistoryResponseMessage)
+	if getAddressHistoryResponse.Error != nil {
+		return nil, c.convertRPCError(getAddressHistoryResponse.Error)
+	}
+	return getAddressHistoryResponse, nil
+}
@@ -3,8 +3,8 @@ package rpcclient
 import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetBalanceByAddress sends an RPC request respective to the function's name and returns the RPC server's response
-func (c *RPCClient) GetBalanceByAddress(address string) (*appmessage.GetBalanceByAddressResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBalanceByAddressRequest(address))
+func (c *RPCClient) GetBalanceByAddress(address string, includeUnconfirmed bool) (*appmessage.GetBalanceByAddressResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBalanceByAddressRequest(address, includeUnconfirmed))
 	if err != nil {
 		return nil, err
 	}
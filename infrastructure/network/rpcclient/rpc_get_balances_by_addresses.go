@@ -3,8 +3,8 @@ package rpcclient
 import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetBalancesByAddresses sends an RPC request respective to the function's name and returns the RPC server's response
-func (c *RPCClient) GetBalancesByAddresses(addresses []string) (*appmessage.GetBalancesByAddressesResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBalancesByAddressesRequest(addresses))
+func (c *RPCClient) GetBalancesByAddresses(addresses []string, includeUnconfirmed bool) (*appmessage.GetBalancesByAddressesResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBalancesByAddressesRequest(addresses, includeUnconfirmed))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetTransactionAcceptance sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetTransactionAcceptance(transactionIDs []string) (*appmessage.GetTransactionAcceptanceResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetTransactionAcceptanceRequestMessage(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdGetTransactionAcceptanceResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	getTransactionAcceptanceResponse := response.(*appmessage.GetTransactionAcceptanceResponseMessage)
+	if getTransactionAcceptanceResponse.Error != nil {
+		return nil, c.convertRPCError(getTransactionAcceptanceResponse.Error)
+	}
+	return getTransactionAcceptanceResponse, nil
+}
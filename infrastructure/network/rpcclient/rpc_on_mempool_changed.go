@@ -0,0 +1,58 @@
+package rpcclient
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// RegisterForMempoolChangedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
+// Additionally, it starts listening for the appropriate notification using the given handler function.
+// If both addresses and scriptPublicKeys are empty, notifications are sent for all transactions
+func (c *RPCClient) RegisterForMempoolChangedNotifications(addresses []string, scriptPublicKeys []*appmessage.RPCScriptPublicKey,
+	onMempoolChanged func(notification *appmessage.MempoolChangedNotificationMessage)) error {
+
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyMempoolChangedRequestMessage(addresses, scriptPublicKeys))
+	if err != nil {
+		return err
+	}
+	response, err := c.route(appmessage.CmdNotifyMempoolChangedResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return err
+	}
+	notifyMempoolChangedResponse := response.(*appmessage.NotifyMempoolChangedResponseMessage)
+	if notifyMempoolChangedResponse.Error != nil {
+		return c.convertRPCError(notifyMempoolChangedResponse.Error)
+	}
+	spawn("RegisterForMempoolChangedNotifications", func() {
+		for {
+			notification, err := c.route(appmessage.CmdMempoolChangedNotificationMessage).Dequeue()
+			if err != nil {
+				if errors.Is(err, routerpkg.ErrRouteClosed) {
+					break
+				}
+				panic(err)
+			}
+			mempoolChangedNotification := notification.(*appmessage.MempoolChangedNotificationMessage)
+			onMempoolChanged(mempoolChangedNotification)
+		}
+	})
+	return nil
+}
+
+// StopNotifyingMempoolChanged sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) StopNotifyingMempoolChanged() error {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewStopNotifyingMempoolChangedRequestMessage())
+	if err != nil {
+		return err
+	}
+	response, err := c.route(appmessage.CmdStopNotifyingMempoolChangedResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return err
+	}
+	stopNotifyingMempoolChangedResponse := response.(*appmessage.StopNotifyingMempoolChangedResponseMessage)
+	if stopNotifyingMempoolChangedResponse.Error != nil {
+		return c.convertRPCError(stopNotifyingMempoolChangedResponse.Error)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// SetLogLevel sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) SetLogLevel(subsystem string, level string) (*appmessage.SetLogLevelResponseMessage, error) {
+	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewSetLogLevelRequestMessage(subsystem, level))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.route(appmessage.CmdSetLogLevelResponseMessage).DequeueWithTimeout(c.timeout)
+	if err != nil {
+		return nil, err
+	}
+	setLogLevelResponse := response.(*appmessage.SetLogLevelResponseMessage)
+	if setLogLevelResponse.Error != nil {
+		return nil, c.convertRPCError(setLogLevelResponse.Error)
+	}
+	return setLogLevelResponse, nil
+}
@@ -19,6 +19,7 @@ type RPCClient struct {
 	*grpcclient.GRPCClient
 
 	rpcAddress           string
+	connectOptions       *grpcclient.ConnectOptions
 	rpcRouter            *rpcRouter
 	isConnected          uint32
 	isClosed             uint32
@@ -30,9 +31,16 @@ type RPCClient struct {
 
 // NewRPCClient creates a new RPC client
 func NewRPCClient(rpcAddress string) (*RPCClient, error) {
+	return NewRPCClientWithOptions(rpcAddress, &grpcclient.ConnectOptions{})
+}
+
+// NewRPCClientWithOptions creates a new RPC client that connects using TLS
+// and authentication as specified in the given options
+func NewRPCClientWithOptions(rpcAddress string, connectOptions *grpcclient.ConnectOptions) (*RPCClient, error) {
 	rpcClient := &RPCClient{
-		rpcAddress: rpcAddress,
-		timeout:    defaultTimeout,
+		rpcAddress:     rpcAddress,
+		connectOptions: connectOptions,
+		timeout:        defaultTimeout,
 	}
 	err := rpcClient.connect()
 	if err != nil {
@@ -43,7 +51,7 @@ func NewRPCClient(rpcAddress string) (*RPCClient, error) {
 }
 
 func (c *RPCClient) connect() error {
-	rpcClient, err := grpcclient.Connect(c.rpcAddress)
+	rpcClient, err := grpcclient.ConnectWithOptions(c.rpcAddress, c.connectOptions)
 	if err != nil {
 		return errors.Wrapf(err, "error connecting to address %s", c.rpcAddress)
 	}
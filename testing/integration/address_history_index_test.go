@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestAddressHistoryIndex(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+		addressHistoryIndex:     true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have spendable UTXOs
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	// Nothing was spent yet, so the history should consist of exactly the
+	// credits that are currently unspent, except for the coinbase of the
+	// last block, which was accepted only by the virtual
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	history := getFullAddressHistory(t, kaspad, miningAddress1, 0)
+	if len(history) != len(utxosByAddressesResponse.Entries)-1 {
+		t.Fatalf("Unexpected amount of history entries. Want: %d, got: %d",
+			len(utxosByAddressesResponse.Entries)-1, len(history))
+	}
+	for _, historyEntry := range history {
+		if historyEntry.IsDebit {
+			t.Fatalf("Unexpected debit of outpoint %s:%d in address history",
+				historyEntry.Outpoint.TransactionID, historyEntry.Outpoint.Index)
+		}
+		utxoEntry := findUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, historyEntry.Outpoint)
+		if utxoEntry == nil {
+			t.Fatalf("Credit of outpoint %s:%d is missing from the UTXO set",
+				historyEntry.Outpoint.TransactionID, historyEntry.Outpoint.Index)
+		}
+		if historyEntry.Amount != utxoEntry.UTXOEntry.Amount {
+			t.Fatalf("Unexpected credit amount. Want: %d, got: %d",
+				utxoEntry.UTXOEntry.Amount, historyEntry.Amount)
+		}
+	}
+	for i := 1; i < len(history); i++ {
+		if history[i].DAAScore < history[i-1].DAAScore {
+			t.Fatalf("Address history is not ordered by DAA score")
+		}
+	}
+
+	// Spend a UTXO and make sure both its debit and the new credit appear
+	spentEntry := findUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, history[0].Outpoint)
+	rpcTransaction := buildTransactionForUTXOIndexTest(t, spentEntry)
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	transactionID := submitTransactionResponse.TransactionID
+
+	// Mine a block to include the transaction, and another one to accept it
+	mineNextBlock(t, kaspad)
+	acceptingBlock := mineNextBlock(t, kaspad)
+	acceptingBlockHash := consensushashing.BlockHash(acceptingBlock).String()
+
+	history = getFullAddressHistory(t, kaspad, miningAddress1, 7)
+
+	debitEntry := findAddressHistoryEntry(history, spentEntry.Outpoint, true)
+	if debitEntry == nil {
+		t.Fatalf("Missing debit of outpoint %s:%d in address history",
+			spentEntry.Outpoint.TransactionID, spentEntry.Outpoint.Index)
+	}
+	if debitEntry.TransactionID != transactionID {
+		t.Fatalf("Unexpected debiting transaction. Want: %s, got: %s", transactionID, debitEntry.TransactionID)
+	}
+	if debitEntry.Amount != spentEntry.UTXOEntry.Amount {
+		t.Fatalf("Unexpected debit amount. Want: %d, got: %d", spentEntry.UTXOEntry.Amount, debitEntry.Amount)
+	}
+	if debitEntry.AcceptingBlockHash != acceptingBlockHash {
+		t.Fatalf("Unexpected accepting block hash. Want: %s, got: %s",
+			acceptingBlockHash, debitEntry.AcceptingBlockHash)
+	}
+
+	creditEntry := findAddressHistoryEntry(history,
+		&appmessage.RPCOutpoint{TransactionID: transactionID, Index: 0}, false)
+	if creditEntry == nil {
+		t.Fatalf("Missing credit of transaction %s in address history", transactionID)
+	}
+	if creditEntry.AcceptingBlockHash != acceptingBlockHash {
+		t.Fatalf("Unexpected accepting block hash. Want: %s, got: %s",
+			acceptingBlockHash, creditEntry.AcceptingBlockHash)
+	}
+	if creditEntry.Amount != spentEntry.UTXOEntry.Amount-1000 {
+		t.Fatalf("Unexpected credit amount. Want: %d, got: %d",
+			spentEntry.UTXOEntry.Amount-1000, creditEntry.Amount)
+	}
+}
+
+// getFullAddressHistory fetches the whole history of the given address
+// page by page. A pageSize of 0 fetches the maximum allowed per page.
+func getFullAddressHistory(t *testing.T, harness *appHarness, address string,
+	pageSize uint32) []*appmessage.AddressHistoryEntry {
+
+	var history []*appmessage.AddressHistoryEntry
+	cursor := ""
+	for {
+		getAddressHistoryResponse, err := harness.rpcClient.GetAddressHistory(address, pageSize, cursor)
+		if err != nil {
+			t.Fatalf("Error getting address history: %s", err)
+		}
+		history = append(history, getAddressHistoryResponse.Entries...)
+		cursor = getAddressHistoryResponse.NextCursor
+		if pageSize == 0 || len(getAddressHistoryResponse.Entries) < int(pageSize) {
+			return history
+		}
+	}
+}
+
+func findAddressHistoryEntry(history []*appmessage.AddressHistoryEntry, outpoint *appmessage.RPCOutpoint,
+	isDebit bool) *appmessage.AddressHistoryEntry {
+
+	for _, entry := range history {
+		if *entry.Outpoint == *outpoint && entry.IsDebit == isDebit {
+			return entry
+		}
+	}
+	return nil
+}
+
+func findUTXOsByAddressesEntry(entries []*appmessage.UTXOsByAddressesEntry,
+	outpoint *appmessage.RPCOutpoint) *appmessage.UTXOsByAddressesEntry {
+
+	for _, entry := range entries {
+		if *entry.Outpoint == *outpoint {
+			return entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestDebugTransactionScripts(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	// Debug a transaction that spends a UTXO from the virtual UTXO set
+	const fee = 1000
+	transaction := buildTransactionWithFee(t, entry, fee)
+	expectValidTrace(t, kaspad, transaction, nil)
+
+	// Debug a transaction that spends an output of a mempool transaction
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(transaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	childEntry := &appmessage.UTXOsByAddressesEntry{
+		Address:  miningAddress1,
+		Outpoint: &appmessage.RPCOutpoint{TransactionID: submitTransactionResponse.TransactionID, Index: 0},
+		UTXOEntry: &appmessage.RPCUTXOEntry{
+			Amount:          entry.UTXOEntry.Amount - fee,
+			ScriptPublicKey: entry.UTXOEntry.ScriptPublicKey,
+		},
+	}
+	childTransaction := buildTransactionWithFee(t, childEntry, fee)
+	expectValidTrace(t, kaspad, childTransaction, nil)
+
+	// Debug the same transaction with explicitly given UTXO entries
+	expectValidTrace(t, kaspad, childTransaction, []*appmessage.RPCUTXOEntry{childEntry.UTXOEntry})
+
+	// A tampered signature fails on OP_CHECKSIG
+	tamperedTransaction := buildTransactionWithFee(t, childEntry, fee)
+	signatureScript := []byte(tamperedTransaction.Inputs[0].SignatureScript)
+	if signatureScript[2] == '0' {
+		signatureScript[2] = '1'
+	} else {
+		signatureScript[2] = '0'
+	}
+	tamperedTransaction.Inputs[0].SignatureScript = string(signatureScript)
+	response, err := kaspad.rpcClient.DebugTransactionScripts(tamperedTransaction, nil)
+	if err != nil {
+		t.Fatalf("DebugTransactionScripts: %s", err)
+	}
+	if len(response.InputTraces) != 1 {
+		t.Fatalf("Expected 1 input trace, got %d", len(response.InputTraces))
+	}
+	inputTrace := response.InputTraces[0]
+	if inputTrace.IsValid || inputTrace.Error == "" {
+		t.Fatalf("Expected the tampered input to be invalid")
+	}
+	lastStep := inputTrace.Steps[len(inputTrace.Steps)-1]
+	if lastStep.Opcode != "OP_CHECKSIG" {
+		t.Fatalf("Expected the tampered input to fail on OP_CHECKSIG, but the last opcode is %s", lastStep.Opcode)
+	}
+
+	// The amount of given UTXO entries must match the amount of inputs
+	_, err = kaspad.rpcClient.DebugTransactionScripts(childTransaction,
+		[]*appmessage.RPCUTXOEntry{childEntry.UTXOEntry, childEntry.UTXOEntry})
+	if err == nil || !strings.Contains(err.Error(), "one per input") {
+		t.Fatalf("Expected an error for a mismatching amount of UTXO entries, got: %v", err)
+	}
+
+	// Transactions with too many inputs are rejected before their scripts are traced
+	tooManyInputsTransaction := buildTransactionWithFee(t, childEntry, fee)
+	for len(tooManyInputsTransaction.Inputs) <= 100 {
+		tooManyInputsTransaction.Inputs = append(tooManyInputsTransaction.Inputs, tooManyInputsTransaction.Inputs[0])
+	}
+	_, err = kaspad.rpcClient.DebugTransactionScripts(tooManyInputsTransaction, nil)
+	if err == nil || !strings.Contains(err.Error(), "inputs") {
+		t.Fatalf("Expected an error for a transaction with too many inputs, got: %v", err)
+	}
+}
+
+func expectValidTrace(t *testing.T, harness *appHarness, transaction *appmessage.RPCTransaction,
+	utxoEntries []*appmessage.RPCUTXOEntry) {
+
+	response, err := harness.rpcClient.DebugTransactionScripts(transaction, utxoEntries)
+	if err != nil {
+		t.Fatalf("DebugTransactionScripts: %s", err)
+	}
+	if len(response.InputTraces) != 1 {
+		t.Fatalf("Expected 1 input trace, got %d", len(response.InputTraces))
+	}
+	inputTrace := response.InputTraces[0]
+	if !inputTrace.IsValid {
+		t.Fatalf("Input is unexpectedly invalid: %s", inputTrace.Error)
+	}
+	if len(inputTrace.Scripts) != 2 {
+		t.Fatalf("Expected 2 scripts, got %d", len(inputTrace.Scripts))
+	}
+	lastStep := inputTrace.Steps[len(inputTrace.Steps)-1]
+	if lastStep.Opcode != "OP_CHECKSIG" {
+		t.Fatalf("Expected the last opcode to be OP_CHECKSIG, got %s", lastStep.Opcode)
+	}
+	if len(lastStep.DataStack) != 1 || lastStep.DataStack[0] != "01" {
+		t.Fatalf("Expected the final data stack to be [01], got %v", lastStep.DataStack)
+	}
+}
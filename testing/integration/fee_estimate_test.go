@@ -0,0 +1,74 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestGetFeeEstimate(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	// The mempool is empty, so every bucket should suggest the minimum fee rate
+	getFeeEstimateResponse, err := kaspad.rpcClient.GetFeeEstimate()
+	if err != nil {
+		t.Fatalf("Error getting fee estimate: %s", err)
+	}
+	minimumFeeRate := getFeeEstimateResponse.LowBucket.FeeRate
+	for _, bucket := range []*appmessage.RPCFeeRateBucket{getFeeEstimateResponse.PriorityBucket,
+		getFeeEstimateResponse.NormalBucket, getFeeEstimateResponse.LowBucket} {
+
+		if bucket.FeeRate != minimumFeeRate {
+			t.Fatalf("Unexpected fee rate. Want: %f, got: %f", minimumFeeRate, bucket.FeeRate)
+		}
+		if bucket.EstimatedInclusionDAAScore != getFeeEstimateResponse.VirtualDAAScore+1 {
+			t.Fatalf("Unexpected estimated inclusion DAA score. Want: %d, got: %d",
+				getFeeEstimateResponse.VirtualDAAScore+1, bucket.EstimatedInclusionDAAScore)
+		}
+	}
+
+	// A transaction in the mempool shouldn't raise the fee rates while there's room for it
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	oldestEntry := utxosByAddressesResponse.Entries[0]
+	for _, entry := range utxosByAddressesResponse.Entries {
+		if entry.UTXOEntry.BlockDAAScore < oldestEntry.UTXOEntry.BlockDAAScore {
+			oldestEntry = entry
+		}
+	}
+	rpcTransaction := buildTransactionForUTXOIndexTest(t, oldestEntry)
+	_, err = kaspad.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+
+	getFeeEstimateResponse, err = kaspad.rpcClient.GetFeeEstimate()
+	if err != nil {
+		t.Fatalf("Error getting fee estimate: %s", err)
+	}
+	if getFeeEstimateResponse.PriorityBucket.FeeRate != minimumFeeRate {
+		t.Fatalf("Unexpected priority fee rate. Want: %f, got: %f",
+			minimumFeeRate, getFeeEstimateResponse.PriorityBucket.FeeRate)
+	}
+}
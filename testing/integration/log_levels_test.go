@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+func TestLogLevels(t *testing.T) {
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// All the kaspad instances of the test run in this process and share its
+	// loggers, so the original level is restored once the test is done
+	const subsystem = "BDAG"
+	originalLevel := logger.LogLevels()[subsystem]
+	defer func() {
+		_, err := kaspad.rpcClient.SetLogLevel(subsystem, originalLevel.Name())
+		if err != nil {
+			t.Fatalf("Error restoring the log level: %s", err)
+		}
+	}()
+
+	_, err := kaspad.rpcClient.SetLogLevel(subsystem, "critical")
+	if err != nil {
+		t.Fatalf("Error setting the log level: %s", err)
+	}
+
+	getLogLevelsResponse, err := kaspad.rpcClient.GetLogLevels()
+	if err != nil {
+		t.Fatalf("Error getting the log levels: %s", err)
+	}
+	found := false
+	for _, logLevel := range getLogLevelsResponse.LogLevels {
+		if logLevel.Subsystem == subsystem {
+			found = true
+			if logLevel.Level != "critical" {
+				t.Fatalf("Unexpected log level of %s. Want: critical, got: %s", subsystem, logLevel.Level)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("GetLogLevels didn't return the log level of %s", subsystem)
+	}
+
+	_, err = kaspad.rpcClient.SetLogLevel("NOSUCHSUBSYSTEM", "debug")
+	if err == nil {
+		t.Fatalf("Expected an error when setting the log level of an unknown subsystem")
+	}
+	_, err = kaspad.rpcClient.SetLogLevel(subsystem, "verbose")
+	if err == nil {
+		t.Fatalf("Expected an error when setting an invalid log level")
+	}
+}
@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestMempoolChangedNotifications(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	onMempoolChangedChan := make(chan *appmessage.MempoolChangedNotificationMessage, 10)
+	err := kaspad.rpcClient.RegisterForMempoolChangedNotifications([]string{miningAddress1}, nil,
+		func(notification *appmessage.MempoolChangedNotificationMessage) {
+			onMempoolChangedChan <- notification
+		})
+	if err != nil {
+		t.Fatalf("Failed to register for mempool changed notifications: %s", err)
+	}
+
+	// Registering with a malformed address should fail
+	err = kaspad.rpcClient.RegisterForMempoolChangedNotifications([]string{"kaspasim:invalid"}, nil,
+		func(*appmessage.MempoolChangedNotificationMessage) {})
+	if err == nil {
+		t.Fatalf("Expected an error when registering with an invalid address")
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(buildTransactionWithFee(t, entry, 1000), false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	originalTransactionID := submitTransactionResponse.TransactionID
+	expectMempoolChanges(t, onMempoolChangedChan, []*expectedMempoolChange{
+		{changeType: "added", transactionID: originalTransactionID},
+	})
+
+	submitTransactionReplacementResponse, err := kaspad.rpcClient.SubmitTransactionReplacement(
+		buildTransactionWithFee(t, entry, 2000))
+	if err != nil {
+		t.Fatalf("Error submitting transaction replacement: %s", err)
+	}
+	replacementTransactionID := submitTransactionReplacementResponse.TransactionID
+	expectMempoolChanges(t, onMempoolChangedChan, []*expectedMempoolChange{
+		{changeType: "removed", transactionID: originalTransactionID, removalReason: "replaced"},
+		{changeType: "added", transactionID: replacementTransactionID},
+	})
+
+	mineNextBlock(t, kaspad)
+	expectMempoolChanges(t, onMempoolChangedChan, []*expectedMempoolChange{
+		{changeType: "removed", transactionID: replacementTransactionID, removalReason: "includedInBlock"},
+	})
+
+	err = kaspad.rpcClient.StopNotifyingMempoolChanged()
+	if err != nil {
+		t.Fatalf("Failed to stop notifying mempool changed: %s", err)
+	}
+}
+
+type expectedMempoolChange struct {
+	changeType    string
+	transactionID string
+	removalReason string
+}
+
+func expectMempoolChanges(t *testing.T, onMempoolChangedChan <-chan *appmessage.MempoolChangedNotificationMessage,
+	expectedChanges []*expectedMempoolChange) {
+
+	var notification *appmessage.MempoolChangedNotificationMessage
+	select {
+	case notification = <-onMempoolChangedChan:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for a mempool changed notification")
+	}
+
+	if len(notification.Changes) != len(expectedChanges) {
+		t.Fatalf("Expected %d changes, got %d", len(expectedChanges), len(notification.Changes))
+	}
+	for i, change := range notification.Changes {
+		expectedChange := expectedChanges[i]
+		if change.Type != expectedChange.changeType {
+			t.Fatalf("Unexpected type of change %d. Want: %s, got: %s", i, expectedChange.changeType, change.Type)
+		}
+		if change.Transaction.VerboseData.TransactionID != expectedChange.transactionID {
+			t.Fatalf("Unexpected transaction of change %d. Want: %s, got: %s",
+				i, expectedChange.transactionID, change.Transaction.VerboseData.TransactionID)
+		}
+		if change.RemovalReason != expectedChange.removalReason {
+			t.Fatalf("Unexpected removal reason of change %d. Want: %q, got: %q",
+				i, expectedChange.removalReason, change.RemovalReason)
+		}
+		if change.IsOrphan {
+			t.Fatalf("Unexpected orphan in change %d", i)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestMempoolPersistence(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(buildTransactionWithFee(t, entry, 1000), false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+
+	saveMempoolResponse, err := kaspad.rpcClient.SaveMempool()
+	if err != nil {
+		t.Fatalf("Error saving the mempool: %s", err)
+	}
+	if saveMempoolResponse.SavedTransactionCount != 1 {
+		t.Fatalf("Unexpected saved transaction count. Want: 1, got: %d", saveMempoolResponse.SavedTransactionCount)
+	}
+
+	// The saved transaction is already in the mempool, so loading it again should drop it
+	loadMempoolResponse, err := kaspad.rpcClient.LoadMempool()
+	if err != nil {
+		t.Fatalf("Error loading the mempool: %s", err)
+	}
+	if loadMempoolResponse.AcceptedTransactionCount != 0 || loadMempoolResponse.RejectedTransactionCount != 1 {
+		t.Fatalf("Unexpected load result. Want: 0 accepted and 1 rejected, got: %d accepted and %d rejected",
+			loadMempoolResponse.AcceptedTransactionCount, loadMempoolResponse.RejectedTransactionCount)
+	}
+
+	// Restart kaspad and make sure the transaction survived
+	kaspad.rpcClient.Close()
+	kaspad.app.Stop()
+	err = kaspad.database.Close()
+	if err != nil {
+		t.Fatalf("Error closing database context: %+v", err)
+	}
+	setDatabaseContext(t, kaspad)
+	setApp(t, kaspad)
+	kaspad.app.Start()
+	setRPCClient(t, kaspad)
+
+	getMempoolEntriesResponse, err := kaspad.rpcClient.GetMempoolEntries()
+	if err != nil {
+		t.Fatalf("Error getting mempool entries: %s", err)
+	}
+	if len(getMempoolEntriesResponse.Entries) != 1 ||
+		getMempoolEntriesResponse.Entries[0].Transaction.VerboseData.TransactionID != submitTransactionResponse.TransactionID {
+
+		t.Fatalf("Expected the mempool to contain the transaction submitted before the restart")
+	}
+
+	// Once the transaction is mined, loading it again should drop it
+	mineNextBlock(t, kaspad)
+	loadMempoolResponse, err = kaspad.rpcClient.LoadMempool()
+	if err != nil {
+		t.Fatalf("Error loading the mempool: %s", err)
+	}
+	if loadMempoolResponse.AcceptedTransactionCount != 0 || loadMempoolResponse.RejectedTransactionCount != 1 {
+		t.Fatalf("Unexpected load result. Want: 0 accepted and 1 rejected, got: %d accepted and %d rejected",
+			loadMempoolResponse.AcceptedTransactionCount, loadMempoolResponse.RejectedTransactionCount)
+	}
+	getMempoolEntriesResponse, err = kaspad.rpcClient.GetMempoolEntries()
+	if err != nil {
+		t.Fatalf("Error getting mempool entries: %s", err)
+	}
+	if len(getMempoolEntriesResponse.Entries) != 0 {
+		t.Fatalf("Expected the mempool to be empty after loading a mined transaction")
+	}
+}
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
+)
+
+func TestRPCTLSAndAuthentication(t *testing.T) {
+	harness := &appHarness{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	}
+	setConfig(t, harness, 0)
+	certificateFile := filepath.Join(harness.config.AppDir, "rpc.cert")
+	harness.config.RPCTLS = true
+	harness.config.RPCCert = certificateFile
+	harness.config.RPCKey = filepath.Join(harness.config.AppDir, "rpc.key")
+	harness.config.RPCUser = "admin"
+	harness.config.RPCPass = "admin-password"
+	harness.config.RPCSubmitAuthToken = "submit-token"
+	harness.config.RPCReadOnlyAuthToken = "read-only-token"
+	setDatabaseContext(t, harness)
+	setApp(t, harness)
+	harness.app.Start()
+	defer func() {
+		harness.app.Stop()
+		err := harness.database.Close()
+		if err != nil {
+			t.Errorf("Error closing database context: %+v", err)
+		}
+	}()
+
+	connect := func(options *grpcclient.ConnectOptions) (*rpcclient.RPCClient, error) {
+		rpcClient, err := rpcclient.NewRPCClientWithOptions(harness.rpcAddress, options)
+		if err != nil {
+			return nil, err
+		}
+		rpcClient.SetTimeout(rpcTimeout)
+		return rpcClient, nil
+	}
+
+	// The self-signed certificate isn't trusted unless it's given explicitly
+	_, err := connect(&grpcclient.ConnectOptions{UseTLS: true, Username: "admin", Password: "admin-password"})
+	if err == nil {
+		t.Fatalf("Expected connecting without trusting the certificate to fail")
+	}
+
+	_, err = connect(&grpcclient.ConnectOptions{UseTLS: true, CertificateFile: certificateFile,
+		Username: "admin", Password: "wrong-password"})
+	if err == nil || !strings.Contains(err.Error(), "invalid credentials") {
+		t.Fatalf("Expected an invalid credentials error, got: %v", err)
+	}
+
+	readOnlyClient, err := connect(&grpcclient.ConnectOptions{UseTLS: true, CertificateFile: certificateFile,
+		AuthToken: "read-only-token"})
+	if err != nil {
+		t.Fatalf("Error connecting with the read-only token: %+v", err)
+	}
+	defer readOnlyClient.Close()
+
+	_, err = readOnlyClient.GetBlockDAGInfo()
+	if err != nil {
+		t.Fatalf("Error getting the DAG info with the read-only token: %+v", err)
+	}
+	_, err = readOnlyClient.SaveMempool()
+	if err == nil || !strings.Contains(err.Error(), "requires admin permissions") {
+		t.Fatalf("Expected a permission error when saving the mempool with the read-only token, got: %v", err)
+	}
+	_, err = readOnlyClient.SubmitTransaction(&appmessage.RPCTransaction{}, false)
+	if err == nil || !strings.Contains(err.Error(), "requires submit permissions") {
+		t.Fatalf("Expected a permission error when submitting a transaction with the read-only token, got: %v", err)
+	}
+
+	submitClient, err := connect(&grpcclient.ConnectOptions{UseTLS: true, CertificateFile: certificateFile,
+		AuthToken: "submit-token"})
+	if err != nil {
+		t.Fatalf("Error connecting with the submit token: %+v", err)
+	}
+	defer submitClient.Close()
+
+	// The transaction is invalid, so it's rejected, but only after passing the permission check
+	_, err = submitClient.SubmitTransaction(&appmessage.RPCTransaction{}, false)
+	if err == nil || strings.Contains(err.Error(), "permissions") {
+		t.Fatalf("Expected the invalid transaction to be rejected for being invalid, got: %v", err)
+	}
+	_, err = submitClient.SaveMempool()
+	if err == nil || !strings.Contains(err.Error(), "requires admin permissions") {
+		t.Fatalf("Expected a permission error when saving the mempool with the submit token, got: %v", err)
+	}
+
+	adminClient, err := connect(&grpcclient.ConnectOptions{UseTLS: true, CertificateFile: certificateFile,
+		Username: "admin", Password: "admin-password"})
+	if err != nil {
+		t.Fatalf("Error connecting with the admin credentials: %+v", err)
+	}
+	defer adminClient.Close()
+
+	_, err = adminClient.SaveMempool()
+	if err != nil {
+		t.Fatalf("Error saving the mempool with the admin credentials: %+v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package integration
 
 import (
-	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
 	"testing"
 	"time"
 
@@ -41,11 +40,12 @@ func TestRPCMaxInboundConnections(t *testing.T) {
 		t.Fatalf("Failed to close the default harness RPCClient: %s", err)
 	}
 
-	// Connect `RPCMaxInboundConnections` clients. We expect this to succeed immediately
+	// Connect `RPCMaxClients` clients. We expect this to succeed immediately
+	maxClients := harness.config.RPCMaxClients
 	rpcClients := []*testRPCClient{}
 	doneChan := make(chan error)
 	go func() {
-		for i := 0; i < grpcserver.RPCMaxInboundConnections; i++ {
+		for i := 0; i < maxClients; i++ {
 			rpcClient, err := newTestRPCClient(harness.rpcAddress)
 			if err != nil {
 				doneChan <- err
@@ -60,7 +60,7 @@ func TestRPCMaxInboundConnections(t *testing.T) {
 			t.Fatalf("newTestRPCClient: %s", err)
 		}
 	case <-time.After(time.Second * 5):
-		t.Fatalf("Timeout for connecting %d RPC connections elapsed", grpcserver.RPCMaxInboundConnections)
+		t.Fatalf("Timeout for connecting %d RPC connections elapsed", maxClients)
 	}
 
 	// Try to connect another client. We expect this to fail
@@ -15,7 +15,7 @@ func TestVirtualSelectedParentChain(t *testing.T) {
 
 	// Register to virtual selected parent chain changes
 	onVirtualSelectedParentChainChangedChan := make(chan *appmessage.VirtualSelectedParentChainChangedNotificationMessage)
-	err := kaspad1.rpcClient.RegisterForVirtualSelectedParentChainChangedNotifications(
+	err := kaspad1.rpcClient.RegisterForVirtualSelectedParentChainChangedNotifications(true,
 		func(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage) {
 			onVirtualSelectedParentChainChangedChan <- notification
 		})
@@ -29,6 +29,7 @@ func TestVirtualSelectedParentChain(t *testing.T) {
 	chain1TipHash := consensushashing.BlockHash(kaspad1.config.NetParams().GenesisBlock)
 	chain1TipHashString := chain1TipHash.String()
 	const blockAmountToMine = 10
+	var previousMinedBlock *externalapi.DomainBlock
 	for i := 0; i < blockAmountToMine; i++ {
 		minedBlock := mineNextBlock(t, kaspad1)
 		notification := <-onVirtualSelectedParentChainChangedChan
@@ -46,6 +47,25 @@ func TestVirtualSelectedParentChain(t *testing.T) {
 			t.Fatalf("Unexpected block hash in AddedChainBlockHashes. Want: %s, got: %s",
 				minedBlockHashString, notification.AddedChainBlockHashes[0])
 		}
+
+		// Make sure that the added chain block accepted the coinbase
+		// transaction of its selected parent
+		if len(notification.AcceptedTransactionIDs) != 1 {
+			t.Fatalf("Unexpected length of AcceptedTransactionIDs. Want: %d, got: %d",
+				1, len(notification.AcceptedTransactionIDs))
+		}
+		if notification.AcceptedTransactionIDs[0].AcceptingBlockHash != minedBlockHashString {
+			t.Fatalf("Unexpected accepting block hash. Want: %s, got: %s",
+				minedBlockHashString, notification.AcceptedTransactionIDs[0].AcceptingBlockHash)
+		}
+		if previousMinedBlock != nil {
+			previousCoinbaseID := consensushashing.TransactionID(previousMinedBlock.Transactions[0]).String()
+			if !containsString(notification.AcceptedTransactionIDs[0].AcceptedTransactionIDs, previousCoinbaseID) {
+				t.Fatalf("Coinbase transaction %s of the selected parent was not reported as accepted by %s",
+					previousCoinbaseID, minedBlockHashString)
+			}
+		}
+		previousMinedBlock = minedBlock
 		chain1TipHashString = minedBlockHashString
 	}
 
@@ -104,7 +124,7 @@ func TestVirtualSelectedParentChain(t *testing.T) {
 
 	// Get the virtual selected parent chain from the tip of
 	// the first chain
-	virtualSelectedParentChainFromChain1Tip, err := kaspad1.rpcClient.GetVirtualSelectedParentChainFromBlock(chain1TipHashString)
+	virtualSelectedParentChainFromChain1Tip, err := kaspad1.rpcClient.GetVirtualSelectedParentChainFromBlock(chain1TipHashString, true)
 	if err != nil {
 		t.Fatalf("GetVirtualSelectedParentChainFromBlock failed: %s", err)
 	}
@@ -127,4 +147,39 @@ func TestVirtualSelectedParentChain(t *testing.T) {
 		t.Fatalf("Unexpected last added chain block. Want: %s, got: %s",
 			chain2TipHashString, lastAddedChainBlock)
 	}
+
+	// Make sure that accepted transaction IDs were returned for
+	// every added chain block, in the same order
+	if len(virtualSelectedParentChainFromChain1Tip.AcceptedTransactionIDs) != blockAmountToMine+1 {
+		t.Fatalf("Unexpected length of virtualSelectedParentChainFromChain1Tip.AcceptedTransactionIDs. Want: %d, got: %d",
+			blockAmountToMine+1, len(virtualSelectedParentChainFromChain1Tip.AcceptedTransactionIDs))
+	}
+	for i, acceptedTransactionIDs := range virtualSelectedParentChainFromChain1Tip.AcceptedTransactionIDs {
+		if acceptedTransactionIDs.AcceptingBlockHash != virtualSelectedParentChainFromChain1Tip.AddedChainBlockHashes[i] {
+			t.Fatalf("Unexpected accepting block hash at index %d. Want: %s, got: %s", i,
+				virtualSelectedParentChainFromChain1Tip.AddedChainBlockHashes[i], acceptedTransactionIDs.AcceptingBlockHash)
+		}
+		if len(acceptedTransactionIDs.AcceptedTransactionIDs) == 0 {
+			t.Fatalf("Chain block %s unexpectedly accepted no transactions", acceptedTransactionIDs.AcceptingBlockHash)
+		}
+	}
+
+	// Make sure that accepted transaction IDs are omitted when not requested
+	virtualSelectedParentChainWithoutAcceptedTransactionIDs, err :=
+		kaspad1.rpcClient.GetVirtualSelectedParentChainFromBlock(chain1TipHashString, false)
+	if err != nil {
+		t.Fatalf("GetVirtualSelectedParentChainFromBlock failed: %s", err)
+	}
+	if len(virtualSelectedParentChainWithoutAcceptedTransactionIDs.AcceptedTransactionIDs) != 0 {
+		t.Fatalf("AcceptedTransactionIDs is unexpectedly not empty")
+	}
+}
+
+func containsString(strings []string, s string) bool {
+	for _, str := range strings {
+		if str == s {
+			return true
+		}
+	}
+	return false
 }
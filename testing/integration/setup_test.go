@@ -24,6 +24,8 @@ type appHarness struct {
 	config                  *config.Config
 	database                database.Database
 	utxoIndex               bool
+	txIndex                 bool
+	addressHistoryIndex     bool
 	overrideDAGParams       *dagconfig.Params
 }
 
@@ -33,6 +35,8 @@ type harnessParams struct {
 	miningAddress           string
 	miningAddressPrivateKey string
 	utxoIndex               bool
+	txIndex                 bool
+	addressHistoryIndex     bool
 	overrideDAGParams       *dagconfig.Params
 	protocolVersion         uint32
 }
@@ -45,6 +49,8 @@ func setupHarness(t *testing.T, params *harnessParams) (harness *appHarness, tea
 		miningAddress:           params.miningAddress,
 		miningAddressPrivateKey: params.miningAddressPrivateKey,
 		utxoIndex:               params.utxoIndex,
+		txIndex:                 params.txIndex,
+		addressHistoryIndex:     params.addressHistoryIndex,
 		overrideDAGParams:       params.overrideDAGParams,
 	}
 
@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/protocol/flowcontext"
+)
+
+func TestSubmitTransactionReplacement(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	originalTransaction := buildTransactionWithFee(t, entry, 1000)
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(originalTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	originalTransactionID := submitTransactionResponse.TransactionID
+
+	// A regular submission of a double spend should still be rejected
+	replacement := buildTransactionWithFee(t, entry, 2000)
+	_, err = kaspad.rpcClient.SubmitTransaction(replacement, false)
+	if err == nil || !strings.Contains(err.Error(), "already spent by transaction") {
+		t.Fatalf("Expected a double spend error, got: %v", err)
+	}
+
+	// A replacement that doesn't pay more should be rejected
+	_, err = kaspad.rpcClient.SubmitTransactionReplacement(buildTransactionWithFee(t, entry, 1000-1))
+	if err == nil || !strings.Contains(err.Error(), "not higher than") {
+		t.Fatalf("Expected an insufficient fee error, got: %v", err)
+	}
+
+	submitTransactionReplacementResponse, err := kaspad.rpcClient.SubmitTransactionReplacement(replacement)
+	if err != nil {
+		t.Fatalf("Error submitting transaction replacement: %s", err)
+	}
+	if len(submitTransactionReplacementResponse.ReplacedTransactionIDs) != 1 ||
+		submitTransactionReplacementResponse.ReplacedTransactionIDs[0] != originalTransactionID {
+
+		t.Fatalf("Unexpected replaced transactions. Want: [%s], got: %v",
+			originalTransactionID, submitTransactionReplacementResponse.ReplacedTransactionIDs)
+	}
+
+	getMempoolEntriesResponse, err := kaspad.rpcClient.GetMempoolEntries()
+	if err != nil {
+		t.Fatalf("Error getting mempool entries: %s", err)
+	}
+	if len(getMempoolEntriesResponse.Entries) != 1 ||
+		getMempoolEntriesResponse.Entries[0].Transaction.VerboseData.TransactionID !=
+			submitTransactionReplacementResponse.TransactionID {
+
+		t.Fatalf("Expected the mempool to contain only the replacement")
+	}
+}
+
+func TestTransactionReplacementRelay(t *testing.T) {
+	harnesses, teardown := setupHarnesses(t, []*harnessParams{
+		{
+			p2pAddress:              p2pAddress1,
+			rpcAddress:              rpcAddress1,
+			miningAddress:           miningAddress1,
+			miningAddressPrivateKey: miningAddress1PrivateKey,
+			utxoIndex:               true,
+		},
+		{
+			p2pAddress:              p2pAddress2,
+			rpcAddress:              rpcAddress2,
+			miningAddress:           miningAddress2,
+			miningAddressPrivateKey: miningAddress2PrivateKey,
+		},
+	})
+	defer teardown()
+	submitter, relayee := harnesses[0], harnesses[1]
+	connect(t, submitter, relayee)
+
+	relayeeBlockAddedChan := make(chan *appmessage.RPCBlockHeader)
+	setOnBlockAddedHandler(t, relayee, func(notification *appmessage.BlockAddedNotificationMessage) {
+		relayeeBlockAddedChan <- notification.Block.Header
+	})
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, submitter)
+	waitForPayeeToReceiveBlock(t, relayeeBlockAddedChan)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, submitter)
+		waitForPayeeToReceiveBlock(t, relayeeBlockAddedChan)
+	}
+
+	utxosByAddressesResponse, err := submitter.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	// Transaction IDs are only broadcast once TransactionIDPropagationInterval
+	// passed since the previous broadcast
+	time.Sleep(flowcontext.TransactionIDPropagationInterval)
+	submitTransactionResponse, err := submitter.rpcClient.SubmitTransaction(buildTransactionWithFee(t, entry, 1000), false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	waitForMempoolEntry(t, relayee, submitTransactionResponse.TransactionID, true)
+
+	time.Sleep(flowcontext.TransactionIDPropagationInterval)
+	submitTransactionReplacementResponse, err :=
+		submitter.rpcClient.SubmitTransactionReplacement(buildTransactionWithFee(t, entry, 2000))
+	if err != nil {
+		t.Fatalf("Error submitting transaction replacement: %s", err)
+	}
+	waitForMempoolEntry(t, relayee, submitTransactionReplacementResponse.TransactionID, true)
+	waitForMempoolEntry(t, relayee, submitTransactionResponse.TransactionID, false)
+}
+
+// waitForMempoolEntry waits until the transaction with the given ID is in the mempool
+// of the given harness, or until it isn't if isExpectedInMempool is false
+func waitForMempoolEntry(t *testing.T, harness *appHarness, transactionID string, isExpectedInMempool bool) {
+	deadline := time.Now().Add(defaultTimeout)
+	for time.Now().Before(deadline) {
+		_, err := harness.rpcClient.GetMempoolEntry(transactionID)
+		if err != nil && !strings.Contains(err.Error(), "not found") {
+			t.Fatalf("Error getting mempool entry: %+v", err)
+		}
+		if (err == nil) == isExpectedInMempool {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timeout waiting for transaction %s to be in the mempool: %t", transactionID, isExpectedInMempool)
+}
@@ -0,0 +1,135 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestTXIndex(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+		txIndex:                 true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have spendable UTXOs
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	if len(utxosByAddressesResponse.Entries) == 0 {
+		t.Fatalf("No UTXOs to spend")
+	}
+
+	// Spend the oldest UTXO, since the newest ones may still be immature coinbase outputs
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	// Submit a transaction and make sure it's reported as in the mempool
+	rpcTransaction := buildTransactionForUTXOIndexTest(t, entry)
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(rpcTransaction, false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	transactionID := submitTransactionResponse.TransactionID
+
+	getTransactionResponse, err := kaspad.rpcClient.GetTransaction(transactionID, false)
+	if err != nil {
+		t.Fatalf("Error getting transaction: %s", err)
+	}
+	if !getTransactionResponse.IsInMempool {
+		t.Fatalf("Expected transaction %s to be in the mempool", transactionID)
+	}
+
+	// Mine a block to include the transaction, and make sure it's reported as
+	// included but not accepted until a chain block merges the including block
+	includingBlock := mineNextBlock(t, kaspad)
+	includingBlockHash := consensushashing.BlockHash(includingBlock).String()
+
+	getTransactionResponse, err = kaspad.rpcClient.GetTransaction(transactionID, false)
+	if err != nil {
+		t.Fatalf("Error getting transaction: %s", err)
+	}
+	if getTransactionResponse.IsInMempool {
+		t.Fatalf("Expected transaction %s not to be in the mempool", transactionID)
+	}
+	if len(getTransactionResponse.IncludingBlockHashes) != 1 ||
+		getTransactionResponse.IncludingBlockHashes[0] != includingBlockHash {
+		t.Fatalf("Unexpected including block hashes of an unaccepted transaction. Want: [%s], got: %s",
+			includingBlockHash, getTransactionResponse.IncludingBlockHashes)
+	}
+	if getTransactionResponse.AcceptingBlockHash != "" {
+		t.Fatalf("Expected transaction %s not to be accepted, but it was accepted by %s",
+			transactionID, getTransactionResponse.AcceptingBlockHash)
+	}
+
+	// Mine another block to accept the transaction
+	acceptingBlock := mineNextBlock(t, kaspad)
+
+	getTransactionResponse, err = kaspad.rpcClient.GetTransaction(transactionID, true)
+	if err != nil {
+		t.Fatalf("Error getting transaction: %s", err)
+	}
+	if getTransactionResponse.IsInMempool {
+		t.Fatalf("Expected transaction %s not to be in the mempool", transactionID)
+	}
+	if getTransactionResponse.Transaction.VerboseData == nil ||
+		getTransactionResponse.Transaction.VerboseData.TransactionID != transactionID {
+		t.Fatalf("Unexpected transaction verbose data: %+v", getTransactionResponse.Transaction.VerboseData)
+	}
+	if len(getTransactionResponse.IncludingBlockHashes) != 1 ||
+		getTransactionResponse.IncludingBlockHashes[0] != includingBlockHash {
+		t.Fatalf("Unexpected including block hashes. Want: [%s], got: %s",
+			includingBlockHash, getTransactionResponse.IncludingBlockHashes)
+	}
+	acceptingBlockHash := consensushashing.BlockHash(acceptingBlock).String()
+	if getTransactionResponse.AcceptingBlockHash != acceptingBlockHash {
+		t.Fatalf("Unexpected accepting block hash. Want: %s, got: %s",
+			acceptingBlockHash, getTransactionResponse.AcceptingBlockHash)
+	}
+	if getTransactionResponse.Confirmations == 0 {
+		t.Fatalf("Expected transaction %s to have confirmations", transactionID)
+	}
+
+	// Make sure GetTransactionAcceptance agrees with GetTransaction
+	getTransactionAcceptanceResponse, err := kaspad.rpcClient.GetTransactionAcceptance([]string{transactionID})
+	if err != nil {
+		t.Fatalf("Error getting transaction acceptance: %s", err)
+	}
+	if len(getTransactionAcceptanceResponse.Entries) != 1 {
+		t.Fatalf("Unexpected amount of entries. Want: 1, got: %d", len(getTransactionAcceptanceResponse.Entries))
+	}
+	expectedEntry := &appmessage.TransactionAcceptanceEntry{
+		TransactionID:           transactionID,
+		IsAccepted:              true,
+		AcceptingBlockHash:      acceptingBlockHash,
+		IncludingBlockHash:      includingBlockHash,
+		AcceptingBlockBlueScore: getTransactionResponse.AcceptingBlockBlueScore,
+		Confirmations:           getTransactionResponse.Confirmations,
+	}
+	if *getTransactionAcceptanceResponse.Entries[0] != *expectedEntry {
+		t.Fatalf("Unexpected transaction acceptance entry. Want: %+v, got: %+v",
+			expectedEntry, getTransactionAcceptanceResponse.Entries[0])
+	}
+}
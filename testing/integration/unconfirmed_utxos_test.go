@@ -0,0 +1,119 @@
+package integration
+
+import (
+	"testing"
+)
+
+func TestUnconfirmedUTXOs(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Mine some blocks so that we'd have a spendable UTXO
+	const blockAmountToMine = 100
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	confirmedUTXOCount := len(utxosByAddressesResponse.Entries)
+	entry := utxosByAddressesResponse.Entries[0]
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.UTXOEntry.BlockDAAScore < entry.UTXOEntry.BlockDAAScore {
+			entry = utxosByAddressesEntry
+		}
+	}
+
+	const fee = 1000
+	submitTransactionResponse, err := kaspad.rpcClient.SubmitTransaction(buildTransactionWithFee(t, entry, fee), false)
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+
+	// Without includeUnconfirmed the mempool is ignored
+	utxosByAddressesResponse, err = kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	if len(utxosByAddressesResponse.Entries) != confirmedUTXOCount {
+		t.Fatalf("Expected %d UTXOs, got %d", confirmedUTXOCount, len(utxosByAddressesResponse.Entries))
+	}
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		if utxosByAddressesEntry.IsUnconfirmed || utxosByAddressesEntry.IsSpentByUnconfirmed {
+			t.Fatalf("Unexpected unconfirmed UTXO %+v", utxosByAddressesEntry.Outpoint)
+		}
+	}
+
+	utxosByAddressesResponse, err = kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, true)
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	if len(utxosByAddressesResponse.Entries) != confirmedUTXOCount+1 {
+		t.Fatalf("Expected %d UTXOs, got %d", confirmedUTXOCount+1, len(utxosByAddressesResponse.Entries))
+	}
+	foundSpent, foundUnconfirmed := false, false
+	for _, utxosByAddressesEntry := range utxosByAddressesResponse.Entries {
+		switch {
+		case *utxosByAddressesEntry.Outpoint == *entry.Outpoint:
+			if !utxosByAddressesEntry.IsSpentByUnconfirmed {
+				t.Fatalf("The UTXO spent by the transaction isn't marked as spent")
+			}
+			foundSpent = true
+		case utxosByAddressesEntry.Outpoint.TransactionID == submitTransactionResponse.TransactionID:
+			if !utxosByAddressesEntry.IsUnconfirmed {
+				t.Fatalf("The output of the transaction isn't marked as unconfirmed")
+			}
+			if utxosByAddressesEntry.UTXOEntry.Amount != entry.UTXOEntry.Amount-fee {
+				t.Fatalf("Unexpected amount of the unconfirmed UTXO. Want: %d, got: %d",
+					entry.UTXOEntry.Amount-fee, utxosByAddressesEntry.UTXOEntry.Amount)
+			}
+			foundUnconfirmed = true
+		default:
+			if utxosByAddressesEntry.IsUnconfirmed || utxosByAddressesEntry.IsSpentByUnconfirmed {
+				t.Fatalf("Unexpected unconfirmed UTXO %+v", utxosByAddressesEntry.Outpoint)
+			}
+		}
+	}
+	if !foundSpent || !foundUnconfirmed {
+		t.Fatalf("Missing UTXOs. Found spent: %t, found unconfirmed: %t", foundSpent, foundUnconfirmed)
+	}
+
+	getBalanceByAddressResponse, err := kaspad.rpcClient.GetBalanceByAddress(miningAddress1, true)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %s", err)
+	}
+	if getBalanceByAddressResponse.UnconfirmedIncoming != entry.UTXOEntry.Amount-fee {
+		t.Fatalf("Unexpected unconfirmed incoming balance. Want: %d, got: %d",
+			entry.UTXOEntry.Amount-fee, getBalanceByAddressResponse.UnconfirmedIncoming)
+	}
+	if getBalanceByAddressResponse.UnconfirmedOutgoing != entry.UTXOEntry.Amount {
+		t.Fatalf("Unexpected unconfirmed outgoing balance. Want: %d, got: %d",
+			entry.UTXOEntry.Amount, getBalanceByAddressResponse.UnconfirmedOutgoing)
+	}
+
+	getBalancesByAddressesResponse, err := kaspad.rpcClient.GetBalancesByAddresses([]string{miningAddress1}, false)
+	if err != nil {
+		t.Fatalf("Failed to get balances: %s", err)
+	}
+	balancesEntry := getBalancesByAddressesResponse.Entries[0]
+	if balancesEntry.Balance != getBalanceByAddressResponse.Balance {
+		t.Fatalf("Unexpected balance. Want: %d, got: %d", getBalanceByAddressResponse.Balance, balancesEntry.Balance)
+	}
+	if balancesEntry.UnconfirmedIncoming != 0 || balancesEntry.UnconfirmedOutgoing != 0 {
+		t.Fatalf("Unexpected unconfirmed balances when the mempool isn't included")
+	}
+}
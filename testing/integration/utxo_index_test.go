@@ -107,7 +107,7 @@ func TestUTXOIndex(t *testing.T) {
 
 	// Get all the UTXOs and make sure the response is equivalent
 	// to the data collected via notifications
-	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1})
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1}, false)
 	if err != nil {
 		t.Fatalf("Failed to get UTXOs: %s", err)
 	}
@@ -151,6 +151,11 @@ func TestUTXOIndex(t *testing.T) {
 }
 
 func buildTransactionForUTXOIndexTest(t *testing.T, entry *appmessage.UTXOsByAddressesEntry) *appmessage.RPCTransaction {
+	return buildTransactionWithFee(t, entry, 1000)
+}
+
+// buildTransactionWithFee builds a transaction that spends the given entry to miningAddress1, paying the given fee
+func buildTransactionWithFee(t *testing.T, entry *appmessage.UTXOsByAddressesEntry, fee uint64) *appmessage.RPCTransaction {
 	transactionIDBytes, err := hex.DecodeString(entry.Outpoint.TransactionID)
 	if err != nil {
 		t.Fatalf("Error decoding transaction ID: %s", err)
@@ -172,7 +177,7 @@ func buildTransactionForUTXOIndexTest(t *testing.T, entry *appmessage.UTXOsByAdd
 		t.Fatalf("Error generating script: %+v", err)
 	}
 
-	txOuts := []*appmessage.TxOut{appmessage.NewTxOut(entry.UTXOEntry.Amount-1000, toScript)}
+	txOuts := []*appmessage.TxOut{appmessage.NewTxOut(entry.UTXOEntry.Amount-fee, toScript)}
 
 	fromScriptCode, err := hex.DecodeString(entry.UTXOEntry.ScriptPublicKey.Script)
 	if err != nil {